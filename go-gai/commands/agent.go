@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+// runAgentToolCall executes `call` with the matching tool of `tools`
+// and returns the output for the AI, which also describes errors.
+func runAgentToolCall(app *types.AppContext, tools map[string]*types.Tool, call types.AIToolCall, reader *bufio.Reader) string {
+	tool, ok := tools[call.Name]
+	if !ok {
+		return fmt.Sprintf("ERROR: tool '%s' is not allowed", call.Name)
+	}
+
+	if !app.AlwaysYes {
+		app.WriteErrorString(fmt.Sprintf("Call tool '%s' with %s [y(es)/N(o)]?: ", call.Name, call.Arguments))
+
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(strings.ToLower(input))
+
+		if input != "y" && input != "yes" {
+			return "ERROR: the user denied the call of the tool"
+		}
+	}
+
+	app.Dbgf("Calling tool '%v' with %v ...%v", call.Name, call.Arguments, app.EOL)
+
+	output, err := tool.Handler(app, call.Arguments)
+	if err != nil {
+		return fmt.Sprintf("ERROR: %s", err.Error())
+	}
+	return output
+}
+
+// Init_agent_Command initializes the `agent` command.
+func Init_agent_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var maxSteps int
+	var reset bool
+
+	var agentCmd = &cobra.Command{
+		Use:     "agent [TASK]",
+		Aliases: []string{"ag"},
+		Short:   "AI agent",
+		Long:    `Lets the AI solve a task with the help of the tools of --allow-tools.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			app.InitAI()
+
+			if maxSteps < 1 {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("--max-steps must be at least 1")))
+			}
+
+			allowedTools, err := app.GetAllowedTools()
+			app.CheckIfError(err)
+
+			tools := map[string]*types.Tool{}
+			definitions := make([]types.ToolDefinition, 0)
+			for _, t := range allowedTools {
+				tools[t.Definition.Name] = t
+				definitions = append(definitions, t.Definition)
+			}
+			if len(definitions) == 0 {
+				app.Dbgf("No tools allowed, use --allow-tools to provide some%v", app.EOL)
+			}
+
+			if len(definitions) > 0 && !app.AlwaysYes && app.IsInputFromStdin() {
+				// confirmations would read the same STDIN and deny all calls
+				app.CheckIfError(types.NewTypedError(
+					types.ErrorTypeUsage,
+					errors.New("calls of tools cannot be confirmed, because STDIN is read as task, use --yes to allow them without confirmation"),
+				))
+			}
+
+			message, err := app.GetInput(args)
+			app.CheckIfError(err)
+
+			message = strings.TrimSpace(message)
+			if message == "" {
+				app.CheckIfError(errors.New("no task defined"))
+			}
+
+			chat, err := app.NewChatContext()
+			app.CheckIfError(err)
+
+			if reset {
+				chat.ResetConversation()
+			}
+
+			reader := bufio.NewReader(app.Stdin)
+
+			toolResults := make([]types.AIToolResult, 0)
+			for step := 1; step <= maxSteps; step++ {
+				app.Dbgf("Step %v of max. %v ...%v", step, maxSteps, app.EOL)
+
+				answer, conversation, err := app.ChatAndValidate(chat, message, types.AIClientChatOptions{
+					ToolResults: toolResults,
+					Tools:       definitions,
+				})
+				app.CheckIfError(err)
+
+				toolCalls := conversation[len(conversation)-1].ToolCalls
+				if len(toolCalls) == 0 {
+					app.OutputAIAnswer(answer)
+					app.OutputAIUsageOf(conversation)
+					return
+				}
+
+				// next request only submits the results
+				message = ""
+				toolResults = make([]types.AIToolResult, 0)
+				for _, call := range toolCalls {
+					toolResults = append(toolResults, types.AIToolResult{
+						Content:    runAgentToolCall(app, tools, call, reader),
+						ToolCallId: call.Id,
+					})
+				}
+			}
+
+			app.CheckIfError(fmt.Errorf("task has not been finished after %v step(s), see --max-steps", maxSteps))
+		},
+	}
+
+	agentCmd.Flags().IntVarP(&maxSteps, "max-steps", "", 10, "maximum number of requests to the AI")
+	agentCmd.Flags().BoolVarP(&reset, "reset", "", false, "reset conversation")
+
+	app.WithCurlCLIFlags(agentCmd)
+	app.WithHighlightCLIFlags(agentCmd)
+	app.WithToolsCLIFlags(agentCmd)
+	app.WithYesCliFlags(agentCmd)
+
+	parentCmd.AddCommand(
+		agentCmd,
+	)
+}
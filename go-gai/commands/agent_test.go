@@ -0,0 +1,281 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+// testAgentMessage is a message of a request of `newTestAgentServer`.
+type testAgentMessage struct {
+	Content    json.RawMessage `json:"content"`
+	Role       string          `json:"role"`
+	ToolCallId string          `json:"tool_call_id"`
+	ToolCalls  []struct {
+		Function struct {
+			Arguments string `json:"arguments"`
+			Name      string `json:"name"`
+		} `json:"function"`
+		Id string `json:"id"`
+	} `json:"tool_calls"`
+}
+
+// testAgentRequest is a request of `newTestAgentServer`.
+type testAgentRequest struct {
+	Messages []testAgentMessage `json:"messages"`
+	Tools    []struct {
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	} `json:"tools"`
+}
+
+// newTestAgentToolCall returns an answer of `newTestAgentServer`,
+// which calls tool `name` with `arguments`.
+func newTestAgentToolCall(id string, name string, arguments string) map[string]any {
+	return map[string]any{
+		"role": "assistant",
+		"tool_calls": []any{
+			map[string]any{
+				"function": map[string]any{"arguments": arguments, "name": name},
+				"id":       id,
+				"type":     "function",
+			},
+		},
+	}
+}
+
+// newTestAgentServer starts a server for chat completions of the OpenAI API,
+// which answers with the messages of `answers` in turn, repeating the last one,
+// and returns the list of received requests.
+func newTestAgentServer(t *testing.T, app *types.AppContext, answers ...map[string]any) *[]testAgentRequest {
+	t.Helper()
+
+	requests := make([]testAgentRequest, 0)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var body testAgentRequest
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"model": "gpt-4o",
+			"choices": []any{
+				map[string]any{
+					"message": answers[min(len(requests), len(answers))-1],
+				},
+			},
+		})
+	})
+	startTestOpenAIServer(t, app, mux)
+
+	return &requests
+}
+
+// getTestAgentToolResult returns the content of the tool message of `request`
+// for the call with `id`.
+func getTestAgentToolResult(t *testing.T, request testAgentRequest, id string) string {
+	t.Helper()
+
+	for _, m := range request.Messages {
+		if m.Role == "tool" && m.ToolCallId == id {
+			return getTestMessageText(m.Content)
+		}
+	}
+
+	t.Fatalf("no result of tool call '%s' found in %+v", id, request.Messages)
+	return ""
+}
+
+func TestAgentToolCall(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	requests := newTestAgentServer(t, app,
+		newTestAgentToolCall("call_1", "shell", `{"command":"echo hello"}`),
+		map[string]any{"content": "The shell says hello.", "role": "assistant"},
+	)
+
+	runTestCommand(t, app, Init_agent_Command, "agent", "Say hello", "--allow-tools", "shell", "--yes")
+
+	if output := readTestOutput(t, app.Stdout); output != "The shell says hello." {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(*requests))
+	}
+
+	// tools are submitted with each request
+	for i, r := range *requests {
+		if len(r.Tools) != 1 || r.Tools[0].Function.Name != "shell" {
+			t.Errorf("request %d: expected shell tool, got %+v", i, r.Tools)
+		}
+	}
+
+	// second request contains call and its result
+	second := (*requests)[1]
+
+	var call *testAgentMessage
+	for i, m := range second.Messages {
+		if m.Role == "assistant" && len(m.ToolCalls) > 0 {
+			call = &second.Messages[i]
+		}
+	}
+	if call == nil || call.ToolCalls[0].Id != "call_1" || call.ToolCalls[0].Function.Name != "shell" {
+		t.Fatalf("expected tool call in %+v", second.Messages)
+	}
+
+	result := getTestAgentToolResult(t, second, "call_1")
+	if !strings.HasPrefix(result, "exit code: 0\n") || !strings.Contains(result, "hello") {
+		t.Errorf("unexpected tool result %q", result)
+	}
+}
+
+func TestAgentConfirmation(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses touch command")
+	}
+
+	tests := []struct {
+		input          string
+		expectedCalled bool
+	}{
+		{"y\n", true},
+		{"yes\n", true},
+		{"n\n", false},
+		{"\n", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		// task is not read from STDIN
+		app := newTestApp(t, map[string]string{
+			"GAI_INPUT_ORDER": "args",
+		})
+
+		requests := newTestAgentServer(t, app,
+			newTestAgentToolCall("call_1", "shell", `{"command":"touch called.txt"}`),
+			map[string]any{"content": "Done.", "role": "assistant"},
+		)
+
+		writeTestStdin(t, app, test.input)
+
+		runTestCommand(t, app, Init_agent_Command, "agent", "Create a file", "--allow-tools", "shell")
+
+		if output := readTestOutput(t, app.Stderr); !strings.Contains(output, `Call tool 'shell' with {"command":"touch called.txt"} [y(es)/N(o)]?: `) {
+			t.Errorf("%q: expected confirmation, got %q", test.input, output)
+		}
+
+		_, err := os.Stat(filepath.Join(app.WorkingDirectory, "called.txt"))
+		if called := err == nil; called != test.expectedCalled {
+			t.Errorf("%q: expected called %v, got %v", test.input, test.expectedCalled, called)
+		}
+
+		result := getTestAgentToolResult(t, (*requests)[1], "call_1")
+		if denied := result == "ERROR: the user denied the call of the tool"; denied == test.expectedCalled {
+			t.Errorf("%q: unexpected tool result %q", test.input, result)
+		}
+	}
+}
+
+func TestAgentUnknownTool(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	requests := newTestAgentServer(t, app,
+		newTestAgentToolCall("call_1", "read_file", `{"path":"secret.txt"}`),
+		map[string]any{"content": "I cannot read it.", "role": "assistant"},
+	)
+
+	runTestCommand(t, app, Init_agent_Command, "agent", "Read secret.txt", "--allow-tools", "shell", "--yes")
+
+	if result := getTestAgentToolResult(t, (*requests)[1], "call_1"); result != "ERROR: tool 'read_file' is not allowed" {
+		t.Errorf("unexpected tool result %q", result)
+	}
+	if output := readTestOutput(t, app.Stdout); output != "I cannot read it." {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
+func TestAgentMaxSteps(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+
+		newTestAgentServer(t, app,
+			newTestAgentToolCall("call_1", "shell", `{"command":"echo again"}`),
+		)
+
+		runTestCommand(t, app, Init_agent_Command, "agent", "Never stop", "--allow-tools", "shell", "--yes", "--max-steps", testCase)
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestAgentMaxSteps", "3")
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(output, "task has not been finished after 3 step(s), see --max-steps") {
+		t.Errorf("expected error message about max steps, got %q", output)
+	}
+}
+
+func TestAgentTaskFromStdin(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		newTestAgentServer(t, app,
+			map[string]any{"content": "Should not be sent.", "role": "assistant"},
+		)
+		writeTestStdin(t, app, "Create a file")
+
+		runTestCommand(t, app, Init_agent_Command, "agent", "--allow-tools", "shell")
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestAgentTaskFromStdin", "default")
+
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(output, "use --yes") {
+		t.Errorf("expected error message about --yes, got %q", output)
+	}
+}
@@ -33,6 +33,8 @@ import (
 )
 
 func init_analize_code_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var chunks chunkSettings
+
 	var analizeCodeCmd = &cobra.Command{
 		Use:     "code",
 		Aliases: []string{"c"},
@@ -81,7 +83,13 @@ After this, I will submit my question or query, and you will follow it exactly a
 Answer with 'OK' if you understand this.`)
 
 			// start creating a pseudo conversation
-			_, _, err = chat.AppendTextFilesAsPseudoConversation(files)
+			maxFileTokens, strategy, err := getChunkOptions(app, cmd, &chunks)
+			app.CheckIfError(err)
+
+			_, _, err = chat.AppendTextFilesAsPseudoConversation(files, types.AppendTextFilesAsPseudoConversationOptions{
+				ChunkStrategy: &strategy,
+				MaxFileTokens: &maxFileTokens,
+			})
 			app.CheckIfError(err)
 
 			// setup final message and instructions
@@ -133,15 +141,19 @@ After this, the user will submit their question or query, and you will follow it
 				ResponseSchemaName: &responseSchemaName,
 				SystemPrompt:       &systemPrompt,
 			})
-			answer, _, err := app.AI.Chat(chat, message, chatOptions...)
+			answer, conversation, err := app.ChatAndValidate(chat, message, chatOptions...)
 			app.CheckIfError(err)
 
 			app.OutputAIAnswer(answer)
+			app.OutputAIUsageOf(conversation)
 		},
 	}
 
+	withChunkCLIFlags(analizeCodeCmd, &chunks, 0)
+
 	app.WithChatCLIFlags(analizeCodeCmd)
 	app.WithLanguageCLIFlags(analizeCodeCmd)
+	app.WithPseudoConversationCLIFlags(analizeCodeCmd)
 
 	parentCmd.AddCommand(
 		analizeCodeCmd,
@@ -248,15 +260,17 @@ After this, the user will submit their question or query, and you will follow it
 				ResponseSchemaName: &responseSchemaName,
 				SystemPrompt:       &systemPrompt,
 			})
-			answer, _, err := app.AI.Chat(chat, message, chatOptions...)
+			answer, conversation, err := app.ChatAndValidate(chat, message, chatOptions...)
 			app.CheckIfError(err)
 
 			app.OutputAIAnswer(answer)
+			app.OutputAIUsageOf(conversation)
 		},
 	}
 
 	app.WithChatCLIFlags(analizeTextCmd)
 	app.WithLanguageCLIFlags(analizeTextCmd)
+	app.WithPseudoConversationCLIFlags(analizeTextCmd)
 
 	parentCmd.AddCommand(
 		analizeTextCmd,
@@ -0,0 +1,172 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+type changelogCommit struct {
+	Diff    string `json:"diff,omitempty"`
+	Hash    string `json:"hash"`
+	Message string `json:"message"`
+}
+
+// Init_changelog_Command initializes the `changelog` command.
+func Init_changelog_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var from string
+	var groupByType bool
+	var includeDiffs bool
+	var to string
+
+	var changelogCmd = &cobra.Command{
+		Use:     "changelog [additional context]",
+		Aliases: []string{"cl", "release-notes"},
+		Short:   "Generate changelog",
+		Long:    `Generates release notes from the commits between two git references.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			git, err := app.NewGitClient()
+			app.CheckIfError(err)
+
+			to = strings.TrimSpace(to)
+			if to == "" {
+				to = "HEAD"
+			}
+
+			from = strings.TrimSpace(from)
+			if from == "" {
+				latestTag, err := git.GetLatestTag()
+				app.CheckIfError(err)
+
+				from = latestTag
+			}
+
+			if from == "" {
+				app.Dbgf("No tag found, collecting all commits up to '%s' ...%s", to, app.EOL)
+			} else {
+				app.Dbgf("Collecting commits between '%s' and '%s' ...%s", from, to, app.EOL)
+			}
+
+			commits, err := git.GetCommitsBetween(from, to)
+			app.CheckIfError(err)
+
+			if len(commits) == 0 {
+				app.CheckIfError(errors.New("no commits found"))
+			}
+
+			app.Dbgf("Found %d commits%s", len(commits), app.EOL)
+
+			changelogCommits := make([]changelogCommit, 0, len(commits))
+			for _, c := range commits {
+				message, err := c.GetMessage()
+				app.CheckIfError(err)
+
+				entry := changelogCommit{
+					Hash:    c.Hash(),
+					Message: strings.TrimSpace(message),
+				}
+
+				if includeDiffs {
+					diff, err := c.GetDiff()
+					app.CheckIfError(err)
+
+					entry.Diff = diff
+				}
+
+				changelogCommits = append(changelogCommits, entry)
+			}
+
+			app.InitAI()
+
+			responseSchema, responseSchemaName, err := app.GetResponseSchema()
+			app.CheckIfError(err)
+
+			additionalContext, err := app.GetInput(args)
+			app.CheckIfError(err)
+
+			outputLanguage := strings.TrimSpace(app.OutputLanguage)
+
+			lang := "english"
+			if outputLanguage != "" {
+				lang = outputLanguage
+			}
+
+			groupingInfo := `Group related changes under meaningful Markdown headings.`
+			if groupByType {
+				groupingInfo = `Commit messages may follow the Conventional Commits specification (for example 'feat: ...', 'fix(scope): ...' or 'feat!: ...').
+Group the changes by their type under Markdown headings in this order: breaking changes, features (feat), bug fixes (fix), performance (perf), refactoring (refactor), documentation (docs) and other changes.
+Treat commits without a type prefix as other changes and omit empty groups.`
+			}
+
+			systemPrompt := fmt.Sprintf(`You are an experienced software developer, who writes release notes for end users and developers.
+The user submits a list of git commits as serialized JSON array, starting with the newest one.
+Write concise release notes in Markdown format.
+%s
+Describe each change in one short bullet point, merge duplicates and skip commits without relevant changes, like merges or version bumps.
+Write everything in natural '%s' language.
+Only describe what can be verified from the commits and output only the release notes.`,
+				groupingInfo, lang)
+
+			jsonCommits, err := json.Marshal(changelogCommits)
+			app.CheckIfError(err)
+
+			rangeInfo := fmt.Sprintf("up to '%s'", to)
+			if from != "" {
+				rangeInfo = fmt.Sprintf("between '%s' and '%s'", from, to)
+			}
+
+			prompt := fmt.Sprintf(`Write release notes for the following commits %s: %s`, rangeInfo, jsonCommits)
+			if strings.TrimSpace(additionalContext) != "" {
+				prompt += fmt.Sprintf("%sTake this as additional context: %s", app.EOL, additionalContext)
+			}
+
+			response, err := app.PromptAndValidate(prompt, types.AIClientPromptOptions{
+				ResponseSchema:     responseSchema,
+				ResponseSchemaName: &responseSchemaName,
+				SystemPrompt:       &systemPrompt,
+			})
+			app.CheckIfError(err)
+
+			app.OutputAIAnswer(response.Content)
+			app.OutputAIUsage(response.Usage)
+		},
+	}
+
+	changelogCmd.Flags().StringVarP(&from, "from", "", "", "start reference, which is not included (default: latest tag)")
+	changelogCmd.Flags().BoolVarP(&groupByType, "group-by-type", "", false, "group changes by Conventional Commits types like feat or fix")
+	changelogCmd.Flags().BoolVarP(&includeDiffs, "include-diffs", "", false, "also send the diff of each commit")
+	changelogCmd.Flags().StringVarP(&to, "to", "", "HEAD", "end reference")
+
+	app.WithChatCLIFlags(changelogCmd)
+	app.WithLanguageCLIFlags(changelogCmd)
+
+	parentCmd.AddCommand(
+		changelogCmd,
+	)
+}
@@ -0,0 +1,91 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"os/exec"
+	"strings"
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	app := newTestApp(t, map[string]string{})
+
+	prompts := make([][]testChatMessage, 0)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		prompts = append(prompts, messages)
+		return "## Features\n\n- Add search"
+	})
+
+	runTestGit(t, app, "init", "-q")
+	for _, message := range []string{"chore: initial release", "feat: add search", "fix: handle empty query"} {
+		writeTestFile(t, app, "log.txt", message)
+		runTestGit(t, app, "add", "-A")
+		runTestGit(t, app, "commit", "-q", "-m", message)
+
+		if message == "chore: initial release" {
+			runTestGit(t, app, "tag", "v1.0.0")
+		}
+	}
+
+	runTestCommand(t, app, Init_changelog_Command, "changelog", "--group-by-type", "--include-diffs")
+
+	if len(prompts) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(prompts))
+	}
+
+	var systemPrompt, prompt string
+	for _, m := range prompts[0] {
+		if m.Role == "system" {
+			systemPrompt = m.Content
+		} else if m.Role == "user" {
+			prompt = m.Content
+		}
+	}
+
+	if !strings.Contains(systemPrompt, "Group the changes by their type") {
+		t.Errorf("expected grouping by type in system prompt %q", systemPrompt)
+	}
+
+	// only commits after the latest tag, starting with the newest one
+	if !strings.HasPrefix(prompt, "Write release notes for the following commits between 'v1.0.0' and 'HEAD': ") {
+		t.Errorf("unexpected range in prompt %q", prompt)
+	}
+	fixIndex := strings.Index(prompt, `"message":"fix: handle empty query"`)
+	featIndex := strings.Index(prompt, `"message":"feat: add search"`)
+	if fixIndex < 0 || featIndex < fixIndex || strings.Contains(prompt, `"message":"chore: initial release"`) {
+		t.Errorf("unexpected commits in prompt %q", prompt)
+	}
+	if !strings.Contains(prompt, `+fix: handle empty query`) {
+		t.Errorf("expected diffs in prompt %q", prompt)
+	}
+
+	output := readTestOutput(t, app.Stdout)
+	if !strings.Contains(output, "- Add search") {
+		t.Errorf("unexpected output %q", output)
+	}
+}
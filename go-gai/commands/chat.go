@@ -23,18 +23,384 @@
 package commands
 
 import (
+	"bufio"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
 
 	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
 	"github.com/spf13/cobra"
 )
 
+type chatBatchRecord struct {
+	Context *string  `json:"context,omitempty"`
+	Files   []string `json:"files,omitempty"`
+	Message string   `json:"message"`
+}
+
+type chatBatchResult struct {
+	Answer  *string         `json:"answer,omitempty"`
+	Context string          `json:"context"`
+	Error   *chatBatchError `json:"error,omitempty"`
+	Index   int             `json:"index"`
+	Message string          `json:"message"`
+}
+
+type chatBatchError struct {
+	Message string `json:"message"`
+}
+
+func runChatBatch(app *types.AppContext, chat *types.ChatContext, batchFile string, reset bool, noFilesInHistory bool, concurrency int, baseOptions []types.AIClientChatOptions) {
+	file, err := os.Open(app.GetFullPath(batchFile))
+	app.CheckIfError(err)
+	defer file.Close()
+
+	lines := make([]string, 0)
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	app.CheckIfError(scanner.Err())
+
+	// with more than one worker, records are independent from each other:
+	// each one starts with the stored conversation of its context
+	// (or an empty one with --reset) and the answers are not stored
+	independent := concurrency > 1
+
+	resettedContexts := map[string]bool{}
+
+	app.Dbgf("Processing %v batch records with %v worker(s) ...%v", len(lines), max(concurrency, 1), app.EOL)
+
+	utils.ProcessInOrder(len(lines), concurrency, func(index int) *chatBatchResult {
+		result := &chatBatchResult{
+			Context: app.Context,
+			Index:   index,
+		}
+
+		outputError := func(err error) *chatBatchResult {
+			result.Error = &chatBatchError{
+				Message: err.Error(),
+			}
+
+			return result
+		}
+
+		var record chatBatchRecord
+		err := json.Unmarshal([]byte(lines[index]), &record)
+		if err != nil {
+			return outputError(err)
+		}
+
+		result.Message = record.Message
+		if record.Context != nil {
+			result.Context = *record.Context
+		}
+
+		message := strings.TrimSpace(record.Message)
+		if message == "" {
+			return outputError(errors.New("no chat message defined"))
+		}
+
+		recordChat := chat
+		if independent {
+			recordChat, err = app.NewChatContext(types.NewChatContextOptions{
+				StartEmpty: &reset,
+			})
+			if err != nil {
+				return outputError(err)
+			}
+		}
+
+		result.Context = recordChat.SwitchContext(result.Context)
+
+		if !independent && reset && !resettedContexts[result.Context] {
+			recordChat.ResetConversation()
+
+			resettedContexts[result.Context] = true
+		}
+
+		options := make([]types.AIClientChatOptions, 0)
+		options = append(options, baseOptions...)
+
+		for _, f := range record.Files {
+			file, err := os.Open(app.GetFullPath(f))
+			if err != nil {
+				return outputError(err)
+			}
+			defer file.Close()
+
+			options = append(options, types.AIClientChatOptions{
+				Files: &[]io.Reader{file},
+			})
+		}
+
+		if independent || (noFilesInHistory && len(record.Files) > 0) {
+			noSave := true
+			options = append(options, types.AIClientChatOptions{
+				NoSave: &noSave,
+			})
+		}
+
+		answer, conversation, err := app.ChatAndValidate(recordChat, message, options...)
+		if errors.Is(err, types.ErrRequestDumped) {
+			return nil // curl command has been written instead
+		}
+		if err != nil {
+			return outputError(err)
+		}
+
+		if !independent && noFilesInHistory && len(record.Files) > 0 {
+			err = recordChat.ReplaceFilesWithReferences(conversation, record.Files)
+			if err == nil {
+				err = recordChat.UpdateConversationWith(conversation)
+			}
+			if err != nil {
+				return outputError(err)
+			}
+		}
+
+		result.Answer = &answer
+
+		return result
+	}, func(index int, result *chatBatchResult) bool {
+		if result == nil {
+			return true // only the curl command of the record has been written
+		}
+
+		jsonData, err := json.Marshal(result)
+		app.CheckIfError(err)
+
+		app.Writeln(string(jsonData))
+
+		return true
+	})
+
+	app.Dbg(fmt.Sprintf("Processed %v batch records", len(lines)))
+}
+
+const chatInteractiveHelp = `Commands:
+  /context [NAME]         show or switch the context
+  /exit                   quit
+  /files                  list files, which are attached to the next message
+  /files add PATTERN ...  attach files to the next message
+  /files clear            do not attach any files to the next message
+  /help                   show this help
+  /reset                  reset the conversation of the current context`
+
+func runChatInteractive(app *types.AppContext, chat *types.ChatContext, firstMessage string, files []string, attachments []*types.UrlAttachment, noFilesInHistory bool, baseOptions []types.AIClientChatOptions) {
+	// files and attachments are only submitted with the next message,
+	// after that they are part of the conversation
+	pendingFiles := files
+	pendingAttachments := attachments
+
+	sendMessage := func(message string) error {
+		options := make([]types.AIClientChatOptions, 0)
+		options = append(options, baseOptions...)
+
+		for _, a := range pendingAttachments {
+			options = append(options, types.AIClientChatOptions{
+				Files: &[]io.Reader{a.NewReader()},
+			})
+		}
+
+		for _, f := range pendingFiles {
+			file, err := os.Open(f)
+			if err != nil {
+				return err
+			}
+			defer file.Close()
+
+			options = append(options, types.AIClientChatOptions{
+				Files: &[]io.Reader{file},
+			})
+		}
+
+		if noFilesInHistory && len(pendingFiles) > 0 {
+			noSave := true
+			options = append(options, types.AIClientChatOptions{
+				NoSave: &noSave,
+			})
+		}
+
+		answer, conversation, err := app.ChatAndValidate(chat, message, options...)
+		if err != nil {
+			return err
+		}
+
+		if noFilesInHistory && len(pendingFiles) > 0 {
+			err = chat.ReplaceFilesWithReferences(conversation, pendingFiles)
+			if err != nil {
+				return err
+			}
+
+			err = chat.UpdateConversationWith(conversation)
+			if err != nil {
+				return err
+			}
+		}
+
+		pendingFiles = nil
+		pendingAttachments = nil
+
+		app.OutputAIAnswer(answer)
+		app.Writeln() // separate from next prompt
+		app.OutputAIUsageOf(conversation)
+
+		err = chat.UpdateConversation()
+		if err != nil {
+			return err
+		}
+
+		return app.UpdateLastOutput(answer)
+	}
+
+	runCommand := func(command string, commandArgs []string) (bool, error) {
+		switch command {
+		case "/bye", "/exit", "/quit":
+			return false, nil
+		case "/context", "/ctx":
+			if len(commandArgs) > 0 {
+				chat.SwitchContext(strings.Join(commandArgs, " "))
+			}
+
+			contextName := chat.CurrentContext()
+			if contextName == "" {
+				contextName = "(default)"
+			}
+
+			app.Writeln(fmt.Sprintf("Context: %s", contextName))
+		case "/files":
+			if len(commandArgs) > 0 {
+				switch commandArgs[0] {
+				case "add":
+					newFiles, err := app.FindFiles(commandArgs[1:]...)
+					if err != nil {
+						return true, err
+					}
+					if len(newFiles) == 0 {
+						return true, fmt.Errorf("no files found for %s", strings.Join(commandArgs[1:], " "))
+					}
+
+					pendingFiles = utils.RemoveDuplicateStrings(append(pendingFiles, newFiles...))
+				case "clear":
+					pendingFiles = nil
+				default:
+					return true, fmt.Errorf("unknown files command '%s'", commandArgs[0])
+				}
+			}
+
+			for _, f := range pendingFiles {
+				relPath, err := filepath.Rel(app.WorkingDirectory, f)
+				if err != nil {
+					relPath = f
+				}
+
+				app.Writeln(relPath)
+			}
+			app.Writeln(fmt.Sprintf("%d file(s) will be attached to the next message", len(pendingFiles)))
+		case "/help":
+			app.Writeln(chatInteractiveHelp)
+		case "/reset":
+			chat.ResetConversation()
+
+			err := chat.UpdateConversation()
+			if err != nil {
+				return true, err
+			}
+
+			app.Writeln("Conversation has been reset")
+		default:
+			return true, fmt.Errorf("unknown command '%s', type /help for a list of commands", command)
+		}
+
+		return true, nil
+	}
+
+	outputError := func(err error) {
+		app.WriteErrorString(fmt.Sprintf("Error: %s%s", err.Error(), app.EOL))
+	}
+
+	if firstMessage != "" {
+		err := sendMessage(firstMessage)
+		if err != nil {
+			outputError(err)
+		}
+	}
+
+	scanner := bufio.NewScanner(app.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for {
+		app.WriteString("> ")
+
+		if !scanner.Scan() {
+			app.Writeln()
+			break
+		}
+
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			fields := strings.Fields(line)
+
+			keepRunning, err := runCommand(strings.ToLower(fields[0]), fields[1:])
+			if err != nil {
+				outputError(err)
+			}
+			if !keepRunning {
+				break
+			}
+			continue
+		}
+
+		err := sendMessage(line)
+		if err != nil {
+			outputError(err)
+		}
+	}
+
+	app.CheckIfError(scanner.Err())
+}
+
+// applyChatSystemMode updates the leading system messages of the
+// current conversation of `chat` based on `systemMode`.
+func applyChatSystemMode(app *types.AppContext, chat *types.ChatContext, systemMode string) {
+	if systemMode == "" || systemMode == "once" {
+		return // handled by the clients for new conversations
+	}
+
+	systemPrompt := app.GetSystemPrompt("")
+	if systemPrompt == "" {
+		app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--system-mode %v requires a system prompt, like --system or --system-file", systemMode)))
+	}
+
+	if systemMode == "replace" {
+		chat.EnsureSystemPrompt(systemPrompt, app.GetSystemRole())
+	} else {
+		chat.PrependSystemPrompt(systemPrompt, app.GetSystemRole())
+	}
+}
+
 // Init_chat_Command initializes the `chat` command.
 func Init_chat_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var batchFile string
+	var concurrency uint16
+	var interactive bool
+	var noFilesInHistory bool
 	var reset bool
+	var systemMode string
 
 	var chatCmd = &cobra.Command{
 		Use:     "chat [QUESTION]",
@@ -50,6 +416,63 @@ func Init_chat_Command(app *types.AppContext, parentCmd *cobra.Command) {
 			responseSchema, responseSchemaName, err := app.GetResponseSchema()
 			app.CheckIfError(err)
 
+			systemMode = strings.TrimSpace(strings.ToLower(systemMode))
+			switch systemMode {
+			case "", "once", "prepend", "replace":
+			default:
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("'%v' is no valid system mode, use once, prepend or replace", systemMode)))
+			}
+
+			if strings.TrimSpace(batchFile) != "" {
+				if app.DryRun {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("--dry-run is not supported with --batch")))
+				}
+				if systemMode != "" && systemMode != "once" {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("--system-mode is not supported with --batch")))
+				}
+
+				chat, err := app.NewChatContext()
+				app.CheckIfError(err)
+
+				runChatBatch(app, chat, batchFile, reset, noFilesInHistory, int(concurrency), []types.AIClientChatOptions{
+					{
+						ResponseSchema:     responseSchema,
+						ResponseSchemaName: &responseSchemaName,
+					},
+				})
+				return
+			}
+
+			if interactive {
+				if app.DryRun {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("--dry-run is not supported with --interactive")))
+				}
+
+				chat, err := app.NewChatContext()
+				app.CheckIfError(err)
+
+				if reset {
+					chat.ResetConversation()
+
+					err = chat.UpdateConversation()
+					app.CheckIfError(err)
+				}
+
+				applyChatSystemMode(app, chat, systemMode)
+
+				attachments, err := app.GetUrlAttachments()
+				app.CheckIfError(err)
+
+				// STDIN is used for the messages
+				runChatInteractive(app, chat, strings.TrimSpace(strings.Join(args, " ")), files, attachments, noFilesInHistory, []types.AIClientChatOptions{
+					{
+						ResponseSchema:     responseSchema,
+						ResponseSchemaName: &responseSchemaName,
+					},
+				})
+				return
+			}
+
 			message, err := app.GetInput(args)
 			app.CheckIfError(err)
 
@@ -65,6 +488,8 @@ func Init_chat_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				chat.ResetConversation()
 			}
 
+			applyChatSystemMode(app, chat, systemMode)
+
 			options := make([]types.AIClientChatOptions, 0)
 
 			options = append(options, types.AIClientChatOptions{
@@ -72,6 +497,19 @@ func Init_chat_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				ResponseSchemaName: &responseSchemaName,
 			})
 
+			attachments, err := app.GetUrlAttachments()
+			app.CheckIfError(err)
+
+			// before local files, which have to be the last
+			// items for --no-files-in-history
+			for _, a := range attachments {
+				app.Dbgf("Attaching %v bytes from '%v' as '%v' ...%v", len(a.Data), a.Url, a.Name, app.EOL)
+
+				options = append(options, types.AIClientChatOptions{
+					Files: &[]io.Reader{a.NewReader()},
+				})
+			}
+
 			openedFiles := make([]*os.File, 0)
 			defer func() {
 				for _, of := range openedFiles {
@@ -90,18 +528,77 @@ func Init_chat_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				})
 			}
 
-			answer, _, err := app.AI.Chat(chat, message, options...)
+			if noFilesInHistory && len(files) > 0 {
+				// we store the conversation by ourselves
+				noSave := true
+				options = append(options, types.AIClientChatOptions{
+					NoSave: &noSave,
+				})
+			}
+
+			if app.DryRun {
+				// the complete history is counted, without limits
+				conversation, err := chat.GetConversation()
+				app.CheckIfError(err)
+
+				blobs := make([][]byte, 0)
+				for _, a := range attachments {
+					blobs = append(blobs, a.Data)
+				}
+
+				filesText, binarySize, err := app.GetApproximateContentOfFiles(files, blobs...)
+				app.CheckIfError(err)
+
+				var text strings.Builder
+				for _, item := range conversation {
+					for _, c := range item.Contents {
+						if c.Type == "text" {
+							text.WriteString(c.Content)
+						} else {
+							binarySize += uint64(len(c.Content))
+						}
+					}
+				}
+				text.WriteString(message)
+				text.WriteString(filesText)
+
+				err = app.OutputUsageEstimate(text.String(), uint64(text.Len()), binarySize)
+				app.CheckIfError(err)
+				return
+			}
+
+			answer, conversation, err := app.ChatAndValidate(chat, message, options...)
 			app.CheckIfError(err)
 
+			if noFilesInHistory && len(files) > 0 {
+				err = chat.ReplaceFilesWithReferences(conversation, files)
+				app.CheckIfError(err)
+
+				err = chat.UpdateConversationWith(conversation)
+				app.CheckIfError(err)
+			}
+
 			app.OutputAIAnswer(answer)
+			app.OutputAIUsageOf(conversation)
 
 			err = chat.UpdateConversation()
 			app.CheckIfError(err)
+
+			err = app.UpdateLastOutput(answer)
+			app.CheckIfError(err)
 		},
 	}
 
 	app.WithChatCLIFlags(chatCmd)
+	app.WithAttachUrlCLIFlags(chatCmd)
+	app.WithDryRunCliFlags(chatCmd)
+	app.WithHistoryCLIFlags(chatCmd)
+	chatCmd.Flags().StringVarP(&batchFile, "batch", "", "", "JSON Lines file with messages to process")
+	chatCmd.Flags().Uint16VarP(&concurrency, "concurrency", "", 1, "number of records of --batch to process in parallel, which are independent from each other then")
+	chatCmd.Flags().BoolVarP(&interactive, "interactive", "i", false, "read messages and commands like /reset from STDIN until /exit")
+	chatCmd.Flags().BoolVarP(&noFilesInHistory, "no-files-in-history", "", false, "store only references of files in conversation instead of their contents")
 	chatCmd.Flags().BoolVarP(&reset, "reset", "r", false, "reset conversation")
+	chatCmd.Flags().StringVarP(&systemMode, "system-mode", "", "", "how to use the system prompt in existing conversations: once (default), prepend or replace")
 
 	parentCmd.AddCommand(
 		chatCmd,
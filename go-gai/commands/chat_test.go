@@ -0,0 +1,219 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+const testChatBatch = `{"message": "first"}
+{"message": "second"}
+{"message": "third", "context": "Other"}
+
+{"message": ""}
+no json
+{"message": "fourth"}
+`
+
+func runTestChatBatch(t *testing.T, concurrency int) (*types.AppContext, []chatBatchResult) {
+	t.Helper()
+
+	app := newTestApp(t, nil)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		userMessages := make([]string, 0)
+		for _, m := range messages {
+			if m.Role == "user" {
+				userMessages = append(userMessages, m.Content)
+			}
+		}
+
+		// number of user messages and the last one
+		return fmt.Sprintf("%d %s", len(userMessages), userMessages[len(userMessages)-1])
+	})
+
+	batchFile := writeTestFile(t, app, "batch.jsonl", testChatBatch)
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runChatBatch(app, chat, batchFile, false, false, concurrency, []types.AIClientChatOptions{})
+
+	results := make([]chatBatchResult, 0)
+	for _, line := range strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n") {
+		var result chatBatchResult
+		err := json.Unmarshal([]byte(line), &result)
+		if err != nil {
+			t.Fatalf("invalid result %q: %v", line, err)
+		}
+
+		results = append(results, result)
+	}
+
+	if len(results) != 6 {
+		t.Fatalf("expected 6 results, got %d", len(results))
+	}
+	for i, r := range results {
+		if r.Index != i {
+			t.Errorf("expected index %d, got %d", i, r.Index)
+		}
+	}
+
+	for _, i := range []int{3, 4} {
+		if results[i].Error == nil || results[i].Answer != nil {
+			t.Errorf("expected error for record %d, got %+v", i, results[i])
+		}
+	}
+	if results[2].Context != "other" {
+		t.Errorf("expected context 'other', got %q", results[2].Context)
+	}
+
+	return app, results
+}
+
+func TestRunChatBatchSequential(t *testing.T) {
+	_, results := runTestChatBatch(t, 1)
+
+	// each context keeps its own conversation
+	expected := map[int]string{
+		0: "1 first",
+		1: "2 second",
+		2: "1 third",
+		5: "3 fourth",
+	}
+	for i, answer := range expected {
+		if results[i].Answer == nil || *results[i].Answer != answer {
+			t.Errorf("expected answer %q for record %d, got %+v", answer, i, results[i])
+		}
+	}
+}
+
+func TestRunChatBatchConcurrent(t *testing.T) {
+	app, results := runTestChatBatch(t, 4)
+
+	// records are independent from each other
+	expected := map[int]string{
+		0: "1 first",
+		1: "1 second",
+		2: "1 third",
+		5: "1 fourth",
+	}
+	for i, answer := range expected {
+		if results[i].Answer == nil || *results[i].Answer != answer {
+			t.Errorf("expected answer %q for record %d, got %+v", answer, i, results[i])
+		}
+	}
+
+	// ... and their answers are not stored
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	conversation, err := chat.GetConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conversation) != 0 {
+		t.Errorf("expected no stored conversation, got %d items", len(conversation))
+	}
+}
+
+func TestRunChatBatchNoFilesInHistory(t *testing.T) {
+	const fileContent = "TOP SECRET CONTENT"
+
+	for _, noFilesInHistory := range []bool{false, true} {
+		app := newTestApp(t, nil)
+
+		requests := make([][]testChatMessage, 0)
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			requests = append(requests, messages)
+
+			return "ok"
+		})
+
+		writeTestFile(t, app, "docs/secret.txt", fileContent)
+		batchFile := writeTestFile(t, app, "batch.jsonl", `{"message": "read this", "files": ["docs/secret.txt"]}
+{"message": "and now?"}
+`)
+
+		chat, err := app.NewChatContext()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		runChatBatch(app, chat, batchFile, false, noFilesInHistory, 1, []types.AIClientChatOptions{})
+
+		conversation, err := chat.GetConversation()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conversation) != 4 {
+			t.Fatalf("expected 4 conversation items, got %d", len(conversation))
+		}
+
+		encodedContent := base64.StdEncoding.EncodeToString([]byte(fileContent))
+		reference := fmt.Sprintf("The file 'docs/secret.txt' (SHA-256: %x)", sha256.Sum256([]byte(fileContent)))
+
+		isFileStored := false
+		isReferenceStored := false
+		for _, item := range conversation {
+			for _, c := range item.Contents {
+				if strings.Contains(c.Content, encodedContent) {
+					isFileStored = true
+				}
+				if strings.HasPrefix(c.Content, reference) {
+					isReferenceStored = true
+				}
+			}
+		}
+
+		if isFileStored == noFilesInHistory {
+			t.Errorf("no-files-in-history %v: unexpected file content in history", noFilesInHistory)
+		}
+		if isReferenceStored != noFilesInHistory {
+			t.Errorf("no-files-in-history %v: unexpected file reference in history", noFilesInHistory)
+		}
+
+		// the next turn is sent with the history
+		if len(requests) != 2 {
+			t.Fatalf("expected 2 requests, got %d", len(requests))
+		}
+		isReferenceSent := false
+		for _, m := range requests[1] {
+			if strings.Contains(m.Content, reference) {
+				isReferenceSent = true
+			}
+		}
+		if isReferenceSent != noFilesInHistory {
+			t.Errorf("no-files-in-history %v: unexpected file reference in second request", noFilesInHistory)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+// chunkSettings stores the values of the flags of `withChunkCLIFlags`.
+type chunkSettings struct {
+	maxFileTokens int
+	strategy      string
+}
+
+// withChunkCLIFlags sets up `cmd` for CLI flags, which define
+// how large texts are split into chunks.
+func withChunkCLIFlags(cmd *cobra.Command, settings *chunkSettings, defaultMaxFileTokens int) {
+	cmd.Flags().StringVarP(&settings.strategy, "chunk-strategy", "", "auto", "how to split large files: auto, chars, code-symbols, markdown-headings or tokens")
+	cmd.Flags().IntVarP(&settings.maxFileTokens, "max-file-tokens", "", defaultMaxFileTokens, "maximum number of tokens per message, larger files are submitted in parts (0 for no limit)")
+}
+
+// getChunkOptions returns the maximum number of tokens and the strategy
+// of chunks from the flags of `cmd` or, if they are not set, from
+// `GAI_MAX_FILE_TOKENS` and `GAI_CHUNK_STRATEGY` environment variables.
+func getChunkOptions(app *types.AppContext, cmd *cobra.Command, settings *chunkSettings) (int, utils.ChunkStrategy, error) {
+	maxFileTokens := settings.maxFileTokens
+	if !cmd.Flags().Changed("max-file-tokens") {
+		GAI_MAX_FILE_TOKENS := strings.TrimSpace(app.GetEnv("GAI_MAX_FILE_TOKENS"))
+		if GAI_MAX_FILE_TOKENS != "" {
+			value, err := strconv.Atoi(GAI_MAX_FILE_TOKENS)
+			if err != nil {
+				return 0, "", types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("invalid value '%v' for GAI_MAX_FILE_TOKENS: %w", GAI_MAX_FILE_TOKENS, err))
+			}
+
+			maxFileTokens = value
+		}
+	}
+	if maxFileTokens < 0 {
+		return 0, "", types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("maximum number of tokens per message cannot be negative: %v", maxFileTokens))
+	}
+
+	chunkStrategy := settings.strategy
+	if !cmd.Flags().Changed("chunk-strategy") {
+		GAI_CHUNK_STRATEGY := strings.TrimSpace(app.GetEnv("GAI_CHUNK_STRATEGY"))
+		if GAI_CHUNK_STRATEGY != "" {
+			chunkStrategy = GAI_CHUNK_STRATEGY
+		}
+	}
+
+	strategy, err := utils.ParseChunkStrategy(chunkStrategy)
+	if err != nil {
+		return 0, "", types.NewTypedError(types.ErrorTypeUsage, err)
+	}
+
+	return maxFileTokens, strategy, nil
+}
@@ -0,0 +1,373 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+// newTestApp creates an `AppContext` with temporary home and working
+// directories, whose environment only contains `envVars`.
+// Outputs are written to temporary files, s. `readTestOutput`.
+func newTestApp(t *testing.T, envVars map[string]string) *types.AppContext {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	homeDir := filepath.Join(dir, "home")
+	workDir := filepath.Join(dir, "work")
+	for _, d := range []string{homeDir, workDir} {
+		err := os.MkdirAll(d, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := &types.AppContext{
+		EOL:                 "\n",
+		HomeDirectory:       homeDir,
+		Log:                 log.New(io.Discard, "", 0),
+		SkipDefaultEnvFiles: true,
+		Stderr:              newTestFile(t, dir, "stderr"),
+		Stdin:               newTestFile(t, dir, "stdin"),
+		Stdout:              newTestFile(t, dir, "stdout"),
+		Temperature:         -1,
+		Verbosity:           -1,
+		WorkingDirectory:    workDir,
+	}
+
+	app.Init()
+
+	// do not depend on the environment of the test
+	app.EnvVars = map[string]string{}
+	for k, v := range envVars {
+		app.EnvVars[k] = v
+	}
+
+	return app
+}
+
+func newTestFile(t *testing.T, dir string, name string) *os.File {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		file.Close()
+	})
+
+	return file
+}
+
+// testChatMessage is a message of a request of `newTestOpenAIServer`.
+type testChatMessage struct {
+	Content string
+	Role    string
+}
+
+// newTestOpenAIServer starts a server for chat completions of the OpenAI API
+// and initializes the AI client of `app` with it. Each request is answered
+// with the result of `answer`, which receives the submitted messages.
+func newTestOpenAIServer(t *testing.T, app *types.AppContext, answer func(messages []testChatMessage) string) *httptest.Server {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", newTestChatCompletionsHandler(answer))
+
+	return startTestOpenAIServer(t, app, mux)
+}
+
+// newTestChatCompletionsHandler creates a handler for chat completions
+// of the OpenAI API, which answers with the result of `answer`.
+func newTestChatCompletionsHandler(answer func(messages []testChatMessage) string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+				Role    string          `json:"role"`
+			} `json:"messages"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		messages := make([]testChatMessage, 0, len(body.Messages))
+		for _, m := range body.Messages {
+			messages = append(messages, testChatMessage{
+				Content: getTestMessageText(m.Content),
+				Role:    m.Role,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"model": "gpt-4o",
+			"choices": []any{
+				map[string]any{
+					"message": map[string]any{
+						"content": answer(messages),
+						"role":    "assistant",
+					},
+				},
+			},
+			"usage": map[string]any{
+				"completion_tokens": 1,
+				"prompt_tokens":     2,
+				"total_tokens":      3,
+			},
+		})
+	}
+}
+
+// startTestOpenAIServer starts a server with `handler` as OpenAI API
+// and initializes the AI client of `app` with it.
+func startTestOpenAIServer(t *testing.T, app *types.AppContext, handler http.Handler) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	app.BaseUrl = server.URL
+	app.EnvVars["OPENAI_API_KEY"] = "test"
+	app.Model = "openai:gpt-4o"
+	app.InitAI()
+
+	return server
+}
+
+// getTestMessageText returns the text of the `content` of a chat message,
+// which is a string or a list of parts.
+func getTestMessageText(content json.RawMessage) string {
+	var text string
+	if json.Unmarshal(content, &text) == nil {
+		return text
+	}
+
+	var parts []struct {
+		Text string `json:"text"`
+	}
+	json.Unmarshal(content, &parts)
+
+	texts := make([]string, 0, len(parts))
+	for _, p := range parts {
+		texts = append(texts, p.Text)
+	}
+
+	return strings.Join(texts, "\n")
+}
+
+// runTestCommand registers a command with `initCommand`
+// and executes it with `args`.
+func runTestCommand(t *testing.T, app *types.AppContext, initCommand func(*types.AppContext, *cobra.Command), args ...string) {
+	t.Helper()
+
+	rootCmd := &cobra.Command{
+		Use: "gai",
+	}
+	rootCmd.SetOut(app.Stdout)
+	rootCmd.SetErr(app.Stderr)
+
+	initCommand(app, rootCmd)
+
+	rootCmd.SetArgs(args)
+	err := rootCmd.Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// testProcessCase returns the name of the case, which should be run
+// by the current process of `runTestProcess`, if there is one.
+func testProcessCase() string {
+	return os.Getenv("GAI_TEST_PROCESS_CASE")
+}
+
+// runTestProcess runs the test `name` with `testCase` in a new process,
+// because commands exit on errors, and returns its STDERR and exit code.
+func runTestProcess(t *testing.T, name string, testCase string) (string, int) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], fmt.Sprintf("-test.run=^%s$", name))
+	cmd.Env = append(os.Environ(), "GAI_TEST_PROCESS_CASE="+testCase)
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stderr.String(), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stderr.String(), 0
+}
+
+// runTestGit runs git with `args` inside the working directory of `app`.
+func runTestGit(t *testing.T, app *types.AppContext, args ...string) {
+	t.Helper()
+
+	cmd := exec.Command("git", append([]string{"-c", "user.name=Test", "-c", "user.email=test@example.com"}, args...)...)
+	cmd.Dir = app.WorkingDirectory
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("git %v: %v: %s", args, err, output)
+	}
+}
+
+// writeTestStdin writes `content` to the STDIN of `app`.
+func writeTestStdin(t *testing.T, app *types.AppContext, content string) {
+	t.Helper()
+
+	_, err := app.Stdin.WriteString(content)
+	if err == nil {
+		_, err = app.Stdin.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readTestOutput returns everything, which has been written to `file`.
+func readTestOutput(t *testing.T, file *os.File) string {
+	t.Helper()
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// writeTestFile writes `content` to `name` inside the working directory
+// of `app` and returns the full path.
+func writeTestFile(t *testing.T, app *types.AppContext, name string, content string) string {
+	t.Helper()
+
+	fullPath := filepath.Join(app.WorkingDirectory, name)
+
+	err := os.MkdirAll(filepath.Dir(fullPath), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(fullPath, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fullPath
+}
+
+// newTestPDF creates a PDF document with a single page,
+// which contains each of `lines` as text.
+func newTestPDF(lines ...string) []byte {
+	content := &bytes.Buffer{}
+	content.WriteString("BT /F1 12 Tf 72 720 Td 14 TL\n")
+	for _, l := range lines {
+		fmt.Fprintf(content, "(%s) Tj T*\n", l)
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	pdf := &bytes.Buffer{}
+	pdf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, 0, len(objects))
+	for i, o := range objects {
+		offsets = append(offsets, pdf.Len())
+		fmt.Fprintf(pdf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xrefOffset := pdf.Len()
+	fmt.Fprintf(pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+
+	return pdf.Bytes()
+}
+
+// testZipFile is a file of `newTestZip`.
+type testZipFile struct {
+	Content string
+	Name    string
+}
+
+// newTestZip creates a ZIP archive with `files` in their order.
+func newTestZip(t *testing.T, files ...testZipFile) []byte {
+	t.Helper()
+
+	buff := &bytes.Buffer{}
+
+	z := zip.NewWriter(buff)
+	for _, f := range files {
+		w, err := z.Create(f.Name)
+		if err == nil {
+			_, err = w.Write([]byte(f.Content))
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	err := z.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buff.Bytes()
+}
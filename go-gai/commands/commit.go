@@ -34,7 +34,6 @@ import (
 
 	"github.com/mkloubert/gai/types"
 	"github.com/mkloubert/gai/utils"
-	"github.com/pkoukk/tiktoken-go"
 	"github.com/spf13/cobra"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -51,8 +50,42 @@ type commitResponse struct {
 //go:embed res/conventional-commits/index.md
 var conventionalCommitsSpec string
 
+// newCommitResponseSchema returns the default response schema of `commit`.
+func newCommitResponseSchema() *map[string]any {
+	return &map[string]any{
+		"type":        "object",
+		"required":    []string{"description", "type"},
+		"description": "Information about a commit message based on the Conventional Commits specification.",
+		"properties": map[string]any{
+			"type": map[string]any{
+				"type":        "string",
+				"description": "The type of the commit.",
+			},
+			"scope": map[string]any{
+				"type":        "string",
+				"description": "The optional scope.",
+			},
+			"description": map[string]any{
+				"type":        "string",
+				"description": "The description.",
+			},
+			"body": map[string]any{
+				"type":        "string",
+				"description": "The optional body text.",
+			},
+			"footer": map[string]any{
+				"type":        "string",
+				"description": "The option footer text(s).",
+			},
+		},
+	}
+}
+
 // Init_commit_Command initializes the `chat` command.
 func Init_commit_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var amend bool
+	var maxDiffBytes int
+	var maxTotalDiffBytes int
 	var stagedOnly bool
 
 	var commitCmd = &cobra.Command{
@@ -74,7 +107,7 @@ func Init_commit_Command(app *types.AppContext, parentCmd *cobra.Command) {
 
 			app.Dbgf("Found %d staged files%s", len(allStagedFiles), app.EOL)
 
-			if len(allStagedFiles) == 0 {
+			if len(allStagedFiles) == 0 && !amend {
 				// no staged files found, ask user for changed files to stage
 
 				app.Dbg("Asking user for changed files to tage ...")
@@ -133,7 +166,7 @@ func Init_commit_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				}
 			}
 
-			if len(allStagedFiles) == 0 {
+			if len(allStagedFiles) == 0 && !amend {
 				app.CheckIfError(errors.New("no changed or staged files found"))
 			}
 
@@ -274,14 +307,81 @@ Answer with 'OK' if you analyzed it%v.`,
 				}
 			}
 
+			submittedDiffBytes := 0
+			truncateDiff := func(name string, diff string) string {
+				maxBytes := -1 // no limit
+				if maxDiffBytes > 0 {
+					maxBytes = maxDiffBytes
+				}
+				if maxTotalDiffBytes > 0 {
+					remainingBytes := max(maxTotalDiffBytes-submittedDiffBytes, 0)
+					if maxBytes < 0 || remainingBytes < maxBytes {
+						maxBytes = remainingBytes
+					}
+				}
+
+				truncatedDiff, truncated := utils.TruncateText(diff, maxBytes)
+				if truncated > 0 {
+					app.Dbgf("Truncated %d bytes of diff of '%s'%s", truncated, name, app.EOL)
+				}
+
+				submittedDiffBytes += len(diff) - truncated
+
+				return truncatedDiff
+			}
+
+			if amend {
+				// the latest commit will be replaced, so the model
+				// also needs to know its own changes
+				app.Dbg("Appending diff of latest commit ...")
+
+				latestCommitDiff, err := latestCommit.GetDiff()
+				app.CheckIfError(err)
+
+				latestCommitDiff = truncateDiff(latestCommit.Hash(), latestCommitDiff)
+
+				latestCommitMessage, err := latestCommit.GetMessage()
+				app.CheckIfError(err)
+
+				if app.DryRun {
+					app.Writeln(fmt.Sprintf("Diff of latest commit: %s", latestCommit.Hash()))
+					app.Writeln(fmt.Sprintf("\tSize: %d", len(latestCommitDiff)))
+				}
+
+				approximateSubmittedTextSize += uint64(len([]byte(latestCommitDiff)))
+				approximateSubmittedText += latestCommitDiff
+
+				jsonDiff, err := json.Marshal(&latestCommitDiff)
+				app.CheckIfError(err)
+
+				jsonMessage, err := json.Marshal(&latestCommitMessage)
+				app.CheckIfError(err)
+
+				chat.AppendSimplePseudoUserConversation(fmt.Sprintf(
+					`The latest git commit will be amended, so the new commit message must describe its changes too.
+This is its current message as serialized JSON string: %s
+This is the diff of its own changes as serialized JSON string: %s
+Answer with 'OK' if you analyzed it.`,
+					jsonMessage,
+					jsonDiff,
+				),
+					types.AppendSimplePseudoUserConversationOptions{
+						Model: &model,
+						Time:  &startTime,
+					},
+				)
+			}
+
 			app.Dbg("Appending staged files ...")
-			chat.AppendSimplePseudoUserConversation(`Now I continue with the list of staged files.
+			if len(finalStagedFilesToTake) > 0 || !amend {
+				chat.AppendSimplePseudoUserConversation(`Now I continue with the list of staged files.
 Each file contains the diff (or the complete content) between the staged content and the latest commit based on the current state status.
 Answer with 'OK' if you understand this.`,
-				types.AppendSimplePseudoUserConversationOptions{
-					Model: &model,
-					Time:  &startTime,
-				})
+					types.AppendSimplePseudoUserConversationOptions{
+						Model: &model,
+						Time:  &startTime,
+					})
+			}
 			for i, sf := range finalStagedFilesToTake {
 				if app.DryRun {
 					app.Writeln(fmt.Sprintf("Staged file: %s", sf.Name()))
@@ -333,7 +433,9 @@ Answer with 'OK' if you analyzed it%v.`,
 						str, err := utils.EnsurePlainText(currentContent)
 						app.CheckIfError(err)
 
-						approximateSubmittedTextSize += uint64(len(currentContent))
+						str = truncateDiff(sf.Name(), str)
+
+						approximateSubmittedTextSize += uint64(len(str))
 						approximateSubmittedText += str
 
 						jsonData, err := json.Marshal(&str)
@@ -381,6 +483,8 @@ Answer with 'OK' if you analyzed it%v.`,
 						diff, err := latestCommit.Diff(sf)
 						app.CheckIfError(err)
 
+						diff = truncateDiff(sf.Name(), diff)
+
 						approximateSubmittedTextSize += uint64(len([]byte(diff)))
 						approximateSubmittedText += diff
 
@@ -421,20 +525,8 @@ Answer with 'OK' if you analyzed it%v.`,
 			}
 
 			if app.DryRun {
-				app.Writeln(fmt.Sprintf("Approximate size of the total text content transferred: %d", approximateSubmittedTextSize))
-				app.Writeln(fmt.Sprintf("Approximate size of the total binary content transferred: %d", approximateSubmittedBinarySize))
-
-				// tokens
-				{
-					tkm, err := tiktoken.EncodingForModel(model)
-					if err != nil {
-						app.Writeln(fmt.Sprintf("WARN: Could not get GPT tokens for text content transfered: %s", err.Error()))
-					} else {
-						tokens := tkm.Encode(approximateSubmittedText, nil, nil)
-
-						app.Writeln(fmt.Sprintf("Approximate GPT tokens for text content transfered: %d", len(tokens)))
-					}
-				}
+				err := app.OutputUsageEstimate(approximateSubmittedText, approximateSubmittedTextSize, approximateSubmittedBinarySize)
+				app.CheckIfError(err)
 			}
 
 			responseSchema, responseSchemaName, err := app.GetResponseSchema()
@@ -482,33 +574,7 @@ Below is the full Conventional Commits specification for your reference. Always
 			app.Dbg("Setup message")
 
 			if responseSchema == nil {
-				responseSchema = &map[string]any{
-					"type":        "object",
-					"required":    []string{"description", "type"},
-					"description": "Information about a commit message based on the Conventional Commits specification.",
-					"properties": map[string]any{
-						"type": map[string]any{
-							"type":        "string",
-							"description": "The type of the commit.",
-						},
-						"scope": map[string]any{
-							"type":        "string",
-							"description": "The optional scope.",
-						},
-						"description": map[string]any{
-							"type":        "string",
-							"description": "The description.",
-						},
-						"body": map[string]any{
-							"type":        "string",
-							"description": "The optional body text.",
-						},
-						"footer": map[string]any{
-							"type":        "string",
-							"description": "The option footer text(s).",
-						},
-					},
-				}
+				responseSchema = newCommitResponseSchema()
 			} else {
 				app.Dbg("Taking custom response schema")
 			}
@@ -524,6 +590,9 @@ Below is the full Conventional Commits specification for your reference. Always
 				os.Exit(0)
 			}
 
+			// answers with empty required fields are requested again once
+			hasRetriedEmptyFields := false
+
 			var nextRequest func() (string, error)
 			nextRequest = func() (string, error) {
 				chatOptions := make([]types.AIClientChatOptions, 0)
@@ -533,7 +602,7 @@ Below is the full Conventional Commits specification for your reference. Always
 					ResponseSchemaName: &responseSchemaName,
 					SystemPrompt:       &systemPrompt,
 				})
-				answer, _, err := app.AI.Chat(chat, lastMessage, chatOptions...)
+				answer, _, err := app.ChatAndValidate(chat, lastMessage, chatOptions...)
 				app.CheckIfError(err)
 
 				var commit commitResponse
@@ -555,6 +624,35 @@ Below is the full Conventional Commits specification for your reference. Always
 					commitFooter = strings.TrimSpace(*commit.Footer)
 				}
 
+				emptyFields := make([]string, 0)
+				if commitType == "" {
+					emptyFields = append(emptyFields, "type")
+				}
+				if commitDescription == "" {
+					emptyFields = append(emptyFields, "description")
+				}
+				if len(emptyFields) > 0 {
+					// never commit something like ": "
+					if hasRetriedEmptyFields {
+						app.CheckIfError(types.NewTypedError(
+							types.ErrorTypeSchemaValidation,
+							fmt.Errorf("answer contains no commit %s", strings.Join(emptyFields, " and ")),
+						))
+					}
+					hasRetriedEmptyFields = true
+
+					app.Dbgf("Answer has empty %v, trying again ...%v", strings.Join(emptyFields, " and "), app.EOL)
+
+					lastMessage = fmt.Sprintf(
+						`Your previous answer had an empty %s.
+Answer again with the complete JSON, where "type" is a Conventional Commits type like "feat" or "fix" and "description" is a short summary of the changes, which must not be empty.
+Your JSON:`,
+						strings.Join(emptyFields, " and "),
+					)
+
+					return nextRequest()
+				}
+
 				if commitScope != "" {
 					commitScope = fmt.Sprintf("(%s)", commitScope)
 				}
@@ -631,9 +729,15 @@ Below is the full Conventional Commits specification for your reference. Always
 			commitMessage, err := nextRequest()
 			app.CheckIfError(err)
 
-			app.Dbg("Running 'git commit' ...")
+			gitArgs := []string{"commit"}
+			if amend {
+				gitArgs = append(gitArgs, "--amend")
+			}
+			gitArgs = append(gitArgs, "-m", commitMessage)
+
+			app.Dbgf("Running 'git %s' ...%s", strings.Join(gitArgs[:len(gitArgs)-2], " "), app.EOL)
 
-			c := git.CreateExecCommand("git", "commit", "-m", commitMessage)
+			c := git.CreateExecCommand("git", gitArgs...)
 
 			c.Stderr = app.Stderr
 			c.Stdin = app.Stdin
@@ -645,7 +749,12 @@ Below is the full Conventional Commits specification for your reference. Always
 	}
 
 	app.WithDryRunCliFlags(commitCmd)
+	app.WithPseudoConversationCLIFlags(commitCmd)
+	app.WithValidationCLIFlags(commitCmd)
 	app.WithYesCliFlags(commitCmd)
+	commitCmd.Flags().BoolVarP(&amend, "amend", "", false, "amend the latest commit instead of creating a new one")
+	commitCmd.Flags().IntVarP(&maxDiffBytes, "max-diff-bytes", "", 0, "maximum number of bytes of the diff of each file (0 for no limit)")
+	commitCmd.Flags().IntVarP(&maxTotalDiffBytes, "max-total-diff-bytes", "", 0, "maximum number of bytes of the diffs of all files (0 for no limit)")
 	commitCmd.Flags().BoolVarP(&stagedOnly, "staged-only", "", false, "only submit staged files for comparsion")
 
 	parentCmd.AddCommand(
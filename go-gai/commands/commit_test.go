@@ -0,0 +1,147 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+// newTestGitRepository creates a repository with one commit and
+// a staged change inside the working directory of `app`.
+func newTestGitRepository(t *testing.T, app *types.AppContext) {
+	t.Helper()
+
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	// for `git commit` of the command
+	t.Setenv("GIT_AUTHOR_NAME", "Test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "test@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "Test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "test@example.com")
+
+	writeTestFile(t, app, "README.md", "# Test")
+	runTestGit(t, app, "init", "-q")
+	runTestGit(t, app, "add", "-A")
+	runTestGit(t, app, "commit", "-q", "-m", "initial")
+
+	writeTestFile(t, app, "README.md", "# Test\n\nUsage")
+	runTestGit(t, app, "add", "README.md")
+}
+
+func TestCommitEmptyRequiredFields(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	newTestGitRepository(t, app)
+
+	prompts := make([]string, 0)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		prompts = append(prompts, messages[len(messages)-1].Content)
+
+		if len(prompts) == 1 {
+			return `{"type":"","description":" "}`
+		}
+		return `{"type":"docs","description":"add usage"}`
+	})
+
+	runTestCommand(t, app, Init_commit_Command, "commit", "--yes")
+
+	if len(prompts) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(prompts))
+	}
+	if !strings.Contains(prompts[1], "Your previous answer had an empty type and description.") {
+		t.Errorf("expected specific instruction, got %q", prompts[1])
+	}
+
+	cmd := exec.Command("git", "log", "-1", "--format=%s")
+	cmd.Dir = app.WorkingDirectory
+	output, err := cmd.Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subject := strings.TrimSpace(string(output)); subject != "docs: add usage" {
+		t.Errorf("expected commit subject %q, got %q", "docs: add usage", subject)
+	}
+}
+
+func TestCommitEmptyRequiredFieldsAgain(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		newTestGitRepository(t, app)
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			return `{"type":"fix","description":""}`
+		})
+
+		runTestCommand(t, app, Init_commit_Command, "commit", "--yes")
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestCommitEmptyRequiredFieldsAgain", "default")
+
+	if exitCode != 8 {
+		t.Errorf("expected exit code 8, got %d", exitCode)
+	}
+	if !strings.Contains(output, "answer contains no commit description") {
+		t.Errorf("expected error message about empty description, got %q", output)
+	}
+}
+
+func TestCommitMaxDiffBytes(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	newTestGitRepository(t, app)
+
+	lines := make([]string, 0)
+	for i := range 100 {
+		lines = append(lines, fmt.Sprintf("line %d", i))
+	}
+	writeTestFile(t, app, "README.md", strings.Join(lines, "\n"))
+	writeTestFile(t, app, "NEW.md", strings.Join(lines, "\n"))
+	runTestGit(t, app, "add", "-A")
+
+	var submitted string
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		for _, m := range messages {
+			submitted += m.Content + "\n"
+		}
+
+		return `{"type":"docs","description":"add lines"}`
+	})
+
+	runTestCommand(t, app, Init_commit_Command, "commit", "--yes", "--max-diff-bytes", "200", "--max-total-diff-bytes", "300")
+
+	if strings.Contains(submitted, "line 99") {
+		t.Errorf("expected oversized diffs to be truncated, got %q", submitted)
+	}
+	if n := strings.Count(submitted, "... [truncated "); n != 2 {
+		t.Errorf("expected 2 truncation markers, got %d in %q", n, submitted)
+	}
+}
@@ -0,0 +1,174 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+func init_config_get_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var configGetCmd = &cobra.Command{
+		Use:     "get <KEY>",
+		Aliases: []string{"g"},
+		Short:   "Get setting",
+		Long:    `Outputs a setting of the .gairc file of the current directory, like model or defaults.flags.model.`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			value, err := app.RCFile.GetFlagValue(args[0])
+			app.CheckIfError(err)
+
+			if value == nil {
+				app.CheckIfError(fmt.Errorf("setting '%s' is not defined", args[0]))
+			}
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(value, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+				return
+			}
+
+			if list, ok := value.([]string); ok {
+				for _, item := range list {
+					app.Writeln(item)
+				}
+				return
+			}
+
+			app.Writeln(fmt.Sprint(value))
+		},
+	}
+
+	parentCmd.AddCommand(
+		configGetCmd,
+	)
+}
+
+func init_config_list_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var configListCmd = &cobra.Command{
+		Use:     "list",
+		Aliases: []string{"ls", "l"},
+		Short:   "List settings",
+		Long:    `Lists all defined settings of the .gairc file of the current directory.`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			settings := map[string]any{}
+			keys := make([]string, 0)
+			for _, key := range types.GetRCFileFlagKeys() {
+				value, err := app.RCFile.GetFlagValue(key)
+				app.CheckIfError(err)
+
+				if value != nil {
+					path := fmt.Sprintf("defaults.flags.%s", key)
+
+					settings[path] = value
+					keys = append(keys, path)
+				}
+			}
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(settings, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+				return
+			}
+
+			for _, path := range keys {
+				value := settings[path]
+				if list, ok := value.([]string); ok {
+					jsonData, err := json.Marshal(list)
+					app.CheckIfError(err)
+
+					value = string(jsonData)
+				}
+
+				app.Writeln(fmt.Sprintf("%s\t%v", path, value))
+			}
+		},
+	}
+
+	parentCmd.AddCommand(
+		configListCmd,
+	)
+}
+
+func init_config_set_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var configSetCmd = &cobra.Command{
+		Use:     "set <KEY> <VALUE> [VALUE...]",
+		Aliases: []string{"s"},
+		Short:   "Set setting",
+		Long:    `Sets a setting in the .gairc file of the current directory, which is created if needed. Lists, like files, take all values.`,
+		Args:    cobra.MinimumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			value, key, err := types.ParseRCFileFlagValue(args[0], args[1:])
+			app.CheckIfError(err)
+
+			if key == "model" {
+				_, _, err := types.ParseModelWithProvider(fmt.Sprint(value))
+				if err != nil {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, err))
+				}
+			}
+
+			err = app.SetRCFileValue(value, "defaults", "flags", key)
+			app.CheckIfError(err)
+
+			rcFilePath, err := app.GetRCFilePath()
+			app.CheckIfError(err)
+
+			app.Dbgf("Setting 'defaults.flags.%v' written to '%v'%v", key, rcFilePath, app.EOL)
+		},
+	}
+
+	parentCmd.AddCommand(
+		configSetCmd,
+	)
+}
+
+// Init_config_Command initializes the `config` command.
+func Init_config_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var configCmd = &cobra.Command{
+		Use:     "config [action]",
+		Aliases: []string{"cfg"},
+		Short:   "Settings",
+		Long:    fmt.Sprintf("Manages the settings of the .gairc file of the current directory: %s", strings.Join(types.GetRCFileFlagKeys(), ", ")),
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	init_config_get_Command(app, configCmd)
+	init_config_list_Command(app, configCmd)
+	init_config_set_Command(app, configCmd)
+
+	parentCmd.AddCommand(
+		configCmd,
+	)
+}
@@ -23,20 +23,59 @@
 package commands
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"database/sql"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
+	"sync"
+	"text/template"
 	"time"
 
 	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
 	"github.com/spf13/cobra"
 )
 
+// defaultDescribeOutputTemplate is the default for the names
+// of the files of `--output-dir`.
+const defaultDescribeOutputTemplate = "{{.Filename}}.json"
+
+type audioDescriptionResponse struct {
+	AudioInformation    audioDescriptionResponseAudioInformation `json:"audio_information,omitempty"`
+	FileModifiationTime string                                   `json:"file_modifiation_time,omitempty"`
+	Filename            string                                   `json:"filename,omitempty"`
+	Filesize            int64                                    `json:"filesize,omitempty"`
+	Transcript          string                                   `json:"transcript,omitempty"`
+}
+
+type audioDescriptionResponseAudioInformation struct {
+	Summary string   `json:"summary"`
+	Tags    []string `json:"tags"`
+	Title   string   `json:"title"`
+}
+
+type fileDescriptionResponse struct {
+	FileInformation     fileDescriptionResponseFileInformation `json:"file_information,omitempty"`
+	FileModifiationTime string                                 `json:"file_modifiation_time,omitempty"`
+	Filename            string                                 `json:"filename,omitempty"`
+	Filesize            int64                                  `json:"filesize,omitempty"`
+	MimeType            string                                 `json:"mime_type,omitempty"`
+}
+
+type fileDescriptionResponseFileInformation struct {
+	Summary string   `json:"summary"`
+	Tags    []string `json:"tags"`
+	Title   string   `json:"title"`
+}
+
 type imageDescriptionResponse struct {
 	FileModifiationTime string                                   `json:"file_modifiation_time,omitempty"`
 	Filename            string                                   `json:"filename,omitempty"`
@@ -50,11 +89,718 @@ type imageDescriptionResponseImageInformation struct {
 	Title               string   `json:"title"`
 }
 
+// checkTagLimits checks the values of `--min-tags` and `--max-tags`.
+func checkTagLimits(app *types.AppContext, minTags uint16, maxTags uint16) {
+	if maxTags < minTags {
+		app.CheckIfError(types.NewTypedError(
+			types.ErrorTypeUsage,
+			fmt.Errorf("--max-tags (%d) must not be less than --min-tags (%d)", maxTags, minTags),
+		))
+	}
+}
+
+// normalizeTags removes empty and duplicate tags and keeps not more than
+// `maxTags` of them, because not all models respect the limits of the schema.
+func normalizeTags(tags []string, minTags uint16, maxTags uint16) ([]string, error) {
+	normalizedTags := make([]string, 0, len(tags))
+	for _, t := range tags {
+		t = strings.TrimSpace(t)
+		if t != "" && !slices.Contains(normalizedTags, t) {
+			normalizedTags = append(normalizedTags, t)
+		}
+	}
+
+	if len(normalizedTags) > int(maxTags) {
+		normalizedTags = normalizedTags[:maxTags]
+	}
+	if len(normalizedTags) < int(minTags) {
+		return normalizedTags, types.NewTypedError(
+			types.ErrorTypeSchemaValidation,
+			fmt.Errorf("got %d tag(s), but at least %d are required", len(normalizedTags), minTags),
+		)
+	}
+
+	return normalizedTags, nil
+}
+
+// newDescribeAudioResponseSchema returns the default response schema of `describe audio`.
+func newDescribeAudioResponseSchema(minTags uint16, maxTags uint16) *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"audio_information"},
+		"properties": map[string]any{
+			"audio_information": map[string]any{
+				"type":        "object",
+				"description": "Information about the audio.",
+				"required":    []string{"summary", "tags", "title"},
+				"properties": map[string]any{
+					"summary": map[string]any{
+						"description": "A concise summary of the audio.",
+						"type":        "string",
+					},
+					"tags": map[string]any{
+						"type":     "array",
+						"minItems": minTags,
+						"maxItems": maxTags,
+						"items": map[string]any{
+							"type":        "string",
+							"description": "Word or small text that categorized the audio.",
+						},
+					},
+					"title": map[string]any{
+						"description": "A short and descriptive title for the audio.",
+						"type":        "string",
+					},
+				},
+			},
+		},
+	}
+}
+
+// newDescribeFileResponseSchema returns the default response schema of `describe files`.
+func newDescribeFileResponseSchema(minTags uint16, maxTags uint16) *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"file_information"},
+		"properties": map[string]any{
+			"file_information": map[string]any{
+				"type":        "object",
+				"description": "Information about the file.",
+				"required":    []string{"summary", "tags", "title"},
+				"properties": map[string]any{
+					"summary": map[string]any{
+						"description": "A concise summary of the file.",
+						"type":        "string",
+					},
+					"tags": map[string]any{
+						"type":     "array",
+						"minItems": minTags,
+						"maxItems": maxTags,
+						"items": map[string]any{
+							"type":        "string",
+							"description": "Word or small text that categorized the file.",
+						},
+					},
+					"title": map[string]any{
+						"description": "A short and descriptive title for the file.",
+						"type":        "string",
+					},
+				},
+			},
+		},
+	}
+}
+
+// newDescribeImageResponseSchema returns the default response schema of `describe images`.
+func newDescribeImageResponseSchema(minTags uint16, maxTags uint16) *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"image_information"},
+		"properties": map[string]any{
+			"image_information": map[string]any{
+				"type":        "object",
+				"description": "Information about the image.",
+				"required":    []string{"detailed_description", "tags", "title"},
+				"properties": map[string]any{
+					"detailed_description": map[string]any{
+						"description": "A detailed description what is in the image.",
+						"type":        "string",
+					},
+					"tags": map[string]any{
+						"type":     "array",
+						"minItems": minTags,
+						"maxItems": maxTags,
+						"items": map[string]any{
+							"type":        "string",
+							"description": "Word or small text that categorized the image.",
+						},
+					},
+					"title": map[string]any{
+						"description": "A short and descriptive title for the image.",
+						"type":        "string",
+					},
+				},
+			},
+		},
+	}
+}
+
+// describeSettings stores the values of the flags of `withDescribeCLIFlags`.
+type describeSettings struct {
+	forceUpdate    bool
+	maxTags        uint16
+	minTags        uint16
+	updateExisting bool
+}
+
+// withDescribeCLIFlags sets up `cmd` for the CLI flags, which are shared
+// by the subcommands of `describe`, that describe local files.
+func withDescribeCLIFlags(app *types.AppContext, cmd *cobra.Command, settings *describeSettings) {
+	cmd.Flags().BoolVarP(&settings.forceUpdate, "force-update", "", false, "")
+	cmd.Flags().Uint16VarP(&settings.maxTags, "max-tags", "", 10, "")
+	cmd.Flags().Uint16VarP(&settings.minTags, "min-tags", "", 1, "")
+	cmd.Flags().BoolVarP(&settings.updateExisting, "update-existing", "", false, "")
+
+	app.WithBudgetCLIFlags(cmd)
+	app.WithDatabaseCLIFlags(cmd)
+	app.WithLanguageCLIFlags(cmd)
+	app.WithOutputDirCLIFlags(cmd, defaultDescribeOutputTemplate)
+	app.WithValidationCLIFlags(cmd)
+}
+
+// fileToDescribe contains the data of a file, which has to be described.
+type fileToDescribe struct {
+	contentHash string
+	data        []byte
+	file        string
+	filename    string
+	filesize    int64
+	fileModTime string
+	fromStdin   bool
+	index       int
+}
+
+// describeContext contains the state of a subcommand of `describe`,
+// which describes local files and stores them in a database table.
+type describeContext struct {
+	app            *types.AppContext
+	db             *sql.DB
+	dbMutex        sync.Mutex
+	files          []string
+	outputTemplate *template.Template
+	settings       *describeSettings
+	stdinData      []byte
+	table          string
+}
+
+// newDescribeContext checks the flags of `settings`, initializes the AI,
+// collects the files and opens the database, if defined, where
+// `createTable` creates `table` for the descriptions.
+func newDescribeContext(app *types.AppContext, settings *describeSettings, table string, createTable string) *describeContext {
+	checkTagLimits(app, settings.minTags, settings.maxTags)
+
+	outputTemplate, err := app.GetOutputFileTemplate(defaultDescribeOutputTemplate)
+	app.CheckIfError(err)
+
+	// also checks the prices, which are required by --budget
+	err = app.CheckSpentBudget()
+	app.CheckIfError(err)
+
+	app.InitAI()
+
+	files, err := app.GetFiles()
+	app.CheckIfError(err)
+
+	db, err := app.OpenSQLDatabase()
+	app.CheckIfError(err)
+
+	if db != nil {
+		_, err = app.ExecSQLWithRetry(db, createTable)
+		app.CheckIfError(err)
+
+		// databases of older versions have no content hash
+		err = app.EnsureSQLColumn(db, table, "content_hash", "TEXT")
+		app.CheckIfError(err)
+
+		createIndex := fmt.Sprintf(`CREATE UNIQUE INDEX IF NOT EXISTS idx_%[1]s_file_path ON %[1]s(file_path);`, table)
+		_, err = app.ExecSQLWithRetry(db, createIndex)
+		app.CheckIfError(err)
+	}
+
+	return &describeContext{
+		app:            app,
+		db:             db,
+		files:          files,
+		outputTemplate: outputTemplate,
+		settings:       settings,
+		table:          table,
+	}
+}
+
+// addStdinFile adds `data` from STDIN as last file with the name `name`.
+func (dc *describeContext) addStdinFile(name string, data []byte) {
+	dc.files = append(dc.files, name)
+	dc.stdinData = data
+}
+
+// checkFiles exits, if there are no files to describe.
+func (dc *describeContext) checkFiles() {
+	if len(dc.files) == 0 {
+		dc.app.CheckIfError(errors.New("no files found or defined"))
+	}
+}
+
+// close closes the database, if open.
+func (dc *describeContext) close() {
+	if dc.db != nil {
+		dc.db.Close()
+	}
+}
+
+// describeEach checks, prepares and describes the files one after another
+// with `describe` and outputs the lines, which are returned.
+func (dc *describeContext) describeEach(describe func(file *fileToDescribe) []string) {
+	dc.checkFiles()
+
+	utils.ProcessInOrder(len(dc.files), 1, func(i int) []string {
+		file, lines := dc.prepareFile(i)
+		if file == nil {
+			return lines
+		}
+
+		return describe(file)
+	}, dc.writeLines)
+}
+
+// getLanguage returns the language of the descriptions.
+func (dc *describeContext) getLanguage() string {
+	outputLanguage := strings.TrimSpace(dc.app.OutputLanguage)
+	if outputLanguage != "" {
+		return outputLanguage
+	}
+
+	return "english"
+}
+
+// getResponseSchema returns the custom response schema and its name or,
+// if not defined, the one from `newDefaultSchema` and `defaultName`.
+func (dc *describeContext) getResponseSchema(newDefaultSchema func(minTags uint16, maxTags uint16) *map[string]any, defaultName string) (*map[string]any, string) {
+	responseSchema, responseSchemaName, err := dc.app.GetResponseSchema()
+	dc.app.CheckIfError(err)
+
+	if responseSchema == nil {
+		// we want structured output
+
+		responseSchema = newDefaultSchema(dc.settings.minTags, dc.settings.maxTags)
+	}
+	if strings.TrimSpace(responseSchemaName) == "" {
+		responseSchemaName = defaultName
+	}
+
+	return responseSchema, responseSchemaName
+}
+
+// normalizeTags normalizes `tags` with the values of `--min-tags` and `--max-tags`.
+func (dc *describeContext) normalizeTags(tags []string) ([]string, error) {
+	return normalizeTags(tags, dc.settings.minTags, dc.settings.maxTags)
+}
+
+// prepareFile reads the file with the index `i` and returns `nil` and
+// the lines to output, if it should not or cannot be described.
+func (dc *describeContext) prepareFile(i int) (*fileToDescribe, []string) {
+	f := dc.files[i]
+
+	if dc.stdinData != nil && i == len(dc.files)-1 {
+		// there is no file, which can be tracked in a database
+		return &fileToDescribe{
+			contentHash: fmt.Sprintf("%x", sha256.Sum256(dc.stdinData)),
+			data:        dc.stdinData,
+			file:        f,
+			filename:    f,
+			filesize:    int64(len(dc.stdinData)),
+			fileModTime: time.Now().UTC().Format(time.RFC3339),
+			fromStdin:   true,
+			index:       i,
+		}, nil
+	}
+
+	info, err := os.Stat(f)
+	if err != nil {
+		return nil, dc.toErrorLines(f, err)
+	}
+
+	data, err := os.ReadFile(f)
+	if err != nil {
+		return nil, dc.toErrorLines(f, err)
+	}
+
+	filename, err := filepath.Rel(dc.app.WorkingDirectory, f)
+	if err != nil {
+		filename = f
+	}
+
+	file := &fileToDescribe{
+		contentHash: fmt.Sprintf("%x", sha256.Sum256(data)),
+		data:        data,
+		file:        f,
+		filename:    filename,
+		filesize:    info.Size(),
+		fileModTime: info.ModTime().UTC().Format(time.RFC3339),
+		index:       i,
+	}
+
+	if dc.shouldSkip(file) {
+		return nil, nil
+	}
+
+	return file, nil
+}
+
+// save outputs the `description` of `file` and upserts the values
+// of `columns` together with the information of `file` in the database.
+func (dc *describeContext) save(file *fileToDescribe, description any, columns map[string]any) []string {
+	// ... and finally a cleaned JSON
+	cleanJson, err := json.Marshal(description)
+	if err != nil {
+		return dc.toErrorLines(file.file, err)
+	}
+
+	var lines []string
+	if dc.outputTemplate != nil {
+		_, err := dc.app.WriteOutputFile(dc.outputTemplate, file.filename, file.index, cleanJson)
+		if err != nil {
+			return dc.toErrorLines(file.file, err)
+		}
+	} else {
+		lines = []string{string(cleanJson)}
+	}
+
+	if dc.db != nil && !file.fromStdin {
+		dc.upsert(file, columns)
+	}
+
+	return lines
+}
+
+// shouldSkip checks if `file` is already in the database and
+// should not be described (again).
+func (dc *describeContext) shouldSkip(file *fileToDescribe) bool {
+	if dc.db == nil || dc.settings.forceUpdate {
+		return false
+	}
+
+	// check for existing entries and if they should be updated
+	// (database access is serialized)
+	dc.dbMutex.Lock()
+	defer dc.dbMutex.Unlock()
+
+	var lastFilesize int64
+	var lastModified string
+	var lastContentHash sql.NullString
+
+	err := dc.db.QueryRow(
+		fmt.Sprintf(`SELECT last_filesize, last_modified, content_hash FROM %s
+WHERE file_path = ?;`, dc.table),
+		file.filename,
+	).Scan(&lastFilesize, &lastModified, &lastContentHash)
+
+	if err == sql.ErrNoRows {
+		return false
+	}
+	dc.app.CheckIfError(err)
+
+	// exists
+	if !dc.settings.updateExisting {
+		return true // ... but do not update
+	}
+
+	if lastContentHash.Valid && lastContentHash.String == file.contentHash {
+		dc.app.Dbgf("Skipping '%v', because its content has not changed%v", file.filename, dc.app.EOL)
+
+		if lastFilesize != file.filesize || lastModified != file.fileModTime {
+			_, err := dc.app.ExecSQLWithRetry(
+				dc.db,
+				fmt.Sprintf(`UPDATE %s SET last_filesize = ?, last_modified = ? WHERE file_path = ?;`, dc.table),
+				file.filesize,
+				file.fileModTime,
+				file.filename,
+			)
+			dc.app.CheckIfError(err)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// toErrorLines returns the lines to output for the error `err` of the file `f`.
+func (dc *describeContext) toErrorLines(f string, err error) []string {
+	errorObj := &map[string]any{
+		"file": f,
+		"error": map[string]any{
+			"message": err.Error(),
+		},
+	}
+
+	data, err2 := json.Marshal(&errorObj)
+	if err2 != nil {
+		return []string{err2.Error()}
+	}
+	return []string{fmt.Sprintf("ERROR: %s", data)}
+}
+
+// upsert inserts or updates the values of `columns` together
+// with the information of `file` in the database.
+func (dc *describeContext) upsert(file *fileToDescribe, columns map[string]any) {
+	values := map[string]any{
+		"content_hash":  file.contentHash,
+		"last_filesize": file.filesize,
+		"last_modified": file.fileModTime,
+	}
+	maps.Copy(values, columns)
+
+	names := slices.Sorted(maps.Keys(values))
+
+	args := []any{file.filename}
+	updates := make([]string, 0, len(names)+1)
+	for _, name := range names {
+		args = append(args, values[name])
+		updates = append(updates, fmt.Sprintf("    %[1]s=excluded.%[1]s", name))
+	}
+	updates = append(updates, "    updated_at=CURRENT_TIMESTAMP")
+
+	upsert := fmt.Sprintf(`INSERT INTO %s
+(file_path, %s) VALUES (?%s)
+ON CONFLICT(file_path) DO UPDATE SET
+%s;`,
+		dc.table,
+		strings.Join(names, ", "),
+		strings.Repeat(", ?", len(names)),
+		strings.Join(updates, ",\n"),
+	)
+
+	dc.dbMutex.Lock()
+	defer dc.dbMutex.Unlock()
+
+	_, err := dc.app.ExecSQLWithRetry(dc.db, upsert, args...)
+	dc.app.CheckIfError(err)
+}
+
+// writeLines outputs `lines` and is used as emitter of `utils.ProcessInOrder`.
+func (dc *describeContext) writeLines(i int, lines []string) bool {
+	for _, line := range lines {
+		dc.app.Writeln(line)
+	}
+
+	return true
+}
+
+func init_describe_audio_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var settings describeSettings
+
+	var describeAudioCmd = &cobra.Command{
+		Use:     "audio",
+		Aliases: []string{"audios", "a"},
+		Short:   "Describe audio",
+		Long:    `Transcribes audio files and describes them with title, summary and tags.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dc := newDescribeContext(app, &settings, "audio", `CREATE TABLE IF NOT EXISTS audio (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  file_path TEXT NOT NULL,
+  last_filesize INTEGER NOT NULL,
+  last_modified DATETIME NOT NULL,
+  content_hash TEXT,
+  title TEXT NOT NULL,
+  summary TEXT NOT NULL,
+  tags TEXT NOT NULL,
+  transcript TEXT NOT NULL,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  updated_at DATETIME
+);`)
+			defer dc.close()
+
+			responseSchema, responseSchemaName := dc.getResponseSchema(newDescribeAudioResponseSchema, "DescribeAudioSchema")
+
+			systemPrompt := fmt.Sprintf(`You are an AI assistant that helps users organize their audio collections like podcasts or voice memos.
+The user submits the transcript of an audio file as serialized JSON string.
+For the transcript, generate:
+- A concise and informative summary of the audio in natural '%s' language.
+- A short and descriptive title of the main topic of the audio.
+- A set of relevant tags that summarize the main topics, speakers, themes and activities of the audio. The tags should be lowercase, and without special characters.
+Be objective and accurate. Do not include personal opinions or assumptions that cannot be verified from the transcript itself.`, dc.getLanguage())
+
+			dc.describeEach(func(file *fileToDescribe) []string {
+				mimeType := utils.DetectMime(file.data)
+				if !strings.HasPrefix(mimeType, "audio/") {
+					app.Dbgf("Skipping '%v', because '%v' is no audio format%v", file.filename, mimeType, app.EOL)
+					return nil
+				}
+
+				// transcriptions are not counted, but should
+				// also not be done, if there is no budget left
+				err := app.CheckSpentBudget()
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				app.Dbgf("Transcribing '%v' ...%v", file.filename, app.EOL)
+
+				transcribeResponse, err := app.AI.Transcribe(bytes.NewReader(file.data))
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				transcript := strings.TrimSpace(transcribeResponse.Content)
+
+				jsonData, err := json.Marshal(transcript)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				promptOptions := make([]types.AIClientPromptOptions, 0)
+				promptOptions = append(promptOptions, types.AIClientPromptOptions{
+					ResponseSchema:     responseSchema,
+					ResponseSchemaName: &responseSchemaName,
+					SystemPrompt:       &systemPrompt,
+				})
+
+				response, err := app.PromptAndValidate(
+					fmt.Sprintf("This is the transcript of the audio file '%s': %s", file.filename, jsonData),
+					promptOptions...,
+				)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				// ensure we have correct response ...
+				var audioDescription audioDescriptionResponse
+				err = json.Unmarshal([]byte(response.Content), &audioDescription)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				audioDescription.AudioInformation.Tags, err = dc.normalizeTags(audioDescription.AudioInformation.Tags)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				audioDescription.Filename = file.filename
+				audioDescription.Filesize = file.filesize
+				audioDescription.FileModifiationTime = file.fileModTime
+				audioDescription.Transcript = transcript
+
+				return dc.save(file, &audioDescription, map[string]any{
+					"summary":    audioDescription.AudioInformation.Summary,
+					"tags":       strings.Join(audioDescription.AudioInformation.Tags, ","),
+					"title":      audioDescription.AudioInformation.Title,
+					"transcript": audioDescription.Transcript,
+				})
+			})
+		},
+	}
+
+	withDescribeCLIFlags(app, describeAudioCmd, &settings)
+
+	parentCmd.AddCommand(
+		describeAudioCmd,
+	)
+}
+
+func init_describe_files_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var settings describeSettings
+
+	var describeFilesCmd = &cobra.Command{
+		Use:     "files",
+		Aliases: []string{"file", "documents", "docs", "f"},
+		Short:   "Describe files",
+		Long:    `Describes any kind of files, like documents or images, with title, summary and tags.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			dc := newDescribeContext(app, &settings, "documents", `CREATE TABLE IF NOT EXISTS documents (
+  id INTEGER PRIMARY KEY AUTOINCREMENT,
+  file_path TEXT NOT NULL,
+  mime_type TEXT NOT NULL,
+  last_filesize INTEGER NOT NULL,
+  last_modified DATETIME NOT NULL,
+  content_hash TEXT,
+  title TEXT NOT NULL,
+  summary TEXT NOT NULL,
+  tags TEXT NOT NULL,
+  created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+  updated_at DATETIME
+);`)
+			defer dc.close()
+
+			responseSchema, responseSchemaName := dc.getResponseSchema(newDescribeFileResponseSchema, "DescribeFileSchema")
+
+			systemPrompt := fmt.Sprintf(`You are an AI assistant that helps users organize their local documents and files.
+The user submits a file, either as image or as its text content in form of a serialized JSON string.
+For each file, generate:
+- A concise and informative summary of the file in natural '%s' language.
+- A short and descriptive title of the main topic of the file.
+- A set of relevant tags that summarize the main topics, themes, and elements of the file. The tags should be lowercase, and without special characters.
+Be objective and accurate. Do not include personal opinions or assumptions that cannot be verified from the file itself.`, dc.getLanguage())
+
+			dc.describeEach(func(file *fileToDescribe) []string {
+				mimeType := utils.DetectMime(file.data)
+
+				promptOptions := make([]types.AIClientPromptOptions, 0)
+				promptOptions = append(promptOptions, types.AIClientPromptOptions{
+					ResponseSchema:     responseSchema,
+					ResponseSchemaName: &responseSchemaName,
+					SystemPrompt:       &systemPrompt,
+				})
+
+				prompt := ""
+				if strings.HasPrefix(mimeType, "image/") {
+					prompt = fmt.Sprintf("This is the image file '%s'.", file.filename)
+
+					promptOptions = append(promptOptions, types.AIClientPromptOptions{
+						Files: &[]io.Reader{bytes.NewReader(file.data)},
+					})
+				} else {
+					text, err := utils.EnsurePlainText(file.data)
+					if err != nil {
+						return dc.toErrorLines(file.file, err)
+					}
+
+					if utils.MaybeBinary([]byte(text)) {
+						return dc.toErrorLines(file.file, fmt.Errorf("could not extract text of file with mime type '%v'", mimeType))
+					}
+
+					jsonData, err := json.Marshal(text)
+					if err != nil {
+						return dc.toErrorLines(file.file, err)
+					}
+
+					prompt = fmt.Sprintf("This is the content of the file '%s': %s", file.filename, jsonData)
+				}
+
+				app.Dbgf("Describing '%v' ...%v", file.filename, app.EOL)
+
+				response, err := app.PromptAndValidate(prompt, promptOptions...)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				// ensure we have correct response ...
+				var fileDescription fileDescriptionResponse
+				err = json.Unmarshal([]byte(response.Content), &fileDescription)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				fileDescription.FileInformation.Tags, err = dc.normalizeTags(fileDescription.FileInformation.Tags)
+				if err != nil {
+					return dc.toErrorLines(file.file, err)
+				}
+
+				fileDescription.Filename = file.filename
+				fileDescription.Filesize = file.filesize
+				fileDescription.FileModifiationTime = file.fileModTime
+				fileDescription.MimeType = mimeType
+
+				return dc.save(file, &fileDescription, map[string]any{
+					"mime_type": fileDescription.MimeType,
+					"summary":   fileDescription.FileInformation.Summary,
+					"tags":      strings.Join(fileDescription.FileInformation.Tags, ","),
+					"title":     fileDescription.FileInformation.Title,
+				})
+			})
+		},
+	}
+
+	withDescribeCLIFlags(app, describeFilesCmd, &settings)
+
+	parentCmd.AddCommand(
+		describeFilesCmd,
+	)
+}
+
 func init_describe_images_Command(app *types.AppContext, parentCmd *cobra.Command) {
-	var forceUpdate bool
-	var maxTags uint16
-	var minTags uint16
-	var updateExisting bool
+	var batchImages uint16
+	var concurrency uint16
+	var settings describeSettings
 
 	var initCodeCmd = &cobra.Command{
 		Use:     "images",
@@ -62,51 +808,40 @@ func init_describe_images_Command(app *types.AppContext, parentCmd *cobra.Comman
 		Short:   "Describe image",
 		Long:    `Describes images with tags.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			app.InitAI()
-
-			files, err := app.GetFiles()
-			app.CheckIfError(err)
-
-			db, err := app.OpenSQLDatabase()
-			app.CheckIfError(err)
-
-			if db != nil {
-				createTable := `CREATE TABLE IF NOT EXISTS images (
+			dc := newDescribeContext(app, &settings, "images", `CREATE TABLE IF NOT EXISTS images (
   id INTEGER PRIMARY KEY AUTOINCREMENT,
   file_path TEXT NOT NULL,
   last_filesize INTEGER NOT NULL,
   last_modified DATETIME NOT NULL,
+  content_hash TEXT,
   title TEXT NOT NULL,
   description TEXT NOT NULL,
   tags TEXT NOT NULL,
   created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
   updated_at DATETIME
-);`
-				_, err = db.Exec(createTable)
-				app.CheckIfError(err)
+);`)
+			defer dc.close()
 
-				createIndex := `CREATE UNIQUE INDEX IF NOT EXISTS idx_images_file_path ON images(file_path);`
-				_, err = db.Exec(createIndex)
+			if app.StdinBinary {
+				// read STDIN before the input is collected,
+				// so it does not become part of the prompt
+				stdinData, stdinMime, stdinName, err := app.ReadBinaryStdin()
 				app.CheckIfError(err)
 
-				defer func() {
-					db.Close()
-				}()
-			}
+				if !strings.HasPrefix(stdinMime, "image/") {
+					app.CheckIfError(types.NewTypedError(
+						types.ErrorTypeUsage,
+						fmt.Errorf("data from STDIN is no image but '%s'", stdinMime),
+					))
+				}
 
-			if len(files) == 0 {
-				app.CheckIfError(errors.New("no files found or defined"))
+				// the image from STDIN is always the last one
+				dc.addStdinFile(stdinName, stdinData)
 			}
 
-			responseSchema, responseSchemaName, err := app.GetResponseSchema()
-			app.CheckIfError(err)
-
-			outputLanguage := strings.TrimSpace(app.OutputLanguage)
+			dc.checkFiles()
 
-			lang := "english"
-			if outputLanguage != "" {
-				lang = outputLanguage
-			}
+			responseSchema, responseSchemaName := dc.getResponseSchema(newDescribeImageResponseSchema, "DescribeImageSchema")
 
 			prompt, err := app.GetInput(args)
 			app.CheckIfError(err)
@@ -121,182 +856,241 @@ For each provided image file, generate:
 - A concise and informative description of the image in natural '%s' language, suitable for someone who cannot see the photo.
 - A short and descriptive title of the main objects in the image.
 - A set of relevant tags that summarize the main objects, themes, activities, and visual elements present in the image. The tags should be lowercase, and without special characters.
-Be objective and accurate. Do not include personal opinions or assumptions that cannot be verified from the image itself.`, lang)
+Be objective and accurate. Do not include personal opinions or assumptions that cannot be verified from the image itself.`, dc.getLanguage())
+
+			batchSize := max(int(batchImages), 1)
 
-			if responseSchema == nil {
-				// we want structured output
+			// for multiple images in one request, the schema of
+			// a single image becomes the one of an array item
+			var batchResponseSchema *map[string]any
+			batchResponseSchemaName := fmt.Sprintf("%sBatch", responseSchemaName)
+			if batchSize > 1 {
+				itemSchema := map[string]any{}
+				maps.Copy(itemSchema, *responseSchema)
 
-				responseSchema = &map[string]any{
+				itemProperties := map[string]any{
+					"filename": map[string]any{
+						"description": "The filename of the image.",
+						"type":        "string",
+					},
+				}
+				if properties, ok := itemSchema["properties"].(map[string]any); ok {
+					maps.Copy(itemProperties, properties)
+				}
+				itemSchema["properties"] = itemProperties
+
+				itemRequired := []string{"filename"}
+				switch required := itemSchema["required"].(type) {
+				case []string:
+					itemRequired = append(itemRequired, required...)
+				case []any:
+					// from custom schema file
+					for _, r := range required {
+						if name, ok := r.(string); ok {
+							itemRequired = append(itemRequired, name)
+						}
+					}
+				}
+				itemSchema["required"] = itemRequired
+
+				batchResponseSchema = &map[string]any{
 					"type":     "object",
-					"required": []string{"image_information", "tags", "title"},
+					"required": []string{"images"},
 					"properties": map[string]any{
-						"image_information": map[string]any{
-							"type":        "object",
-							"description": "Information about the image.",
-							"required":    []string{"detailed_description", "tags", "title"},
-							"properties": map[string]any{
-								"detailed_description": map[string]any{
-									"description": "A detailed description what is in the image.",
-									"type":        "string",
-								},
-								"tags": map[string]any{
-									"type":    "array",
-									"minimum": minTags,
-									"maximum": maxTags,
-									"items": map[string]any{
-										"type":        "string",
-										"description": "Word or small text that categorized the image.",
-									},
-								},
-								"title": map[string]any{
-									"description": "A short and descriptive title for the image.",
-									"type":        "string",
-								},
-							},
+						"images": map[string]any{
+							"type":        "array",
+							"description": "Information about each image.",
+							"items":       itemSchema,
 						},
 					},
 				}
 			}
-			if strings.TrimSpace(responseSchemaName) == "" {
-				responseSchemaName = "DescribeImageSchema"
+
+			// outputs and stores the description of an image
+			saveImageDescription := func(img *fileToDescribe, imageDescription imageDescriptionResponse) []string {
+				tags, err := dc.normalizeTags(imageDescription.ImageInformation.Tags)
+				if err != nil {
+					return dc.toErrorLines(img.file, err)
+				}
+				imageDescription.ImageInformation.Tags = tags
+
+				imageDescription.Filename = img.filename
+				imageDescription.Filesize = img.filesize
+				imageDescription.FileModifiationTime = img.fileModTime
+
+				return dc.save(img, &imageDescription, map[string]any{
+					"description": imageDescription.ImageInformation.DetailedDescription,
+					"tags":        strings.Join(imageDescription.ImageInformation.Tags, ","),
+					"title":       imageDescription.ImageInformation.Title,
+				})
 			}
 
-			for _, f := range files {
-				outputError := func(err error) {
-					errorObj := &map[string]any{
-						"file": f,
-						"error": map[string]any{
-							"message": err.Error(),
-						},
-					}
+			// describes a single image and returns the lines to output
+			describeImage := func(img *fileToDescribe) []string {
+				promptOptions := make([]types.AIClientPromptOptions, 0)
+				promptOptions = append(promptOptions, types.AIClientPromptOptions{
+					Files:              &[]io.Reader{bytes.NewReader(img.data)},
+					ResponseSchema:     responseSchema,
+					ResponseSchemaName: &responseSchemaName,
+					SystemPrompt:       &systemPrompt,
+				})
 
-					data, err2 := json.Marshal(&errorObj)
-					if err2 != nil {
-						app.Writeln(err2)
-					} else {
-						app.Writeln(fmt.Sprintf("ERROR: %s", data))
-					}
+				response, err := app.PromptAndValidate(prompt, promptOptions...)
+				if err != nil {
+					return dc.toErrorLines(img.file, err)
 				}
 
-				func() {
-					info, err := os.Stat(f)
-					if err != nil {
-						outputError(err)
-						return
-					}
+				// ensure we have correct response ...
+				var imageDescription imageDescriptionResponse
+				err = json.Unmarshal([]byte(response.Content), &imageDescription)
+				if err != nil {
+					return dc.toErrorLines(img.file, err)
+				}
 
-					// get file size and last update time
-					filesize := info.Size()
-					fileModTime := info.ModTime().UTC().Format(time.RFC3339)
+				return saveImageDescription(img, imageDescription)
+			}
 
-					file, err := os.Open(f)
-					if err != nil {
-						outputError(err)
-						return
+			// describes multiple images in one request and returns
+			// the lines to output by the index of the file
+			var describeImageBatch func(imgs []*fileToDescribe) map[int][]string
+			describeImageBatch = func(imgs []*fileToDescribe) map[int][]string {
+				lines := map[int][]string{}
+
+				outputErrorForAll := func(err error) map[int][]string {
+					for _, img := range imgs {
+						lines[img.index] = dc.toErrorLines(img.file, err)
 					}
+					return lines
+				}
+
+				filenames := make([]string, 0, len(imgs))
+				imageFiles := make([]io.Reader, 0, len(imgs))
+				for _, img := range imgs {
+					filenames = append(filenames, img.filename)
+					imageFiles = append(imageFiles, types.NewNamedReader(bytes.NewReader(img.data), img.filename))
+				}
 
-					defer file.Close()
+				jsonFilenames, err := json.Marshal(filenames)
+				if err != nil {
+					return outputErrorForAll(err)
+				}
 
-					filename, err := filepath.Rel(app.WorkingDirectory, f)
-					if err != nil {
-						filename = f
+				batchPrompt := fmt.Sprintf(`%s
+The %d images are submitted in this order and have these filenames as serialized JSON array: %s
+Describe each image separately and return one item with its filename for each of them.`,
+					prompt,
+					len(imgs),
+					jsonFilenames,
+				)
+
+				promptOptions := make([]types.AIClientPromptOptions, 0)
+				promptOptions = append(promptOptions, types.AIClientPromptOptions{
+					Files:              &imageFiles,
+					ResponseSchema:     batchResponseSchema,
+					ResponseSchemaName: &batchResponseSchemaName,
+					SystemPrompt:       &systemPrompt,
+				})
+
+				response, err := app.PromptAndValidate(batchPrompt, promptOptions...)
+				if err != nil {
+					if types.GetErrorType(err) != types.ErrorTypeRequestTooLarge {
+						return outputErrorForAll(err)
 					}
 
-					if db != nil && !forceUpdate {
-						// check for existing entries and if they should be updated
-
-						var lastFilesize int64
-						var lastModified string
-
-						err := db.QueryRow(
-							`SELECT last_filesize, last_modified FROM images
-WHERE file_path = ?;`,
-							filename,
-						).Scan(&lastFilesize, &lastModified)
-
-						if err == nil {
-							// exists
-							if !updateExisting {
-								return // ... but do not update
-							}
-						} else if err != sql.ErrNoRows {
-							app.CheckIfError(err)
+					// split oversized batch and try again
+					app.Dbgf("Request with %d images is too large, splitting it ...%v", len(imgs), app.EOL)
+
+					half := len(imgs) / 2
+					for _, part := range [][]*fileToDescribe{imgs[:half], imgs[half:]} {
+						if len(part) == 1 {
+							lines[part[0].index] = describeImage(part[0])
+						} else {
+							maps.Copy(lines, describeImageBatch(part))
 						}
 					}
 
-					promptOptions := make([]types.AIClientPromptOptions, 0)
-					promptOptions = append(promptOptions, types.AIClientPromptOptions{
-						Files:              &[]io.Reader{file},
-						ResponseSchema:     responseSchema,
-						ResponseSchemaName: &responseSchemaName,
-						SystemPrompt:       &systemPrompt,
-					})
+					return lines
+				}
 
-					response, err := app.AI.Prompt(prompt, promptOptions...)
-					if err != nil {
-						outputError(err)
-						return
+				var batchResponse struct {
+					Images []imageDescriptionResponse `json:"images"`
+				}
+				err = json.Unmarshal([]byte(response.Content), &batchResponse)
+				if err != nil {
+					return outputErrorForAll(err)
+				}
+
+				for _, img := range imgs {
+					found := false
+					for _, imageDescription := range batchResponse.Images {
+						if imageDescription.Filename == img.filename ||
+							filepath.Base(imageDescription.Filename) == filepath.Base(img.filename) {
+							lines[img.index] = saveImageDescription(img, imageDescription)
+
+							found = true
+							break
+						}
 					}
 
-					// ensure we have correct response ...
-					var imageDescription imageDescriptionResponse
-					err = json.Unmarshal([]byte(response.Content), &imageDescription)
-					if err != nil {
-						outputError(err)
-						return
+					if !found {
+						lines[img.index] = dc.toErrorLines(img.file, errors.New("no description returned for this image"))
 					}
+				}
 
-					imageDescription.Filename = filename
-					imageDescription.Filesize = filesize
-					imageDescription.FileModifiationTime = fileModTime
+				return lines
+			}
 
-					// ... and finally a cleaned JSON
-					cleanJson, err := json.Marshal(&imageDescription)
-					if err != nil {
-						outputError(err)
-						return
+			// describes a group of files and returns
+			// the lines to output by the index of the file
+			describeFiles := func(indexes []int) map[int][]string {
+				lines := map[int][]string{}
+
+				imgs := make([]*fileToDescribe, 0, len(indexes))
+				for _, i := range indexes {
+					img, l := dc.prepareFile(i)
+					if img != nil {
+						imgs = append(imgs, img)
+					} else {
+						lines[i] = l
 					}
+				}
 
-					app.Writeln(string(cleanJson))
+				if len(imgs) == 1 {
+					lines[imgs[0].index] = describeImage(imgs[0])
+				} else if len(imgs) > 1 {
+					maps.Copy(lines, describeImageBatch(imgs))
+				}
 
-					if db != nil {
-						func() {
-							stmt, err := db.Prepare(`INSERT INTO images
-(file_path, title, description, tags, last_filesize, last_modified) VALUES (?, ?, ?, ?, ?, ?)
-ON CONFLICT(file_path) DO UPDATE SET
-    description=excluded.description,
-    tags=excluded.tags,
-	title=excluded.title,
-    last_filesize=excluded.last_filesize,
-    last_modified=excluded.last_modified,
-	updated_at=CURRENT_TIMESTAMP;`)
-							app.CheckIfError(err)
-
-							defer stmt.Close()
-
-							_, err = stmt.Exec(
-								imageDescription.Filename,
-								imageDescription.ImageInformation.Title,
-								imageDescription.ImageInformation.DetailedDescription,
-								strings.Join(imageDescription.ImageInformation.Tags, ","),
-								filesize,
-								fileModTime,
-							)
-							app.CheckIfError(err)
-						}()
-					}
-				}()
+				return lines
 			}
+
+			// each job is a group of up to `batchSize` files,
+			// whose lines are output in the order of the files
+			groupCount := (len(dc.files) + batchSize - 1) / batchSize
+
+			utils.ProcessInOrder(groupCount, int(concurrency), func(g int) []string {
+				indexes := make([]int, 0, batchSize)
+				for i := g * batchSize; i < min((g+1)*batchSize, len(dc.files)); i++ {
+					indexes = append(indexes, i)
+				}
+
+				linesByIndex := describeFiles(indexes)
+
+				lines := make([]string, 0, len(indexes))
+				for _, i := range indexes {
+					lines = append(lines, linesByIndex[i]...)
+				}
+
+				return lines
+			}, dc.writeLines)
 		},
 	}
 
-	initCodeCmd.Flags().BoolVarP(&forceUpdate, "force-update", "", false, "")
-	initCodeCmd.Flags().Uint16VarP(&maxTags, "max-tags", "", 10, "")
-	initCodeCmd.Flags().Uint16VarP(&minTags, "min-tags", "", 1, "")
-	initCodeCmd.Flags().BoolVarP(&updateExisting, "update-existing", "", false, "")
+	initCodeCmd.Flags().Uint16VarP(&batchImages, "batch-images", "", 1, "")
+	initCodeCmd.Flags().Uint16VarP(&concurrency, "concurrency", "", 1, "")
 
-	app.WithDatabaseCLIFlags(initCodeCmd)
-	app.WithLanguageCLIFlags(initCodeCmd)
+	withDescribeCLIFlags(app, initCodeCmd, &settings)
+	app.WithStdinBinaryCLIFlags(initCodeCmd)
 
 	parentCmd.AddCommand(
 		initCodeCmd,
@@ -306,7 +1100,7 @@ ON CONFLICT(file_path) DO UPDATE SET
 // Init_describe_Command initializes the `describe` command.
 func Init_describe_Command(app *types.AppContext, parentCmd *cobra.Command) {
 	var initCmd = &cobra.Command{
-		Use:     "describes [resource]",
+		Use:     "describe [resource]",
 		Aliases: []string{"describes", "desc", "d"},
 		Short:   "Describe",
 		Long:    `Describes a resource.`,
@@ -315,7 +1109,10 @@ func Init_describe_Command(app *types.AppContext, parentCmd *cobra.Command) {
 		},
 	}
 
+	init_describe_audio_Command(app, initCmd)
+	init_describe_files_Command(app, initCmd)
 	init_describe_images_Command(app, initCmd)
+	init_describe_pr_Command(app, initCmd)
 
 	parentCmd.AddCommand(
 		initCmd,
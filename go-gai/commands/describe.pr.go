@@ -0,0 +1,194 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+type pullRequestDescriptionResponse struct {
+	Changes []string `json:"changes"`
+	Summary string   `json:"summary"`
+	Title   string   `json:"title"`
+}
+
+// newDescribePullRequestResponseSchema returns the default response schema of `describe pr`.
+func newDescribePullRequestResponseSchema() *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"changes", "summary", "title"},
+		"properties": map[string]any{
+			"changes": map[string]any{
+				"type":        "array",
+				"description": "The list of the most important changes.",
+				"items": map[string]any{
+					"type":        "string",
+					"description": "A single change.",
+				},
+			},
+			"summary": map[string]any{
+				"description": "A short summary of what the changes do and why.",
+				"type":        "string",
+			},
+			"title": map[string]any{
+				"description": "A short and descriptive title for the pull request.",
+				"type":        "string",
+			},
+		},
+	}
+}
+
+func init_describe_pr_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var base string
+
+	var describePRCmd = &cobra.Command{
+		Use:     "pr [additional context]",
+		Aliases: []string{"pull-request", "pullrequest"},
+		Short:   "Describe pull request",
+		Long:    `Describes the changes of the current branch as pull request.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			git, err := app.NewGitClient()
+			app.CheckIfError(err)
+
+			base = strings.TrimSpace(base)
+			if base == "" {
+				base = "main"
+			}
+
+			app.Dbgf("Comparing current branch with '%s' ...%s", base, app.EOL)
+
+			diff, changedFiles, err := git.DiffBranches(base)
+			app.CheckIfError(err)
+
+			if len(changedFiles) == 0 || strings.TrimSpace(diff) == "" {
+				app.CheckIfError(fmt.Errorf("no changes found compared to '%s'", base))
+			}
+
+			app.Dbgf("Found %d changed files%s", len(changedFiles), app.EOL)
+
+			app.InitAI()
+
+			responseSchema, responseSchemaName, err := app.GetResponseSchema()
+			app.CheckIfError(err)
+
+			additionalContext, err := app.GetInput(args)
+			app.CheckIfError(err)
+
+			outputLanguage := strings.TrimSpace(app.OutputLanguage)
+
+			lang := "english"
+			if outputLanguage != "" {
+				lang = outputLanguage
+			}
+
+			systemPrompt := fmt.Sprintf(`You are an experienced software developer, who writes pull request descriptions for reviewers.
+For the changes of a branch, generate:
+- A short and descriptive title of the pull request.
+- A summary of what the changes do and why, in 1 to 3 sentences.
+- A list of the most important changes, one item per change.
+Write everything in natural '%s' language.
+Be objective and accurate. Only describe what can be verified from the changes.`, lang)
+
+			customSchema := responseSchema != nil
+			if !customSchema {
+				// we want structured output
+
+				responseSchema = newDescribePullRequestResponseSchema()
+			}
+			if strings.TrimSpace(responseSchemaName) == "" {
+				responseSchemaName = "DescribePullRequestSchema"
+			}
+
+			fileList := make([]string, 0, len(changedFiles))
+			for _, cf := range changedFiles {
+				fileList = append(fileList, fmt.Sprintf("%s %s", cf.ChangeStatus(), cf.Name()))
+			}
+
+			jsonFileList, err := json.Marshal(fileList)
+			app.CheckIfError(err)
+
+			jsonDiff, err := json.Marshal(diff)
+			app.CheckIfError(err)
+
+			prompt := fmt.Sprintf(`Write a pull request description for the changes of my current branch compared to '%s'.
+These are the changed files with their git status as serialized JSON array: %s
+This is the diff as serialized JSON string: %s`,
+				base,
+				jsonFileList,
+				jsonDiff,
+			)
+			if strings.TrimSpace(additionalContext) != "" {
+				prompt += fmt.Sprintf("%sTake this as additional context: %s", app.EOL, additionalContext)
+			}
+
+			response, err := app.PromptAndValidate(prompt, types.AIClientPromptOptions{
+				ResponseSchema:     responseSchema,
+				ResponseSchemaName: &responseSchemaName,
+				SystemPrompt:       &systemPrompt,
+			})
+			app.CheckIfError(err)
+
+			app.OutputAIUsage(response.Usage)
+
+			if customSchema || app.JSONOutput {
+				// keep the structured output
+				app.OutputAIAnswer(response.Content)
+				return
+			}
+
+			var description pullRequestDescriptionResponse
+			err = json.Unmarshal([]byte(response.Content), &description)
+			app.CheckIfError(err)
+
+			if strings.TrimSpace(description.Title) == "" {
+				app.CheckIfError(errors.New("no title returned"))
+			}
+
+			markdown := fmt.Sprintf("# %s%s%s%s", strings.TrimSpace(description.Title), app.EOL, app.EOL, strings.TrimSpace(description.Summary))
+			if len(description.Changes) > 0 {
+				markdown += app.EOL + app.EOL
+				for _, change := range description.Changes {
+					markdown += fmt.Sprintf("- %s%s", strings.TrimSpace(change), app.EOL)
+				}
+			}
+
+			app.OutputAIAnswer(strings.TrimSpace(markdown) + app.EOL)
+		},
+	}
+
+	describePRCmd.Flags().StringVarP(&base, "base", "", "main", "base branch to compare with")
+
+	app.WithHighlightCLIFlags(describePRCmd)
+	app.WithLanguageCLIFlags(describePRCmd)
+	app.WithValidationCLIFlags(describePRCmd)
+
+	parentCmd.AddCommand(
+		describePRCmd,
+	)
+}
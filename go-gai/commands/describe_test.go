@@ -0,0 +1,605 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/gai/types"
+)
+
+// newTestWAV creates a WAV file with `samples` samples of silence (8 kHz, mono, 8-bit).
+func newTestWAV(samples int) []byte {
+	var buff bytes.Buffer
+	buff.WriteString("RIFF")
+	binary.Write(&buff, binary.LittleEndian, uint32(36+samples))
+	buff.WriteString("WAVEfmt ")
+	for _, v := range []any{uint32(16), uint16(1), uint16(1), uint32(8000), uint32(8000), uint16(1), uint16(8)} {
+		binary.Write(&buff, binary.LittleEndian, v)
+	}
+	buff.WriteString("data")
+	binary.Write(&buff, binary.LittleEndian, uint32(samples))
+	buff.Write(bytes.Repeat([]byte{128}, samples))
+
+	return buff.Bytes()
+}
+
+func TestDescribeAudio(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	var transcriptions atomic.Int32
+	var prompts atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/audio/transcriptions", func(w http.ResponseWriter, r *http.Request) {
+		transcriptions.Add(1)
+
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		data, _ := io.ReadAll(file)
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"text": fmt.Sprintf(" Voice memo with %d bytes ", len(data)),
+		})
+	})
+	mux.HandleFunc("/v1/chat/completions", newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+		prompts.Add(1)
+
+		system := messages[0].Content
+		if !strings.Contains(system, "in natural 'German' language") {
+			return "unexpected system prompt: " + system
+		}
+
+		user := messages[len(messages)-1].Content
+		title := "Unknown"
+		if strings.Contains(user, `'memos/a.wav': "Voice memo with 144 bytes"`) {
+			title = "Memo A"
+		} else if strings.Contains(user, `'memos/b.wav': "Voice memo with 244 bytes"`) {
+			title = "Memo B"
+		}
+
+		return fmt.Sprintf(`{"audio_information": {"summary": "Summary of %s", "tags": ["memo", " ", "memo", "silence"], "title": "%s"}}`, title, title)
+	}))
+	startTestOpenAIServer(t, app, mux)
+
+	writeTestFile(t, app, "memos/a.wav", string(newTestWAV(100)))
+	writeTestFile(t, app, "memos/b.wav", string(newTestWAV(200)))
+	writeTestFile(t, app, "memos/notes.txt", "no audio")
+
+	app.FilePatterns = []string{"memos/*"}
+
+	runTestCommand(t, app, Init_describe_Command, "describe", "audio", "--database", "gai.sqlite", "--language", "German")
+
+	lines := strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 descriptions, got %q", lines)
+	}
+
+	for i, name := range []string{"a", "b"} {
+		var description audioDescriptionResponse
+		err := json.Unmarshal([]byte(lines[i]), &description)
+		if err != nil {
+			t.Fatalf("invalid description %q: %v", lines[i], err)
+		}
+
+		title := "Memo " + strings.ToUpper(name)
+		if description.Filename != "memos/"+name+".wav" || description.AudioInformation.Title != title {
+			t.Errorf("unexpected description %+v", description)
+		}
+		if description.AudioInformation.Summary != "Summary of "+title {
+			t.Errorf("unexpected summary %q", description.AudioInformation.Summary)
+		}
+		if strings.Join(description.AudioInformation.Tags, ",") != "memo,silence" {
+			t.Errorf("unexpected tags %v", description.AudioInformation.Tags)
+		}
+		if !strings.HasPrefix(description.Transcript, "Voice memo with ") || strings.TrimSpace(description.Transcript) != description.Transcript {
+			t.Errorf("unexpected transcript %q", description.Transcript)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", app.GetFullPath("gai.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("SELECT file_path, title, summary, tags, transcript, content_hash FROM audio ORDER BY file_path;")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	stored := make([]string, 0)
+	for rows.Next() {
+		var filePath, title, summary, tags, transcript string
+		var contentHash sql.NullString
+		err := rows.Scan(&filePath, &title, &summary, &tags, &transcript, &contentHash)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !contentHash.Valid || len(contentHash.String) != 64 {
+			t.Errorf("invalid content hash %v of %v", contentHash, filePath)
+		}
+
+		stored = append(stored, strings.Join([]string{filePath, title, summary, tags, transcript}, "|"))
+	}
+
+	expected := []string{
+		"memos/a.wav|Memo A|Summary of Memo A|memo,silence|Voice memo with 144 bytes",
+		"memos/b.wav|Memo B|Summary of Memo B|memo,silence|Voice memo with 244 bytes",
+	}
+	if strings.Join(stored, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected rows %q, got %q", expected, stored)
+	}
+
+	if transcriptions.Load() != 2 || prompts.Load() != 2 {
+		t.Fatalf("expected 2 transcriptions and 2 prompts, got %d and %d", transcriptions.Load(), prompts.Load())
+	}
+
+	// unchanged files are not described again
+	runTestCommand(t, app, Init_describe_Command, "describe", "audio", "--database", "gai.sqlite", "--update-existing")
+
+	if transcriptions.Load() != 2 || prompts.Load() != 2 {
+		t.Errorf("expected no new requests, got %d transcriptions and %d prompts", transcriptions.Load(), prompts.Load())
+	}
+}
+
+// newTestDOCX creates a Word document with `text`.
+func newTestDOCX(t *testing.T, text string) []byte {
+	t.Helper()
+
+	return newTestZip(t,
+		testZipFile{Name: "[Content_Types].xml", Content: `<?xml version="1.0"?><Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types"/>`},
+		testZipFile{Name: "word/document.xml", Content: `<?xml version="1.0"?><w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main"><w:body><w:p><w:r><w:t>` + text + `</w:t></w:r></w:p></w:body></w:document>`},
+	)
+}
+
+// newTestPNG creates a PNG image with `width` x `height` pixels.
+func newTestPNG(t *testing.T, width int, height int) []byte {
+	t.Helper()
+
+	buff := &bytes.Buffer{}
+	err := png.Encode(buff, image.NewRGBA(image.Rect(0, 0, width, height)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buff.Bytes()
+}
+
+func TestDescribeFiles(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		system := messages[0].Content
+		if !strings.Contains(system, "in natural 'German' language") {
+			return "unexpected system prompt: " + system
+		}
+
+		user := messages[len(messages)-1].Content
+		title := "Unknown"
+		switch {
+		case strings.Contains(user, `'docs/report.pdf':`) && strings.Contains(user, "Quarterly numbers"):
+			title = "Report"
+		case strings.Contains(user, `'docs/letter.docx': "Dear customer"`):
+			title = "Letter"
+		case strings.HasPrefix(user, "This is the image file 'docs/photo.png'."):
+			title = "Photo"
+		}
+
+		return fmt.Sprintf(`{"file_information": {"summary": "About %s", "tags": ["a", "b", "a", " "], "title": "%s"}}`, title, title)
+	})
+
+	writeTestFile(t, app, "docs/report.pdf", string(newTestPDF("Quarterly numbers")))
+	writeTestFile(t, app, "docs/letter.docx", string(newTestDOCX(t, "Dear customer")))
+	writeTestFile(t, app, "docs/photo.png", string(newTestPNG(t, 2, 2)))
+	writeTestFile(t, app, "docs/random.bin", string([]byte{0, 1, 2, 3, 0, 0, 0, 255}))
+
+	app.FilePatterns = []string{"docs/*"}
+
+	runTestCommand(t, app, Init_describe_Command, "describe", "files", "--database", "gai.sqlite", "--language", "German")
+
+	lines := strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 lines, got %q", lines)
+	}
+
+	// files are sorted
+	expected := []struct {
+		filename string
+		mimeType string
+		title    string
+	}{
+		{"docs/letter.docx", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", "Letter"},
+		{"docs/photo.png", "image/png", "Photo"},
+		{"docs/random.bin", "", ""},
+		{"docs/report.pdf", "application/pdf", "Report"},
+	}
+	for i, e := range expected {
+		if e.title == "" {
+			// binary files without text
+			if !strings.HasPrefix(lines[i], "ERROR: ") || !strings.Contains(lines[i], e.filename) {
+				t.Errorf("expected error of %v, got %q", e.filename, lines[i])
+			}
+			continue
+		}
+
+		var description fileDescriptionResponse
+		err := json.Unmarshal([]byte(lines[i]), &description)
+		if err != nil {
+			t.Fatalf("invalid description %q: %v", lines[i], err)
+		}
+
+		if description.Filename != e.filename || description.MimeType != e.mimeType {
+			t.Errorf("unexpected file %q with mime type %q", description.Filename, description.MimeType)
+		}
+		if description.FileInformation.Title != e.title || description.FileInformation.Summary != "About "+e.title {
+			t.Errorf("%v: unexpected information %+v", e.filename, description.FileInformation)
+		}
+		if strings.Join(description.FileInformation.Tags, ",") != "a,b" {
+			t.Errorf("%v: expected unique tags, got %v", e.filename, description.FileInformation.Tags)
+		}
+	}
+
+	db, err := sql.Open("sqlite3", app.GetFullPath("gai.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var count int
+	err = db.QueryRow("SELECT COUNT(*) FROM documents WHERE title || ':' || tags IN ('Letter:a,b', 'Photo:a,b', 'Report:a,b');").Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 stored documents, got %d", count)
+	}
+}
+
+func TestDescribeImagesOnlyChangedContent(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	var prompts atomic.Int32
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		n := prompts.Add(1)
+
+		return fmt.Sprintf(`{"image_information": {"detailed_description": "Description %d", "tags": ["pixel"], "title": "Image %d"}}`, n, n)
+	})
+
+	aFile := writeTestFile(t, app, "a.png", string(newTestPNG(t, 2, 2)))
+	bFile := writeTestFile(t, app, "b.png", string(newTestPNG(t, 2, 2)))
+
+	app.FilePatterns = []string{"*.png"}
+
+	describeImages := func(args ...string) {
+		t.Helper()
+
+		runTestCommand(t, app, Init_describe_Command, append([]string{"describe", "images", "--database", "gai.sqlite"}, args...)...)
+	}
+
+	db, err := sql.Open("sqlite3", app.GetFullPath("gai.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	getTitles := func() string {
+		t.Helper()
+
+		rows, err := db.Query("SELECT file_path || '=' || title FROM images ORDER BY file_path;")
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer rows.Close()
+
+		titles := make([]string, 0)
+		for rows.Next() {
+			var title string
+			rows.Scan(&title)
+
+			titles = append(titles, title)
+		}
+
+		return strings.Join(titles, ",")
+	}
+
+	describeImages()
+	if titles := getTitles(); titles != "a.png=Image 1,b.png=Image 2" {
+		t.Fatalf("unexpected titles %q", titles)
+	}
+
+	// a.png is only touched and b.png gets new content
+	modTime := time.Now().Add(time.Hour)
+	err = os.Chtimes(aFile, modTime, modTime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(bFile, newTestPNG(t, 3, 3), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	describeImages("--update-existing")
+	if titles := getTitles(); titles != "a.png=Image 1,b.png=Image 3" {
+		t.Errorf("unexpected titles %q", titles)
+	}
+
+	// ... but the time of the unchanged file is updated
+	var lastModified string
+	err = db.QueryRow("SELECT last_modified FROM images WHERE file_path = 'a.png';").Scan(&lastModified)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastModified != modTime.UTC().Format(time.RFC3339) {
+		t.Errorf("expected last modified %v, got %v", modTime.UTC().Format(time.RFC3339), lastModified)
+	}
+
+	// existing entries are not updated by default
+	err = os.WriteFile(aFile, newTestPNG(t, 4, 4), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	describeImages()
+	if prompts.Load() != 3 {
+		t.Errorf("expected 3 prompts, got %d", prompts.Load())
+	}
+
+	// ... and all of them with --force-update
+	describeImages("--force-update")
+	if titles := getTitles(); titles != "a.png=Image 4,b.png=Image 5" {
+		t.Errorf("unexpected titles %q", titles)
+	}
+}
+
+func TestDescribeImagesBatch(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	imageCounts := make([]int, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		imageCounts = append(imageCounts, strings.Count(string(data), `"type":"image_url"`))
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+			// b.png is submitted without path
+			return `{"images": [
+  {"filename": "c.png", "image_information": {"detailed_description": "Third", "tags": ["pixel"], "title": "C"}},
+  {"filename": "a.png", "image_information": {"detailed_description": "First", "tags": ["pixel"], "title": "A"}},
+  {"filename": "b.png", "image_information": {"detailed_description": "Second", "tags": ["pixel"], "title": "B"}}
+]}`
+		})(w, r)
+	})
+	startTestOpenAIServer(t, app, mux)
+
+	for _, name := range []string{"a.png", "images/b.png", "c.png", "d.png"} {
+		writeTestFile(t, app, name, string(newTestPNG(t, 2, 2)))
+	}
+
+	app.FilePatterns = []string{"a.png", "images/b.png", "c.png"}
+
+	runTestCommand(t, app, Init_describe_Command, "describe", "images", "--batch-images", "3")
+
+	if len(imageCounts) != 1 || imageCounts[0] != 3 {
+		t.Fatalf("expected 1 request with 3 images, got %v", imageCounts)
+	}
+
+	lines := strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 descriptions, got %q", lines)
+	}
+
+	// in the order of the files
+	expected := []struct {
+		filename string
+		title    string
+	}{
+		{"a.png", "A"},
+		{"c.png", "C"},
+		{filepath.Join("images", "b.png"), "B"},
+	}
+	for i, e := range expected {
+		var description imageDescriptionResponse
+		err := json.Unmarshal([]byte(lines[i]), &description)
+		if err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+
+		if description.Filename != e.filename || description.ImageInformation.Title != e.title {
+			t.Errorf("line %d: expected %v with title %v, got %q", i, e.filename, e.title, lines[i])
+		}
+	}
+}
+
+func TestDescribeImagesConcurrency(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		prompt := messages[len(messages)-1].Content
+
+		names := []string{"c.png", "d.png"}
+		if strings.Contains(prompt, `"a.png"`) {
+			// first batch should be finished last
+			time.Sleep(100 * time.Millisecond)
+
+			names = []string{"a.png", "b.png"}
+		}
+
+		images := make([]string, 0, len(names))
+		for _, name := range names {
+			images = append(images, fmt.Sprintf(`{"filename": %q, "image_information": {"detailed_description": "Image", "tags": ["pixel"], "title": %q}}`, name, name))
+		}
+		return fmt.Sprintf(`{"images": [%s]}`, strings.Join(images, ","))
+	})
+
+	for _, name := range []string{"a.png", "b.png", "c.png", "d.png"} {
+		writeTestFile(t, app, name, string(newTestPNG(t, 2, 2)))
+	}
+
+	app.FilePatterns = []string{"*.png"}
+
+	runTestCommand(t, app, Init_describe_Command, "describe", "images", "--batch-images", "2", "--concurrency", "2")
+
+	lines := strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n")
+
+	// in the order of the files
+	titles := make([]string, 0, len(lines))
+	for i, line := range lines {
+		var description imageDescriptionResponse
+		err := json.Unmarshal([]byte(line), &description)
+		if err != nil {
+			t.Fatalf("line %d: %v", i, err)
+		}
+
+		titles = append(titles, description.ImageInformation.Title)
+	}
+
+	expected := []string{"a.png", "b.png", "c.png", "d.png"}
+	if !slices.Equal(titles, expected) {
+		t.Errorf("expected %v, got %v", expected, titles)
+	}
+}
+
+func TestDescribeImagesStdinBinary(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	bodies := make([]string, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+			return `{"image_information": {"detailed_description": "A pixel", "tags": ["pixel"], "title": "Pixel"}}`
+		})(w, r)
+	})
+	startTestOpenAIServer(t, app, mux)
+
+	writeTestStdin(t, app, string(newTestPNG(t, 1, 1)))
+
+	runTestCommand(t, app, Init_describe_Command, "describe", "images", "--stdin-binary")
+
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"type":"image_url"`) {
+		t.Fatalf("expected 1 request with image, got %q", bodies)
+	}
+
+	var description imageDescriptionResponse
+	err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &description)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if description.Filename != "stdin.png" || description.ImageInformation.Title != "Pixel" {
+		t.Errorf("unexpected description %+v", description)
+	}
+}
+
+func TestNormalizeTags(t *testing.T) {
+	tests := []struct {
+		tags          []string
+		minTags       uint16
+		maxTags       uint16
+		expected      []string
+		expectedError bool
+	}{
+		{[]string{"a", "b"}, 1, 10, []string{"a", "b"}, false},
+		{[]string{" a ", "", "b", "a", "c", "d"}, 1, 3, []string{"a", "b", "c"}, false},
+		{[]string{"a", "a", " "}, 2, 10, []string{"a"}, true},
+		{[]string{}, 0, 10, []string{}, false},
+	}
+
+	for i, test := range tests {
+		tags, err := normalizeTags(test.tags, test.minTags, test.maxTags)
+
+		if strings.Join(tags, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%d: expected tags %q, got %q", i, test.expected, tags)
+		}
+		if test.expectedError && types.GetErrorType(err) != types.ErrorTypeSchemaValidation {
+			t.Errorf("%d: expected schema validation error, got %v", i, err)
+		} else if !test.expectedError && err != nil {
+			t.Errorf("%d: %v", i, err)
+		}
+	}
+}
+
+func TestDescribeImagesMaxTags(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	bodies := make([]string, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+			// model does not respect the schema
+			return `{"image_information": {"detailed_description": "A pixel", "tags": ["red", "", "pixel", "red", "tiny", "square"], "title": "Pixel"}}`
+		})(w, r)
+	})
+	startTestOpenAIServer(t, app, mux)
+
+	writeTestFile(t, app, "pixel.png", string(newTestPNG(t, 1, 1)))
+	app.FilePatterns = []string{"*.png"}
+
+	runTestCommand(t, app, Init_describe_Command, "describe", "images", "--database", "gai.sqlite", "--max-tags", "3", "--no-validate")
+
+	if len(bodies) != 1 || !strings.Contains(bodies[0], `"maxItems":3`) || !strings.Contains(bodies[0], `"minItems":1`) {
+		t.Fatalf("expected tag limits in schema of request, got %q", bodies)
+	}
+
+	db, err := sql.Open("sqlite3", app.GetFullPath("gai.sqlite"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	var tags string
+	err = db.QueryRow("SELECT tags FROM images WHERE file_path = 'pixel.png';").Scan(&tags)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if tags != "red,pixel,tiny" {
+		t.Errorf("unexpected stored tags %q", tags)
+	}
+}
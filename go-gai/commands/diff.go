@@ -0,0 +1,297 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+// Init_diff_Command initializes the `diff` command.
+func Init_diff_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var maxDiffBytes int
+	var maxTotalDiffBytes int
+	var review bool
+	var staged bool
+
+	var diffCmd = &cobra.Command{
+		Use:     "diff [ref] [other ref]",
+		Aliases: []string{"di"},
+		Short:   "Explain git diff",
+		Long:    `Explains the changes of the working tree, the staging area (--staged), a commit or between two refs in plain English.`,
+		Args:    cobra.MaximumNArgs(2),
+		Run: func(cmd *cobra.Command, args []string) {
+			startTime := app.GetISOTime()
+
+			if staged && len(args) > 0 {
+				app.CheckIfError(types.NewTypedError(
+					types.ErrorTypeUsage,
+					errors.New("--staged cannot be combined with refs"),
+				))
+			}
+
+			git, err := app.NewGitClient()
+			app.CheckIfError(err)
+
+			diffArgs := make([]string, 0)
+			what := "the changes of my working tree, which are not staged yet"
+			if staged {
+				diffArgs = append(diffArgs, "--cached")
+				what = "the staged changes"
+			} else if len(args) == 1 {
+				// the own changes of the commit, compared to its parent
+				commitArgs, err := getDiffArgsOfCommit(git, args[0])
+				app.CheckIfError(err)
+
+				diffArgs = append(diffArgs, commitArgs...)
+				what = fmt.Sprintf("the changes of the commit '%s'", args[0])
+			} else if len(args) == 2 {
+				diffArgs = append(diffArgs, args[0], args[1])
+				what = fmt.Sprintf("the changes between '%s' and '%s'", args[0], args[1])
+			}
+
+			app.Dbgf("Running 'git diff %s' ...%s", strings.Join(diffArgs, " "), app.EOL)
+
+			_, changedFiles, err := git.Diff(diffArgs...)
+			app.CheckIfError(err)
+
+			if len(changedFiles) == 0 {
+				app.CheckIfError(errors.New("no changes found"))
+			}
+
+			app.Dbgf("Found %d changed files%s", len(changedFiles), app.EOL)
+
+			app.InitAI()
+
+			model := app.AI.ChatModel()
+
+			startEmpty := true
+
+			contextOptions := make([]types.NewChatContextOptions, 0)
+			contextOptions = append(contextOptions, types.NewChatContextOptions{
+				StartEmpty: &startEmpty,
+			})
+
+			chat, err := app.NewChatContext(contextOptions...)
+			app.CheckIfError(err)
+
+			pseudoOptions := types.AppendSimplePseudoUserConversationOptions{
+				Model: &model,
+				Time:  &startTime,
+			}
+
+			submittedDiffBytes := 0
+			truncateDiff := func(name string, diff string) string {
+				maxBytes := -1 // no limit
+				if maxDiffBytes > 0 {
+					maxBytes = maxDiffBytes
+				}
+				if maxTotalDiffBytes > 0 {
+					remainingBytes := max(maxTotalDiffBytes-submittedDiffBytes, 0)
+					if maxBytes < 0 || remainingBytes < maxBytes {
+						maxBytes = remainingBytes
+					}
+				}
+
+				truncatedDiff, truncated := utils.TruncateText(diff, maxBytes)
+				if truncated > 0 {
+					app.Dbgf("Truncated %d bytes of diff of '%s'%s", truncated, name, app.EOL)
+				}
+
+				submittedDiffBytes += len(diff) - truncated
+
+				return truncatedDiff
+			}
+
+			approximateSubmittedTextSize := uint64(0)
+			approximateSubmittedText := ""
+
+			chat.AppendSimplePseudoUserConversation(fmt.Sprintf(
+				`I will start by submitting the diff of each changed file of %s as serialized JSON string.
+Answer with 'OK' if you understand this.`,
+				what,
+			), pseudoOptions)
+			for i, cf := range changedFiles {
+				diff, err := git.DiffFile(cf.Name(), diffArgs...)
+				app.CheckIfError(err)
+
+				diff = truncateDiff(cf.Name(), diff)
+
+				if app.DryRun {
+					app.Writeln(fmt.Sprintf("Changed file: %s", cf.Name()))
+					app.Writeln(fmt.Sprintf("\tStatus: %s", cf.ChangeStatus()))
+					app.Writeln(fmt.Sprintf("\tSize: %d", len(diff)))
+				} else {
+					app.Dbgf("Changed file: '%s' (%s)%s", cf.Name(), cf.ChangeStatus(), app.EOL)
+				}
+
+				approximateSubmittedTextSize += uint64(len(diff))
+				approximateSubmittedText += diff
+
+				messageSuffix := ""
+				if i > 0 {
+					messageSuffix = " and integrate it with the context of the other changed files"
+				}
+
+				status := getDiffChangeStatusDescription(cf.ChangeStatus())
+
+				if strings.TrimSpace(diff) == "" {
+					chat.AppendSimplePseudoUserConversation(fmt.Sprintf(
+						`The file with the path '%s' has been %s without changes of its content.
+Answer with 'OK' if you analyzed it%v.`,
+						cf.Name(),
+						status,
+						messageSuffix,
+					), pseudoOptions)
+					continue
+				}
+
+				jsonData, err := json.Marshal(&diff)
+				app.CheckIfError(err)
+
+				chat.AppendSimplePseudoUserConversation(fmt.Sprintf(
+					`This is the diff of the %s file with the path '%s': %s.
+Answer with 'OK' if you analyzed it%v.`,
+					status,
+					cf.Name(),
+					jsonData,
+					messageSuffix,
+				), pseudoOptions)
+			}
+
+			if app.DryRun {
+				err := app.OutputUsageEstimate(approximateSubmittedText, approximateSubmittedTextSize, 0)
+				app.CheckIfError(err)
+
+				app.Writeln("Stop here because of dry run mode.")
+
+				os.Exit(0)
+			}
+
+			outputLanguage := strings.TrimSpace(app.OutputLanguage)
+
+			lang := "english"
+			if outputLanguage != "" {
+				lang = outputLanguage
+			}
+
+			var systemPrompt string
+			var lastMessage string
+			if review {
+				systemPrompt = fmt.Sprintf(`You are an experienced software developer, who reviews code changes.
+For the submitted diffs:
+- Start with a short summary of what has been changed.
+- List possible bugs, like logic errors, missing error handling, race conditions, security issues or breaking changes, each with the affected file, a severity (low, medium or high) and a suggestion how to fix it.
+- If you do not find any possible bug, say so instead of inventing one.
+Format your answer as Markdown and write everything in natural '%s' language.
+Be objective and accurate. Only report what can be verified from the changes.`, lang)
+
+				lastMessage = "OK, this was the last file. Now review the changes and flag possible bugs."
+			} else {
+				systemPrompt = fmt.Sprintf(`You are an experienced software developer, who explains code changes to other developers.
+For the submitted diffs:
+- Explain what has been changed in plain words, grouped by topic instead of by file, if possible.
+- Explain why these changes might matter, like changed behavior, new features or possible side effects.
+Format your answer as Markdown and write everything in natural '%s' language.
+Be objective and accurate. Only describe what can be verified from the changes.`, lang)
+
+				lastMessage = "OK, this was the last file. Now explain what changed and why it might matter."
+			}
+
+			doNotSaveConversation := true
+
+			chatOptions := make([]types.AIClientChatOptions, 0)
+			chatOptions = append(chatOptions, types.AIClientChatOptions{
+				NoSave:       &doNotSaveConversation,
+				SystemPrompt: &systemPrompt,
+			})
+			answer, conversation, err := app.ChatAndValidate(chat, lastMessage, chatOptions...)
+			app.CheckIfError(err)
+
+			app.OutputAIAnswer(answer)
+			app.OutputAIUsageOf(conversation)
+		},
+	}
+
+	app.WithDryRunCliFlags(diffCmd)
+	app.WithHighlightCLIFlags(diffCmd)
+	app.WithLanguageCLIFlags(diffCmd)
+	app.WithPseudoConversationCLIFlags(diffCmd)
+	diffCmd.Flags().IntVarP(&maxDiffBytes, "max-diff-bytes", "", 0, "maximum number of bytes of the diff of each file (0 for no limit)")
+	diffCmd.Flags().IntVarP(&maxTotalDiffBytes, "max-total-diff-bytes", "", 0, "maximum number of bytes of the diffs of all files (0 for no limit)")
+	diffCmd.Flags().BoolVarP(&review, "review", "", false, "review the changes and flag possible bugs")
+	diffCmd.Flags().BoolVarP(&staged, "staged", "", false, "explain the staged changes")
+
+	parentCmd.AddCommand(
+		diffCmd,
+	)
+}
+
+// getDiffArgsOfCommit returns the arguments for `git diff` to get the own
+// changes of the commit `ref`, which are compared with the empty tree,
+// if it is a root commit without parent.
+func getDiffArgsOfCommit(git *types.GitClient, ref string) ([]string, error) {
+	err := git.CreateExecCommand("git", "rev-parse", "--verify", "--quiet", fmt.Sprintf("%s^{commit}", ref)).Run()
+	if err != nil {
+		return nil, fmt.Errorf("unknown commit '%s'", ref)
+	}
+
+	err = git.CreateExecCommand("git", "rev-parse", "--verify", "--quiet", fmt.Sprintf("%s^", ref)).Run()
+	if err == nil {
+		return []string{fmt.Sprintf("%s^!", ref)}, nil
+	}
+
+	// root commit
+	output, err := git.CreateExecCommand("git", "hash-object", "-t", "tree", "--stdin").Output()
+	if err != nil {
+		return nil, err
+	}
+
+	return []string{strings.TrimSpace(string(output)), ref}, nil
+}
+
+// getDiffChangeStatusDescription returns the description of
+// a change status of `git diff --name-status`, like `A` or `M`.
+func getDiffChangeStatusDescription(status string) string {
+	switch status {
+	case "A":
+		return "added"
+	case "C":
+		return "copied"
+	case "D":
+		return "deleted"
+	case "R":
+		return "renamed"
+	case "T":
+		return "type changed"
+	}
+
+	return "modified"
+}
@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+const sampleEnvFile = `# settings and secrets of gAI
+# see https://github.com/mkloubert/gai#environment-variables
+
+# OPENAI_API_KEY=
+# GEMINI_API_KEY=
+# AZURE_OPENAI_API_KEY=
+# AZURE_OPENAI_ENDPOINT=
+# GAI_DEFAULT_CHAT_MODEL=openai:gpt-4o-mini
+`
+
+const sampleRCFile = `# settings of gAI for this directory
+# see https://github.com/mkloubert/gai#commands-and-sub-commands
+
+# defaults:
+#   flags:
+#     model: openai:gpt-4o-mini
+#     history-limit: 10
+#     exclude:
+#       - "vendor/**"
+`
+
+type doctorCheck struct {
+	Message string `json:"message"`
+	Name    string `json:"name"`
+	Path    string `json:"path,omitempty"`
+	Status  string `json:"status"`
+}
+
+func Init_doctor_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var fix bool
+	var force bool
+
+	var doctorCmd = &cobra.Command{
+		Use:     "doctor",
+		Aliases: []string{"doc"},
+		Short:   "Check setup",
+		Long:    `Checks the app directory, the sample config files and the permissions of secret files and optionally fixes them.`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			checks := make([]doctorCheck, 0)
+			addCheck := func(name string, path string, status string, message string) {
+				checks = append(checks, doctorCheck{
+					Message: message,
+					Name:    name,
+					Path:    path,
+					Status:  status,
+				})
+			}
+
+			// app directory
+			appDir := filepath.Join(app.HomeDirectory, ".gai")
+			if stat, err := os.Stat(appDir); err == nil && stat.IsDir() {
+				addCheck("app-dir", appDir, "ok", "exists")
+			} else if err != nil && !os.IsNotExist(err) {
+				app.CheckIfError(err)
+			} else if fix {
+				_, err := app.EnsureAppDir()
+				app.CheckIfError(err)
+
+				addCheck("app-dir", appDir, "fixed", "created")
+			} else {
+				addCheck("app-dir", appDir, "missing", "does not exist")
+			}
+
+			// sample files
+			rcFile, err := app.GetRCFilePath()
+			app.CheckIfError(err)
+
+			sampleFiles := []struct {
+				content string
+				mode    os.FileMode
+				name    string
+				path    string
+			}{
+				{sampleEnvFile, 0600, "env-file", filepath.Join(appDir, ".env")},
+				{sampleRCFile, 0644, "rc-file", rcFile},
+			}
+			for _, sf := range sampleFiles {
+				_, err := os.Stat(sf.path)
+				exists := err == nil
+				if err != nil && !os.IsNotExist(err) {
+					app.CheckIfError(err)
+				}
+
+				if exists && !(fix && force) {
+					addCheck(sf.name, sf.path, "ok", "exists")
+					continue
+				}
+				if !fix {
+					addCheck(sf.name, sf.path, "missing", "does not exist")
+					continue
+				}
+
+				if _, err := os.Stat(filepath.Dir(sf.path)); os.IsNotExist(err) {
+					// the app directory can only be missing here,
+					// if it could not be created
+					addCheck(sf.name, sf.path, "missing", "directory does not exist")
+					continue
+				}
+
+				err = os.WriteFile(sf.path, []byte(sf.content), sf.mode)
+				app.CheckIfError(err)
+				err = os.Chmod(sf.path, sf.mode) // WriteFile does not change mode of existing files
+				app.CheckIfError(err)
+
+				if exists {
+					addCheck(sf.name, sf.path, "fixed", "overwritten with sample")
+				} else {
+					addCheck(sf.name, sf.path, "fixed", "sample created")
+				}
+			}
+
+			// permissions of secret files
+			if runtime.GOOS != "windows" {
+				secretFiles := app.GetDefaultEnvFiles(appDir)
+				for _, f := range app.EnvFiles {
+					if !filepath.IsAbs(f) {
+						f = filepath.Join(app.WorkingDirectory, f)
+					}
+					secretFiles = append(secretFiles, f)
+				}
+
+				checked := map[string]bool{}
+				for _, f := range secretFiles {
+					if checked[f] {
+						continue
+					}
+					checked[f] = true
+
+					stat, err := os.Stat(f)
+					if err != nil {
+						if os.IsNotExist(err) {
+							continue
+						}
+						app.CheckIfError(err)
+					}
+					if stat.IsDir() {
+						continue
+					}
+
+					mode := stat.Mode().Perm()
+					if mode&0077 == 0 {
+						addCheck("permissions", f, "ok", fmt.Sprintf("%04o", mode))
+					} else if fix {
+						err := os.Chmod(f, 0600)
+						app.CheckIfError(err)
+
+						addCheck("permissions", f, "fixed", fmt.Sprintf("changed from %04o to 0600", mode))
+					} else {
+						addCheck("permissions", f, "warn", fmt.Sprintf("%04o, readable by others", mode))
+					}
+				}
+			}
+
+			problems := 0
+			for _, c := range checks {
+				if c.Status == "missing" || c.Status == "warn" {
+					problems++
+				}
+			}
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(checks, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+			} else {
+				for _, c := range checks {
+					app.Writeln(fmt.Sprintf("%s\t%s\t%s\t%s", c.Status, c.Name, c.Path, c.Message))
+				}
+			}
+
+			if problems > 0 && !fix {
+				app.WriteErrorString(fmt.Sprintf("%d problem(s) found, run 'gai doctor --fix' to fix them%s", problems, app.EOL))
+			}
+		},
+	}
+
+	doctorCmd.Flags().BoolVarP(&fix, "fix", "", false, "create missing directories and sample config files and secure permissions of secret files")
+	doctorCmd.Flags().BoolVarP(&force, "force", "", false, "overwrite existing sample config files with --fix")
+
+	parentCmd.AddCommand(
+		doctorCmd,
+	)
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+// runTestDoctor runs `doctor` with `args` and returns the checks
+// as `status name message` strings.
+func runTestDoctor(t *testing.T, app *types.AppContext, args ...string) []string {
+	t.Helper()
+
+	app.JSONOutput = true
+	app.Stdout = newTestFile(t, t.TempDir(), "stdout")
+
+	runTestCommand(t, app, Init_doctor_Command, append([]string{"doctor"}, args...)...)
+
+	var checks []doctorCheck
+	err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &checks)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := make([]string, 0)
+	for _, c := range checks {
+		result = append(result, fmt.Sprintf("%s %s %s", c.Status, c.Name, c.Message))
+	}
+
+	return result
+}
+
+func TestDoctorFix(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("permissions are not checked on Windows")
+	}
+
+	app := newTestApp(t, map[string]string{})
+
+	envFile := filepath.Join(app.HomeDirectory, ".gai", ".env")
+	rcFile := filepath.Join(app.WorkingDirectory, ".gairc.yaml")
+
+	steps := []struct {
+		args     []string
+		expected []string
+	}{
+		{
+			[]string{},
+			[]string{"missing app-dir does not exist", "missing env-file does not exist", "missing rc-file does not exist"},
+		},
+		{
+			[]string{"--fix"},
+			[]string{"fixed app-dir created", "fixed env-file sample created", "fixed rc-file sample created", "ok permissions 0600"},
+		},
+		// idempotent
+		{
+			[]string{"--fix"},
+			[]string{"ok app-dir exists", "ok env-file exists", "ok rc-file exists", "ok permissions 0600"},
+		},
+	}
+
+	for i, step := range steps {
+		checks := runTestDoctor(t, app, step.args...)
+
+		if strings.Join(checks, "\n") != strings.Join(step.expected, "\n") {
+			t.Fatalf("step %d: expected %q, got %q", i+1, step.expected, checks)
+		}
+	}
+
+	for file, expected := range map[string]string{envFile: sampleEnvFile, rcFile: sampleRCFile} {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != expected {
+			t.Errorf("%v: expected sample %q, got %q", file, expected, string(data))
+		}
+	}
+
+	// own settings with insecure permissions
+	err := os.WriteFile(envFile, []byte("OPENAI_API_KEY=secret\n"), 0644)
+	if err == nil {
+		err = os.Chmod(envFile, 0644)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	checks := runTestDoctor(t, app)
+	if checks[len(checks)-1] != "warn permissions 0644, readable by others" {
+		t.Errorf("expected warning about permissions, got %q", checks)
+	}
+
+	checks = runTestDoctor(t, app, "--fix")
+	if checks[len(checks)-1] != "fixed permissions changed from 0644 to 0600" {
+		t.Errorf("expected fixed permissions, got %q", checks)
+	}
+
+	data, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "OPENAI_API_KEY=secret\n" {
+		t.Errorf("expected own settings to be kept without --force, got %q", string(data))
+	}
+
+	checks = runTestDoctor(t, app, "--fix", "--force")
+	if checks[1] != "fixed env-file overwritten with sample" {
+		t.Errorf("expected overwritten env file, got %q", checks)
+	}
+}
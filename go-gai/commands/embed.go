@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+// defaultEmbedMaxFileTokens is the default maximum number of tokens
+// of a text, before it is split into chunks with one embedding each.
+const defaultEmbedMaxFileTokens = 8000
+
+type embedResult struct {
+	Chunk     *int      `json:"chunk,omitempty"`
+	Embedding []float32 `json:"embedding"`
+	Model     string    `json:"model"`
+	Source    string    `json:"source"`
+}
+
+type embedSource struct {
+	chunk *int
+	name  string
+}
+
+// Init_embed_Command initializes the `embed` command.
+func Init_embed_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var chunks chunkSettings
+
+	var embedCmd = &cobra.Command{
+		Use:     "embed",
+		Aliases: []string{"emb"},
+		Short:   "Create embeddings",
+		Long:    `Creates embedding vectors of files as defined in --file and --files flags and/or data from STDIN and outputs them as JSON lines. Large texts are split into chunks, which get their own embeddings with their index in the output.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			model := app.InitEmbedAI()
+
+			files, err := app.GetFiles()
+			app.CheckIfError(err)
+
+			maxFileTokens, strategy, err := getChunkOptions(app, cmd, &chunks)
+			app.CheckIfError(err)
+
+			tokenizer := utils.NewTextTokenizer(model)
+
+			sources := make([]embedSource, 0)
+			texts := make([]string, 0)
+
+			// large texts are split into chunks with own embeddings
+			appendSource := func(name string, data []byte) {
+				text, err := utils.EnsurePlainText(data)
+				app.CheckIfError(err)
+
+				textChunks := utils.Chunk(text, utils.ChunkOptions{
+					MaxSize:   maxFileTokens,
+					Name:      name,
+					Strategy:  strategy,
+					Tokenizer: tokenizer,
+				})
+				if len(textChunks) == 1 {
+					sources = append(sources, embedSource{name: name})
+					texts = append(texts, text)
+					return
+				}
+
+				app.Dbgf("Embedding '%v' in %v chunks ...%v", name, len(textChunks), app.EOL)
+
+				for i, chunk := range textChunks {
+					sources = append(sources, embedSource{chunk: &i, name: name})
+					texts = append(texts, chunk)
+				}
+			}
+
+			for _, f := range files {
+				data, err := os.ReadFile(f)
+				app.CheckIfError(err)
+
+				name, err := filepath.Rel(app.WorkingDirectory, f)
+				if err != nil {
+					name = f
+				}
+
+				appendSource(name, data)
+			}
+
+			// check if standard input has been piped
+			stdinStat, _ := app.Stdin.Stat()
+			if (stdinStat.Mode() & os.ModeCharDevice) == 0 {
+				data, err := io.ReadAll(app.Stdin)
+				app.CheckIfError(err)
+
+				if len(data) > 0 {
+					appendSource("<stdin>", data)
+				}
+			}
+
+			if len(texts) == 0 {
+				app.CheckIfError(errors.New("no files or STDIN data found"))
+			}
+
+			options := types.AIClientEmbedOptions{
+				Model: &model,
+			}
+
+			// creates the embeddings of `texts` and splits
+			// requests, which are too large, into smaller ones
+			var embedTexts func(texts []string) (types.AIClientEmbedResponse, error)
+			embedTexts = func(texts []string) (types.AIClientEmbedResponse, error) {
+				response, err := app.AI.Embed(texts, options)
+				if err == nil || len(texts) < 2 || types.GetErrorType(err) != types.ErrorTypeRequestTooLarge {
+					return response, err
+				}
+
+				app.Dbgf("Request with %d texts is too large, splitting it ...%v", len(texts), app.EOL)
+
+				half := len(texts) / 2
+
+				response, err = embedTexts(texts[:half])
+				if err != nil {
+					return response, err
+				}
+
+				secondResponse, err := embedTexts(texts[half:])
+				response.Embeddings = append(response.Embeddings, secondResponse.Embeddings...)
+
+				return response, err
+			}
+
+			response, err := embedTexts(texts)
+			app.CheckIfError(err)
+
+			if len(response.Embeddings) != len(texts) {
+				app.CheckIfError(fmt.Errorf("expected %v embeddings, but got %v", len(texts), len(response.Embeddings)))
+			}
+
+			for i, embedding := range response.Embeddings {
+				jsonData, err := json.Marshal(&embedResult{
+					Chunk:     sources[i].chunk,
+					Embedding: embedding,
+					Model:     response.Model,
+					Source:    sources[i].name,
+				})
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+			}
+		},
+	}
+
+	withChunkCLIFlags(embedCmd, &chunks, defaultEmbedMaxFileTokens)
+
+	app.WithCurlCLIFlags(embedCmd)
+
+	parentCmd.AddCommand(
+		embedCmd,
+	)
+}
@@ -0,0 +1,206 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestEmbed(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	requests := make([][]string, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input []string `json:"input"`
+			Model string   `json:"model"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil || body.Model != "custom-embedding" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, body.Input)
+
+		if len(body.Input) > 2 {
+			http.Error(w, "too large", http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		// in reverse order to check the indexes
+		data := make([]any, 0)
+		for i := len(body.Input) - 1; i >= 0; i-- {
+			data = append(data, map[string]any{
+				"embedding": []float32{float32(len(body.Input[i])), 0.5},
+				"index":     i,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"data":  data,
+			"model": body.Model,
+		})
+	})
+	startTestOpenAIServer(t, app, mux)
+	app.Model = "custom-embedding"
+
+	writeTestFile(t, app, "a.txt", "a")
+	writeTestFile(t, app, "docs/b.txt", "bb")
+	writeTestStdin(t, app, "ccc")
+
+	app.Files = []string{"a.txt", "docs/b.txt"}
+
+	runTestCommand(t, app, Init_embed_Command, "embed")
+
+	// first request is split into two
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	if len(requests[1]) != 1 || len(requests[2]) != 2 {
+		t.Errorf("unexpected requests after splitting %v", requests)
+	}
+
+	expected := []string{
+		`{"embedding":[1,0.5],"model":"custom-embedding","source":"a.txt"}`,
+		`{"embedding":[2,0.5],"model":"custom-embedding","source":"docs/b.txt"}`,
+		`{"embedding":[3,0.5],"model":"custom-embedding","source":"\u003cstdin\u003e"}`,
+	}
+
+	output := strings.TrimSpace(readTestOutput(t, app.Stdout))
+	if output != strings.Join(expected, "\n") {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
+func TestEmbedChunks(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	inputs := make([]string, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/embeddings", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Input []string `json:"input"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		inputs = append(inputs, body.Input...)
+
+		data := make([]any, 0)
+		for i, input := range body.Input {
+			data = append(data, map[string]any{
+				"embedding": []float32{float32(len(input))},
+				"index":     i,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"data":  data,
+			"model": "text-embedding-3-small",
+		})
+	})
+	startTestOpenAIServer(t, app, mux)
+	app.Model = ""
+
+	writeTestFile(t, app, "a.txt", "0123456789abc")
+	writeTestFile(t, app, "b.txt", "short")
+
+	app.Files = []string{"a.txt", "b.txt"}
+
+	runTestCommand(t, app, Init_embed_Command, "embed", "--chunk-strategy", "chars", "--max-file-tokens", "5")
+
+	expectedInputs := []string{"01234", "56789", "abc", "short"}
+	if strings.Join(inputs, ",") != strings.Join(expectedInputs, ",") {
+		t.Errorf("expected inputs %q, got %q", expectedInputs, inputs)
+	}
+
+	// only chunks of split texts have an index
+	expected := []string{
+		`{"chunk":0,"embedding":[5],"model":"text-embedding-3-small","source":"a.txt"}`,
+		`{"chunk":1,"embedding":[5],"model":"text-embedding-3-small","source":"a.txt"}`,
+		`{"chunk":2,"embedding":[3],"model":"text-embedding-3-small","source":"a.txt"}`,
+		`{"embedding":[5],"model":"text-embedding-3-small","source":"b.txt"}`,
+	}
+
+	output := strings.TrimSpace(readTestOutput(t, app.Stdout))
+	if output != strings.Join(expected, "\n") {
+		t.Errorf("unexpected output %q", output)
+	}
+}
+
+func TestEmbedModel(t *testing.T) {
+	tests := []struct {
+		flag          string
+		env           string
+		expectedModel string
+	}{
+		{"", "", "text-embedding-3-small"},
+		{"", "text-embedding-3-large", "text-embedding-3-large"},
+		{"openai:custom-embedding", "text-embedding-3-large", "custom-embedding"},
+	}
+
+	for _, test := range tests {
+		// no chat model is required
+		app := newTestApp(t, map[string]string{
+			"GAI_EMBED_MODEL": test.env,
+			"OPENAI_API_KEY":  "test",
+		})
+
+		models := make([]string, 0)
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var body struct {
+				Model string `json:"model"`
+			}
+			json.NewDecoder(r.Body).Decode(&body)
+			models = append(models, body.Model)
+
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": []any{map[string]any{"embedding": []float32{1}, "index": 0}},
+			})
+		}))
+		t.Cleanup(server.Close)
+
+		app.BaseUrl = server.URL
+		app.Model = test.flag
+
+		writeTestStdin(t, app, "text")
+
+		runTestCommand(t, app, Init_embed_Command, "embed")
+
+		if len(models) != 1 || models[0] != test.expectedModel {
+			t.Errorf("flag %q, env %q: expected model %q, got %v", test.flag, test.env, test.expectedModel, models)
+		}
+
+		expected := fmt.Sprintf(`{"embedding":[1],"model":%q,"source":"\u003cstdin\u003e"}`, test.expectedModel)
+		if output := strings.TrimSpace(readTestOutput(t, app.Stdout)); output != expected {
+			t.Errorf("flag %q, env %q: expected output %q, got %q", test.flag, test.env, expected, output)
+		}
+	}
+}
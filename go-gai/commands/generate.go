@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+func init_generate_image_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var count int
+	var imageModel string
+	var output string
+	var size string
+
+	var generateImageCmd = &cobra.Command{
+		Use:     "image [PROMPT]",
+		Aliases: []string{"images", "img", "i"},
+		Short:   "Generate image",
+		Long:    `Generates one or more images from a prompt.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			app.InitAI()
+
+			prompt, err := app.GetInput(args)
+			app.CheckIfError(err)
+
+			prompt = strings.TrimSpace(prompt)
+			if prompt == "" {
+				app.CheckIfError(errors.New("no prompt defined"))
+			}
+
+			model := strings.TrimSpace(imageModel)
+			if model == "" {
+				model = strings.TrimSpace(app.GetEnv("GAI_IMAGE_MODEL"))
+			}
+
+			options := types.AIClientGenerateImageOptions{
+				Count: &count,
+				Size:  &size,
+			}
+			if model != "" {
+				options.Model = &model
+			}
+
+			response, err := app.AI.GenerateImage(prompt, options)
+			app.CheckIfError(err)
+
+			now := time.Now()
+			for i, data := range response.Images {
+				fileExt := utils.GetImageFileExtension(utils.DetectMime(data))
+				if fileExt == "" {
+					fileExt = ".png"
+				}
+
+				outputFile := getGeneratedImageFilePath(app, output, i, len(response.Images), fileExt, now)
+
+				app.Dbgf("Writing image to '%v' ...%v", outputFile, app.EOL)
+
+				err := os.MkdirAll(filepath.Dir(outputFile), 0755)
+				app.CheckIfError(err)
+
+				err = os.WriteFile(outputFile, data, 0644)
+				app.CheckIfError(err)
+
+				app.Writeln(outputFile)
+			}
+		},
+	}
+
+	generateImageCmd.Flags().IntVarP(&count, "count", "n", 1, "number of images to generate")
+	generateImageCmd.Flags().StringVarP(&imageModel, "image-model", "", "", "custom image model, like dall-e-3 or gpt-image-1")
+	generateImageCmd.Flags().StringVarP(&output, "output", "o", "", "output directory or file template with {index} and {ext} placeholders")
+	generateImageCmd.Flags().StringVarP(&size, "size", "", "", "size of the images, like 1024x1024")
+
+	app.WithCurlCLIFlags(generateImageCmd)
+
+	parentCmd.AddCommand(
+		generateImageCmd,
+	)
+}
+
+// getGeneratedImageFilePath returns the path of the file for the
+// generated image at the zero-based `index` based on `output`,
+// which is a directory or a file template.
+func getGeneratedImageFilePath(app *types.AppContext, output string, index int, count int, fileExt string, now time.Time) string {
+	output = strings.TrimSpace(output)
+
+	isDir := output == "" || strings.HasSuffix(output, "/") || strings.HasSuffix(output, string(os.PathSeparator))
+
+	if output != "" && !filepath.IsAbs(output) {
+		output = filepath.Join(app.WorkingDirectory, output)
+	}
+	if output == "" {
+		output = app.WorkingDirectory
+	}
+
+	if !isDir {
+		info, err := os.Stat(output)
+		isDir = err == nil && info.IsDir()
+	}
+
+	if isDir {
+		filename := fmt.Sprintf("image-%s", now.Format("20060102-150405"))
+		if count > 1 {
+			filename += fmt.Sprintf("-%d", index+1)
+		}
+
+		return filepath.Join(output, filename+fileExt)
+	}
+
+	// file template
+	hasIndex := strings.Contains(output, "{index}")
+	hasExt := strings.Contains(output, "{ext}")
+
+	outputFile := strings.ReplaceAll(output, "{index}", fmt.Sprintf("%d", index+1))
+	outputFile = strings.ReplaceAll(outputFile, "{ext}", strings.TrimPrefix(fileExt, "."))
+
+	currentExt := filepath.Ext(outputFile)
+	if !hasIndex && count > 1 {
+		// keep files unique
+		outputFile = fmt.Sprintf("%s-%d%s", strings.TrimSuffix(outputFile, currentExt), index+1, currentExt)
+	}
+	if !hasExt && currentExt == "" {
+		outputFile += fileExt
+	}
+
+	return outputFile
+}
+
+// Init_generate_Command initializes the `generate` command.
+func Init_generate_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var generateCmd = &cobra.Command{
+		Use:     "generate [resource]",
+		Aliases: []string{"gen", "g"},
+		Short:   "Generate",
+		Long:    `Generates a resource.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	init_generate_image_Command(app, generateCmd)
+
+	parentCmd.AddCommand(
+		generateCmd,
+	)
+}
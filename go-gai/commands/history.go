@@ -0,0 +1,175 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+// historySnippetLength is the maximum number of characters of the
+// snippet of a message in the output of `history`.
+const historySnippetLength = 80
+
+type historyEntry struct {
+	Context   string `json:"context"`
+	Directory string `json:"directory"`
+	Role      string `json:"role"`
+	Snippet   string `json:"snippet"`
+	Time      string `json:"time"`
+
+	time time.Time
+}
+
+// Init_history_Command initializes the `history` command.
+func Init_history_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var limit int
+	var since string
+	var until string
+
+	var historyCmd = &cobra.Command{
+		Use:     "history",
+		Aliases: []string{"hist"},
+		Short:   "Show history",
+		Long:    `Lists the latest messages of all contexts of all directories, newest first.`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			if limit < 0 {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--limit must not be negative")))
+			}
+
+			parseTime := func(name string, value string) *time.Time {
+				if strings.TrimSpace(value) == "" {
+					return nil
+				}
+
+				t, err := utils.ParseSinceTime(value, app.GetNow())
+				if err != nil {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("invalid value for --%s: %w", name, err)))
+				}
+				return &t
+			}
+
+			sinceTime := parseTime("since", since)
+			untilTime := parseTime("until", until)
+
+			chat, err := app.NewChatContext()
+			app.CheckIfError(err)
+
+			entries := make([]historyEntry, 0)
+
+			repo := chat.Conversations
+			if repo != nil {
+				for dir, contexts := range repo.Conversations {
+					for name, context := range contexts {
+						if context == nil {
+							continue
+						}
+
+						displayName := name
+						if displayName == "" {
+							displayName = "(default)"
+						}
+
+						for _, item := range context.Conversation {
+							itemTime, err := time.Parse(time.RFC3339, item.Time)
+							if err != nil {
+								app.Dbgf("Skipping item with invalid time '%v'%v", item.Time, app.EOL)
+								continue
+							}
+
+							if sinceTime != nil && itemTime.Before(*sinceTime) {
+								continue
+							}
+							if untilTime != nil && itemTime.After(*untilTime) {
+								continue
+							}
+
+							text := ""
+							for _, c := range item.Contents {
+								if c.Type == "text" {
+									text = c.Content
+									break
+								}
+							}
+							if text == "" && len(item.ToolCalls) > 0 {
+								text = fmt.Sprintf("-> %v(%v)", item.ToolCalls[0].Name, item.ToolCalls[0].Arguments)
+							}
+
+							entries = append(entries, historyEntry{
+								Context:   displayName,
+								Directory: dir,
+								Role:      item.Role,
+								Snippet:   utils.GetSnippet(text, historySnippetLength),
+								Time:      item.Time,
+								time:      itemTime,
+							})
+						}
+					}
+				}
+			}
+
+			// newest first, stable for items of the same second
+			sort.SliceStable(entries, func(x, y int) bool {
+				if !entries[x].time.Equal(entries[y].time) {
+					return entries[x].time.After(entries[y].time)
+				}
+				if entries[x].Directory != entries[y].Directory {
+					return entries[x].Directory < entries[y].Directory
+				}
+				return entries[x].Context < entries[y].Context
+			})
+
+			if limit > 0 && len(entries) > limit {
+				entries = entries[:limit]
+			}
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(entries, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+				return
+			}
+
+			for _, e := range entries {
+				app.Writeln(fmt.Sprintf("%v\t%v\t%v\t%v\t%v", e.Time, e.Directory, e.Context, e.Role, e.Snippet))
+			}
+		},
+	}
+
+	historyCmd.Flags().IntVarP(&limit, "limit", "", 20, "maximum number of messages (0 for no limit)")
+	historyCmd.Flags().StringVarP(&since, "since", "", "", "only messages since this time, like 24h, 2025-01-31 or 2025-01-31T12:00:00Z")
+	historyCmd.Flags().StringVarP(&until, "until", "", "", "only messages until this time, like 1h, 2025-01-31 or 2025-01-31T12:00:00Z")
+
+	parentCmd.AddCommand(
+		historyCmd,
+	)
+}
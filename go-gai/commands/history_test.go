@@ -0,0 +1,167 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+// storeTestHistory stores `items` in the context `context` of `dir`.
+func storeTestHistory(t *testing.T, app *types.AppContext, dir string, context string, items ...*types.ConversationRepositoryConversationItem) {
+	t.Helper()
+
+	workDir := app.WorkingDirectory
+	defer func() {
+		app.WorkingDirectory = workDir
+	}()
+	app.WorkingDirectory = dir
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat.SwitchContext(context)
+	for _, item := range items {
+		chat.AppendConversationItem(item)
+	}
+
+	err = chat.UpdateConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// newTestHistoryItem returns a new conversation item with `text`.
+func newTestHistoryItem(role string, time string, text string) *types.ConversationRepositoryConversationItem {
+	return &types.ConversationRepositoryConversationItem{
+		Role: role,
+		Contents: types.ConversationRepositoryConversationItemContents{
+			{Content: text, Type: "text"},
+		},
+		Time: time,
+	}
+}
+
+// newTestHistoryApp returns a new app with a repository of two directories
+// and three contexts, whose messages are stored from 2026-01-01 to 2026-01-05.
+func newTestHistoryApp(t *testing.T) (*types.AppContext, string) {
+	t.Helper()
+
+	app := newTestApp(t, nil)
+
+	otherDir := filepath.Join(filepath.Dir(app.WorkingDirectory), "other")
+
+	storeTestHistory(t, app, app.WorkingDirectory, "",
+		newTestHistoryItem("user", "2026-01-01T00:00:00Z", "What is\n  Go?"),
+		newTestHistoryItem("assistant", "2026-01-03T00:00:00Z", strings.Repeat("x", 100)),
+	)
+	storeTestHistory(t, app, app.WorkingDirectory, "work",
+		newTestHistoryItem("user", "2026-01-02T00:00:00Z", "Write a test"),
+	)
+	storeTestHistory(t, app, otherDir, "notes",
+		newTestHistoryItem("user", "2026-01-04T00:00:00Z", "Read a.txt"),
+		&types.ConversationRepositoryConversationItem{
+			Role:      "assistant",
+			Time:      "2026-01-05T00:00:00Z",
+			ToolCalls: []types.AIToolCall{{Arguments: `{"path":"a.txt"}`, Id: "call_1", Name: "read_file"}},
+		},
+	)
+
+	return app, otherDir
+}
+
+func TestHistory(t *testing.T) {
+	app, otherDir := newTestHistoryApp(t)
+	workDir := app.WorkingDirectory
+
+	runTestCommand(t, app, Init_history_Command, "history")
+
+	expected := strings.Join([]string{
+		fmt.Sprintf("2026-01-05T00:00:00Z\t%s\tnotes\tassistant\t-> read_file({\"path\":\"a.txt\"})", otherDir),
+		fmt.Sprintf("2026-01-04T00:00:00Z\t%s\tnotes\tuser\tRead a.txt", otherDir),
+		fmt.Sprintf("2026-01-03T00:00:00Z\t%s\t(default)\tassistant\t%s...", workDir, strings.Repeat("x", historySnippetLength)),
+		fmt.Sprintf("2026-01-02T00:00:00Z\t%s\twork\tuser\tWrite a test", workDir),
+		fmt.Sprintf("2026-01-01T00:00:00Z\t%s\t(default)\tuser\tWhat is Go?", workDir),
+	}, "\n") + "\n"
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected output %q, got %q", expected, output)
+	}
+}
+
+func TestHistoryFilters(t *testing.T) {
+	tests := []struct {
+		args     []string
+		expected []string
+	}{
+		{[]string{"--limit", "2"}, []string{"2026-01-05T00:00:00Z", "2026-01-04T00:00:00Z"}},
+		{[]string{"--since", "2026-01-04"}, []string{"2026-01-05T00:00:00Z", "2026-01-04T00:00:00Z"}},
+		{[]string{"--until", "2026-01-02"}, []string{"2026-01-02T00:00:00Z", "2026-01-01T00:00:00Z"}},
+		{[]string{"--since", "2026-01-02", "--until", "2026-01-04", "--limit", "2"}, []string{"2026-01-04T00:00:00Z", "2026-01-03T00:00:00Z"}},
+		{[]string{"--since", "2026-02-01"}, []string{}},
+	}
+
+	for _, test := range tests {
+		app, _ := newTestHistoryApp(t)
+
+		runTestCommand(t, app, Init_history_Command, append([]string{"history"}, test.args...)...)
+
+		times := make([]string, 0)
+		for _, line := range strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n") {
+			if line != "" {
+				times = append(times, strings.Split(line, "\t")[0])
+			}
+		}
+
+		if strings.Join(times, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%v: expected times %v, got %v", test.args, test.expected, times)
+		}
+	}
+}
+
+func TestHistoryInvalidSince(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, nil)
+		app.Stderr = os.Stderr
+
+		runTestCommand(t, app, Init_history_Command, "history", "--since", testCase)
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestHistoryInvalidSince", "yesterday")
+
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(output, "invalid value for --since") {
+		t.Errorf("expected error message about --since, got %q", output)
+	}
+}
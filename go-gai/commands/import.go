@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+func init_import_conversation_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var importConversationCmd = &cobra.Command{
+		Use:     "conversation <file>",
+		Aliases: []string{"c"},
+		Short:   "Import conversation",
+		Long:    `Imports an exported conversation in JSON or YAML format and appends it to the conversation of current context.`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			file := args[0]
+			if !filepath.IsAbs(file) {
+				file = filepath.Join(app.WorkingDirectory, file)
+			}
+
+			data, err := os.ReadFile(file)
+			app.CheckIfError(err)
+
+			chat, err := app.NewChatContext()
+			app.CheckIfError(err)
+
+			count, err := chat.ImportConversation(data)
+			app.CheckIfError(err)
+
+			app.Dbgf("Imported %v conversation item(s)%v", count, app.EOL)
+
+			err = chat.UpdateConversation()
+			app.CheckIfError(err)
+		},
+	}
+
+	parentCmd.AddCommand(
+		importConversationCmd,
+	)
+}
+
+// Init_import_Command initializes the `import` command.
+func Init_import_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var importCmd = &cobra.Command{
+		Use:     "import [resource]",
+		Aliases: []string{"imp"},
+		Short:   "Import",
+		Long:    `Imports a resource.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	init_import_conversation_Command(app, importCmd)
+
+	parentCmd.AddCommand(
+		importCmd,
+	)
+}
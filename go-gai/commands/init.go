@@ -23,9 +23,11 @@
 package commands
 
 import (
+	"bufio"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -42,6 +44,10 @@ type initCodeResponseProjectFile struct {
 	TextContent      string `json:"text_content"`
 }
 
+type initGitignoreResponse struct {
+	Gitignore string `json:"gitignore"`
+}
+
 type initCodeResponseProject struct {
 	ProjectFiles []initCodeResponseProjectFile `json:"project_files"`
 	Readme       string                        `json:"readme"`
@@ -49,6 +55,12 @@ type initCodeResponseProject struct {
 
 const allowedFileChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ_-.0123456789/ "
 
+// initCodeReadmeDescription is the description of the README in the default schema of `init code`.
+const initCodeReadmeDescription = "Markdown with detailed information on what is required to start the project."
+
+// initDocsReadmeDescription is the description of the README in the default schema of `init docs`.
+const initDocsReadmeDescription = "Markdown of the README of the project with an overview and links to the documentation."
+
 func cleanupPath(s string) string {
 	s = strings.ReplaceAll(s, fmt.Sprintf("%c", os.PathSeparator), "/")
 	s = strings.ReplaceAll(s, "\t", "  ")
@@ -70,6 +82,427 @@ func cleanupPath(s string) string {
 	return strings.TrimSpace(result)
 }
 
+func ensureDirOfFile(fp string) error {
+	dir := filepath.Dir(fp)
+	err := os.MkdirAll(dir, 0755)
+
+	return err
+}
+
+// getFileDataOf returns the data of the file content `textContent`
+// of an answer, which can also be a data URI for binary data.
+func getFileDataOf(textContent string) []byte {
+	dataUri := strings.TrimSpace(textContent)
+	if strings.HasPrefix(dataUri, "data:") {
+		d, err := utils.DataURIToBytes(dataUri)
+		if err == nil {
+			return d
+		}
+	}
+
+	return []byte(dataUri) // fallback
+}
+
+// getInitProjectFilePath returns the full path of `relativeFilePath`,
+// which must be inside `projectRoot`.
+func getInitProjectFilePath(projectRoot string, relativeFilePath string) (string, string, error) {
+	relPath := cleanupPath(relativeFilePath)
+	fullPath := filepath.Join(projectRoot, relPath)
+
+	requiredPrefix := fmt.Sprintf("%s%c", projectRoot, os.PathSeparator)
+	if !strings.HasPrefix(fullPath, requiredPrefix) {
+		return relPath, fullPath, fmt.Errorf("invalid file path '%s'", fullPath)
+	}
+
+	return relPath, fullPath, nil
+}
+
+// newInitGitignoreResponseSchema returns the default response schema of `init gitignore`.
+func newInitGitignoreResponseSchema() *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"gitignore"},
+		"properties": map[string]any{
+			"gitignore": map[string]any{
+				"description": "The complete content of the .gitignore file.",
+				"type":        "string",
+			},
+		},
+	}
+}
+
+func newInitProjectResponseSchema(readmeDescription string) *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"project_files", "readme"},
+		"properties": map[string]any{
+			"project_files": map[string]any{
+				"type":        "array",
+				"description": "List of files to create.",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []string{"text_content", "explanation", "relative_file_path"},
+					"properties": map[string]any{
+						"relative_file_path": map[string]any{
+							"description": "Relative path of the file to create using / as path separators.",
+							"type":        "string",
+						},
+						"text_content": map[string]any{
+							"description": "Text content or data URI if file contains binary data like image, audio or video.",
+							"type":        "string",
+						},
+						"explanation": map[string]any{
+							"description": "Detailed information what the file is and for what it is used for.",
+							"type":        "string",
+						},
+					},
+				},
+			},
+			"readme": map[string]any{
+				"description": readmeDescription,
+				"type":        "string",
+			},
+		},
+	}
+}
+
+// writeInitProjectFiles writes the files and the README of `project`
+// into `projectRoot`.
+func writeInitProjectFiles(app *types.AppContext, projectRoot string, project *initCodeResponseProject) {
+	for _, newFile := range project.ProjectFiles {
+		relPath, fullPath, err := getInitProjectFilePath(projectRoot, newFile.RelativeFilePath)
+		app.CheckIfError(err)
+
+		err = ensureDirOfFile(fullPath)
+		app.CheckIfError(err)
+
+		err = os.WriteFile(fullPath, getFileDataOf(newFile.TextContent), 0644)
+		app.CheckIfError(err)
+
+		app.OutputAIAnswer(fmt.Sprintf(
+			`Created *%s*:
+%s%s`,
+			relPath,
+			newFile.Explanation,
+			app.EOL,
+		))
+	}
+
+	// README file
+	{
+		relPath := "README.md"
+		readmeFile := filepath.Join(projectRoot, relPath)
+
+		err := ensureDirOfFile(readmeFile)
+		app.CheckIfError(err)
+
+		err = os.WriteFile(readmeFile, []byte(project.Readme), 0644)
+		app.CheckIfError(err)
+
+		app.OutputAIAnswer(fmt.Sprintf(
+			`Finally created *%s*%s`,
+			relPath,
+			app.EOL,
+		))
+	}
+}
+
+func init_init_docs_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var force bool
+	var format string
+
+	var initDocsCmd = &cobra.Command{
+		Use:     "docs [directory] [instructions]",
+		Aliases: []string{"doc", "d"},
+		Short:   "Init docs",
+		Long:    `Initializes documentation of a project.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			format = strings.TrimSpace(strings.ToLower(format))
+
+			var formatInfo string
+			switch format {
+			case "mkdocs":
+				formatInfo = `a MkDocs site with a 'mkdocs.yml' configuration in the root directory and all pages as Markdown files inside the 'docs/' directory, starting with 'docs/index.md'`
+			case "docusaurus":
+				formatInfo = `a Docusaurus site with 'docusaurus.config.js', 'sidebars.js' and 'package.json' in the root directory and all pages as Markdown files inside the 'docs/' directory, starting with 'docs/intro.md'`
+			case "", "plain":
+				formatInfo = `plain Markdown files inside the 'docs/' directory, starting with 'docs/index.md', which links all other pages`
+			default:
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("format '%s' is not supported, use mkdocs, docusaurus or plain", format)))
+			}
+
+			app.InitAI()
+
+			files, err := app.GetFiles()
+			app.CheckIfError(err)
+
+			if len(files) == 0 {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("no files of the project defined, use --file or --files")))
+			}
+
+			responseSchema, responseSchemaName, err := app.GetResponseSchema()
+			app.CheckIfError(err)
+
+			docsRoot := app.WorkingDirectory
+			if len(args) > 0 {
+				docsRoot = app.GetFullPath(args[0])
+			}
+
+			message, err := app.GetInput(args[min(len(args), 1):])
+			app.CheckIfError(err)
+
+			outputLanguage := strings.TrimSpace(app.OutputLanguage)
+
+			langInfo := "same language as the source code and its comments"
+			if outputLanguage != "" {
+				langInfo = fmt.Sprintf("'%s' language", outputLanguage)
+			}
+
+			systemPrompt := fmt.Sprintf(`You are an expert in writing documentation of software projects.
+The user submits the files of a project, which you have to analyze.
+Create a documentation of the project, which describes its purpose, installation, configuration, usage and architecture, based on the submitted files only, and do not invent features.
+Besides a README, always create a 'CONTRIBUTING.md' file with information how to set up a development environment and how to contribute.
+The documentation has to be structured as %s.
+Always answer in %s.`,
+				formatInfo,
+				langInfo,
+			)
+
+			if responseSchema == nil {
+				responseSchema = newInitProjectResponseSchema(initDocsReadmeDescription)
+			}
+			if strings.TrimSpace(responseSchemaName) == "" {
+				responseSchemaName = "CreateProjectDocumentationSchema"
+			}
+
+			if strings.TrimSpace(message) == "" {
+				message = "Create the documentation of the submitted project."
+			}
+
+			promptOptions := make([]types.AIClientPromptOptions, 0)
+			promptOptions = append(promptOptions, types.AIClientPromptOptions{
+				ResponseSchema:     responseSchema,
+				ResponseSchemaName: &responseSchemaName,
+				SystemPrompt:       &systemPrompt,
+			})
+
+			openedFiles := make([]*os.File, 0)
+			defer func() {
+				for _, of := range openedFiles {
+					of.Close()
+				}
+			}()
+
+			for _, f := range files {
+				file, err := os.Open(f)
+				app.CheckIfError(err)
+
+				openedFiles = append(openedFiles, file)
+
+				name, err := filepath.Rel(app.WorkingDirectory, f)
+				if err != nil {
+					name = f
+				}
+
+				// the model needs the paths to understand the structure
+				promptOptions = append(promptOptions, types.AIClientPromptOptions{
+					Files: &[]io.Reader{types.NewNamedReader(file, name)},
+				})
+			}
+
+			response, err := app.PromptAndValidate(message, promptOptions...)
+			app.CheckIfError(err)
+
+			var newDocs initCodeResponseProject
+			err = json.Unmarshal([]byte(response.Content), &newDocs)
+			app.CheckIfError(err)
+
+			if !force {
+				// check all files before writing anything
+				relPaths := []string{"README.md"}
+				for _, newFile := range newDocs.ProjectFiles {
+					relPaths = append(relPaths, newFile.RelativeFilePath)
+				}
+
+				for _, rp := range relPaths {
+					relPath, fullPath, err := getInitProjectFilePath(docsRoot, rp)
+					app.CheckIfError(err)
+
+					_, err = os.Stat(fullPath)
+					if err == nil {
+						app.CheckIfError(fmt.Errorf("'%s' already exists (overwrite with --force)", relPath))
+					}
+				}
+			}
+
+			writeInitProjectFiles(app, docsRoot, &newDocs)
+		},
+	}
+
+	app.WithLanguageCLIFlags(initDocsCmd)
+	app.WithValidationCLIFlags(initDocsCmd)
+	initDocsCmd.Flags().BoolVarP(&force, "force", "", false, "overwrite existing files")
+	initDocsCmd.Flags().StringVarP(&format, "format", "", "plain", "format of documentation: mkdocs, docusaurus or plain")
+
+	parentCmd.AddCommand(
+		initDocsCmd,
+	)
+}
+
+// mergeGitignore returns the lines of `generated`, which are not part of
+// `existing`, appended to `existing`. The second value is `false`,
+// if there is nothing to append.
+func mergeGitignore(existing string, generated string) (string, bool) {
+	existingLines := map[string]bool{}
+	for _, line := range strings.Split(existing, "\n") {
+		existingLines[strings.TrimSpace(line)] = true
+	}
+
+	newLines := make([]string, 0)
+	for _, line := range strings.Split(strings.ReplaceAll(generated, "\r\n", "\n"), "\n") {
+		line = strings.TrimRight(line, " \t")
+
+		if strings.TrimSpace(line) == "" {
+			if len(newLines) > 0 && newLines[len(newLines)-1] != "" {
+				newLines = append(newLines, "") // keep groups
+			}
+			continue
+		}
+
+		if !existingLines[strings.TrimSpace(line)] {
+			newLines = append(newLines, line)
+		}
+	}
+
+	added := strings.TrimSpace(strings.Join(newLines, "\n"))
+	if added == "" {
+		return existing, false
+	}
+
+	existing = strings.TrimRight(existing, " \t\r\n")
+	if existing == "" {
+		return added + "\n", true
+	}
+
+	return existing + "\n\n" + added + "\n", true
+}
+
+func init_init_gitignore_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var appendToExisting bool
+
+	var initGitignoreCmd = &cobra.Command{
+		Use:     "gitignore [instructions]",
+		Aliases: []string{"gi"},
+		Short:   "Init .gitignore",
+		Long:    `Initializes a .gitignore file for the project types of the current directory.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			app.InitAI()
+
+			projectTypes, err := app.DetectProjectTypes()
+			app.CheckIfError(err)
+
+			if len(projectTypes) == 0 {
+				app.Dbg("No project types detected")
+			}
+
+			gitignoreFile := filepath.Join(app.WorkingDirectory, ".gitignore")
+
+			existingData, err := os.ReadFile(gitignoreFile)
+			if err != nil && !os.IsNotExist(err) {
+				app.CheckIfError(err)
+			}
+			gitignoreExists := err == nil
+
+			// STDIN is needed for confirmation
+			message := strings.TrimSpace(strings.Join(args, " "))
+
+			var projectInfo strings.Builder
+			if len(projectTypes) == 0 {
+				projectInfo.WriteString("No known project types have been detected in the directory.")
+			} else {
+				projectInfo.WriteString("These project types have been detected by their marker files:")
+				for _, pt := range projectTypes {
+					projectInfo.WriteString(fmt.Sprintf("\n- %s: %s", pt.Name, strings.Join(pt.Markers, ", ")))
+				}
+			}
+
+			if message != "" {
+				projectInfo.WriteString(fmt.Sprintf("\n\nTake care of the following: %s", message))
+			}
+
+			systemPrompt := `You are an expert in setting up git repositories.
+Create the content of a '.gitignore' file, which is located in the root directory of a project with the submitted project types.
+Marker files inside sub directories mean that the respective project is located there.
+Only ignore files, which are generated, downloaded, local or secret, like build outputs, dependencies, caches, logs, IDE settings and env files, but never source code or lock files.
+Group the patterns by topic with short comments.`
+
+			responseSchema, responseSchemaName, err := app.GetResponseSchema()
+			app.CheckIfError(err)
+
+			if responseSchema == nil {
+				responseSchema = newInitGitignoreResponseSchema()
+			}
+			if strings.TrimSpace(responseSchemaName) == "" {
+				responseSchemaName = "CreateGitignoreSchema"
+			}
+
+			response, err := app.PromptAndValidate(projectInfo.String(), types.AIClientPromptOptions{
+				ResponseSchema:     responseSchema,
+				ResponseSchemaName: &responseSchemaName,
+				SystemPrompt:       &systemPrompt,
+			})
+			app.CheckIfError(err)
+
+			var gitignoreResponse initGitignoreResponse
+			err = json.Unmarshal([]byte(response.Content), &gitignoreResponse)
+			app.CheckIfError(err)
+
+			newContent := strings.TrimSpace(gitignoreResponse.Gitignore) + "\n"
+			question := "Write .gitignore"
+			if gitignoreExists {
+				if appendToExisting {
+					var hasChanges bool
+					newContent, hasChanges = mergeGitignore(string(existingData), gitignoreResponse.Gitignore)
+					if !hasChanges {
+						app.Writeln(".gitignore already contains all patterns")
+						return
+					}
+
+					question = "Append missing patterns to .gitignore"
+				} else {
+					question = "Overwrite existing .gitignore"
+				}
+			}
+
+			app.Writeln(newContent)
+
+			if !app.AlwaysYes {
+				app.WriteString(fmt.Sprintf("%s [y(es)/N(o)]?: ", question))
+
+				reader := bufio.NewReader(app.Stdin)
+
+				input, _ := reader.ReadString('\n')
+				input = strings.TrimSpace(strings.ToLower(input))
+
+				if input != "y" && input != "yes" {
+					return
+				}
+			}
+
+			err = os.WriteFile(gitignoreFile, []byte(newContent), 0644)
+			app.CheckIfError(err)
+		},
+	}
+
+	app.WithValidationCLIFlags(initGitignoreCmd)
+	app.WithYesCliFlags(initGitignoreCmd)
+	initGitignoreCmd.Flags().BoolVarP(&appendToExisting, "append", "", false, "only append missing patterns to an existing .gitignore")
+
+	parentCmd.AddCommand(
+		initGitignoreCmd,
+	)
+}
+
 func init_init_project_Command(app *types.AppContext, parentCmd *cobra.Command) {
 	var initCodeCmd = &cobra.Command{
 		Use:     "code [project]",
@@ -88,7 +521,12 @@ func init_init_project_Command(app *types.AppContext, parentCmd *cobra.Command)
 				app.CheckIfError(errors.New("no valid project name provided"))
 			}
 
-			projectRoot := filepath.Join(app.WorkingDirectory, projectName)
+			parentDir := app.GetOutputDir()
+			if parentDir == "" {
+				parentDir = app.WorkingDirectory
+			}
+
+			projectRoot := filepath.Join(parentDir, projectName)
 
 			info, err := os.Stat(projectRoot)
 			if err == nil {
@@ -118,39 +556,7 @@ Always answer in %s.`,
 			)
 
 			if responseSchema == nil {
-				responseSchema = &map[string]any{
-					"type":     "object",
-					"required": []string{"project_files", "readme"},
-					"properties": map[string]any{
-						"project_files": map[string]any{
-							"type":        "array",
-							"description": "List of files to create.",
-							"items": map[string]any{
-								"type":     "object",
-								"required": []string{"text_content", "explanation", "relative_file_path"},
-								"properties": map[string]any{
-									"relative_file_path": map[string]any{
-										"description": "Relative path of the file to create using / as path separators.",
-										"type":        "string",
-									},
-									"text_content": map[string]any{
-										"description": "Text content or data URI if file contains binary data like image, audio or video.",
-										"type":        "string",
-									},
-									"explanation": map[string]any{
-										"description": "Detailed information what the file is and for what it is used for.",
-										"type":        "string",
-									},
-								},
-							},
-						},
-						"readme": map[string]any{
-							"description": "Markdown with detailed information on what is required to start the project.",
-							"type":        "string",
-						},
-					},
-				}
-
+				responseSchema = newInitProjectResponseSchema(initCodeReadmeDescription)
 			}
 			if strings.TrimSpace(responseSchemaName) == "" {
 				responseSchemaName = "CreateSoftwareProjectSchema"
@@ -162,79 +568,20 @@ Always answer in %s.`,
 				ResponseSchemaName: &responseSchemaName,
 				SystemPrompt:       &systemPrompt,
 			})
-			response, err := app.AI.Prompt(message, promptOptions...)
+			response, err := app.PromptAndValidate(message, promptOptions...)
 			app.CheckIfError(err)
 
 			var newProject initCodeResponseProject
 			err = json.Unmarshal([]byte(response.Content), &newProject)
 			app.CheckIfError(err)
 
-			ensureDirOfFile := func(fp string) error {
-				dir := filepath.Dir(fp)
-				err := os.MkdirAll(dir, 0755)
-
-				return err
-			}
-
-			for _, newFile := range newProject.ProjectFiles {
-				relPath := cleanupPath(newFile.RelativeFilePath)
-				fullPath := filepath.Join(projectRoot, relPath)
-
-				requiredPrefix := fmt.Sprintf("%s%c", projectRoot, os.PathSeparator)
-				if !strings.HasPrefix(fullPath, requiredPrefix) {
-					app.CheckIfError(fmt.Errorf("invalid file path '%s'", fullPath))
-				}
-
-				err := ensureDirOfFile(fullPath)
-				app.CheckIfError(err)
-
-				var data []byte
-
-				dataUri := strings.TrimSpace(newFile.TextContent)
-				if strings.HasPrefix(dataUri, "data:") {
-					d, err := utils.DataURIToBytes(dataUri)
-					if err != nil {
-						d = []byte(dataUri) // fallback
-					}
-
-					data = d
-				} else {
-					data = []byte(dataUri)
-				}
-
-				err = os.WriteFile(fullPath, data, 0644)
-				app.CheckIfError(err)
-
-				app.OutputAIAnswer(fmt.Sprintf(
-					`Created *%s*:
-%s%s`,
-					relPath,
-					newFile.Explanation,
-					app.EOL,
-				))
-			}
-
-			// README file
-			{
-				relPath := "README.md"
-				readmeFile := filepath.Join(projectRoot, relPath)
-
-				err := ensureDirOfFile(readmeFile)
-				app.CheckIfError(err)
-
-				err = os.WriteFile(readmeFile, []byte(newProject.Readme), 0644)
-				app.CheckIfError(err)
-
-				app.OutputAIAnswer(fmt.Sprintf(
-					`Finally created *%s*%s`,
-					relPath,
-					app.EOL,
-				))
-			}
+			writeInitProjectFiles(app, projectRoot, &newProject)
 		},
 	}
 
 	app.WithLanguageCLIFlags(initCodeCmd)
+	app.WithValidationCLIFlags(initCodeCmd)
+	initCodeCmd.Flags().StringVarP(&app.OutputDir, "output-dir", "", "", "directory in which the project directory is created")
 
 	parentCmd.AddCommand(
 		initCodeCmd,
@@ -253,6 +600,8 @@ func Init_init_Command(app *types.AppContext, parentCmd *cobra.Command) {
 		},
 	}
 
+	init_init_docs_Command(app, initCmd)
+	init_init_gitignore_Command(app, initCmd)
 	init_init_project_Command(app, initCmd)
 
 	parentCmd.AddCommand(
@@ -0,0 +1,202 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testInitDocsAnswer = `{
+	"project_files": [
+		{"relative_file_path": "docs/index.md", "text_content": "# Docs", "explanation": "Start page"},
+		{"relative_file_path": "CONTRIBUTING.md", "text_content": "# Contributing", "explanation": "How to contribute"}
+	],
+	"readme": "# Project"
+}`
+
+func TestInitDocs(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	app.FilePatterns = []string{"main.go"}
+	writeTestFile(t, app, "main.go", "package main")
+
+	systemPrompts := make([]string, 0)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		systemPrompts = append(systemPrompts, messages[0].Content)
+		return testInitDocsAnswer
+	})
+
+	runTestCommand(t, app, Init_init_Command, "init", "docs", "--format", "mkdocs")
+
+	if len(systemPrompts) != 1 || !strings.Contains(systemPrompts[0], "MkDocs") {
+		t.Errorf("expected system prompt for MkDocs, got %q", systemPrompts)
+	}
+
+	expectedFiles := map[string]string{
+		"README.md":       "# Project",
+		"CONTRIBUTING.md": "# Contributing",
+		"docs/index.md":   "# Docs",
+	}
+	for name, expected := range expectedFiles {
+		data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, name))
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+
+		if string(data) != expected {
+			t.Errorf("%v: expected %q, got %q", name, expected, string(data))
+		}
+	}
+}
+
+func TestInitDocsExistingFiles(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		app.FilePatterns = []string{"main.go"}
+		app.WorkingDirectory = testCase
+
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			return testInitDocsAnswer
+		})
+
+		runTestCommand(t, app, Init_init_Command, "init", "docs")
+		return
+	}
+
+	app := newTestApp(t, map[string]string{})
+	writeTestFile(t, app, "main.go", "package main")
+	writeTestFile(t, app, "CONTRIBUTING.md", "# Old")
+
+	output, exitCode := runTestProcess(t, "TestInitDocsExistingFiles", app.WorkingDirectory)
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(output, "'CONTRIBUTING.md' already exists (overwrite with --force)") {
+		t.Errorf("expected error message about existing file, got %q", output)
+	}
+
+	// nothing must be written, if one file exists
+	for _, name := range []string{"README.md", "docs/index.md"} {
+		if _, err := os.Stat(filepath.Join(app.WorkingDirectory, name)); !os.IsNotExist(err) {
+			t.Errorf("expected no %v, got %v", name, err)
+		}
+	}
+}
+
+func TestGetInitProjectFilePath(t *testing.T) {
+	root := filepath.Join(t.TempDir(), "project")
+
+	relPath, fullPath, err := getInitProjectFilePath(root, "docs/in:dex.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if relPath != "docs/in_dex.md" {
+		t.Errorf("expected relative path %q, got %q", "docs/in_dex.md", relPath)
+	}
+	if fullPath != filepath.Join(root, "docs", "in_dex.md") {
+		t.Errorf("expected full path inside %q, got %q", root, fullPath)
+	}
+
+	for _, p := range []string{"../outside.md", "docs/../../outside.md", ""} {
+		if _, _, err := getInitProjectFilePath(root, p); err == nil {
+			t.Errorf("expected error for path %q", p)
+		}
+	}
+}
+
+func TestInitGitignore(t *testing.T) {
+	for _, appendToExisting := range []bool{false, true} {
+		app := newTestApp(t, map[string]string{})
+		writeTestFile(t, app, "go.mod", "module test")
+		writeTestFile(t, app, "web/package.json", "{}")
+		writeTestFile(t, app, ".gitignore", "# Go\n*.exe\nsecret.txt\n")
+
+		prompts := make([]string, 0)
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			prompts = append(prompts, messages[len(messages)-1].Content)
+			return `{"gitignore": "# Go\n*.exe\n\n# Node.js\nnode_modules/\n"}`
+		})
+
+		args := []string{"init", "gitignore", "--yes"}
+		if appendToExisting {
+			args = append(args, "--append")
+		}
+
+		runTestCommand(t, app, Init_init_Command, args...)
+
+		if len(prompts) != 1 {
+			t.Fatalf("%v: expected 1 request, got %d", appendToExisting, len(prompts))
+		}
+		for _, expected := range []string{"- Go: go.mod", "- Node.js: web/package.json"} {
+			if !strings.Contains(prompts[0], expected) {
+				t.Errorf("%v: expected %q in prompt, got %q", appendToExisting, expected, prompts[0])
+			}
+		}
+
+		data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, ".gitignore"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "# Go\n*.exe\n\n# Node.js\nnode_modules/\n"
+		if appendToExisting {
+			// keep own patterns
+			expected = "# Go\n*.exe\nsecret.txt\n\n# Node.js\nnode_modules/\n"
+		}
+		if string(data) != expected {
+			t.Errorf("%v: expected %q, got %q", appendToExisting, expected, string(data))
+		}
+	}
+}
+
+func TestMergeGitignore(t *testing.T) {
+	tests := []struct {
+		name            string
+		existing        string
+		generated       string
+		expected        string
+		expectedChanges bool
+	}{
+		{"empty", "", "a\n\nb\n", "a\n\nb\n", true},
+		{"nothing new", "a\nb\n", "b\r\na\r\n", "a\nb\n", false},
+		{"missing patterns", "a\n", "a\n\n# b\nb  \n", "a\n\n# b\nb\n", true},
+	}
+
+	for _, test := range tests {
+		merged, hasChanges := mergeGitignore(test.existing, test.generated)
+
+		if merged != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.name, test.expected, merged)
+		}
+		if hasChanges != test.expectedChanges {
+			t.Errorf("%v: expected changes %v, got %v", test.name, test.expectedChanges, hasChanges)
+		}
+	}
+}
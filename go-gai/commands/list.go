@@ -23,11 +23,13 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/mkloubert/gai/types"
@@ -35,7 +37,77 @@ import (
 	"github.com/spf13/cobra"
 )
 
+func init_list_contexts_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var allDirs bool
+
+	var listContextsCmd = &cobra.Command{
+		Use:     "contexts",
+		Aliases: []string{"context", "ctx"},
+		Short:   "List contexts",
+		Long:    `Lists conversation contexts with number of messages and time of last message.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			chat, err := app.NewChatContext()
+			app.CheckIfError(err)
+
+			repo := chat.Conversations
+			if repo == nil || repo.Conversations == nil {
+				return // nothing stored yet
+			}
+
+			dirs := make([]string, 0)
+			for dir := range repo.Conversations {
+				if allDirs || dir == app.WorkingDirectory {
+					dirs = append(dirs, dir)
+				}
+			}
+			sort.Strings(dirs)
+
+			for _, dir := range dirs {
+				contexts := repo.Conversations[dir]
+
+				names := make([]string, 0)
+				for name := range contexts {
+					names = append(names, name)
+				}
+				sort.Strings(names)
+
+				for _, name := range names {
+					messageCount := 0
+					lastTime := ""
+
+					context := contexts[name]
+					if context != nil {
+						messageCount = len(context.Conversation)
+						if messageCount > 0 {
+							lastTime = context.Conversation[messageCount-1].Time
+						}
+					}
+
+					displayName := name
+					if displayName == "" {
+						displayName = "(default)"
+					}
+
+					if allDirs {
+						app.Writeln(fmt.Sprintf("%v\t%v\t%v\t%v", dir, displayName, messageCount, lastTime))
+					} else {
+						app.Writeln(fmt.Sprintf("%v\t%v\t%v", displayName, messageCount, lastTime))
+					}
+				}
+			}
+		},
+	}
+
+	listContextsCmd.Flags().BoolVarP(&allDirs, "all-dirs", "", false, "list contexts of all directories")
+
+	parentCmd.AddCommand(
+		listContextsCmd,
+	)
+}
+
 func init_list_conversation_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var since string
+
 	var listConversationCmd = &cobra.Command{
 		Use:     "conversation",
 		Aliases: []string{"c"},
@@ -48,7 +120,35 @@ func init_list_conversation_Command(app *types.AppContext, parentCmd *cobra.Comm
 			conversation, err := chat.GetConversation()
 			app.CheckIfError(err)
 
-			chroma := app.GetChromaSettings()
+			if strings.TrimSpace(since) != "" {
+				sinceTime, err := utils.ParseSinceTime(since, app.GetNow())
+				if err != nil {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("invalid value for --since: %w", err)))
+				}
+
+				filteredConversation := make(types.ConversationRepositoryConversation, 0)
+				for _, item := range conversation {
+					itemTime, err := time.Parse(time.RFC3339, item.Time)
+					if err != nil {
+						app.Dbgf("Skipping item with invalid time '%v'%v", item.Time, app.EOL)
+						continue
+					}
+
+					if !itemTime.Before(sinceTime) {
+						filteredConversation = append(filteredConversation, item)
+					}
+				}
+
+				conversation = filteredConversation
+			}
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(conversation, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+				return
+			}
 
 			for i, item := range conversation {
 				if i > 0 {
@@ -57,13 +157,20 @@ func init_list_conversation_Command(app *types.AppContext, parentCmd *cobra.Comm
 
 				app.Writeln(fmt.Sprintf("%v:", item.Role))
 				for _, content := range item.Contents {
-					chroma.HighlightMarkdown(content.Content)
+					app.OutputMarkdown(content.Content)
 					app.Writeln()
 				}
+				for _, call := range item.ToolCalls {
+					app.Writeln(fmt.Sprintf("-> %v(%v)", call.Name, call.Arguments))
+				}
 			}
 		},
 	}
 
+	app.WithHighlightCLIFlags(listConversationCmd)
+
+	listConversationCmd.Flags().StringVarP(&since, "since", "", "", "only items since this time, like 24h, 2025-01-31 or 2025-01-31T12:00:00Z")
+
 	parentCmd.AddCommand(
 		listConversationCmd,
 	)
@@ -159,38 +266,15 @@ func init_list_files_Command(app *types.AppContext, parentCmd *cobra.Command) {
 }
 
 func init_list_models_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var probe bool
+	var refreshCapabilities bool
+
 	var listFilesCmd = &cobra.Command{
 		Use:   "models",
 		Short: "List models",
 		Long:  `Lists AI models for each supported provider.`,
 		Run: func(cmd *cobra.Command, args []string) {
-			clients := make([]types.AIClient, 0)
-
-			ollama, ollamaErr := app.NewAIClient("ollama")
-			if ollamaErr != nil {
-				app.Dbgf("WARN: could not create Ollama client: %s%s", ollamaErr.Error(), app.EOL)
-			} else {
-				clients = append(clients, ollama)
-			}
-
-			openai, openaiErr := app.NewAIClient("openai")
-			if openaiErr != nil {
-				app.Dbgf("WARN: could not create OpenAI client: %s%s", openaiErr.Error(), app.EOL)
-			} else {
-				clients = append(clients, openai)
-			}
-
-			modelList := make([]types.AIModel, 0)
-
-			for _, c := range clients {
-				loadedModels, err := c.GetModels()
-				if err != nil {
-					app.Dbgf("WARN: Could not load models: %s", err.Error())
-					continue
-				}
-
-				modelList = append(modelList, loadedModels...)
-			}
+			modelList := app.GetAllModels()
 
 			sort.Slice(modelList, func(x, y int) bool {
 				strX := modelList[x].String()
@@ -203,12 +287,47 @@ func init_list_models_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				)
 			})
 
+			if !probe && !refreshCapabilities {
+				for _, m := range modelList {
+					app.Writeln(m.String())
+				}
+				return
+			}
+
+			capabilities, err := app.GetModelCapabilities()
+			app.CheckIfError(err)
+
 			for _, m := range modelList {
-				app.Writeln(m.String())
+				fullName := m.String()
+
+				modelCapabilities, ok := capabilities[fullName]
+				if !ok || refreshCapabilities {
+					// use own client instance, so we do not change
+					// the chat model of the listing client
+					client, err := app.NewAIClient(m.Client().Provider())
+					app.CheckIfError(err)
+
+					modelCapabilities, err = app.ProbeModelCapabilities(client, m.Name())
+					if err != nil {
+						app.Dbgf("WARN: Could not probe model '%v': %v%v", fullName, err.Error(), app.EOL)
+						modelCapabilities = []string{}
+					}
+
+					capabilities[fullName] = modelCapabilities
+
+					err = app.UpdateModelCapabilities(capabilities)
+					app.CheckIfError(err)
+				}
+
+				app.Writeln(fmt.Sprintf("%v\t%v", fullName, strings.Join(modelCapabilities, ",")))
 			}
 		},
 	}
 
+	listFilesCmd.Flags().BoolVarP(&probe, "probe", "", false, "probe capabilities of models without cached data (costs API calls)")
+	listFilesCmd.Flags().BoolVarP(&app.RefreshModels, "refresh", "", false, "load models from the providers instead of the cache")
+	listFilesCmd.Flags().BoolVarP(&refreshCapabilities, "refresh-capabilities", "", false, "probe capabilities of all models again (costs API calls)")
+
 	parentCmd.AddCommand(
 		listFilesCmd,
 	)
@@ -226,10 +345,13 @@ func Init_list_Command(app *types.AppContext, parentCmd *cobra.Command) {
 		},
 	}
 
+	init_list_contexts_Command(app, listCmd)
 	init_list_conversation_Command(app, listCmd)
 	init_list_env_Command(app, listCmd)
 	init_list_files_Command(app, listCmd)
 	init_list_models_Command(app, listCmd)
+	init_list_schema_Command(app, listCmd)
+	init_list_schema_presets_Command(app, listCmd)
 
 	parentCmd.AddCommand(
 		listCmd,
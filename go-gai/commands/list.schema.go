@@ -0,0 +1,243 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+// defaultCommandSchema stores the default response schema of a command.
+type defaultCommandSchema struct {
+	// Name is the default name of the schema.
+	Name string
+	// NewSchema creates the default schema.
+	NewSchema func(app *types.AppContext) (*map[string]any, error)
+}
+
+// listSchemaOutput is the output of `list schema` with `--json`.
+type listSchemaOutput struct {
+	Command string          `json:"command"`
+	Name    string          `json:"name"`
+	Schema  *map[string]any `json:"schema"`
+}
+
+// defaultCommandSchemas contains the default response schemas by command.
+var defaultCommandSchemas = map[string]defaultCommandSchema{
+	"commit": {
+		Name: "CreateGitCommitMessageSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newCommitResponseSchema(), nil
+		},
+	},
+	"describe audio": {
+		Name: "DescribeAudioSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newDescribeAudioResponseSchema(1, 10), nil
+		},
+	},
+	"describe files": {
+		Name: "DescribeFileSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newDescribeFileResponseSchema(1, 10), nil
+		},
+	},
+	"describe images": {
+		Name: "DescribeImageSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newDescribeImageResponseSchema(1, 10), nil
+		},
+	},
+	"describe pr": {
+		Name: "DescribePullRequestSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newDescribePullRequestResponseSchema(), nil
+		},
+	},
+	"init code": {
+		Name: "CreateSoftwareProjectSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newInitProjectResponseSchema(initCodeReadmeDescription), nil
+		},
+	},
+	"init docs": {
+		Name: "CreateProjectDocumentationSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newInitProjectResponseSchema(initDocsReadmeDescription), nil
+		},
+	},
+	"init gitignore": {
+		Name: "CreateGitignoreSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newInitGitignoreResponseSchema(), nil
+		},
+	},
+	"prompt": {
+		Name: "WriteFilesSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			return newPromptFilesResponseSchema(), nil
+		},
+	},
+	"update code": {
+		Name: "FileUpdateSchema",
+		NewSchema: func(app *types.AppContext) (*map[string]any, error) {
+			files, err := app.GetFiles()
+			if err != nil {
+				return nil, err
+			}
+
+			// the schema has one property for each file
+			filesToUpdate := make([]string, 0, len(files))
+			for _, f := range files {
+				relPath, err := filepath.Rel(app.WorkingDirectory, f)
+				if err != nil {
+					return nil, err
+				}
+
+				filesToUpdate = append(filesToUpdate, relPath)
+			}
+			if len(filesToUpdate) == 0 {
+				filesToUpdate = append(filesToUpdate, "path/to/file")
+			}
+
+			return newUpdateCodeResponseSchema(filesToUpdate), nil
+		},
+	},
+}
+
+// getDefaultCommandSchemaNames returns the sorted names of the
+// commands of `defaultCommandSchemas`.
+func getDefaultCommandSchemaNames() []string {
+	names := make([]string, 0, len(defaultCommandSchemas))
+	for name := range defaultCommandSchemas {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func init_list_schema_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var listSchemaCmd = &cobra.Command{
+		Use:     "schema [command]",
+		Aliases: []string{"s"},
+		Short:   "List default schemas",
+		Long:    `Lists the commands with a default response schema or prints the default response schema of a command.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			command := strings.ToLower(strings.Join(strings.Fields(strings.Join(args, " ")), " "))
+
+			if command == "" {
+				names := getDefaultCommandSchemaNames()
+
+				if app.JSONOutput {
+					jsonData, err := json.MarshalIndent(names, "", "  ")
+					app.CheckIfError(err)
+
+					app.Writeln(string(jsonData))
+					return
+				}
+
+				for _, name := range names {
+					app.Writeln(fmt.Sprintf("%s\t%s", name, defaultCommandSchemas[name].Name))
+				}
+				return
+			}
+
+			defaultSchema, ok := defaultCommandSchemas[command]
+			if !ok {
+				app.CheckIfError(types.NewTypedError(
+					types.ErrorTypeUsage,
+					fmt.Errorf("no default schema for command '%s', use one of: %s", command, strings.Join(getDefaultCommandSchemaNames(), ", ")),
+				))
+			}
+
+			// an overwritten schema is used instead of the default one
+			schema, schemaName, err := app.GetResponseSchema()
+			app.CheckIfError(err)
+
+			if schema == nil {
+				schema, err = defaultSchema.NewSchema(app)
+				app.CheckIfError(err)
+			}
+			if strings.TrimSpace(schemaName) == "" {
+				schemaName = defaultSchema.Name
+			}
+
+			var output any = schema
+			if app.JSONOutput {
+				output = &listSchemaOutput{
+					Command: command,
+					Name:    schemaName,
+					Schema:  schema,
+				}
+			}
+
+			jsonData, err := json.MarshalIndent(output, "", "  ")
+			app.CheckIfError(err)
+
+			app.Writeln(string(jsonData))
+		},
+	}
+
+	app.WithSchemaCLIFlags(listSchemaCmd)
+
+	parentCmd.AddCommand(
+		listSchemaCmd,
+	)
+}
+
+func init_list_schema_presets_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var listSchemaPresetsCmd = &cobra.Command{
+		Use:     "schema-presets",
+		Aliases: []string{"presets", "schemas"},
+		Short:   "List schema presets",
+		Long:    `Lists the named response schemas inside the schemas directory of the app, which can be used with --schema-name.`,
+		Args:    cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			presets, err := app.GetSchemaPresets()
+			app.CheckIfError(err)
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(presets, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+				return
+			}
+
+			for _, p := range presets {
+				app.Writeln(fmt.Sprintf("%s\t%s", p.Name, p.Path))
+			}
+		},
+	}
+
+	parentCmd.AddCommand(
+		listSchemaPresetsCmd,
+	)
+}
@@ -0,0 +1,391 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+)
+
+// storeTestConversations stores conversations with `counts` messages
+// in the contexts of `dir`, with their names as keys.
+func storeTestConversations(t *testing.T, app *types.AppContext, dir string, counts map[string]int) {
+	t.Helper()
+
+	workDir := app.WorkingDirectory
+	defer func() {
+		app.WorkingDirectory = workDir
+	}()
+	app.WorkingDirectory = dir
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for context, count := range counts {
+		chat.SwitchContext(context)
+
+		for i := 0; i < count; i++ {
+			chat.AppendConversationItem(&types.ConversationRepositoryConversationItem{
+				Role: "user",
+				Time: fmt.Sprintf("2026-01-0%dT00:00:00Z", i+1),
+			})
+		}
+	}
+
+	err = chat.UpdateConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestListContexts(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	otherDir := filepath.Join(filepath.Dir(app.WorkingDirectory), "other")
+
+	storeTestConversations(t, app, app.WorkingDirectory, map[string]int{
+		"":     2,
+		"work": 3,
+	})
+	storeTestConversations(t, app, otherDir, map[string]int{
+		"notes": 1,
+	})
+
+	runTestCommand(t, app, Init_list_Command, "list", "contexts")
+
+	expected := "(default)\t2\t2026-01-02T00:00:00Z\nwork\t3\t2026-01-03T00:00:00Z\n"
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected output %q, got %q", expected, output)
+	}
+}
+
+func TestListContextsOfAllDirs(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	otherDir := filepath.Join(filepath.Dir(app.WorkingDirectory), "other")
+
+	storeTestConversations(t, app, app.WorkingDirectory, map[string]int{
+		"work": 1,
+	})
+	storeTestConversations(t, app, otherDir, map[string]int{
+		"notes": 2,
+	})
+
+	runTestCommand(t, app, Init_list_Command, "list", "contexts", "--all-dirs")
+
+	expected := fmt.Sprintf(
+		"%s\tnotes\t2\t2026-01-02T00:00:00Z\n%s\twork\t1\t2026-01-01T00:00:00Z\n",
+		otherDir, app.WorkingDirectory,
+	)
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected output %q, got %q", expected, output)
+	}
+}
+
+func TestListContextsWithoutConversations(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	runTestCommand(t, app, Init_list_Command, "list", "contexts", "--all-dirs")
+
+	if output := readTestOutput(t, app.Stdout); output != "" {
+		t.Errorf("expected no output, got %q", output)
+	}
+}
+
+func TestListConversationNoColor(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"NO_COLOR": "1",
+	})
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat.AppendConversationItem(&types.ConversationRepositoryConversationItem{
+		Role: "assistant",
+		Contents: types.ConversationRepositoryConversationItemContents{
+			{Content: "# Title\n\n```go\nfunc main() {}\n```", Type: "text"},
+		},
+		Time: "2026-01-01T00:00:00Z",
+	})
+	err = chat.UpdateConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runTestCommand(t, app, Init_list_Command, "list", "conversation")
+	app.OutputAIAnswer("**bold**")
+
+	expected := "assistant:\n# Title\n\n```go\nfunc main() {}\n```\n**bold**"
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected output %q, got %q", expected, output)
+	}
+}
+
+func TestListConversationJSON(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.JSONOutput = true
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+	chat.AppendConversationItem(&types.ConversationRepositoryConversationItem{
+		Role: "user",
+		Contents: types.ConversationRepositoryConversationItemContents{
+			{Content: "What is on the image?", Type: "text"},
+			{Content: "data:image/png;base64,AAAA", Name: "photo.png", Type: "image"},
+		},
+		Time: "2026-01-01T00:00:00Z",
+	})
+	chat.AppendConversationItem(&types.ConversationRepositoryConversationItem{
+		Role: "assistant",
+		Contents: types.ConversationRepositoryConversationItemContents{
+			{Content: "Let me read it.", Type: "text"},
+		},
+		Model:     "gpt-4o",
+		Time:      "2026-01-02T00:00:00Z",
+		ToolCalls: []types.AIToolCall{{Arguments: `{"path":"a.txt"}`, Id: "call_1", Name: "read_file"}},
+		Usage:     &types.AIUsage{CompletionTokens: 1, PromptTokens: 2, TotalTokens: 3},
+	})
+	chat.AppendConversationItem(&types.ConversationRepositoryConversationItem{
+		Role: "tool",
+		Contents: types.ConversationRepositoryConversationItemContents{
+			{Content: "A cat", Type: "text"},
+		},
+		Time:       "2026-01-03T00:00:00Z",
+		ToolCallId: "call_1",
+	})
+	err = chat.UpdateConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	stored, err := chat.GetConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	runTestCommand(t, app, Init_list_Command, "list", "conversation")
+
+	var conversation types.ConversationRepositoryConversation
+	err = json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &conversation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(conversation, stored) {
+		expected, _ := json.Marshal(stored)
+		got, _ := json.Marshal(conversation)
+
+		t.Errorf("expected %s, got %s", expected, got)
+	}
+}
+
+func TestListConversationSince(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.JSONOutput = true
+
+	storeTestConversations(t, app, app.WorkingDirectory, map[string]int{
+		"": 3,
+	})
+
+	runTestCommand(t, app, Init_list_Command, "list", "conversation", "--since", "2026-01-02")
+
+	var conversation types.ConversationRepositoryConversation
+	err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &conversation)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	times := make([]string, 0)
+	for _, item := range conversation {
+		times = append(times, item.Time)
+	}
+
+	expected := []string{"2026-01-02T00:00:00Z", "2026-01-03T00:00:00Z"}
+	if !reflect.DeepEqual(times, expected) {
+		t.Errorf("expected items of %v, got %v", expected, times)
+	}
+}
+
+func TestListSchema(t *testing.T) {
+	for _, name := range getDefaultCommandSchemaNames() {
+		app := newTestApp(t, nil)
+
+		runTestCommand(t, app, Init_list_Command, append([]string{"list", "schema"}, strings.Fields(name)...)...)
+
+		var schema map[string]any
+		err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &schema)
+		if err != nil {
+			t.Errorf("%s: schema is no valid JSON: %v", name, err)
+			continue
+		}
+
+		if errs, _ := utils.LintJSONSchema(schema); len(errs) > 0 {
+			t.Errorf("%s: expected valid schema, got %v", name, errs)
+		}
+	}
+}
+
+func TestListSchemaCommands(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	runTestCommand(t, app, Init_list_Command, "list", "schema")
+
+	lines := strings.Split(strings.TrimSpace(readTestOutput(t, app.Stdout)), "\n")
+	if len(lines) != len(defaultCommandSchemas) {
+		t.Fatalf("expected %d commands, got %q", len(defaultCommandSchemas), lines)
+	}
+	if lines[0] != "commit\tCreateGitCommitMessageSchema" {
+		t.Errorf("unexpected first command %q", lines[0])
+	}
+}
+
+func TestListSchemaJSON(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.JSONOutput = true
+
+	runTestCommand(t, app, Init_list_Command, "list", "schema", "describe", "pr")
+
+	var output listSchemaOutput
+	err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.Command != "describe pr" || output.Name != "DescribePullRequestSchema" {
+		t.Errorf("unexpected command %q or name %q", output.Command, output.Name)
+	}
+	if expected := newDescribePullRequestResponseSchema(); !reflect.DeepEqual(jsonRoundTrip(t, expected), jsonRoundTrip(t, output.Schema)) {
+		t.Errorf("expected schema %v, got %v", *expected, *output.Schema)
+	}
+}
+
+func TestListSchemaOverride(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.JSONOutput = true
+
+	writeTestFile(t, app, "schema.json", `{"type": "object", "properties": {"title": {"type": "string"}}}`)
+
+	runTestCommand(t, app, Init_list_Command, "list", "schema", "commit", "--schema", "schema.json", "--schema-name", "MySchema")
+
+	var output listSchemaOutput
+	err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &output)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if output.Name != "MySchema" {
+		t.Errorf("expected name %q, got %q", "MySchema", output.Name)
+	}
+	if _, ok := (*output.Schema)["properties"].(map[string]any)["title"]; !ok {
+		t.Errorf("expected schema of file, got %v", *output.Schema)
+	}
+}
+
+func TestListSchemaPresets(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.JSONOutput = true
+
+	schemasDir, err := app.GetSchemasDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.MkdirAll(schemasDir, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"Invoice.json", "Address.JSON", "notes.txt"} {
+		err = os.WriteFile(filepath.Join(schemasDir, name), []byte(`{"type": "object"}`), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// `schemas` is an alias of `schema-presets`
+	runTestCommand(t, app, Init_list_Command, "list", "schemas")
+
+	var presets []types.SchemaPreset
+	err = json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &presets)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0, len(presets))
+	for _, p := range presets {
+		names = append(names, p.Name)
+	}
+	if expected := []string{"Address", "Invoice"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected presets %v, got %v", expected, names)
+	}
+}
+
+func TestListSchemaUnknownCommand(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, nil)
+		app.Stderr = os.Stderr
+
+		runTestCommand(t, app, Init_list_Command, "list", "schema", testCase)
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestListSchemaUnknownCommand", "chat")
+
+	if exitCode != 2 {
+		t.Errorf("expected exit code 2, got %d", exitCode)
+	}
+	if !strings.Contains(output, "no default schema for command 'chat'") {
+		t.Errorf("expected error message about unknown command, got %q", output)
+	}
+}
+
+// jsonRoundTrip returns `v` after serializing it to JSON and back.
+func jsonRoundTrip(t *testing.T, v any) any {
+	t.Helper()
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var result any
+	err = json.Unmarshal(data, &result)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return result
+}
@@ -0,0 +1,197 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+func init_models_default_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var show bool
+
+	var modelsDefaultCmd = &cobra.Command{
+		Use:     "default [PROVIDER:MODEL]",
+		Aliases: []string{"def", "d"},
+		Short:   "Default model",
+		Long:    `Sets the default chat model in the .gairc file of the current directory or shows the current one.`,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			if show || len(args) == 0 {
+				model, source := app.GetDefaultModel()
+				if model == "" {
+					app.CheckIfError(fmt.Errorf("no default chat model defined"))
+				}
+
+				app.Writeln(fmt.Sprintf("%s\t%s", model, source))
+				return
+			}
+
+			modelWithProvider := args[0]
+
+			provider, model, err := types.ParseModelWithProvider(modelWithProvider)
+			if err != nil {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, err))
+			}
+
+			// check if provider is supported and configured
+			_, err = app.NewAIClient(provider)
+			app.CheckIfError(err)
+
+			// provider is expected in lower case
+			modelWithProvider = fmt.Sprintf("%s:%s", strings.ToLower(provider), model)
+
+			err = app.SetRCFileValue(modelWithProvider, "defaults", "flags", "model")
+			app.CheckIfError(err)
+
+			rcFilePath, err := app.GetRCFilePath()
+			app.CheckIfError(err)
+
+			app.Dbgf("Default model '%v' written to '%v'%v", modelWithProvider, rcFilePath, app.EOL)
+		},
+	}
+
+	modelsDefaultCmd.Flags().BoolVarP(&show, "show", "", false, "show current default model and where it is defined")
+
+	parentCmd.AddCommand(
+		modelsDefaultCmd,
+	)
+}
+
+func init_models_show_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var modelsShowCmd = &cobra.Command{
+		Use:     "show <PROVIDER:MODEL>",
+		Aliases: []string{"info", "i", "s"},
+		Short:   "Show model",
+		Long:    `Shows the capabilities and information of a model.`,
+		Args:    cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			provider, model, err := types.ParseModelWithProvider(args[0])
+			if err != nil {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, err))
+			}
+
+			// client of the provider requires a model of it
+			app.Model = fmt.Sprintf("%s:%s", provider, model)
+
+			client, err := app.NewAIClient(provider)
+			app.CheckIfError(err)
+
+			info, err := app.GetModelInfo(client, model)
+			app.CheckIfError(err)
+
+			if app.JSONOutput {
+				jsonData, err := json.MarshalIndent(struct {
+					Model    string `json:"model"`
+					Provider string `json:"provider"`
+					types.AIModelInfo
+				}{
+					Model:       model,
+					Provider:    provider,
+					AIModelInfo: info,
+				}, "", "  ")
+				app.CheckIfError(err)
+
+				app.Writeln(string(jsonData))
+				return
+			}
+
+			yesNo := func(b *bool) string {
+				if b == nil {
+					return "unknown"
+				}
+				if *b {
+					return "yes"
+				}
+				return "no"
+			}
+
+			writeLine := func(name string, value string) {
+				if value != "" {
+					app.Writeln(fmt.Sprintf("%-16s%s", name+":", value))
+				}
+			}
+
+			formatPrice := func(price *float64) string {
+				if price == nil {
+					return "unknown"
+				}
+				return fmt.Sprintf("$%v per 1K tokens", *price)
+			}
+
+			writeLine("Model", app.Model)
+			writeLine("Provider", provider)
+			writeLine("Name", info.DisplayName)
+			writeLine("Description", info.Description)
+			writeLine("Family", info.Family)
+			writeLine("Parameters", info.ParameterSize)
+			writeLine("Quantization", info.Quantization)
+			writeLine("Vision", yesNo(info.Vision))
+			writeLine("Audio", yesNo(info.Audio))
+			writeLine("JSON schema", yesNo(info.JSONSchema))
+			if info.ContextWindow != nil {
+				writeLine("Context window", fmt.Sprintf("%d tokens", *info.ContextWindow))
+			} else {
+				writeLine("Context window", "unknown")
+			}
+			if info.MaxOutputTokens != nil {
+				writeLine("Max. output", fmt.Sprintf("%d tokens", *info.MaxOutputTokens))
+			}
+			writeLine("Input price", formatPrice(info.InputPrice))
+			writeLine("Output price", formatPrice(info.OutputPrice))
+			writeLine("Owned by", info.OwnedBy)
+			if info.Created != nil {
+				writeLine("Created", info.Created.Format(time.RFC3339))
+			}
+		},
+	}
+
+	parentCmd.AddCommand(
+		modelsShowCmd,
+	)
+}
+
+// Init_models_Command initializes the `models` command.
+func Init_models_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var modelsCmd = &cobra.Command{
+		Use:     "models [action]",
+		Aliases: []string{"model", "m"},
+		Short:   "Models",
+		Long:    `Manages AI models.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			cmd.Help()
+		},
+	}
+
+	init_models_default_Command(app, modelsCmd)
+	init_models_show_Command(app, modelsCmd)
+
+	parentCmd.AddCommand(
+		modelsCmd,
+	)
+}
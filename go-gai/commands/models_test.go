@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+func TestModelsDefault(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"OPENAI_API_KEY": "test",
+	})
+
+	runTestCommand(t, app, Init_models_Command, "models", "default", "OpenAI:gpt-4o-mini")
+
+	runTestCommand(t, app, Init_models_Command, "models", "default", "--show")
+
+	output := readTestOutput(t, app.Stdout)
+	if output != "openai:gpt-4o-mini\tdefaults.flags.model of .gairc\n" {
+		t.Errorf("unexpected output %q", output)
+	}
+
+	// used without --model
+	app.InitAI()
+	if app.AI.Provider() != "openai" || app.AI.ChatModel() != "gpt-4o-mini" {
+		t.Errorf("expected default model openai:gpt-4o-mini, got %v:%v", app.AI.Provider(), app.AI.ChatModel())
+	}
+}
+
+// newTestOllamaShowServer starts a server, which answers `/api/show`
+// of Ollama for `llama3.2` and uses it as base URL of `app`.
+func newTestOllamaShowServer(t *testing.T, app *types.AppContext) {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Model string `json:"model"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+
+		if r.URL.Path != "/api/show" || body.Model != "llama3.2" {
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+  "capabilities": ["completion", "tools"],
+  "details": {"family": "llama", "parameter_size": "3.2B", "quantization_level": "Q4_K_M"},
+  "model_info": {"general.architecture": "llama", "llama.context_length": 8192}
+}`))
+	}))
+	t.Cleanup(server.Close)
+
+	app.BaseUrl = server.URL
+}
+
+func TestModelsShowOllama(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	newTestOllamaShowServer(t, app)
+
+	runTestCommand(t, app, Init_models_Command, "models", "show", "ollama:llama3.2")
+
+	// context window of /api/show replaces the one of the table
+	expected := `Model:          ollama:llama3.2
+Provider:       ollama
+Family:         llama
+Parameters:     3.2B
+Quantization:   Q4_K_M
+Vision:         no
+Audio:          no
+JSON schema:    yes
+Context window: 8192 tokens
+Input price:    $0 per 1K tokens
+Output price:   $0 per 1K tokens
+`
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestModelsShowJSON(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"OPENAI_API_KEY": "test",
+	})
+	app.JSONOutput = true
+	newTestOllamaShowServer(t, app) // has no OpenAI endpoints
+
+	runTestCommand(t, app, Init_models_Command, "models", "show", "openai:gpt-4o-mini-2024-07-18")
+
+	var info map[string]any
+	err := json.Unmarshal([]byte(readTestOutput(t, app.Stdout)), &info)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// values of the longest prefix of the table
+	expected := map[string]any{
+		"audio":          false,
+		"context_window": float64(128000),
+		"input_price":    0.00015,
+		"json_schema":    true,
+		"model":          "gpt-4o-mini-2024-07-18",
+		"output_price":   0.0006,
+		"provider":       "openai",
+		"vision":         true,
+	}
+	if fmt.Sprint(info) != fmt.Sprint(expected) {
+		t.Errorf("expected %v, got %v", expected, info)
+	}
+
+	output := readTestOutput(t, app.Stderr)
+	if !strings.Contains(output, "WARN: could not load information of 'openai:gpt-4o-mini-2024-07-18' from provider") {
+		t.Errorf("expected warning about live information, got %q", output)
+	}
+}
@@ -23,23 +23,527 @@
 package commands
 
 import (
+	"bufio"
+	"bytes"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
+type promptEachLineResult struct {
+	Answer *string              `json:"answer,omitempty"`
+	Error  *promptEachLineError `json:"error,omitempty"`
+	Index  int                  `json:"index"`
+	Line   string               `json:"line"`
+	Usage  *types.AIUsage       `json:"-"`
+
+	err error
+}
+
+// defaultPromptRepeatConcurrency is the number of samples of `--repeat`,
+// which are prompted in parallel, if `--concurrency` is not defined.
+const defaultPromptRepeatConcurrency uint16 = 4
+
+type promptRepeatSample struct {
+	Answer any `json:"answer"`
+	Sample int `json:"sample"`
+}
+
+type promptEachLineError struct {
+	Message string `json:"message"`
+}
+
+func (r *promptEachLineResult) setError(err error) {
+	r.err = err
+	r.Error = &promptEachLineError{Message: err.Error()}
+}
+
+func runPromptEachLine(app *types.AppContext, instruction string, template string, files []string, attachments []*types.UrlAttachment, concurrency int, continueOnError bool, jsonl bool, baseOptions []types.AIClientPromptOptions) {
+	if term.IsTerminal(int(app.Stdin.Fd())) {
+		app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("no lines piped to STDIN")))
+	}
+
+	stopInputTiming := app.StartTiming(types.TimingPhaseInput)
+
+	lines := make([]string, 0)
+
+	scanner := bufio.NewScanner(app.Stdin)
+	scanner.Buffer(make([]byte, 0, 64*1024), 64*1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	app.CheckIfError(scanner.Err())
+
+	stopInputTiming()
+
+	toPrompt := func(i int, line string) string {
+		if template == "" {
+			if instruction == "" {
+				return line
+			}
+			return fmt.Sprintf("%s%s%s", instruction, app.EOL, line)
+		}
+
+		prompt := strings.ReplaceAll(template, "{index}", fmt.Sprint(i))
+		if !strings.Contains(prompt, "{line}") {
+			return fmt.Sprintf("%s%s%s", prompt, app.EOL, line)
+		}
+		return strings.ReplaceAll(prompt, "{line}", line)
+	}
+
+	// also checks the prices, which are required by --budget
+	err := app.CheckSpentBudget()
+	app.CheckIfError(err)
+
+	budget, err := app.GetBudget()
+	app.CheckIfError(err)
+
+	blobs := make([][]byte, 0)
+	for _, a := range attachments {
+		blobs = append(blobs, a.Data)
+	}
+
+	filesText := ""
+	binarySize := uint64(0)
+	if app.DryRun || budget != nil {
+		filesText, binarySize, err = app.GetApproximateContentOfFiles(files, blobs...)
+		app.CheckIfError(err)
+	}
+
+	if app.DryRun {
+		// files are submitted with each line
+		var text strings.Builder
+		for i, line := range lines {
+			text.WriteString(toPrompt(i, line))
+			text.WriteString(filesText)
+		}
+
+		err = app.OutputUsageEstimate(text.String(), uint64(text.Len()), binarySize*uint64(len(lines)))
+		app.CheckIfError(err)
+		return
+	}
+
+	app.Dbgf("Prompting %v lines with %v worker(s) ...%v", len(lines), max(concurrency, 1), app.EOL)
+
+	var firstErr error
+	failedLines := make([]string, 0)
+
+	utils.ProcessInOrder(len(lines), concurrency, func(i int) *promptEachLineResult {
+		result := &promptEachLineResult{
+			Index: i,
+			Line:  lines[i],
+		}
+
+		prompt := toPrompt(i, lines[i])
+
+		// files are submitted with each line
+		err := app.CheckBudget(prompt + filesText)
+		if err != nil {
+			result.setError(err)
+			return result
+		}
+
+		options := make([]types.AIClientPromptOptions, 0)
+		options = append(options, baseOptions...)
+
+		for _, f := range files {
+			file, err := os.Open(f)
+			if err != nil {
+				result.setError(err)
+				return result
+			}
+			defer file.Close()
+
+			options = append(options, types.AIClientPromptOptions{
+				Files: &[]io.Reader{file},
+			})
+		}
+
+		for _, a := range attachments {
+			options = append(options, types.AIClientPromptOptions{
+				Files: &[]io.Reader{a.NewReader()},
+			})
+		}
+
+		response, err := app.PromptAndValidate(prompt, options...)
+		if errors.Is(err, types.ErrRequestDumped) {
+			return result // curl command has been written instead
+		}
+		if err != nil {
+			result.setError(err)
+			return result
+		}
+
+		answer := strings.TrimSpace(response.Content)
+		result.Answer = &answer
+		result.Usage = response.Usage
+
+		return result
+	}, func(i int, result *promptEachLineResult) bool {
+		defer app.StartTiming(types.TimingPhaseRender)()
+
+		app.OutputAIUsage(result.Usage)
+
+		if result.Answer == nil && result.err == nil {
+			return true // only the curl command of the line has been written
+		}
+
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("line %d: %w", i+1, result.err)
+			}
+			failedLines = append(failedLines, fmt.Sprint(i+1))
+		}
+
+		if jsonl {
+			jsonData, err := json.Marshal(result)
+			app.CheckIfError(err)
+
+			app.Writeln(string(jsonData))
+		} else if result.Error != nil {
+			if continueOnError {
+				// keep one output line per input line
+				app.WriteErrorString(fmt.Sprintf("line %d: %s%s", i+1, result.Error.Message, app.EOL))
+				app.Writeln()
+			}
+		} else {
+			app.Writeln(strings.Join(strings.Fields(*result.Answer), " "))
+		}
+
+		// without --continue-on-error the first failed line stops the batch
+		return result.err == nil || continueOnError
+	})
+
+	app.Dbg(fmt.Sprintf("Processed %v lines", len(lines)))
+
+	app.OutputTimings()
+
+	if firstErr != nil && !continueOnError {
+		app.CheckIfError(firstErr)
+	}
+	if len(failedLines) > 0 {
+		app.CheckIfError(fmt.Errorf(
+			"%v of %v lines failed: %v",
+			len(failedLines), len(lines), strings.Join(failedLines, ", "),
+		))
+	}
+}
+
+func runPromptRepeat(app *types.AppContext, prompt string, repeat int, concurrency int, pick string, budgetText string, newOptions func() []types.AIClientPromptOptions) types.AIClientPromptResponse {
+	temperature, err := app.GetTemperature()
+	app.CheckIfError(err)
+	if temperature == 0 {
+		app.WriteErrorString(fmt.Sprintf("WARN: --repeat with a temperature of 0 will probably return the same answer %v times%s", repeat, app.EOL))
+	}
+
+	app.Dbgf("Prompting %v samples with %v worker(s) ...%v", repeat, max(concurrency, 1), app.EOL)
+
+	type sampleResult struct {
+		Duration time.Duration
+		Err      error
+		Response types.AIClientPromptResponse
+	}
+
+	var firstErr error
+	responses := make([]types.AIClientPromptResponse, 0)
+
+	utils.ProcessInOrder(repeat, concurrency, func(i int) *sampleResult {
+		result := &sampleResult{}
+
+		err := app.CheckBudget(budgetText)
+		if err != nil {
+			result.Err = err
+			return result
+		}
+
+		start := time.Now()
+		result.Response, result.Err = app.PromptAndValidate(prompt, newOptions()...)
+		result.Duration = time.Since(start)
+
+		return result
+	}, func(i int, result *sampleResult) bool {
+		if result.Err != nil {
+			firstErr = fmt.Errorf("sample %d: %w", i+1, result.Err)
+			return false
+		}
+
+		app.Dbgf("Sample %v of %v took %v%v", i+1, repeat, result.Duration.Round(time.Millisecond), app.EOL)
+		app.OutputAIUsage(result.Response.Usage)
+
+		responses = append(responses, result.Response)
+		return true
+	})
+
+	app.CheckIfError(firstErr)
+
+	model := responses[len(responses)-1].Model
+
+	if pick == "best" {
+		answers := make([]string, 0)
+		for _, r := range responses {
+			answers = append(answers, strings.TrimSpace(r.Content))
+		}
+
+		jsonPrompt, err := json.Marshal(prompt)
+		app.CheckIfError(err)
+		jsonAnswers, err := json.Marshal(answers)
+		app.CheckIfError(err)
+
+		app.Dbgf("Picking the best of %v samples ...%v", repeat, app.EOL)
+
+		err = app.CheckBudget(string(jsonPrompt) + string(jsonAnswers))
+		app.CheckIfError(err)
+
+		// the response schema of the samples is also used for the best one
+		responseSchema, responseSchemaName, err := app.GetResponseSchema()
+		app.CheckIfError(err)
+
+		response, err := app.PromptAndValidate(fmt.Sprintf(
+			`I sent the following prompt %d times and got different answers.
+This is my prompt as serialized JSON string: %s
+These are the answers as serialized JSON array: %s
+Pick the best answer or merge the answers into the best one.
+Reply only with that answer in the same format as the answers, without any comment on your selection.`,
+			repeat,
+			jsonPrompt,
+			jsonAnswers,
+		), types.AIClientPromptOptions{
+			ResponseSchema:     responseSchema,
+			ResponseSchemaName: &responseSchemaName,
+		})
+		app.CheckIfError(err)
+
+		return response
+	}
+
+	var content strings.Builder
+	if app.JSONOutput {
+		samples := make([]promptRepeatSample, 0)
+		for i, r := range responses {
+			var answer any = strings.TrimSpace(r.Content)
+			if json.Valid([]byte(r.Content)) {
+				answer = json.RawMessage(r.Content)
+			}
+
+			samples = append(samples, promptRepeatSample{
+				Answer: answer,
+				Sample: i + 1,
+			})
+		}
+
+		jsonData, err := json.Marshal(samples)
+		app.CheckIfError(err)
+
+		content.Write(jsonData)
+	} else {
+		for i, r := range responses {
+			if i > 0 {
+				content.WriteString(app.EOL)
+				content.WriteString(app.EOL)
+			}
+
+			content.WriteString(fmt.Sprintf("### Sample %d%s%s", i+1, app.EOL, app.EOL))
+			content.WriteString(strings.TrimSpace(r.Content))
+		}
+	}
+
+	return types.AIClientPromptResponse{
+		Content: content.String(),
+		Model:   model,
+	}
+}
+
+func runPromptSchemaValidation(app *types.AppContext, sampleFile string) {
+	schemaFile := strings.TrimSpace(app.SchemaFile)
+	if schemaFile == "" {
+		app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("--schema-validate-only requires --schema")))
+	}
+
+	data, err := os.ReadFile(app.GetFullPath(schemaFile))
+	app.CheckIfError(err)
+
+	schemaErrors := make([]string, 0)
+	strictIssues := make([]string, 0)
+
+	schemaName := strings.TrimSpace(app.SchemaName)
+	if schemaName != "" && utils.SanitizeJSONSchemaName(schemaName) != schemaName {
+		schemaErrors = append(schemaErrors, fmt.Sprintf("name '%v' must match ^[a-zA-Z0-9_-]+$ and have max. 64 chars", schemaName))
+	}
+
+	var value any
+	err = json.Unmarshal(data, &value)
+	if err != nil {
+		schemaErrors = append(schemaErrors, fmt.Sprintf("$: no valid JSON (%v)", err))
+	} else if schema, ok := value.(map[string]any); !ok {
+		schemaErrors = append(schemaErrors, "$: root must be a JSON object")
+	} else {
+		lintErrors, lintStrictIssues := utils.LintJSONSchema(schema)
+		schemaErrors = append(schemaErrors, lintErrors...)
+		strictIssues = append(strictIssues, lintStrictIssues...)
+
+		if strings.TrimSpace(sampleFile) != "" {
+			sampleData, err := os.ReadFile(app.GetFullPath(sampleFile))
+			app.CheckIfError(err)
+
+			violations, err := utils.ValidateJSONSchema(schema, string(sampleData))
+			app.CheckIfError(err)
+
+			for _, v := range violations {
+				schemaErrors = append(schemaErrors, fmt.Sprintf("sample %s", v))
+			}
+		}
+	}
+
+	if app.JSONOutput {
+		jsonData, err := json.MarshalIndent(map[string]any{
+			"errors":        schemaErrors,
+			"strict_issues": strictIssues,
+			"valid":         len(schemaErrors) == 0,
+		}, "", "  ")
+		app.CheckIfError(err)
+
+		app.Writeln(string(jsonData))
+	} else {
+		for _, e := range schemaErrors {
+			app.Writeln(fmt.Sprintf("ERROR: %s", e))
+		}
+		for _, i := range strictIssues {
+			app.Writeln(fmt.Sprintf("STRICT: %s", i))
+		}
+
+		if len(schemaErrors) == 0 {
+			app.Writeln(fmt.Sprintf("'%s' is valid", schemaFile))
+		}
+	}
+
+	if len(schemaErrors) > 0 {
+		app.CheckIfError(types.NewTypedError(
+			types.ErrorTypeSchemaValidation,
+			fmt.Errorf("validation of '%s' found %v error(s)", schemaFile, len(schemaErrors)),
+		))
+	}
+}
+
+// resolvePromptPositionalArgs builds the prompt of `args`, where arguments
+// with `@` prefix are paths or glob patterns of files, keeping their order:
+// text files are embedded at their position and all other files are returned
+// as attachments, which are referenced at their position. `@@` escapes `@`.
+func resolvePromptPositionalArgs(app *types.AppContext, args []string) (string, []string, error) {
+	parts := make([]string, 0)
+	attachments := make([]string, 0)
+
+	for _, arg := range args {
+		if strings.HasPrefix(arg, "@@") || !strings.HasPrefix(arg, "@") {
+			parts = append(parts, strings.TrimPrefix(arg, "@"))
+			continue
+		}
+
+		pattern := strings.TrimSpace(arg[1:])
+		if pattern == "" {
+			return "", attachments, types.NewTypedError(types.ErrorTypeUsage, errors.New("no file defined after @"))
+		}
+
+		var files []string
+		if stat, err := os.Stat(app.GetFullPath(pattern)); err == nil && !stat.IsDir() {
+			files = []string{app.GetFullPath(pattern)}
+		} else {
+			f, err := app.FindFiles(pattern)
+			if err != nil {
+				return "", attachments, err
+			}
+			files = f
+		}
+		if len(files) == 0 {
+			return "", attachments, types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("no files found for '%s'", arg))
+		}
+
+		for _, f := range files {
+			relPath, err := filepath.Rel(app.WorkingDirectory, f)
+			if err != nil {
+				relPath = f
+			}
+
+			data, err := os.ReadFile(f)
+			if err != nil {
+				return "", attachments, err
+			}
+
+			if utils.MaybeBinary(data) {
+				attachments = append(attachments, f)
+
+				parts = append(parts, fmt.Sprintf("[see attached file '%s']", relPath))
+				continue
+			}
+
+			text, err := app.RedactText(string(data), relPath)
+			if err != nil {
+				return "", attachments, err
+			}
+
+			jsonData, err := json.Marshal(text)
+			if err != nil {
+				return "", attachments, err
+			}
+
+			parts = append(parts, fmt.Sprintf("This is the content of the file '%s' as serialized JSON string: %s", relPath, jsonData))
+		}
+	}
+
+	return strings.Join(parts, app.EOL+app.EOL), attachments, nil
+}
+
 // Init_prompt_Command initializes the `prompt` command.
 func Init_prompt_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var attachGlobPerArg bool
+	var attachLastOutput bool
+	var attachStdinAsFile string
+	var concurrency uint16
+	var contextFromCommand string
+	var contextFromGitDiff bool
+	var continueOnError bool
+	var eachLine bool
+	var failOnEmpty bool
+	var jsonl bool
+	var maxCommandOutput int
+	var pick string
+	var renderMermaid string
+	var repeat uint16
+	var sample string
+	var schemaValidateOnly bool
+	var staged bool
+	var template string
+	var writeFiles bool
+	var writeFilesDryRun bool
+
 	var promptCmd = &cobra.Command{
 		Use:     "prompt [PROMPT]",
 		Aliases: []string{"p"},
 		Short:   "AI prompt",
 		Long:    `Sends a prompt to AI.`,
 		Run: func(cmd *cobra.Command, args []string) {
+			if schemaValidateOnly {
+				// no AI required
+				runPromptSchemaValidation(app, sample)
+				return
+			}
+			if sample != "" {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("--sample requires --schema-validate-only")))
+			}
+
 			app.InitAI()
 
 			files, err := app.GetFiles()
@@ -48,6 +552,92 @@ func Init_prompt_Command(app *types.AppContext, parentCmd *cobra.Command) {
 			responseSchema, responseSchemaName, err := app.GetResponseSchema()
 			app.CheckIfError(err)
 
+			attachments, err := app.GetUrlAttachments()
+			app.CheckIfError(err)
+
+			writeFiles = writeFiles || writeFilesDryRun
+			if writeFiles {
+				if eachLine {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--write-files cannot be used with --each-line")))
+				}
+				if repeat > 1 && pick == "" {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--write-files requires --pick with --repeat")))
+				}
+
+				if responseSchema == nil {
+					responseSchema = newPromptFilesResponseSchema()
+				}
+				if strings.TrimSpace(responseSchemaName) == "" {
+					responseSchemaName = "WriteFilesSchema"
+				}
+			}
+
+			if attachGlobPerArg && eachLine {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--attach-glob-per-arg cannot be used with --each-line")))
+			}
+
+			if renderMermaid != "" {
+				if eachLine {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--render-mermaid cannot be used with --each-line")))
+				}
+
+				// fail before sending the request
+				_, err = app.GetMermaidFormat()
+				app.CheckIfError(err)
+			}
+
+			pick = strings.TrimSpace(strings.ToLower(pick))
+			if pick != "" && pick != "best" {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("'%v' is no valid value for --pick, use best", pick)))
+			}
+			if repeat > 1 {
+				if eachLine {
+					app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--repeat cannot be used with --each-line")))
+				}
+
+				if !cmd.Flags().Changed("concurrency") {
+					concurrency = min(repeat, defaultPromptRepeatConcurrency)
+				}
+			} else if pick != "" {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, fmt.Errorf("--pick requires --repeat with at least 2 samples")))
+			}
+
+			if eachLine {
+				// STDIN contains the prompts
+				runPromptEachLine(app, strings.TrimSpace(strings.Join(args, " ")), template, files, attachments, int(concurrency), continueOnError, jsonl, []types.AIClientPromptOptions{
+					{
+						ResponseSchema:     responseSchema,
+						ResponseSchemaName: &responseSchemaName,
+					},
+				})
+				return
+			}
+
+			stopInputTiming := app.StartTiming(types.TimingPhaseInput)
+
+			var stdinData []byte
+			attachStdinAsFile = strings.TrimSpace(attachStdinAsFile)
+			if attachStdinAsFile != "" || app.StdinBinary {
+				// read STDIN before the input is collected,
+				// so it does not become part of the prompt
+				var stdinName string
+				stdinData, _, stdinName, err = app.ReadBinaryStdin()
+				app.CheckIfError(err)
+
+				if attachStdinAsFile == "" {
+					attachStdinAsFile = stdinName
+				}
+			}
+
+			if attachGlobPerArg {
+				// files with @ prefix at their positions
+				positionalPrompt, positionalFiles, err := resolvePromptPositionalArgs(app, args)
+				app.CheckIfError(err)
+
+				args = []string{positionalPrompt}
+				files = append(files, positionalFiles...) // attached after the other files
+			}
+
 			prompt, err := app.GetInput(args)
 			app.CheckIfError(err)
 
@@ -56,13 +646,78 @@ func Init_prompt_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				app.CheckIfError(errors.New("no prompt defined"))
 			}
 
-			options := make([]types.AIClientPromptOptions, 0)
+			if attachLastOutput {
+				lastOutput, err := app.GetLastOutput()
+				app.CheckIfError(err)
 
-			options = append(options, types.AIClientPromptOptions{
-				ResponseSchema:     responseSchema,
-				ResponseSchemaName: &responseSchemaName,
-			})
+				if lastOutput == nil {
+					app.CheckIfError(errors.New("no last output found"))
+				}
+
+				jsonData, err := json.Marshal(lastOutput)
+				app.CheckIfError(err)
 
+				prompt = fmt.Sprintf(
+					`This is the output of my previous request as serialized JSON string: %s
+Take it as context for the following: %s`,
+					jsonData,
+					prompt,
+				)
+			}
+
+			if strings.TrimSpace(contextFromCommand) != "" {
+				result, err := app.RunShellCommand(contextFromCommand, maxCommandOutput)
+				app.CheckIfError(err)
+
+				output := result.Output
+				if result.Truncated {
+					output += fmt.Sprintf("%v[output truncated after %v bytes]", app.EOL, maxCommandOutput)
+				}
+
+				jsonData, err := json.Marshal(output)
+				app.CheckIfError(err)
+
+				prompt = fmt.Sprintf(
+					`This is the output of the command '%s', which exited with code %d, as serialized JSON string: %s
+Take it as context for the following: %s`,
+					contextFromCommand,
+					result.ExitCode,
+					jsonData,
+					prompt,
+				)
+			}
+
+			if contextFromGitDiff {
+				git, err := app.NewGitClient()
+				app.CheckIfError(err)
+
+				diff, err := git.GetDiff(staged)
+				app.CheckIfError(err)
+
+				if strings.TrimSpace(diff) == "" {
+					app.CheckIfError(errors.New("no changes found in git diff"))
+				}
+
+				jsonData, err := json.Marshal(diff)
+				app.CheckIfError(err)
+
+				diffName := "git diff"
+				if staged {
+					diffName = "git diff --staged"
+				}
+
+				prompt = fmt.Sprintf(
+					`This is the output of '%s' of my repository as serialized JSON string: %s
+Take it as context for the following: %s`,
+					diffName,
+					jsonData,
+					prompt,
+				)
+			}
+
+			stopInputTiming()
+
+			var openedFilesMutex sync.Mutex
 			openedFiles := make([]*os.File, 0)
 			defer func() {
 				for _, of := range openedFiles {
@@ -70,25 +725,153 @@ func Init_prompt_Command(app *types.AppContext, parentCmd *cobra.Command) {
 				}
 			}()
 
-			for _, f := range files {
-				file, err := os.Open(f)
-				app.CheckIfError(err)
-
-				openedFiles = append(openedFiles, file)
+			// with --repeat, each sample needs its own readers
+			newOptions := func() []types.AIClientPromptOptions {
+				options := make([]types.AIClientPromptOptions, 0)
 
 				options = append(options, types.AIClientPromptOptions{
-					Files: &[]io.Reader{file},
+					ResponseSchema:     responseSchema,
+					ResponseSchemaName: &responseSchemaName,
 				})
+
+				for _, f := range files {
+					file, err := os.Open(f)
+					app.CheckIfError(err)
+
+					openedFilesMutex.Lock()
+					openedFiles = append(openedFiles, file)
+					openedFilesMutex.Unlock()
+
+					options = append(options, types.AIClientPromptOptions{
+						Files: &[]io.Reader{file},
+					})
+				}
+
+				if stdinData != nil {
+					app.Dbgf("Attaching %v bytes from STDIN as '%v' ...%v", len(stdinData), attachStdinAsFile, app.EOL)
+
+					options = append(options, types.AIClientPromptOptions{
+						Files: &[]io.Reader{types.NewNamedReader(bytes.NewReader(stdinData), attachStdinAsFile)},
+					})
+				}
+
+				for _, a := range attachments {
+					app.Dbgf("Attaching %v bytes from '%v' as '%v' ...%v", len(a.Data), a.Url, a.Name, app.EOL)
+
+					options = append(options, types.AIClientPromptOptions{
+						Files: &[]io.Reader{a.NewReader()},
+					})
+				}
+
+				return options
+			}
+
+			blobs := make([][]byte, 0)
+			if stdinData != nil {
+				blobs = append(blobs, stdinData)
+			}
+			for _, a := range attachments {
+				blobs = append(blobs, a.Data)
+			}
+
+			if app.DryRun {
+				filesText, binarySize, err := app.GetApproximateContentOfFiles(files, blobs...)
+				app.CheckIfError(err)
+
+				// files are submitted with each sample
+				samples := max(int(repeat), 1)
+				text := strings.Repeat(prompt+filesText, samples)
+
+				err = app.OutputUsageEstimate(text, uint64(len(text)), binarySize*uint64(samples))
+				app.CheckIfError(err)
+				return
+			}
+
+			budgetText := ""
+			budget, err := app.GetBudget()
+			app.CheckIfError(err)
+			if budget != nil {
+				// refuse requests, which would be too expensive
+				filesText, _, err := app.GetApproximateContentOfFiles(files, blobs...)
+				app.CheckIfError(err)
+
+				budgetText = prompt + filesText
+
+				err = app.CheckBudget(budgetText)
+				app.CheckIfError(err)
+			}
+
+			var response types.AIClientPromptResponse
+			if repeat > 1 {
+				response = runPromptRepeat(app, prompt, int(repeat), int(concurrency), pick, budgetText, newOptions)
+			} else {
+				response, err = app.PromptAndValidate(prompt, newOptions()...)
+				app.CheckIfError(err)
+			}
+
+			if !cmd.Flags().Changed("fail-on-empty") {
+				// scripts usually expect content
+				failOnEmpty = !term.IsTerminal(int(app.Stdout.Fd()))
+			}
+			if failOnEmpty && strings.TrimSpace(response.Content) == "" {
+				app.CheckIfError(types.NewTypedError(
+					types.ErrorTypeEmptyResponse,
+					fmt.Errorf("model '%v' returned an empty answer", response.Model),
+				))
+			}
+
+			stopRenderTiming := app.StartTiming(types.TimingPhaseRender)
+			if writeFiles {
+				writePromptResponseFiles(app, response.Content, writeFilesDryRun)
+			} else {
+				app.OutputAIAnswer(response.Content)
+			}
+			stopRenderTiming()
+
+			app.OutputAIUsage(response.Usage)
+
+			if renderMermaid != "" {
+				_, err := app.RenderMermaidDiagrams(response.Content, renderMermaid)
+				app.CheckIfError(err)
 			}
 
-			response, err := app.AI.Prompt(prompt, options...)
+			err = app.UpdateLastOutput(response.Content)
 			app.CheckIfError(err)
 
-			app.OutputAIAnswer(response.Content)
+			app.OutputTimings()
 		},
 	}
 
 	app.WithPromptCLIFlags(promptCmd)
+	app.WithAttachUrlCLIFlags(promptCmd)
+	app.WithBudgetCLIFlags(promptCmd)
+	app.WithDryRunCliFlags(promptCmd)
+	app.WithMermaidCLIFlags(promptCmd, &renderMermaid)
+	app.WithStdinBinaryCLIFlags(promptCmd)
+	app.WithTeeCLIFlags(promptCmd)
+	app.WithYesCliFlags(promptCmd)
+	promptCmd.Flags().BoolVarP(&attachGlobPerArg, "attach-glob-per-arg", "", false, "handle arguments with @ prefix as files or glob patterns, which are embedded or attached at their positions")
+	promptCmd.Flags().BoolVarP(&attachLastOutput, "attach-last-output", "", false, "attach output of previous run as context")
+	promptCmd.Flags().StringVarP(&attachStdinAsFile, "attach-stdin-as-file", "", "", "attach data from STDIN as file with this name instead of using it as prompt")
+	promptCmd.Flags().Uint16VarP(&concurrency, "concurrency", "", 1, "number of lines of --each-line or samples of --repeat to prompt in parallel (default for --repeat: 4)")
+	promptCmd.Flags().StringVarP(&contextFromCommand, "context-from-command", "", "", "run shell command and attach its output as context")
+	promptCmd.Flags().BoolVarP(&contextFromGitDiff, "context-from-git-diff", "", false, "attach current git diff as context")
+	promptCmd.Flags().BoolVarP(&continueOnError, "continue-on-error", "", false, "process all lines of --each-line even if some of them fail and summarize the failures at the end")
+	promptCmd.Flags().BoolVarP(&app.Dedent, "dedent", "", false, "remove common indentation and repeating empty lines from input")
+	promptCmd.Flags().BoolVarP(&eachLine, "each-line", "", false, "use each non-empty line of STDIN as separate prompt")
+	promptCmd.Flags().BoolVarP(&failOnEmpty, "fail-on-empty", "", false, "exit with error if answer is empty (default if STDOUT is no terminal)")
+	promptCmd.Flags().BoolVarP(&jsonl, "jsonl", "", false, "output results of --each-line as JSON Lines")
+	promptCmd.Flags().IntVarP(&maxCommandOutput, "max-command-output", "", 65536, "maximum number of bytes of output of --context-from-command (-1 for no limit)")
+	promptCmd.Flags().StringVarP(&pick, "pick", "", "", "with best, let the model pick or merge the best answer of the samples of --repeat")
+	promptCmd.Flags().Uint16VarP(&repeat, "repeat", "", 0, "send the prompt this number of times and output all answers numbered")
+	promptCmd.Flags().StringVarP(&sample, "sample", "", "", "JSON file, which is validated against --schema with --schema-validate-only")
+	promptCmd.Flags().StringVarP(&app.SaveAttachmentsDir, "save-attachments", "", "", "write extracted text of attachments to this directory")
+	promptCmd.Flags().BoolVarP(&schemaValidateOnly, "schema-validate-only", "", false, "only check the file of --schema for errors and OpenAI strict mode issues without sending a prompt")
+	promptCmd.Flags().BoolVarP(&staged, "staged", "", false, "use staged changes for --context-from-git-diff")
+	promptCmd.Flags().BoolVarP(&app.VerboseTiming, "verbose-timing", "", false, "write durations of input gathering, file extraction, request build, network round-trip and rendering to STDERR")
+	promptCmd.Flags().StringVarP(&template, "template", "", "", "prompt template for --each-line with {line} and {index} placeholders")
+	promptCmd.Flags().BoolVarP(&writeFiles, "write-files", "", false, "write the files of an answer in {\"files\":[{\"path\":...,\"content\":...}]} shape into the current directory instead of printing it")
+	promptCmd.Flags().BoolVarP(&writeFilesDryRun, "write-files-dry-run", "", false, "only list the files, which would be written by --write-files")
 
 	parentCmd.AddCommand(
 		promptCmd,
@@ -0,0 +1,160 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+)
+
+type promptResponseFiles struct {
+	Files *[]promptResponseFile `json:"files"`
+}
+
+type promptResponseFile struct {
+	Content *string `json:"content"`
+	Path    string  `json:"path"`
+}
+
+func newPromptFilesResponseSchema() *map[string]any {
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"files"},
+		"properties": map[string]any{
+			"files": map[string]any{
+				"type":        "array",
+				"description": "List of files to write.",
+				"items": map[string]any{
+					"type":     "object",
+					"required": []string{"path", "content"},
+					"properties": map[string]any{
+						"path": map[string]any{
+							"description": "Relative path of the file using / as path separators.",
+							"type":        "string",
+						},
+						"content": map[string]any{
+							"description": "Text content or data URI if file contains binary data like image, audio or video.",
+							"type":        "string",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// writePromptResponseFiles writes the files of `answer`, which must
+// have the shape `{"files":[{"path":"...","content":"..."}]}`, into the
+// working directory or only lists them, if `dryRun` is `true`.
+func writePromptResponseFiles(app *types.AppContext, answer string, dryRun bool) {
+	var response promptResponseFiles
+	err := json.Unmarshal([]byte(answer), &response)
+	if err != nil {
+		app.CheckIfError(types.NewTypedError(types.ErrorTypeInvalidJSON, err))
+	}
+	if response.Files == nil {
+		app.CheckIfError(types.NewTypedError(types.ErrorTypeSchemaValidation, errors.New(`answer has no "files" property with a list of objects with "path" and "content"`)))
+	}
+
+	type fileToWrite struct {
+		data     []byte
+		exists   bool
+		fullPath string
+		relPath  string
+	}
+
+	// check everything before anything is written
+	filesToWrite := make([]fileToWrite, 0)
+	for i, f := range *response.Files {
+		if strings.TrimSpace(f.Path) == "" || f.Content == nil {
+			app.CheckIfError(types.NewTypedError(types.ErrorTypeSchemaValidation, fmt.Errorf(`file #%v of answer has no "path" or "content"`, i+1)))
+		}
+
+		relPath, fullPath, err := getInitProjectFilePath(app.WorkingDirectory, f.Path)
+		app.CheckIfError(err)
+
+		_, err = os.Stat(fullPath)
+		if err != nil && !os.IsNotExist(err) {
+			app.CheckIfError(err)
+		}
+
+		filesToWrite = append(filesToWrite, fileToWrite{
+			data:     getFileDataOf(*f.Content),
+			exists:   err == nil,
+			fullPath: fullPath,
+			relPath:  relPath,
+		})
+	}
+
+	if len(filesToWrite) == 0 {
+		app.Dbgf("Answer contains no files%v", app.EOL)
+		return
+	}
+
+	describe := func(f fileToWrite) string {
+		action := "create"
+		if f.exists {
+			action = "overwrite"
+		}
+		return fmt.Sprintf("%s\t%s\t%d bytes", action, f.relPath, len(f.data))
+	}
+
+	if dryRun {
+		for _, f := range filesToWrite {
+			app.Writeln(describe(f))
+		}
+		return
+	}
+
+	if !app.AlwaysYes {
+		for _, f := range filesToWrite {
+			app.WriteErrorString(describe(f) + app.EOL)
+		}
+		app.WriteErrorString(fmt.Sprintf("Write %v file(s) [y(es)/N(o)]?: ", len(filesToWrite)))
+
+		reader := bufio.NewReader(app.Stdin)
+
+		input, _ := reader.ReadString('\n')
+		input = strings.TrimSpace(strings.ToLower(input))
+
+		if input != "y" && input != "yes" {
+			return
+		}
+	}
+
+	for _, f := range filesToWrite {
+		err := ensureDirOfFile(f.fullPath)
+		app.CheckIfError(err)
+
+		err = os.WriteFile(f.fullPath, f.data, 0644)
+		app.CheckIfError(err)
+
+		app.Writeln(fmt.Sprintf("Wrote %s", f.relPath))
+	}
+}
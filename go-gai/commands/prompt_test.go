@@ -0,0 +1,708 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+)
+
+func TestPromptFailOnEmpty(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			return " \n "
+		})
+
+		args := []string{"prompt", "Say nothing"}
+		if testCase == "flag" {
+			args = append(args, "--fail-on-empty")
+		}
+
+		runTestCommand(t, app, Init_prompt_Command, args...)
+		return
+	}
+
+	for _, testCase := range []string{"default", "flag"} {
+		output, exitCode := runTestProcess(t, "TestPromptFailOnEmpty", testCase)
+
+		if exitCode != 7 {
+			t.Errorf("%v: expected exit code 7, got %d", testCase, exitCode)
+		}
+		if !strings.Contains(output, "model 'gpt-4o' returned an empty answer") {
+			t.Errorf("%v: expected error message about empty answer, got %q", testCase, output)
+		}
+	}
+}
+
+func TestPromptNoFailOnEmpty(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		return ""
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Say nothing", "--fail-on-empty=false")
+
+	output := readTestOutput(t, app.Stderr)
+	if output != "" {
+		t.Errorf("expected no error, got %q", output)
+	}
+}
+
+func TestPromptContextFromGitDiff(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	for _, staged := range []bool{false, true} {
+		app := newTestApp(t, map[string]string{})
+
+		prompts := make([]string, 0)
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			prompts = append(prompts, messages[len(messages)-1].Content)
+			return "Nothing breaks."
+		})
+
+		writeTestFile(t, app, "staged.txt", "old staged")
+		writeTestFile(t, app, "unstaged.txt", "old unstaged")
+		runTestGit(t, app, "init", "-q")
+		runTestGit(t, app, "add", "-A")
+		runTestGit(t, app, "commit", "-q", "-m", "initial")
+
+		writeTestFile(t, app, "staged.txt", "new staged")
+		writeTestFile(t, app, "unstaged.txt", "new unstaged")
+		runTestGit(t, app, "add", "staged.txt")
+
+		args := []string{"prompt", "Why might this break tests?", "--context-from-git-diff"}
+		if staged {
+			args = append(args, "--staged")
+		}
+
+		runTestCommand(t, app, Init_prompt_Command, args...)
+
+		if len(prompts) != 1 {
+			t.Fatalf("staged=%v: expected 1 request, got %d", staged, len(prompts))
+		}
+
+		prompt := prompts[0]
+		if !strings.HasSuffix(prompt, "Take it as context for the following: Why might this break tests?") {
+			t.Errorf("staged=%v: prompt does not end with question %q", staged, prompt)
+		}
+
+		expectedDiff, unexpectedDiff := "+new unstaged", "+new staged"
+		expectedName := "This is the output of 'git diff' of my repository"
+		if staged {
+			expectedDiff, unexpectedDiff = unexpectedDiff, expectedDiff
+			expectedName = "This is the output of 'git diff --staged' of my repository"
+		}
+
+		if !strings.HasPrefix(prompt, expectedName) {
+			t.Errorf("staged=%v: unexpected start of prompt %q", staged, prompt)
+		}
+		if !strings.Contains(prompt, expectedDiff) || strings.Contains(prompt, unexpectedDiff) {
+			t.Errorf("staged=%v: unexpected diff in prompt %q", staged, prompt)
+		}
+	}
+}
+
+func TestPromptContextFromCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands require a POSIX shell")
+	}
+
+	app := newTestApp(t, map[string]string{})
+
+	prompts := make([]string, 0)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		prompts = append(prompts, messages[len(messages)-1].Content)
+		return "The test expects 42."
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Explain these test failures",
+		"--context-from-command", "echo '--- FAIL: TestAnswer'; echo 'got 41' >&2; exit 1",
+		"--max-command-output", "25")
+
+	if len(prompts) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(prompts))
+	}
+
+	expected := `This is the output of the command 'echo '--- FAIL: TestAnswer'; echo 'got 41' >&2; exit 1', which exited with code 1, as serialized JSON string: "--- FAIL: TestAnswer\ngot \n[output truncated after 25 bytes]"
+Take it as context for the following: Explain these test failures`
+	if prompts[0] != expected {
+		t.Errorf("unexpected prompt %q", prompts[0])
+	}
+}
+
+func TestPromptAttachStdinAsFile(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	type testContentPart struct {
+		File struct {
+			FileData string `json:"file_data"`
+			Filename string `json:"filename"`
+		} `json:"file"`
+		Text string `json:"text"`
+		Type string `json:"type"`
+	}
+
+	parts := make([]testContentPart, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content []testContentPart `json:"content"`
+			} `json:"messages"`
+		}
+		data, _ := io.ReadAll(r.Body)
+		json.Unmarshal(data, &body)
+		for _, m := range body.Messages {
+			parts = append(parts, m.Content...)
+		}
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+			return "Sales went up."
+		})(w, r)
+	})
+	startTestOpenAIServer(t, app, mux)
+
+	report := "# Report\n\nSales went up."
+	writeTestStdin(t, app, report)
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Summarize the report", "--attach-stdin-as-file", "report.md")
+
+	if len(parts) != 2 {
+		t.Fatalf("expected 2 content parts, got %+v", parts)
+	}
+
+	// arguments are the prompt ...
+	if parts[0].Type != "text" || parts[0].Text != "Summarize the report" {
+		t.Errorf("unexpected prompt %+v", parts[0])
+	}
+
+	// ... and STDIN is the named file
+	file := parts[1]
+	if file.Type != "file" || file.File.Filename != "report.md" {
+		t.Errorf("unexpected attachment %+v", file)
+	}
+	if !strings.Contains(file.File.FileData, base64.StdEncoding.EncodeToString([]byte(report))) {
+		t.Errorf("unexpected data of attachment %q", file.File.FileData)
+	}
+}
+
+func TestPromptStdinBinary(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	bodies := make([]string, 0)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+
+		r.Body = io.NopCloser(bytes.NewReader(data))
+		newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+			return "A red pixel."
+		})(w, r)
+	})
+	startTestOpenAIServer(t, app, mux)
+
+	png := newTestPNG(t, 1, 1)
+	writeTestStdin(t, app, string(png))
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "What is this?", "--stdin-binary")
+
+	if len(bodies) != 1 {
+		t.Fatalf("expected 1 request, got %d", len(bodies))
+	}
+
+	// STDIN is an image and not part of the prompt
+	if !strings.Contains(bodies[0], `"text":"What is this?"`) {
+		t.Errorf("prompt not found in request %q", bodies[0])
+	}
+	if !strings.Contains(bodies[0], "data:image/png;base64,"+base64.StdEncoding.EncodeToString(png)) {
+		t.Errorf("image not found in request %q", bodies[0])
+	}
+}
+
+func TestPromptEachLine(t *testing.T) {
+	for _, jsonl := range []bool{false, true} {
+		app := newTestApp(t, map[string]string{})
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			return strings.ToUpper(messages[len(messages)-1].Content) + "\n  done"
+		})
+
+		writeTestStdin(t, app, "apple\n\nbanana\n  cherry  \n")
+
+		args := []string{"prompt", "--each-line", "--template", "translate {line} ({index})", "--concurrency", "3"}
+		if jsonl {
+			args = append(args, "--jsonl")
+		}
+
+		runTestCommand(t, app, Init_prompt_Command, args...)
+
+		expected := "TRANSLATE APPLE (0) done\nTRANSLATE BANANA (1) done\nTRANSLATE CHERRY (2) done\n"
+		if jsonl {
+			expected = `{"answer":"TRANSLATE APPLE (0)\n  done","index":0,"line":"apple"}
+{"answer":"TRANSLATE BANANA (1)\n  done","index":1,"line":"banana"}
+{"answer":"TRANSLATE CHERRY (2)\n  done","index":2,"line":"cherry"}
+`
+		}
+
+		output := readTestOutput(t, app.Stdout)
+		if output != expected {
+			t.Errorf("jsonl=%v: unexpected output %q", jsonl, output)
+		}
+	}
+}
+
+func TestPromptEachLineContinueOnError(t *testing.T) {
+	if testProcessCase() != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+
+		mux := http.NewServeMux()
+		mux.HandleFunc("/v1/chat/completions", func(w http.ResponseWriter, r *http.Request) {
+			data, _ := io.ReadAll(r.Body)
+			if strings.Contains(string(data), "banana") {
+				http.Error(w, "failed", http.StatusBadRequest)
+				return
+			}
+
+			r.Body = io.NopCloser(bytes.NewReader(data))
+			newTestChatCompletionsHandler(func(messages []testChatMessage) string {
+				return "OK"
+			})(w, r)
+		})
+		startTestOpenAIServer(t, app, mux)
+
+		writeTestStdin(t, app, "apple\nbanana\ncherry\n")
+
+		runTestCommand(t, app, Init_prompt_Command, "prompt", "--each-line", "--continue-on-error")
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestPromptEachLineContinueOnError", "continue")
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(output, "line 2: request failed with status 400: failed") {
+		t.Errorf("expected error of line 2, got %q", output)
+	}
+	if !strings.HasSuffix(output, "1 of 3 lines failed: 2\n") {
+		t.Errorf("expected summary of failures, got %q", output)
+	}
+}
+
+func TestPromptBudget(t *testing.T) {
+	if testProcessCase() != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{
+			"GAI_PRICE_INPUT": "1000",
+		})
+		app.Stderr = os.Stderr
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			os.Exit(42) // must not be sent
+			return ""
+		})
+
+		runTestCommand(t, app, Init_prompt_Command, "prompt", "Tell me a joke.", "--budget", "0.5")
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestPromptBudget", "budget")
+
+	if exitCode != 9 {
+		t.Errorf("expected exit code 9, got %d", exitCode)
+	}
+	if !strings.Contains(output, "exceed the budget of 0.500000") {
+		t.Errorf("expected budget error, got %q", output)
+	}
+}
+
+func TestPromptShowCost(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_PRICE_INPUT":  "1",
+		"GAI_PRICE_OUTPUT": "2",
+	})
+	app.ShowCost = true
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		return "OK"
+	})
+
+	writeTestStdin(t, app, "a\nb\nc\n")
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "--each-line")
+	app.OutputSessionCosts()
+
+	// each answer uses 2 prompt and 1 completion token
+	expected := "Requests: 3\nToken usage: prompt=6 completion=3 total=9\nEstimated costs: 0.012000\n"
+
+	output := readTestOutput(t, app.Stderr)
+	if output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestPromptVerboseTiming(t *testing.T) {
+	app := newTestApp(t, nil)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		return "OK"
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Hi", "--verbose-timing")
+
+	output := readTestOutput(t, app.Stderr)
+
+	phases := []string{
+		"input gathering:",
+		"file reading/extraction:",
+		"request build:",
+		"network round-trip:",
+		"rendering:",
+	}
+	for _, phase := range phases {
+		if !strings.Contains(output, phase) {
+			t.Errorf("expected phase %q in %q", phase, output)
+		}
+	}
+
+	// network round-trip of the mock server has been measured
+	for _, line := range strings.Split(output, "\n") {
+		if strings.HasPrefix(line, "network round-trip:") {
+			if strings.HasSuffix(line, " 0s") {
+				t.Errorf("expected measured network round-trip, got %q", line)
+			}
+		}
+	}
+
+	answer := readTestOutput(t, app.Stdout)
+	if strings.Contains(answer, "network round-trip") {
+		t.Errorf("expected no timings in STDOUT, got %q", answer)
+	}
+}
+
+func TestPromptSaveAttachments(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_ATTACHMENT_MAX_INLINE": "1", // always extract text
+	})
+	app.FilePatterns = []string{"docs/*"}
+
+	writeTestFile(t, app, "docs/report.pdf", string(newTestPDF("Quarterly numbers")))
+	writeTestFile(t, app, "docs/letter.docx", string(newTestDOCX(t, "Dear customer")))
+
+	var submitted string
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		submitted = messages[len(messages)-1].Content
+		return "OK"
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Summarize", "--save-attachments", "extracted")
+
+	// paths inside working directory are kept
+	expectedFiles := map[string]string{
+		"docs/report.pdf.txt":  "Quarterly numbers",
+		"docs/letter.docx.txt": "Dear customer",
+	}
+	for name, expected := range expectedFiles {
+		data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, "extracted", filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+
+		// same text as submitted to the model
+		text := strings.TrimSpace(string(data))
+		if !strings.Contains(text, expected) || !strings.Contains(submitted, text) {
+			t.Errorf("%v: expected submitted text with %q, got %q", name, expected, text)
+		}
+	}
+}
+
+func TestPromptSchemaValidateOnly(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	writeTestFile(t, app, "schema.json", `{"type": "object", "required": ["title"], "properties": {"title": {"type": "string"}}}`)
+	writeTestFile(t, app, "sample.json", `{"title": "Hello"}`)
+
+	// no server, because no request must be sent
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "--schema", "schema.json", "--sample", "sample.json", "--schema-validate-only")
+
+	expected := "STRICT: $: 'additionalProperties' must be false\n'schema.json' is valid\n"
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+}
+
+func TestPromptSchemaValidateOnlyInvalid(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		app.Stdout = os.Stderr
+		app.WorkingDirectory = testCase
+
+		runTestCommand(t, app, Init_prompt_Command, "prompt", "--schema", "schema.json", "--sample", "sample.json", "--schema-validate-only")
+		return
+	}
+
+	app := newTestApp(t, map[string]string{})
+	writeTestFile(t, app, "schema.json", `{"type": "object", "additionalProperties": false, "required": ["title", "body"], "properties": {"title": {"type": "string"}}}`)
+	writeTestFile(t, app, "sample.json", `{"title": 42}`)
+
+	output, exitCode := runTestProcess(t, "TestPromptSchemaValidateOnlyInvalid", app.WorkingDirectory)
+
+	if exitCode != 8 {
+		t.Errorf("expected exit code 8, got %d", exitCode)
+	}
+	for _, expected := range []string{
+		"ERROR: $: required property 'body' is not defined in 'properties'\n",
+		"ERROR: sample $: missing required property 'body'\n",
+		"ERROR: sample $.title: expected string, but got integer\n",
+		"validation of 'schema.json' found 3 error(s)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("expected %q in %q", expected, output)
+		}
+	}
+}
+
+const testPromptFilesAnswer = `{"files": [
+	{"path": "cmd/main.go", "content": "package main"},
+	{"path": "README.md", "content": "# New"},
+	{"path": "data.bin", "content": "data:application/octet-stream;base64,AAEC"}
+]}`
+
+func TestPromptWriteFiles(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	writeTestFile(t, app, "README.md", "# Old")
+
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		return testPromptFilesAnswer
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Create a project", "--write-files", "--yes")
+
+	expectedFiles := map[string]string{
+		"cmd/main.go": "package main",
+		"README.md":   "# New",
+		"data.bin":    "\x00\x01\x02",
+	}
+	for name, expected := range expectedFiles {
+		data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, filepath.FromSlash(name)))
+		if err != nil {
+			t.Errorf("%v: %v", name, err)
+			continue
+		}
+
+		if string(data) != expected {
+			t.Errorf("%v: expected %q, got %q", name, expected, string(data))
+		}
+	}
+
+	// answer is not printed
+	if output := readTestOutput(t, app.Stdout); strings.Contains(output, "files") {
+		t.Errorf("expected no answer in output, got %q", output)
+	}
+}
+
+func TestPromptWriteFilesDryRun(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+	writeTestFile(t, app, "README.md", "# Old")
+
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		return testPromptFilesAnswer
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "Create a project", "--write-files-dry-run")
+
+	expected := "create\tcmd/main.go\t12 bytes\noverwrite\tREADME.md\t5 bytes\ncreate\tdata.bin\t3 bytes\n"
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected %q, got %q", expected, output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, "README.md"))
+	if err != nil || string(data) != "# Old" {
+		t.Errorf("expected unchanged README.md, got %q (%v)", string(data), err)
+	}
+	if _, err := os.Stat(filepath.Join(app.WorkingDirectory, "cmd")); !os.IsNotExist(err) {
+		t.Errorf("expected no written files, got %v", err)
+	}
+}
+
+func TestPromptWriteFilesOutsideWorkingDirectory(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		app.WorkingDirectory = testCase
+
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			return `{"files": [{"path": "ok.txt", "content": "OK"}, {"path": "../evil.txt", "content": "evil"}]}`
+		})
+
+		runTestCommand(t, app, Init_prompt_Command, "prompt", "Create files", "--write-files", "--yes")
+		return
+	}
+
+	app := newTestApp(t, map[string]string{})
+
+	output, exitCode := runTestProcess(t, "TestPromptWriteFilesOutsideWorkingDirectory", app.WorkingDirectory)
+
+	if exitCode != 1 {
+		t.Errorf("expected exit code 1, got %d", exitCode)
+	}
+	if !strings.Contains(output, "invalid file path") {
+		t.Errorf("expected error about invalid path, got %q", output)
+	}
+
+	// nothing must be written, if one path is invalid
+	for _, p := range []string{filepath.Join(app.WorkingDirectory, "ok.txt"), filepath.Join(filepath.Dir(app.WorkingDirectory), "evil.txt")} {
+		if _, err := os.Stat(p); !os.IsNotExist(err) {
+			t.Errorf("expected no file %v, got %v", p, err)
+		}
+	}
+}
+
+func TestResolvePromptPositionalArgs(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	writeTestFile(t, app, "file1.go", "package one")
+	writeTestFile(t, app, "docs/a.md", "# A")
+	writeTestFile(t, app, "docs/b.md", "# B")
+	imageFile := writeTestFile(t, app, "image.png", string(newTestPNG(t, 1, 1)))
+
+	prompt, attachments, err := resolvePromptPositionalArgs(app, []string{
+		"@file1.go", "explain this", "@docs/*.md", "and this", "@image.png", "@@mention",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		`This is the content of the file 'file1.go' as serialized JSON string: "package one"`,
+		"explain this",
+		`This is the content of the file 'docs/a.md' as serialized JSON string: "# A"`,
+		`This is the content of the file 'docs/b.md' as serialized JSON string: "# B"`,
+		"and this",
+		"[see attached file 'image.png']",
+		"@mention",
+	}, app.EOL+app.EOL)
+	if prompt != expected {
+		t.Errorf("expected prompt %q, got %q", expected, prompt)
+	}
+
+	if len(attachments) != 1 || attachments[0] != imageFile {
+		t.Errorf("expected attachments %v, got %v", []string{imageFile}, attachments)
+	}
+}
+
+func TestResolvePromptPositionalArgsNoFiles(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	for _, arg := range []string{"@", "@missing.go"} {
+		_, _, err := resolvePromptPositionalArgs(app, []string{"explain this", arg})
+		if err == nil {
+			t.Errorf("%q: expected an error", arg)
+			continue
+		}
+
+		if errorType := types.GetErrorType(err); errorType != types.ErrorTypeUsage {
+			t.Errorf("%q: expected error type %v, got %v", arg, types.ErrorTypeUsage, errorType)
+		}
+	}
+}
+
+func TestPromptAttachGlobPerArg(t *testing.T) {
+	app := newTestApp(t, map[string]string{})
+
+	writeTestFile(t, app, "file1.go", "package one")
+	writeTestFile(t, app, "file2.go", "package two")
+
+	var submitted string
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		submitted = messages[len(messages)-1].Content
+		return "Two packages."
+	})
+
+	runTestCommand(t, app, Init_prompt_Command, "prompt", "--attach-glob-per-arg", "@file1.go", "explain this", "@file2.go", "and this")
+
+	// files and questions in the order of the arguments
+	last := -1
+	for _, part := range []string{"package one", "explain this", "package two", "and this"} {
+		index := strings.Index(submitted, part)
+		if index <= last {
+			t.Fatalf("expected %q after position %d in prompt %q", part, last, submitted)
+		}
+		last = index
+	}
+}
+
+func TestPromptMaxRequestBytes(t *testing.T) {
+	if testCase := testProcessCase(); testCase != "" {
+		// inside process of `runTestProcess`
+
+		app := newTestApp(t, map[string]string{})
+		app.Stderr = os.Stderr
+		app.Stdout = os.Stderr
+		app.MaxRequestBytes = 1024
+		newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+			return "Should not be sent."
+		})
+
+		runTestCommand(t, app, Init_prompt_Command, "prompt", strings.Repeat("Explain this. ", 100))
+		return
+	}
+
+	output, exitCode := runTestProcess(t, "TestPromptMaxRequestBytes", "default")
+
+	if exitCode != 10 {
+		t.Errorf("expected exit code 10, got %d", exitCode)
+	}
+	if !strings.Contains(output, "which is more than the maximum of 1024 bytes") ||
+		!strings.Contains(output, utils.RequestTooLargeHint) {
+		t.Errorf("expected helpful error message, got %q", output)
+	}
+	if strings.Contains(output, "Should not be sent.") {
+		t.Errorf("expected request not to be sent, got %q", output)
+	}
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/spf13/cobra"
+)
+
+// Init_pull_Command initializes the `pull` command.
+func Init_pull_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var pullCmd = &cobra.Command{
+		Use:   "pull [MODEL]",
+		Short: "Pull Ollama model",
+		Long:  `Downloads a model to the local Ollama server.`,
+		Args:  cobra.ExactArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			model := strings.TrimSpace(args[0])
+			model = strings.TrimPrefix(model, "ollama:")
+			if model == "" {
+				app.CheckIfError(types.NewTypedError(types.ErrorTypeUsage, errors.New("no model defined")))
+			}
+
+			client, err := app.NewAIClient("ollama")
+			app.CheckIfError(err)
+
+			ollama, ok := client.(*types.OllamaClient)
+			if !ok {
+				app.CheckIfError(errors.New("could not create Ollama client"))
+			}
+
+			lastLine := ""
+			err = ollama.PullModel(model, func(progress types.OllamaPullProgress) {
+				line := progress.Status
+				if progress.Total > 0 {
+					line = fmt.Sprintf("%s: %d%%", progress.Status, progress.Completed*100/progress.Total)
+				}
+
+				if line != lastLine {
+					// only write changes
+					app.WriteErrorString(fmt.Sprintf("%s%s", line, app.EOL))
+					lastLine = line
+				}
+			})
+			app.CheckIfError(err)
+		},
+	}
+
+	parentCmd.AddCommand(
+		pullCmd,
+	)
+}
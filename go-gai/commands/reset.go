@@ -23,10 +23,79 @@
 package commands
 
 import (
+	"bufio"
+	"fmt"
+	"strings"
+
 	"github.com/mkloubert/gai/types"
 	"github.com/spf13/cobra"
 )
 
+func init_reset_context_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var all bool
+
+	var resetContextCmd = &cobra.Command{
+		Use:     "context [NAME]",
+		Aliases: []string{"ctx"},
+		Short:   "Delete context",
+		Long:    `Deletes a named conversation context of the current directory.`,
+		Args:    cobra.MaximumNArgs(1),
+		Run: func(cmd *cobra.Command, args []string) {
+			chat, err := app.NewChatContext()
+			app.CheckIfError(err)
+
+			contextName := app.Context
+			if len(args) > 0 {
+				contextName = args[0]
+			}
+
+			question := ""
+			if all {
+				question = fmt.Sprintf("Delete all contexts of '%s'", app.WorkingDirectory)
+			} else {
+				displayName := strings.TrimSpace(contextName)
+				if displayName == "" {
+					displayName = "(default)"
+				}
+
+				question = fmt.Sprintf("Delete context '%s'", displayName)
+			}
+
+			if !app.AlwaysYes {
+				app.WriteString(fmt.Sprintf("%s [y(es)/N(o)]?: ", question))
+
+				reader := bufio.NewReader(app.Stdin)
+
+				input, _ := reader.ReadString('\n')
+				input = strings.TrimSpace(strings.ToLower(input))
+
+				if input != "y" && input != "yes" {
+					return
+				}
+			}
+
+			if all {
+				chat.DeleteAllContexts()
+			} else {
+				if !chat.DeleteContext(contextName) {
+					app.CheckIfError(fmt.Errorf("context '%s' not found", contextName))
+				}
+			}
+
+			err = chat.UpdateConversation()
+			app.CheckIfError(err)
+		},
+	}
+
+	resetContextCmd.Flags().BoolVarP(&all, "all", "", false, "delete all contexts of current directory")
+
+	app.WithYesCliFlags(resetContextCmd)
+
+	parentCmd.AddCommand(
+		resetContextCmd,
+	)
+}
+
 func init_reset_conversation_Command(app *types.AppContext, parentCmd *cobra.Command) {
 	var resetConversationCmd = &cobra.Command{
 		Use:     "conversation",
@@ -62,6 +131,7 @@ func Init_reset_Command(app *types.AppContext, parentCmd *cobra.Command) {
 		},
 	}
 
+	init_reset_context_Command(app, resetCmd)
 	init_reset_conversation_Command(app, resetCmd)
 
 	parentCmd.AddCommand(
@@ -0,0 +1,129 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/mkloubert/gai/types"
+)
+
+// getTestContexts returns the sorted names of the stored contexts of `dir`.
+func getTestContexts(t *testing.T, app *types.AppContext, dir string) string {
+	t.Helper()
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, 0)
+	if chat.Conversations != nil {
+		for name := range chat.Conversations.Conversations[dir] {
+			if name == "" {
+				name = "(default)"
+			}
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	return strings.Join(names, ",")
+}
+
+func TestResetContext(t *testing.T) {
+	tests := []struct {
+		args     []string
+		input    string
+		expected string
+	}{
+		{[]string{"Work", "--yes"}, "", "(default),notes"},
+		{[]string{"work"}, "y\n", "(default),notes"},
+		{[]string{"work"}, "YES\n", "(default),notes"},
+		{[]string{"work"}, "n\n", "(default),notes,work"},
+		{[]string{"work"}, "", "(default),notes,work"},
+		{[]string{"--yes"}, "", "notes,work"},
+		{[]string{"--all", "--yes"}, "", ""},
+		{[]string{"--all"}, "no\n", "(default),notes,work"},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+
+		otherDir := filepath.Join(filepath.Dir(app.WorkingDirectory), "other")
+
+		storeTestConversations(t, app, app.WorkingDirectory, map[string]int{
+			"":      1,
+			"notes": 1,
+			"work":  1,
+		})
+		storeTestConversations(t, app, otherDir, map[string]int{
+			"work": 1,
+		})
+
+		writeTestStdin(t, app, test.input)
+
+		args := append([]string{"reset", "context"}, test.args...)
+		runTestCommand(t, app, Init_reset_Command, args...)
+
+		if contexts := getTestContexts(t, app, app.WorkingDirectory); contexts != test.expected {
+			t.Errorf("%v: expected contexts %q, got %q", test.args, test.expected, contexts)
+		}
+
+		// other directories are not touched
+		if contexts := getTestContexts(t, app, otherDir); contexts != "work" {
+			t.Errorf("%v: expected contexts 'work' of other directory, got %q", test.args, contexts)
+		}
+
+		isAsked := strings.HasSuffix(readTestOutput(t, app.Stdout), "[y(es)/N(o)]?: ")
+		if isAsked == strings.Contains(strings.Join(test.args, " "), "--yes") {
+			t.Errorf("%v: unexpected confirmation %q", test.args, readTestOutput(t, app.Stdout))
+		}
+	}
+}
+
+func TestDeleteUnknownContext(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	storeTestConversations(t, app, app.WorkingDirectory, map[string]int{
+		"work": 1,
+	})
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if chat.DeleteContext("play") {
+		t.Error("expected unknown context not to be deleted")
+	}
+	if !chat.DeleteContext("Work") {
+		t.Error("expected context 'work' to be deleted")
+	}
+	if chat.DeleteContext("work") {
+		t.Error("expected context 'work' to be deleted only once")
+	}
+}
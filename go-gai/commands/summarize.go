@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package commands
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+// defaultSummarizeMaxFileTokens is the default maximum number of tokens
+// of a text, before it is summarized in parts.
+const defaultSummarizeMaxFileTokens = 32000
+
+type summarizeSource struct {
+	data []byte
+	name string
+}
+
+// Init_summarize_Command initializes the `summarize` command.
+func Init_summarize_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var chunks chunkSettings
+	var length string
+
+	var summarizeCmd = &cobra.Command{
+		Use:     "summarize",
+		Aliases: []string{"sum"},
+		Short:   "Summarize",
+		Long:    `Summarizes files as defined in --file and --files flags and/or data from STDIN. Large texts are summarized in parts, whose summaries are merged.`,
+		Run: func(cmd *cobra.Command, args []string) {
+			app.InitAI()
+
+			lengthInfo := ""
+			switch strings.TrimSpace(strings.ToLower(length)) {
+			case "short", "s":
+				lengthInfo = "in one to three sentences"
+			case "", "medium", "m":
+				lengthInfo = "in one or two short paragraphs"
+			case "long", "l":
+				lengthInfo = "in detail with several paragraphs, which cover all important aspects"
+			default:
+				app.CheckIfError(fmt.Errorf("length '%v' is not supported", length))
+			}
+
+			files, err := app.GetFiles()
+			app.CheckIfError(err)
+
+			sources := make([]summarizeSource, 0)
+
+			for _, f := range files {
+				data, err := os.ReadFile(f)
+				app.CheckIfError(err)
+
+				name, err := filepath.Rel(app.WorkingDirectory, f)
+				if err != nil {
+					name = f
+				}
+
+				sources = append(sources, summarizeSource{
+					data: data,
+					name: name,
+				})
+			}
+
+			// check if standard input has been piped
+			stdinStat, _ := app.Stdin.Stat()
+			if (stdinStat.Mode() & os.ModeCharDevice) == 0 {
+				data, err := io.ReadAll(app.Stdin)
+				app.CheckIfError(err)
+
+				if len(data) > 0 {
+					sources = append(sources, summarizeSource{
+						data: data,
+						name: "<stdin>",
+					})
+				}
+			}
+
+			if len(sources) == 0 {
+				app.CheckIfError(errors.New("no files or STDIN data found"))
+			}
+
+			responseSchema, responseSchemaName, err := app.GetResponseSchema()
+			app.CheckIfError(err)
+
+			outputLanguage := strings.TrimSpace(app.OutputLanguage)
+
+			langInfo := "the same language as the text"
+			if outputLanguage != "" {
+				langInfo = fmt.Sprintf("'%s' language", outputLanguage)
+			}
+
+			systemPrompt := fmt.Sprintf(`You are an assistant who summarizes documents.
+The user submits the content of a document as serialized JSON string.
+Summarize it %s, focus on the key points and do not add any information that is not part of the document.
+Answer in %s.`,
+				lengthInfo, langInfo)
+
+			maxFileTokens, strategy, err := getChunkOptions(app, cmd, &chunks)
+			app.CheckIfError(err)
+
+			tokenizer := utils.NewTextTokenizer(app.AI.ChatModel())
+
+			// summarizes `text` or merges summaries, while only
+			// the final answer uses the response schema
+			summarize := func(prompt string, isFinal bool) types.AIClientPromptResponse {
+				promptOptions := make([]types.AIClientPromptOptions, 0)
+				if isFinal {
+					promptOptions = append(promptOptions, types.AIClientPromptOptions{
+						ResponseSchema:     responseSchema,
+						ResponseSchemaName: &responseSchemaName,
+						SystemPrompt:       &systemPrompt,
+					})
+				} else {
+					promptOptions = append(promptOptions, types.AIClientPromptOptions{
+						SystemPrompt: &systemPrompt,
+					})
+				}
+
+				response, err := app.PromptAndValidate(prompt, promptOptions...)
+				app.CheckIfError(err)
+
+				return response
+			}
+
+			for i, s := range sources {
+				if i > 0 {
+					app.Writeln()
+					app.Writeln()
+					app.Writeln("---")
+					app.Writeln()
+				}
+
+				text, err := utils.EnsurePlainText(s.data)
+				app.CheckIfError(err)
+
+				textChunks := utils.Chunk(text, utils.ChunkOptions{
+					MaxSize:   maxFileTokens,
+					Name:      s.name,
+					Strategy:  strategy,
+					Tokenizer: tokenizer,
+				})
+
+				var response types.AIClientPromptResponse
+				if len(textChunks) == 1 {
+					jsonData, err := json.Marshal(text)
+					app.CheckIfError(err)
+
+					app.Dbgf("Summarizing '%v' ...%v", s.name, app.EOL)
+
+					response = summarize(fmt.Sprintf("Summarize the following document '%s': %s", s.name, jsonData), true)
+				} else {
+					// summarize each part and merge the summaries
+					summaries := make([]string, 0, len(textChunks))
+					for j, chunk := range textChunks {
+						jsonData, err := json.Marshal(chunk)
+						app.CheckIfError(err)
+
+						app.Dbgf("Summarizing part %v of %v of '%v' ...%v", j+1, len(textChunks), s.name, app.EOL)
+
+						partResponse := summarize(fmt.Sprintf("Summarize part %d of %d of the following document '%s': %s", j+1, len(textChunks), s.name, jsonData), false)
+						app.OutputAIUsage(partResponse.Usage)
+
+						summaries = append(summaries, partResponse.Content)
+					}
+
+					jsonData, err := json.Marshal(summaries)
+					app.CheckIfError(err)
+
+					app.Dbgf("Merging %v summaries of '%v' ...%v", len(summaries), s.name, app.EOL)
+
+					response = summarize(fmt.Sprintf("Merge the following summaries of the %d parts of the document '%s', which are submitted as serialized JSON array, into one summary: %s", len(summaries), s.name, jsonData), true)
+				}
+
+				if len(sources) > 1 {
+					app.Writeln(fmt.Sprintf("%s:", s.name))
+				}
+
+				app.OutputAIAnswer(response.Content)
+				app.OutputAIUsage(response.Usage)
+			}
+		},
+	}
+
+	app.WithChatCLIFlags(summarizeCmd)
+	app.WithLanguageCLIFlags(summarizeCmd)
+	withChunkCLIFlags(summarizeCmd, &chunks, defaultSummarizeMaxFileTokens)
+	summarizeCmd.Flags().StringVarP(&length, "length", "", "medium", "length of summaries: short, medium or long")
+
+	parentCmd.AddCommand(
+		summarizeCmd,
+	)
+}
@@ -0,0 +1,134 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestSummarize(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	systemPrompts := make([]string, 0)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		for _, m := range messages {
+			if m.Role == "system" {
+				systemPrompts = append(systemPrompts, m.Content)
+			}
+		}
+
+		user := messages[len(messages)-1].Content
+		switch {
+		case strings.Contains(user, `'a.txt': "Alpha\nfile"`):
+			return "Summary A"
+		case strings.Contains(user, `'docs/b.md': "# Beta"`):
+			return "Summary B"
+		case strings.Contains(user, `'<stdin>': "Gamma"`):
+			return "Summary C"
+		}
+		return fmt.Sprintf("unexpected message %q", user)
+	})
+
+	writeTestFile(t, app, "a.txt", "Alpha\nfile")
+	writeTestFile(t, app, "docs/b.md", "# Beta")
+	writeTestStdin(t, app, "Gamma")
+
+	app.Files = []string{"docs/b.md", "a.txt"}
+
+	runTestCommand(t, app, Init_summarize_Command, "summarize", "--language", "German", "--length", "short")
+
+	// one summary per source, separated by a divider
+	expected := "a.txt:\nSummary A\n\n---\n\ndocs/b.md:\nSummary B\n\n---\n\n<stdin>:\nSummary C"
+	if output := readTestOutput(t, app.Stdout); output != expected {
+		t.Errorf("expected output %q, got %q", expected, output)
+	}
+
+	if len(systemPrompts) != 3 {
+		t.Fatalf("expected 3 system prompts, got %d", len(systemPrompts))
+	}
+	for _, p := range systemPrompts {
+		if !strings.Contains(p, "in one to three sentences") || !strings.Contains(p, "Answer in 'German' language.") {
+			t.Errorf("unexpected system prompt %q", p)
+		}
+	}
+}
+
+func TestSummarizeInParts(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	prompts := make([]string, 0)
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		user := messages[len(messages)-1].Content
+		prompts = append(prompts, user)
+
+		switch {
+		case strings.HasPrefix(user, "Summarize part 1 of 2 of the following document 'a.txt': \"0123456789\""):
+			return "Summary 1"
+		case strings.HasPrefix(user, "Summarize part 2 of 2 of the following document 'a.txt': \"abcde\""):
+			return "Summary 2"
+		case strings.HasPrefix(user, "Merge the following summaries of the 2 parts of the document 'a.txt'") &&
+			strings.HasSuffix(user, `["Summary 1","Summary 2"]`):
+			return "Merged summary"
+		}
+		return fmt.Sprintf("unexpected message %q", user)
+	})
+
+	writeTestFile(t, app, "a.txt", "0123456789abcde")
+
+	app.Files = []string{"a.txt"}
+
+	runTestCommand(t, app, Init_summarize_Command, "summarize", "--chunk-strategy", "chars", "--max-file-tokens", "10")
+
+	if output := readTestOutput(t, app.Stdout); output != "Merged summary" {
+		t.Errorf("expected output %q, got %q", "Merged summary", output)
+	}
+	if len(prompts) != 3 {
+		t.Errorf("expected 3 prompts, got %q", prompts)
+	}
+}
+
+func TestSummarizeNotInParts(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_CHUNK_STRATEGY":  "chars",
+		"GAI_MAX_FILE_TOKENS": "10",
+	})
+
+	prompts := 0
+	newTestOpenAIServer(t, app, func(messages []testChatMessage) string {
+		prompts++
+		return "Summary"
+	})
+
+	writeTestFile(t, app, "a.txt", "0123456789abcde")
+
+	app.Files = []string{"a.txt"}
+
+	// flag has a higher priority than the environment variables
+	runTestCommand(t, app, Init_summarize_Command, "summarize", "--max-file-tokens", "0")
+
+	if prompts != 1 {
+		t.Errorf("expected 1 prompt, got %d", prompts)
+	}
+}
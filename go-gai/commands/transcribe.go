@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkloubert/gai/types"
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
+
+// Init_transcribe_Command initializes the `transcribe` command.
+func Init_transcribe_Command(app *types.AppContext, parentCmd *cobra.Command) {
+	var responseFormat string
+
+	var transcribeCmd = &cobra.Command{
+		Use:     "transcribe",
+		Aliases: []string{"tr"},
+		Short:   "Transcribe audio",
+		Long:    `Transcribes audio files as defined in --file and --files flags with the model of --model (default: whisper-1).`,
+		Run: func(cmd *cobra.Command, args []string) {
+			// the global --model flag defines the transcription model
+			model := app.InitTranscribeAI()
+
+			format := strings.TrimSpace(strings.ToLower(responseFormat))
+			switch format {
+			case "json", "srt", "text", "verbose_json", "vtt":
+			default:
+				app.CheckIfError(fmt.Errorf("response format '%v' is not supported", responseFormat))
+			}
+
+			language := strings.TrimSpace(app.OutputLanguage)
+
+			files, err := app.GetFiles()
+			app.CheckIfError(err)
+
+			if len(files) == 0 {
+				app.CheckIfError(errors.New("no files found or defined"))
+			}
+
+			// first check all files ...
+			audioData := make([][]byte, 0)
+			for _, f := range files {
+				data, err := os.ReadFile(f)
+				app.CheckIfError(err)
+
+				mimeType := utils.DetectMime(data)
+				if !strings.HasPrefix(mimeType, "audio/") {
+					filename, err := filepath.Rel(app.WorkingDirectory, f)
+					if err != nil {
+						filename = f
+					}
+
+					app.CheckIfError(fmt.Errorf("'%v' is no audio file (detected mime type '%v')", filename, mimeType))
+				}
+
+				audioData = append(audioData, data)
+			}
+
+			// ... before transcribing them
+			for i, data := range audioData {
+				if i > 0 {
+					app.Writeln()
+				}
+
+				app.Dbgf("Transcribing '%v' ...%v", files[i], app.EOL)
+
+				options := types.AIClientTranscribeOptions{
+					ResponseFormat: &format,
+				}
+				if language != "" {
+					options.Language = &language
+				}
+				options.Model = &model
+
+				response, err := app.AI.Transcribe(bytes.NewReader(data), options)
+				app.CheckIfError(err)
+
+				transcript := response.Content
+				if format == "json" {
+					// client returns the plain text of JSON responses
+					jsonData, err := json.Marshal(map[string]any{
+						"text": transcript,
+					})
+					app.CheckIfError(err)
+
+					transcript = string(jsonData)
+				}
+
+				app.WriteString(strings.TrimRight(transcript, "\r\n"))
+				app.Writeln()
+			}
+		},
+	}
+
+	transcribeCmd.Flags().StringVarP(&responseFormat, "response-format", "", "text", "format of the transcript: text, srt, vtt or json")
+
+	app.WithCurlCLIFlags(transcribeCmd)
+	app.WithLanguageCLIFlags(transcribeCmd)
+
+	parentCmd.AddCommand(
+		transcribeCmd,
+	)
+}
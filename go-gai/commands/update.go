@@ -44,6 +44,42 @@ type updateCodeResponseFileToUpdateToUpdate struct {
 	NewContent  string `json:"new_content"`
 }
 
+// newUpdateCodeResponseSchema returns the default response schema of
+// `update code` with one property for each of `filesToUpdate`.
+func newUpdateCodeResponseSchema(filesToUpdate []string) *map[string]any {
+	properties1 := map[string]any{}
+
+	for _, f := range filesToUpdate {
+		properties1[f] = map[string]any{
+			"description": fmt.Sprintf("Information how the file '%v' should be updated.", f),
+			"type":        "object",
+			"required":    []string{"explanation", "new_content"},
+			"properties": map[string]any{
+				"explanation": map[string]any{
+					"type":        "string",
+					"description": fmt.Sprintf("Detailed explanation of what has been changed in file '%s'.", f),
+				},
+				"new_content": map[string]any{
+					"type":        "string",
+					"description": fmt.Sprintf("New content for file '%s'.", f),
+				},
+			},
+		}
+	}
+
+	return &map[string]any{
+		"type":     "object",
+		"required": []string{"updated_files"},
+		"properties": map[string]any{
+			"updated_files": map[string]any{
+				"type":        "object",
+				"description": "List of files that should be updated.",
+				"properties":  properties1,
+			},
+		},
+	}
+}
+
 func init_update_code_Command(app *types.AppContext, parentCmd *cobra.Command) {
 	var updateCodeCmd = &cobra.Command{
 		Use:     "code",
@@ -140,39 +176,7 @@ Your JSON:`,
 			app.Dbg("Setup final user message")
 
 			if responseSchema == nil {
-				// build default schema
-
-				properties1 := map[string]any{}
-
-				for _, f := range filesToUpdate {
-					properties1[f] = map[string]any{
-						"description": fmt.Sprintf("Information how the file '%v' should be updated.", f),
-						"type":        "object",
-						"required":    []string{"explanation", "new_content"},
-						"properties": map[string]any{
-							"explanation": map[string]any{
-								"type":        "string",
-								"description": fmt.Sprintf("Detailed explanation of what has been changed in file '%s'.", f),
-							},
-							"new_content": map[string]any{
-								"type":        "string",
-								"description": fmt.Sprintf("New content for file '%s'.", f),
-							},
-						},
-					}
-				}
-
-				responseSchema = &map[string]any{
-					"type":     "object",
-					"required": []string{"updated_files"},
-					"properties": map[string]any{
-						"updated_files": map[string]any{
-							"type":        "object",
-							"description": "List of files that should be updated.",
-							"properties":  properties1,
-						},
-					},
-				}
+				responseSchema = newUpdateCodeResponseSchema(filesToUpdate)
 			} else {
 				app.Dbg("Using custom response schema")
 			}
@@ -207,7 +211,7 @@ Please follow the user’s instructions precisely and answer in %s.`,
 				ResponseSchemaName: &responseSchemaName,
 				SystemPrompt:       &systemPrompt,
 			})
-			answer, _, err := app.AI.Chat(chat, message, chatOptions...)
+			answer, _, err := app.ChatAndValidate(chat, message, chatOptions...)
 			app.CheckIfError(err)
 
 			app.Dbg("Marshalling response ...")
@@ -242,6 +246,7 @@ Please follow the user’s instructions precisely and answer in %s.`,
 
 	app.WithChatCLIFlags(updateCodeCmd)
 	app.WithLanguageCLIFlags(updateCodeCmd)
+	app.WithPseudoConversationCLIFlags(updateCodeCmd)
 
 	parentCmd.AddCommand(
 		updateCodeCmd,
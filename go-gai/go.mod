@@ -18,6 +18,7 @@ require (
 	github.com/xuri/excelize/v2 v2.9.1
 	golang.org/x/image v0.28.0
 	golang.org/x/term v0.32.0
+	golang.org/x/text v0.26.0
 )
 
 require (
@@ -54,5 +55,4 @@ require (
 	golang.org/x/net v0.40.0 // indirect
 	golang.org/x/sync v0.15.0 // indirect
 	golang.org/x/sys v0.33.0 // indirect
-	golang.org/x/text v0.26.0 // indirect
 )
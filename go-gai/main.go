@@ -63,35 +63,73 @@ func main() {
 	// Define persistent flags
 	flags := rootCmd.PersistentFlags()
 	flags.StringVarP(&app.ApiKey, "api-key", "k", "", "global API key to use")
+	flags.Int64VarP(&app.AttachmentMaxInline, "attachment-max-inline", "", 0, "maximum size in bytes of attachments to inline as data URI (-1 for no limit)")
 	flags.StringVarP(&app.BaseUrl, "base-url", "u", "", "custom base URL")
+	flags.BoolVarP(&app.Brief, "brief", "", false, "ask for short answers (same as --verbosity 1)")
 	flags.StringVarP(&app.Context, "context", "c", "", "custom context")
+	flags.StringVarP(&app.ConversationFormat, "conversation-format", "", "", "format of conversation file: yaml or json")
 	flags.StringVarP(&app.WorkingDirectory, "cwd", "", "", "current working directory")
-	flags.StringVarP(&app.EOL, "eol", "", fmt.Sprintln(), "custom EOL char sequence")
+	flags.BoolVarP(&app.Detailed, "detailed", "", false, "ask for thorough answers (same as --verbosity 3)")
 	flags.StringArrayVarP(&app.EnvFiles, "env-file", "e", []string{}, "one or more env file to load")
+	flags.StringVarP(&app.EOL, "eol", "", fmt.Sprintln(), "custom EOL char sequence")
+	flags.StringVarP(&app.ErrorFormat, "error-format", "", "", "format of error output: text or json")
+	flags.StringArrayVarP(&app.ExcludePatterns, "exclude", "", []string{}, "one or more patterns of files to exclude from --files")
 	flags.StringArrayVarP(&app.Files, "file", "f", []string{}, "one or more files to use")
 	flags.StringArrayVarP(&app.FilePatterns, "files", "", []string{}, "one or more files in form of patterns to use")
+	flags.VarP(types.NewOptionalFloat64Value(&app.FrequencyPenalty), "frequency-penalty", "", "custom frequency penalty between -2 and 2 (not sent if not defined)")
 	flags.StringVarP(&app.HomeDirectory, "home", "", "", "user's home directory")
-	flags.BoolVarP(&app.SkipDefaultEnvFiles, "skip-env-files", "", false, "do not load default .env files")
+	flags.StringVarP(&app.HttpTimeout, "http-timeout", "", "", "timeout for HTTP requests, like 90s or 5m (0 for none)")
+	flags.BoolVarP(&app.JSONOutput, "json", "", false, "output raw answers without highlighting and check for valid JSON")
+	flags.Int64VarP(&app.MaxRequestBytes, "max-request-bytes", "", 0, "maximum size of the body of a request in bytes, before it is sent (0 for no limit)")
 	flags.Int64VarP(&app.MaxTokens, "max-tokens", "", 0, "maximum number of tokens")
 	flags.StringVarP(&app.Model, "model", "m", "", "default chat model")
+	flags.BoolVarP(&app.NoColor, "no-color", "", false, "do not output any ANSI colors")
+	flags.BoolVarP(&app.NoGitignore, "no-gitignore", "", false, "do not skip files ignored by .gitignore when expanding --files")
+	flags.StringVarP(&app.OpenAIApi, "openai-api", "", "", "OpenAI API to use: chat or responses")
 	flags.StringVarP(&app.OutputFile, "output", "o", "", "write output to this file")
+	flags.VarP(types.NewOptionalFloat64Value(&app.PresencePenalty), "presence-penalty", "", "custom presence penalty between -2 and 2 (not sent if not defined)")
+	flags.BoolVarP(&app.Redact, "redact", "", false, "replace secrets, like API keys or private keys, in files and STDIN with [REDACTED] before sending them")
+	flags.StringVarP(&app.RetryBudget, "retry-budget", "", "", "maximum time for retrying HTTP requests with transient errors, like 30s or 2m, instead of a fixed number of retries")
+	flags.BoolVarP(&app.ShowCost, "show-cost", "", false, "write accumulated token usage and costs of all requests to STDERR at the end")
+	flags.BoolVarP(&app.SkipDefaultEnvFiles, "skip-env-files", "", false, "do not load default .env files")
 	flags.StringVarP(&app.SystemPrompt, "system", "s", "", "custom system prompt")
+	flags.StringVarP(&app.SystemPromptFile, "system-file", "", "", "file with custom system prompt")
 	flags.StringVarP(&app.SystemRole, "system-role", "", "", "custom name/id of the system role")
 	flags.StringVarP(&app.TempDirectory, "temp", "", "", "custom temp directory")
 	flags.Float64VarP(&app.Temperature, "temperature", "t", -1, "custom temperature value")
 	flags.StringVarP(&app.TerminalFormatter, "terminal-formatter", "", "", "custom terminal formatter")
 	flags.StringVarP(&app.TerminalStyle, "terminal-style", "", "", "custom terminal style")
+	flags.VarP(types.NewOptionalFloat64Value(&app.TopP), "top-p", "", "custom nucleus sampling value between 0 and 1 (not sent if not defined)")
 	flags.BoolVarP(&app.Verbose, "verbose", "", false, "verbose output")
+	flags.Int64VarP(&app.Verbosity, "verbosity", "", -1, "level of detail of answers from 0 (very brief) to 3 (detailed), which adjusts system prompt and token limit")
+
+	rootCmd.RegisterFlagCompletionFunc("model", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return app.CompleteModelNames(toComplete)
+	})
 
 	// Initialize commands
+	commands.Init_agent_Command(app, rootCmd)
 	commands.Init_analize_Command(app, rootCmd)
+	commands.Init_changelog_Command(app, rootCmd)
 	commands.Init_chat_Command(app, rootCmd)
 	commands.Init_commit_Command(app, rootCmd)
+	commands.Init_config_Command(app, rootCmd)
 	commands.Init_describe_Command(app, rootCmd)
+	commands.Init_diff_Command(app, rootCmd)
+	commands.Init_doctor_Command(app, rootCmd)
+	commands.Init_embed_Command(app, rootCmd)
+	commands.Init_export_Command(app, rootCmd)
+	commands.Init_generate_Command(app, rootCmd)
+	commands.Init_history_Command(app, rootCmd)
+	commands.Init_import_Command(app, rootCmd)
 	commands.Init_init_Command(app, rootCmd)
 	commands.Init_list_Command(app, rootCmd)
+	commands.Init_models_Command(app, rootCmd)
 	commands.Init_prompt_Command(app, rootCmd)
+	commands.Init_pull_Command(app, rootCmd)
 	commands.Init_reset_Command(app, rootCmd)
+	commands.Init_summarize_Command(app, rootCmd)
+	commands.Init_transcribe_Command(app, rootCmd)
 	commands.Init_update_Command(app, rootCmd)
 
 	app.Log = log.New(app, "", log.Ldate|log.Ltime)
@@ -23,7 +23,9 @@
 package types
 
 import (
+	"fmt"
 	"io"
+	"os"
 )
 
 // AIClient describes a client for an AI provider.
@@ -38,6 +40,12 @@ type AIClient interface {
 	Chat(ctx *ChatContext, msg string, opts ...AIClientChatOptions) (string, ConversationRepositoryConversation, error)
 	// ChatModel returns the current chat model.
 	ChatModel() string
+	// Embed creates embedding vectors for each of the `texts`.
+	Embed(texts []string, opts ...AIClientEmbedOptions) (AIClientEmbedResponse, error)
+	// GenerateImage creates one or more images from `prompt`.
+	GenerateImage(prompt string, opts ...AIClientGenerateImageOptions) (AIClientGenerateImageResponse, error)
+	// GetModelInfo loads live information about `model` from the provider.
+	GetModelInfo(model string) (AIModelInfo, error)
 	// Returns the list of supported AI models.
 	GetModels() ([]AIModel, error)
 	// Prompt does a single AI prompt with a specific `msg`.
@@ -46,32 +54,51 @@ type AIClient interface {
 	Provider() string
 	// SetChatModel sets the current chat model.
 	SetChatModel(m string) error
+	// Transcribe converts speech of audio data from `r` to text.
+	Transcribe(r io.Reader, opts ...AIClientTranscribeOptions) (AIClientTranscribeResponse, error)
 }
 
 // AIClientChatOptions stores additional options for `Chat` method.
 type AIClientChatOptions struct {
 	// Files stores list of one or more file to use for the submission.
 	Files *[]io.Reader
+	// FrequencyPenalty stores a custom frequency penalty, which overwrites `--frequency-penalty`.
+	FrequencyPenalty *float64
 	// `true` if new conversation should not be saved.
 	NoSave *bool
+	// PresencePenalty stores a custom presence penalty, which overwrites `--presence-penalty`.
+	PresencePenalty *float64
 	// ResponseSchema stores the response format.
 	ResponseSchema *map[string]any
 	// ResponseSchemaName stores the response name.
 	ResponseSchemaName *string
 	// SystemPrompt stores the default system prompt.
 	SystemPrompt *string
+	// ToolResults stores the results of the tool calls of the last answer,
+	// which are submitted as `tool` messages before `msg`, which can be empty then.
+	ToolResults []AIToolResult
+	// Tools stores the tools, the AI is allowed to call.
+	Tools []ToolDefinition
+	// TopP stores a custom nucleus sampling value, which overwrites `--top-p`.
+	TopP *float64
 }
 
 // AIClientPromptOptions stores additional options for `Prompt` method.
 type AIClientPromptOptions struct {
 	// Files stores list of one or more file to use for the submission.
 	Files *[]io.Reader
+	// FrequencyPenalty stores a custom frequency penalty, which overwrites `--frequency-penalty`.
+	FrequencyPenalty *float64
+	// PresencePenalty stores a custom presence penalty, which overwrites `--presence-penalty`.
+	PresencePenalty *float64
 	// ResponseSchema stores the response format.
 	ResponseSchema *map[string]any
 	// ResponseSchemaName stores the response name.
 	ResponseSchemaName *string
 	// SystemPrompt stores the default system prompt.
 	SystemPrompt *string
+	// TopP stores a custom nucleus sampling value, which overwrites `--top-p`.
+	TopP *float64
 }
 
 // AIClientPromptResponse stores information about a successful prompt response.
@@ -80,4 +107,133 @@ type AIClientPromptResponse struct {
 	Content string
 	// Model stores the model that has been used.
 	Model string
+	// Usage stores the used tokens, if known.
+	Usage *AIUsage
+}
+
+// AIClientEmbedOptions stores additional options for `Embed` method.
+type AIClientEmbedOptions struct {
+	// Model stores the custom embedding model to use.
+	Model *string
+}
+
+// AIClientEmbedResponse stores information about successfully created embeddings.
+type AIClientEmbedResponse struct {
+	// Embeddings stores the vectors in the same order as the submitted texts.
+	Embeddings [][]float32
+	// Model stores the model that has been used.
+	Model string
+}
+
+// AIClientGenerateImageOptions stores additional options for `GenerateImage` method.
+type AIClientGenerateImageOptions struct {
+	// Count stores the number of images to generate.
+	Count *int
+	// Model stores the custom model to use.
+	Model *string
+	// Size stores the size of the images, like `1024x1024`.
+	Size *string
+}
+
+// AIClientGenerateImageResponse stores information about successfully generated images.
+type AIClientGenerateImageResponse struct {
+	// Images stores the binary data of the generated images.
+	Images [][]byte
+	// Model stores the model that has been used.
+	Model string
+}
+
+// AIClientTranscribeOptions stores additional options for `Transcribe` method.
+type AIClientTranscribeOptions struct {
+	// Language stores the language of the input audio, like `en` or `de`.
+	Language *string
+	// Model stores the custom model to use.
+	Model *string
+	// ResponseFormat stores the custom format of the output, like `json`, `text` or `srt`.
+	ResponseFormat *string
+}
+
+// AIClientTranscribeResponse stores information about a successful transcription.
+type AIClientTranscribeResponse struct {
+	// Content stores the transcript.
+	Content string
+	// Model stores the model that has been used.
+	Model string
+}
+
+// AIToolCall stores a call of a tool, which has been requested by the AI.
+type AIToolCall struct {
+	// Arguments stores the arguments as JSON string.
+	Arguments string `json:"arguments" yaml:"arguments"`
+	// Id stores the ID of the call, which is required for the result.
+	Id string `json:"id" yaml:"id"`
+	// Name stores the name of the tool.
+	Name string `json:"name" yaml:"name"`
+}
+
+// AIToolResult stores the result of an `AIToolCall`.
+type AIToolResult struct {
+	// Content stores the output of the tool.
+	Content string
+	// ToolCallId stores the ID of the underlying `AIToolCall`.
+	ToolCallId string
+}
+
+// AIUsage stores information about used tokens.
+type AIUsage struct {
+	// CompletionTokens stores number of completion / output tokens.
+	CompletionTokens int64 `json:"completion_tokens" yaml:"completion_tokens"`
+	// PromptTokens stores number of prompt / input tokens.
+	PromptTokens int64 `json:"prompt_tokens" yaml:"prompt_tokens"`
+	// TotalTokens stores number of total used tokens.
+	TotalTokens int64 `json:"total_tokens" yaml:"total_tokens"`
+}
+
+// ToolDefinition stores the definition of a tool (function), the AI can call.
+type ToolDefinition struct {
+	// Description stores the description, which tells the AI when to use the tool.
+	Description string
+	// Name stores the unique name of the tool.
+	Name string
+	// Parameters stores the JSON schema of the arguments.
+	Parameters map[string]any
+}
+
+// NamedReader is an `io.Reader` with a file name, which is submitted
+// with the attachment, if supported by the provider.
+type NamedReader struct {
+	io.Reader
+
+	name string
+}
+
+// NewNamedReader creates a new `NamedReader` instance for `r` with the file name `name`.
+func NewNamedReader(r io.Reader, name string) *NamedReader {
+	return &NamedReader{
+		Reader: r,
+		name:   name,
+	}
+}
+
+// Name returns the file name.
+func (r *NamedReader) Name() string {
+	return r.name
+}
+
+// getNameOfReader returns the file name of `r`, if it is a `NamedReader`,
+// or the path of a local file, otherwise an empty string.
+func getNameOfReader(r io.Reader) string {
+	if namedReader, ok := r.(*NamedReader); ok {
+		return namedReader.Name()
+	}
+	if file, ok := r.(*os.File); ok {
+		return file.Name()
+	}
+
+	return ""
+}
+
+// String returns the usage as string like `prompt=10 completion=20 total=30`.
+func (u *AIUsage) String() string {
+	return fmt.Sprintf("prompt=%d completion=%d total=%d", u.PromptTokens, u.CompletionTokens, u.TotalTokens)
 }
@@ -22,7 +22,10 @@
 
 package types
 
-import "fmt"
+import (
+	"fmt"
+	"time"
+)
 
 // AIModel manages an AI model for an `AIClient`.
 type AIModel struct {
@@ -31,6 +34,39 @@ type AIModel struct {
 	name      string
 }
 
+// AIModelInfo stores information and capabilities of a model.
+// Values, which are `nil` or empty, are unknown.
+type AIModelInfo struct {
+	// Audio is `true` if model accepts audio input.
+	Audio *bool `json:"audio"`
+	// ContextWindow stores the maximum number of input tokens.
+	ContextWindow *int64 `json:"context_window"`
+	// Created stores the creation time of the model.
+	Created *time.Time `json:"created,omitempty"`
+	// Description stores a description of the model.
+	Description string `json:"description,omitempty"`
+	// DisplayName stores a human readable name of the model.
+	DisplayName string `json:"display_name,omitempty"`
+	// Family stores the model family, like `llama`.
+	Family string `json:"family,omitempty"`
+	// InputPrice stores the price per 1,000 input tokens.
+	InputPrice *float64 `json:"input_price"`
+	// JSONSchema is `true` if model supports structured output with JSON schema.
+	JSONSchema *bool `json:"json_schema"`
+	// MaxOutputTokens stores the maximum number of output tokens.
+	MaxOutputTokens *int64 `json:"max_output_tokens,omitempty"`
+	// OutputPrice stores the price per 1,000 output tokens.
+	OutputPrice *float64 `json:"output_price"`
+	// OwnedBy stores the organization, which owns the model.
+	OwnedBy string `json:"owned_by,omitempty"`
+	// ParameterSize stores the number of parameters, like `8.0B`.
+	ParameterSize string `json:"parameter_size,omitempty"`
+	// Quantization stores the quantization level, like `Q4_K_M`.
+	Quantization string `json:"quantization,omitempty"`
+	// Vision is `true` if model accepts image input.
+	Vision *bool `json:"vision"`
+}
+
 // Client returns the AI client instance.
 func (m *AIModel) Client() AIClient {
 	return m.client
@@ -49,8 +85,9 @@ func (m *AIModel) ModelType() string {
 // NewAIModel creates a new instance of an `AIModel` for an AI client.
 func NewAIModel(client AIClient, name string, modelType string) *AIModel {
 	return &AIModel{
-		client: client,
-		name:   name,
+		client:    client,
+		modelType: modelType,
+		name:      name,
 	}
 }
 
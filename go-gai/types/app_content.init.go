@@ -44,44 +44,95 @@ func (app *AppContext) initHomeDir() {
 	}
 }
 
-// InitAI initializes the default AI client.
-func (app *AppContext) InitAI() {
-	if strings.TrimSpace(app.Model) == "" {
-		// first try command specific default model
-		// GAI_DEFAULT_COMMAND_MODEL__*
-		envSuffix := strings.Join(app.CommandPath, "_")
-		envSuffix = strings.ToUpper(envSuffix)
-		envName := fmt.Sprintf("GAI_DEFAULT_COMMAND_MODEL__%s", envSuffix)
-
-		app.Model = strings.TrimSpace(
+// GetModelCandidates returns the sources for the default chat model
+// in the order they are checked.
+func (app *AppContext) GetModelCandidates() []string {
+	// GAI_DEFAULT_COMMAND_MODEL__*
+	envSuffix := strings.Join(app.CommandPath, "_")
+	envSuffix = strings.ToUpper(envSuffix)
+
+	return []string{
+		"--model",
+		fmt.Sprintf("GAI_DEFAULT_COMMAND_MODEL__%s", envSuffix),
+		"GAI_DEFAULT_CHAT_MODEL",
+		"defaults.flags.model of .gairc",
+	}
+}
+
+// GetDefaultModel returns the default chat model in `provider:model` format
+// and the source from `GetModelCandidates` it has been taken from
+// or empty strings if there is none.
+func (app *AppContext) GetDefaultModel() (string, string) {
+	candidates := app.GetModelCandidates()
+
+	model := strings.TrimSpace(app.Model)
+	if model != "" {
+		return model, candidates[0]
+	}
+
+	app.Dbgf("Flag %s not set%s", candidates[0], app.EOL)
+
+	// first try command specific default model
+	// and then env variable for common default
+	for _, envName := range candidates[1:3] {
+		model = strings.TrimSpace(
 			app.GetEnv(envName),
 		)
-		if app.Model == "" {
-			// now try env variable for common default
-			app.Model = strings.TrimSpace(app.GetEnv("GAI_DEFAULT_CHAT_MODEL"))
+		if model != "" {
+			return model, envName
 		}
+
+		app.Dbgf("Environment variable %s not set%s", envName, app.EOL)
 	}
 
-	modelWithProvider := strings.TrimSpace(app.Model)
-	if modelWithProvider == "" {
-		app.CheckIfError(fmt.Errorf("no default chat model defined"))
+	// at last the one, which is set by `models default`
+	model = strings.TrimSpace(app.RCFile.Defaults.Flags.Model)
+	if model != "" {
+		return model, candidates[3]
 	}
 
+	return "", ""
+}
+
+// ParseModelWithProvider splits `modelWithProvider` in `provider:model` format
+// into its provider and model.
+func ParseModelWithProvider(modelWithProvider string) (string, string, error) {
+	modelWithProvider = strings.TrimSpace(modelWithProvider)
+
 	sep := strings.Index(modelWithProvider, ":")
 	if sep == -1 {
-		app.CheckIfError(fmt.Errorf("no AI provider defined"))
+		return "", "", fmt.Errorf("no AI provider defined in '%s', use provider:model format", modelWithProvider)
 	}
 
 	provider := strings.TrimSpace(modelWithProvider[:sep])
 	model := strings.TrimSpace(modelWithProvider[sep+1:])
 
 	if provider == "" {
-		app.CheckIfError(fmt.Errorf("no AI provider defined, use provider:model format"))
+		return provider, model, fmt.Errorf("no AI provider defined, use provider:model format")
 	}
 	if model == "" {
-		app.CheckIfError(fmt.Errorf("no chat model defined, use provider:model format"))
+		return provider, model, fmt.Errorf("no chat model defined, use provider:model format")
+	}
+
+	return provider, model, nil
+}
+
+// InitAI initializes the default AI client.
+func (app *AppContext) InitAI() {
+	modelWithProvider, source := app.GetDefaultModel()
+	if modelWithProvider == "" {
+		app.CheckIfError(fmt.Errorf(
+			"no default chat model defined, checked in this order: %s",
+			strings.Join(app.GetModelCandidates(), ", "),
+		))
 	}
 
+	app.Dbgf("Taking model from %s%s", source, app.EOL)
+	app.Model = modelWithProvider
+
+	provider, model, err := ParseModelWithProvider(modelWithProvider)
+	app.CheckIfError(err)
+
 	client, err := app.NewAIClient(provider)
 	app.CheckIfError(err)
 
@@ -90,6 +141,72 @@ func (app *AppContext) InitAI() {
 	app.AI = client
 }
 
+// InitEmbedAI initializes the default AI client for embeddings
+// and returns the embedding model, which is taken from `--model`,
+// `GAI_EMBED_MODEL` or `text-embedding-3-small` of `openai` provider.
+// Models without provider prefix are used with `openai` provider.
+func (app *AppContext) InitEmbedAI() string {
+	modelWithProvider := strings.TrimSpace(app.Model)
+	if modelWithProvider == "" {
+		modelWithProvider = strings.TrimSpace(app.GetEnv("GAI_EMBED_MODEL"))
+	}
+	if modelWithProvider == "" {
+		modelWithProvider = defaultEmbedModel
+	}
+	if !strings.Contains(modelWithProvider, ":") {
+		modelWithProvider = fmt.Sprintf("openai:%s", modelWithProvider)
+	}
+
+	app.Model = modelWithProvider
+
+	provider, model, err := ParseModelWithProvider(modelWithProvider)
+	if err != nil {
+		app.CheckIfError(NewTypedError(ErrorTypeUsage, err))
+	}
+
+	client, err := app.NewAIClient(provider)
+	app.CheckIfError(err)
+
+	app.Dbg(fmt.Sprintf("Using '%v' provider with '%v' model for embeddings ...", provider, model))
+
+	app.AI = client
+
+	return model
+}
+
+// InitTranscribeAI initializes the default AI client for transcriptions
+// and returns the transcription model, which is taken from `--model`,
+// `GAI_TRANSCRIBE_MODEL` or `whisper-1` of `openai` provider.
+// Models without provider prefix are used with `openai` provider.
+func (app *AppContext) InitTranscribeAI() string {
+	modelWithProvider := strings.TrimSpace(app.Model)
+	if modelWithProvider == "" {
+		modelWithProvider = strings.TrimSpace(app.GetEnv("GAI_TRANSCRIBE_MODEL"))
+	}
+	if modelWithProvider == "" {
+		modelWithProvider = defaultTranscribeModel
+	}
+	if !strings.Contains(modelWithProvider, ":") {
+		modelWithProvider = fmt.Sprintf("openai:%s", modelWithProvider)
+	}
+
+	app.Model = modelWithProvider
+
+	provider, model, err := ParseModelWithProvider(modelWithProvider)
+	if err != nil {
+		app.CheckIfError(NewTypedError(ErrorTypeUsage, err))
+	}
+
+	client, err := app.NewAIClient(provider)
+	app.CheckIfError(err)
+
+	app.Dbg(fmt.Sprintf("Using '%v' provider with '%v' model for transcriptions ...", provider, model))
+
+	app.AI = client
+
+	return model
+}
+
 func (app *AppContext) initWorkingDirectory() {
 	// current working directory
 	cwd, err := os.Getwd()
@@ -114,6 +231,11 @@ func (app *AppContext) Init() {
 
 	app.loadRCFile()
 
+	if app.JSONOutput {
+		// ANSI codes would corrupt the JSON
+		app.NoHighlight = true
+	}
+
 	outputFile := app.GetOutputFile()
 	if outputFile != "" {
 		file, err := os.OpenFile(outputFile, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
@@ -0,0 +1,131 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestGetModelCandidates(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.CommandPath = []string{"describe", "images"}
+
+	expected := []string{
+		"--model",
+		"GAI_DEFAULT_COMMAND_MODEL__DESCRIBE_IMAGES",
+		"GAI_DEFAULT_CHAT_MODEL",
+		"defaults.flags.model of .gairc",
+	}
+
+	candidates := app.GetModelCandidates()
+	if !slices.Equal(candidates, expected) {
+		t.Fatalf("expected candidates %v, got %v", expected, candidates)
+	}
+}
+
+func TestGetDefaultModel(t *testing.T) {
+	tests := []struct {
+		name           string
+		flag           string
+		envVars        map[string]string
+		rcModel        string
+		expectedModel  string
+		expectedSource string
+	}{
+		{
+			name:           "flag",
+			flag:           "openai:a",
+			envVars:        map[string]string{"GAI_DEFAULT_COMMAND_MODEL__PROMPT": "openai:b", "GAI_DEFAULT_CHAT_MODEL": "openai:c"},
+			rcModel:        "openai:d",
+			expectedModel:  "openai:a",
+			expectedSource: "--model",
+		},
+		{
+			name:           "command",
+			envVars:        map[string]string{"GAI_DEFAULT_COMMAND_MODEL__PROMPT": "openai:b", "GAI_DEFAULT_CHAT_MODEL": "openai:c"},
+			rcModel:        "openai:d",
+			expectedModel:  "openai:b",
+			expectedSource: "GAI_DEFAULT_COMMAND_MODEL__PROMPT",
+		},
+		{
+			name:           "chat",
+			envVars:        map[string]string{"GAI_DEFAULT_CHAT_MODEL": "openai:c"},
+			rcModel:        "openai:d",
+			expectedModel:  "openai:c",
+			expectedSource: "GAI_DEFAULT_CHAT_MODEL",
+		},
+		{
+			name:           "rc file",
+			rcModel:        "openai:d",
+			expectedModel:  "openai:d",
+			expectedSource: "defaults.flags.model of .gairc",
+		},
+		{
+			name: "none",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			app := newTestApp(t, test.envVars)
+			app.CommandPath = []string{"prompt"}
+			app.Model = test.flag
+			app.RCFile.Defaults.Flags.Model = test.rcModel
+
+			model, source := app.GetDefaultModel()
+			if model != test.expectedModel || source != test.expectedSource {
+				t.Fatalf("expected %q from %q, got %q from %q", test.expectedModel, test.expectedSource, model, source)
+			}
+		})
+	}
+}
+
+func TestParseModelWithProvider(t *testing.T) {
+	tests := []struct {
+		input            string
+		expectedProvider string
+		expectedModel    string
+		expectError      bool
+	}{
+		{input: "openai:gpt-4o", expectedProvider: "openai", expectedModel: "gpt-4o"},
+		{input: " ollama : llama3.1:8b ", expectedProvider: "ollama", expectedModel: "llama3.1:8b"},
+		{input: "gpt-4o", expectError: true},
+		{input: ":gpt-4o", expectError: true},
+		{input: "openai:", expectError: true},
+	}
+
+	for _, test := range tests {
+		provider, model, err := ParseModelWithProvider(test.input)
+		if test.expectError {
+			if err == nil {
+				t.Errorf("%q: expected error", test.input)
+			}
+			continue
+		}
+
+		if err != nil || provider != test.expectedProvider || model != test.expectedModel {
+			t.Errorf("%q: expected %q and %q, got %q and %q (%v)", test.input, test.expectedProvider, test.expectedModel, provider, model, err)
+		}
+	}
+}
@@ -52,6 +52,17 @@ func (app *AppContext) GetEnvOrNil(key string) *string {
 	return nil
 }
 
+// GetDefaultEnvFiles returns the paths of the default `.env` files
+// in the order they are loaded, while `appDir` is the app directory.
+func (app *AppContext) GetDefaultEnvFiles(appDir string) []string {
+	return []string{
+		filepath.Join(app.HomeDirectory, ".env"),
+		filepath.Join(appDir, ".env"),
+		filepath.Join(app.WorkingDirectory, ".env"),
+		filepath.Join(app.WorkingDirectory, ".env.local"),
+	}
+}
+
 func (app *AppContext) loadEnvFilesIfExist() {
 	envVars := map[string]string{}
 
@@ -85,14 +96,7 @@ func (app *AppContext) loadEnvFilesIfExist() {
 		appDir, err := app.EnsureAppDir()
 		app.CheckIfError(err)
 
-		envPaths := []string{
-			filepath.Join(app.HomeDirectory, ".env"),
-			filepath.Join(appDir, ".env"),
-			filepath.Join(app.WorkingDirectory, ".env"),
-			filepath.Join(app.WorkingDirectory, ".env.local"),
-		}
-
-		for _, envPath := range envPaths {
+		for _, envPath := range app.GetDefaultEnvFiles(appDir) {
 			func() {
 				if _, err := os.Stat(envPath); err == nil {
 					loadFromFile(envPath)
@@ -23,24 +23,80 @@
 package types
 
 import (
+	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"os"
 	"path/filepath"
 	"strconv"
 	"strings"
 
-	"golang.org/x/term"
+	"github.com/mkloubert/gai/utils"
 )
 
+const defaultAttachmentMaxInline int64 = 1024 * 1024
+
+const defaultAzureApiVersion = "2024-10-21"
+
+// defaultNoTemperatureModels contains the prefixes of models,
+// which reject a custom `temperature`, like reasoning models.
+var defaultNoTemperatureModels = []string{"gpt-5", "o1", "o3", "o4"}
+
+// verbosityLevel stores the settings of a level of `--verbosity`.
+type verbosityLevel struct {
+	// Instruction is appended to the system prompt.
+	Instruction string
+	// MaxTokens is the token limit of answers, if `--max-tokens` is not defined.
+	MaxTokens int64
+}
+
+// verbosityLevels contains the settings of the levels `0` to `3` of `--verbosity`.
+var verbosityLevels = []verbosityLevel{
+	{Instruction: "Answer as briefly as possible, in one or two sentences and without explanations.", MaxTokens: 256},
+	{Instruction: "Keep your answer short and focus on the essentials.", MaxTokens: 1024},
+	{Instruction: "", MaxTokens: 0},
+	{Instruction: "Answer thoroughly and in detail, with explanations and examples where useful.", MaxTokens: 0},
+}
+
+const initalGeminiChatModel = "gemini:gemini-2.0-flash"
 const initalOllamaChatModel = "ollama:llama3.1:8b"
 const initalOpenAIChatModel = "openai:gpt-4.1-mini"
 
-// GetBaseUrl returns the base URL for API operations, if defined.
-func (app *AppContext) GetBaseUrl() string {
+const defaultEmbedModel = "openai:text-embedding-3-small"
+const defaultTranscribeModel = "openai:whisper-1"
+
+// GetAttachmentMaxInline returns the maximum size in bytes of an attachment,
+// which can be inlined as data URI. A value below `0` means no limit.
+func (app *AppContext) GetAttachmentMaxInline() (int64, error) {
+	maxInline := app.AttachmentMaxInline
+
+	if maxInline == 0 {
+		GAI_ATTACHMENT_MAX_INLINE := strings.TrimSpace(app.GetEnv("GAI_ATTACHMENT_MAX_INLINE"))
+		if GAI_ATTACHMENT_MAX_INLINE != "" {
+			num, err := strconv.ParseInt(GAI_ATTACHMENT_MAX_INLINE, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+
+			maxInline = num
+		}
+	}
+
+	if maxInline == 0 {
+		maxInline = defaultAttachmentMaxInline
+	}
+
+	return maxInline, nil
+}
+
+// GetBaseUrl returns the base URL for API operations of `provider`, if defined:
+// `--base-url`, `GAI_BASE_URL_<PROVIDER>`, like `GAI_BASE_URL_OLLAMA`, and finally `GAI_BASE_URL`.
+func (app *AppContext) GetBaseUrl(provider string) string {
 	baseUrl := strings.TrimSpace(app.BaseUrl)
+	if baseUrl == "" && strings.TrimSpace(provider) != "" {
+		baseUrl = strings.TrimSpace(app.GetEnv(fmt.Sprintf("GAI_BASE_URL_%s", strings.ToUpper(strings.TrimSpace(provider)))))
+	}
 	if baseUrl == "" {
 		baseUrl = strings.TrimSpace(app.GetEnv("GAI_BASE_URL"))
 	}
@@ -48,6 +104,92 @@ func (app *AppContext) GetBaseUrl() string {
 	return baseUrl
 }
 
+// GetConversationFormat returns the format of the conversation file,
+// which is `yaml` (default) or `json`.
+func (app *AppContext) GetConversationFormat() (string, error) {
+	conversationFormat := strings.TrimSpace(strings.ToLower(app.ConversationFormat)) // first try flag
+	if conversationFormat == "" {
+		conversationFormat = strings.TrimSpace(strings.ToLower(app.GetEnv("GAI_CONVERSATION_FORMAT"))) // now try env variable
+	}
+
+	switch conversationFormat {
+	case "", "yaml", "yml":
+		return "yaml", nil
+	case "json":
+		return "json", nil
+	}
+
+	return conversationFormat, fmt.Errorf("'%v' is an unknown conversation format", conversationFormat)
+}
+
+// GetHistoryLimit returns the maximum number of previous turns, which
+// should be sent with a chat request. `0` means no limit.
+func (app *AppContext) GetHistoryLimit() (int64, error) {
+	historyLimit := app.HistoryLimit // first try flag
+
+	if historyLimit < 0 {
+		GAI_HISTORY_LIMIT := strings.TrimSpace(app.GetEnv("GAI_HISTORY_LIMIT")) // now try env variable
+		if GAI_HISTORY_LIMIT != "" {
+			num, err := strconv.ParseInt(GAI_HISTORY_LIMIT, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+
+			historyLimit = num
+		} else if app.RCFile != nil && app.RCFile.Defaults.Flags.HistoryLimit != nil {
+			historyLimit = *app.RCFile.Defaults.Flags.HistoryLimit // and finally .gairc file
+		}
+	}
+
+	if historyLimit < 0 {
+		return 0, nil // not defined => no limit
+	}
+	return historyLimit, nil
+}
+
+// GetHistorySummary returns `true` if turns beyond the history limit should
+// be sent as rolling summary.
+func (app *AppContext) GetHistorySummary() (bool, error) {
+	if app.HistorySummary {
+		return true, nil // flag
+	}
+
+	GAI_HISTORY_SUMMARY := strings.TrimSpace(app.GetEnv("GAI_HISTORY_SUMMARY")) // now try env variable
+	if GAI_HISTORY_SUMMARY != "" {
+		return strconv.ParseBool(GAI_HISTORY_SUMMARY)
+	}
+
+	if app.RCFile != nil && app.RCFile.Defaults.Flags.HistorySummary != nil {
+		return *app.RCFile.Defaults.Flags.HistorySummary, nil // and finally .gairc file
+	}
+	return false, nil
+}
+
+// GetMaxHistoryTokens returns the maximum number of tokens of previous
+// turns, which should be sent with a chat request. `0` means no limit.
+func (app *AppContext) GetMaxHistoryTokens() (int, error) {
+	maxHistoryTokens := app.MaxHistoryTokens // first try flag
+
+	if maxHistoryTokens < 0 {
+		GAI_MAX_HISTORY_TOKENS := strings.TrimSpace(app.GetEnv("GAI_MAX_HISTORY_TOKENS")) // now try env variable
+		if GAI_MAX_HISTORY_TOKENS != "" {
+			num, err := strconv.ParseInt(GAI_MAX_HISTORY_TOKENS, 10, 64)
+			if err != nil {
+				return 0, err
+			}
+
+			maxHistoryTokens = num
+		} else if app.RCFile != nil && app.RCFile.Defaults.Flags.MaxHistoryTokens != nil {
+			maxHistoryTokens = *app.RCFile.Defaults.Flags.MaxHistoryTokens // and finally .gairc file
+		}
+	}
+
+	if maxHistoryTokens < 0 {
+		return 0, nil // not defined => no limit
+	}
+	return int(maxHistoryTokens), nil
+}
+
 // GetMaxTokens returns the maximum number of GPT tokens to return / use.
 func (app *AppContext) GetMaxTokens() (*int64, error) {
 	maxTokens := app.MaxTokens
@@ -64,13 +206,64 @@ func (app *AppContext) GetMaxTokens() (*int64, error) {
 		}
 	}
 
+	if maxTokens <= 0 {
+		verbosity, err := app.GetVerbosity()
+		if err != nil {
+			return nil, err
+		}
+
+		if verbosity > -1 {
+			maxTokens = verbosityLevels[verbosity].MaxTokens // limit of verbosity level
+		}
+	}
+
 	if maxTokens > 0 {
 		return &maxTokens, nil
 	}
 	return nil, nil // let AI decide to use what default
 }
 
+// GetNoTemperatureModels returns the name prefixes of models, which do not
+// accept a `temperature`, from `GAI_NO_TEMPERATURE_MODELS` (comma separated)
+// or the defaults.
+func (app *AppContext) GetNoTemperatureModels() []string {
+	GAI_NO_TEMPERATURE_MODELS := app.GetEnv("GAI_NO_TEMPERATURE_MODELS")
+	if strings.TrimSpace(GAI_NO_TEMPERATURE_MODELS) == "" {
+		return defaultNoTemperatureModels
+	}
+
+	prefixes := make([]string, 0)
+	for _, p := range strings.Split(GAI_NO_TEMPERATURE_MODELS, ",") {
+		p = strings.TrimSpace(strings.ToLower(p))
+		if p != "" {
+			prefixes = append(prefixes, p)
+		}
+	}
+
+	return prefixes
+}
+
+// GetOpenAIApi returns the name of the OpenAI API to use,
+// which is `chat` (default) or `responses`.
+func (app *AppContext) GetOpenAIApi() (string, error) {
+	openaiApi := strings.TrimSpace(strings.ToLower(app.OpenAIApi)) // first try flag
+	if openaiApi == "" {
+		openaiApi = strings.TrimSpace(strings.ToLower(app.GetEnv("GAI_OPENAI_API"))) // now try env variable
+	}
+
+	switch openaiApi {
+	case "", "chat":
+		return "chat", nil
+	case "responses":
+		return "responses", nil
+	}
+
+	return openaiApi, fmt.Errorf("'%v' is an unknown OpenAI API", openaiApi)
+}
+
 // GetResponseSchema loads the data for response format with schema and name.
+// The schema is taken from the file of `--schema` or from a named preset
+// of `--schema-name` inside `GetSchemasDir()`, otherwise it is `nil`.
 func (app *AppContext) GetResponseSchema() (*map[string]any, string, error) {
 	var schema *map[string]any
 	schemaName := ""
@@ -86,42 +279,196 @@ func (app *AppContext) GetResponseSchema() (*map[string]any, string, error) {
 			schemaName = "GaiResponseSchema"
 		}
 
-		file, err := os.Open(schemaFile)
-		if err != nil {
-			return schema, schemaName, err
-		}
-		defer file.Close()
+		sanitizedSchemaName := utils.SanitizeJSONSchemaName(schemaName)
+		if sanitizedSchemaName != schemaName {
+			if app.JSONSchemaStrictName || sanitizedSchemaName == "" {
+				return schema, schemaName, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid schema name, which must match ^[a-zA-Z0-9_-]+$ and have max. 64 chars", schemaName))
+			}
 
-		data, err := io.ReadAll(file)
-		if err != nil {
-			return schema, schemaName, err
+			app.WriteErrorString(fmt.Sprintf("WARN: schema name '%v' has been changed to '%v'%v", schemaName, sanitizedSchemaName, app.EOL))
+
+			schemaName = sanitizedSchemaName
 		}
 
-		var temp map[string]any
-		err = json.Unmarshal(data, &temp)
+		temp, err := loadResponseSchemaFile(schemaFile)
 		if err != nil {
 			return schema, schemaName, err
 		}
 
-		schema = &temp
+		schema = temp
+	} else {
+		presetName := strings.TrimSpace(app.SchemaName)
+		if presetName != "" {
+			// now try named preset
+
+			if utils.SanitizeJSONSchemaName(presetName) != presetName {
+				return schema, schemaName, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid name of a schema preset, which must match ^[a-zA-Z0-9_-]+$ and have max. 64 chars", presetName))
+			}
+
+			preset, err := app.GetSchemaPreset(presetName)
+			if err != nil {
+				return schema, schemaName, err
+			}
+			if preset == nil {
+				schemasDir, err := app.GetSchemasDir()
+				if err != nil {
+					return schema, schemaName, err
+				}
+
+				return schema, schemaName, NewTypedError(ErrorTypeUsage, fmt.Errorf("schema preset '%v' not found in '%v', use --schema for a file or list the presets with `gai list schema-presets`", presetName, schemasDir))
+			}
+
+			app.Dbgf("Loading schema preset from '%v' ...%v", preset.Path, app.EOL)
+
+			temp, err := loadResponseSchemaFile(preset.Path)
+			if err != nil {
+				return schema, schemaName, err
+			}
+
+			schema = temp
+			schemaName = preset.Name
+		}
 	}
 
 	return schema, schemaName, nil
 }
 
+// GetPseudoAnswers returns the list of answers of the assistant
+// in pseudo conversations, which is `OK` by default.
+func (app *AppContext) GetPseudoAnswers() []string {
+	answers := make([]string, 0)
+	for _, a := range app.PseudoAnswers { // first try flags
+		a = strings.TrimSpace(a)
+		if a != "" {
+			answers = append(answers, a)
+		}
+	}
+
+	if len(answers) == 0 {
+		a := strings.TrimSpace(app.GetEnv("GAI_PSEUDO_ANSWER")) // now try env variable
+		if a != "" {
+			answers = append(answers, a)
+		}
+	}
+
+	if len(answers) == 0 {
+		answers = append(answers, "OK")
+	}
+	return answers
+}
+
+// GetPseudoMode returns the mode of pseudo conversations, which is
+// `turns` (default), where each user message is answered by the assistant,
+// or `single`, where all user messages are merged without answers.
+func (app *AppContext) GetPseudoMode() (string, error) {
+	pseudoMode := strings.TrimSpace(strings.ToLower(app.PseudoMode)) // first try flag
+	if pseudoMode == "" {
+		pseudoMode = strings.TrimSpace(strings.ToLower(app.GetEnv("GAI_PSEUDO_MODE"))) // now try env variable
+	}
+
+	switch pseudoMode {
+	case "", "turns":
+		return "turns", nil
+	case "single":
+		return "single", nil
+	}
+
+	return pseudoMode, fmt.Errorf("'%v' is an unknown pseudo conversation mode", pseudoMode)
+}
+
 // GetSystemPrompt returns the system prompt value for AI operations.
 func (app *AppContext) GetSystemPrompt(defaultPrompt string) string {
 	systemPrompt := strings.TrimSpace(app.SystemPrompt) // first try flag
+	if systemPrompt == "" {
+		systemPrompt = app.readSystemPromptFile(app.SystemPromptFile) // now try file from flag
+	}
 	if systemPrompt == "" {
 		systemPrompt = strings.TrimSpace(app.GetEnv("GAI_SYSTEM_PROMPT")) // now try env variable
 	}
+	if systemPrompt == "" {
+		systemPrompt = app.readSystemPromptFile(app.GetEnv("GAI_SYSTEM_PROMPT_FILE")) // now try file from env variable
+	}
 
 	if systemPrompt == "" {
-		return defaultPrompt
+		systemPrompt = defaultPrompt
+	}
+
+	verbosity, err := app.GetVerbosity()
+	app.CheckIfError(err)
+
+	if verbosity > -1 {
+		instruction := verbosityLevels[verbosity].Instruction
+		if instruction != "" {
+			if systemPrompt == "" {
+				systemPrompt = instruction
+			} else {
+				systemPrompt = fmt.Sprintf("%s%s%s%s", strings.TrimSpace(systemPrompt), app.EOL, app.EOL, instruction)
+			}
+		}
 	}
+
 	return systemPrompt
 }
 
+func (app *AppContext) readSystemPromptFile(systemPromptFile string) string {
+	systemPromptFile = strings.TrimSpace(systemPromptFile)
+	if systemPromptFile == "" {
+		return ""
+	}
+
+	if !filepath.IsAbs(systemPromptFile) {
+		systemPromptFile = filepath.Join(app.WorkingDirectory, systemPromptFile)
+	}
+
+	app.Dbgf("Reading system prompt from '%v' ...%v", systemPromptFile, app.EOL)
+
+	data, err := os.ReadFile(systemPromptFile)
+	app.CheckIfError(err)
+
+	return strings.TrimSpace(string(data))
+}
+
+// GetVerbosity returns the level of detail of answers from `0` to `3`
+// by `--brief`, `--detailed`, `--verbosity` or `GAI_VERBOSITY`.
+// `-1` means not defined.
+func (app *AppContext) GetVerbosity() (int64, error) {
+	if app.Brief && app.Detailed {
+		return -1, NewTypedError(ErrorTypeUsage, fmt.Errorf("--brief and --detailed cannot be used together"))
+	}
+
+	verbosity := app.Verbosity // first try flags
+	if app.Brief || app.Detailed {
+		if verbosity > -1 {
+			return -1, NewTypedError(ErrorTypeUsage, fmt.Errorf("--verbosity cannot be used together with --brief or --detailed"))
+		}
+
+		if app.Brief {
+			verbosity = 1
+		} else {
+			verbosity = 3
+		}
+	}
+
+	if verbosity < 0 {
+		GAI_VERBOSITY := strings.TrimSpace(app.GetEnv("GAI_VERBOSITY")) // now try env variable
+		if GAI_VERBOSITY == "" {
+			return -1, nil // not defined
+		}
+
+		num, err := strconv.ParseInt(GAI_VERBOSITY, 10, 64)
+		if err != nil {
+			return -1, NewTypedError(ErrorTypeUsage, fmt.Errorf("invalid GAI_VERBOSITY '%v': %w", GAI_VERBOSITY, err))
+		}
+
+		verbosity = num
+	}
+
+	if verbosity >= int64(len(verbosityLevels)) || verbosity < 0 {
+		return -1, NewTypedError(ErrorTypeUsage, fmt.Errorf("verbosity must be between 0 and %v", len(verbosityLevels)-1))
+	}
+	return verbosity, nil
+}
+
 // GetSystemRole returns the name/ID of the system role for AI operations.
 func (app *AppContext) GetSystemRole() string {
 	systemRole := strings.TrimSpace(app.SystemRole) // first try flag
@@ -136,6 +483,65 @@ func (app *AppContext) GetSystemRole() string {
 	return systemRole
 }
 
+// samplingParameters stores optional sampling parameters of a request.
+type samplingParameters struct {
+	frequencyPenalty *float64
+	presencePenalty  *float64
+	topP             *float64
+}
+
+// getOptionalFloat64 returns `override`, `flagValue` or the value of
+// the env variable `envName` in this order, which must be between `minValue`
+// and `maxValue`. `nil` means that the value is not defined.
+func (app *AppContext) getOptionalFloat64(override *float64, flagValue *float64, envName string, flagName string, minValue float64, maxValue float64) (*float64, error) {
+	value := override
+	if value == nil {
+		value = flagValue
+	}
+	if value == nil {
+		envValue := strings.TrimSpace(app.GetEnv(envName))
+		if envValue != "" {
+			f64, err := strconv.ParseFloat(envValue, 64)
+			if err != nil {
+				return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid value for %v", envValue, envName))
+			}
+
+			value = &f64
+		}
+	}
+
+	if value != nil && (*value < minValue || *value > maxValue) {
+		return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("value of --%v must be between %v and %v", flagName, minValue, maxValue))
+	}
+
+	return value, nil
+}
+
+// getSamplingParameters returns the optional sampling parameters, which can be
+// overwritten per request by `overrides`, like `--top-p` or `GAI_TOP_P`.
+func (app *AppContext) getSamplingParameters(overrides samplingParameters) (samplingParameters, error) {
+	params := samplingParameters{}
+
+	var err error
+
+	params.frequencyPenalty, err = app.getOptionalFloat64(overrides.frequencyPenalty, app.FrequencyPenalty, "GAI_FREQUENCY_PENALTY", "frequency-penalty", -2, 2)
+	if err != nil {
+		return params, err
+	}
+
+	params.presencePenalty, err = app.getOptionalFloat64(overrides.presencePenalty, app.PresencePenalty, "GAI_PRESENCE_PENALTY", "presence-penalty", -2, 2)
+	if err != nil {
+		return params, err
+	}
+
+	params.topP, err = app.getOptionalFloat64(overrides.topP, app.TopP, "GAI_TOP_P", "top-p", 0, 1)
+	if err != nil {
+		return params, err
+	}
+
+	return params, nil
+}
+
 // GetTemperature returns the temperature value for AI operations.
 func (app *AppContext) GetTemperature() (float64, error) {
 	if app.Temperature >= 0 {
@@ -157,6 +563,178 @@ func (app *AppContext) GetTemperature() (float64, error) {
 	return 0.3, nil
 }
 
+// SupportsTemperature returns `false` if the name of `model` starts
+// with one of the prefixes of `GetNoTemperatureModels`.
+func (app *AppContext) SupportsTemperature(model string) bool {
+	model = strings.TrimSpace(strings.ToLower(model))
+
+	for _, prefix := range app.GetNoTemperatureModels() {
+		if strings.HasPrefix(model, prefix) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// GetTokenPrices returns the prices per 1,000 input and output tokens,
+// as defined by `GAI_PRICE_INPUT` and `GAI_PRICE_OUTPUT`.
+func (app *AppContext) GetTokenPrices() (utils.TokenPrices, error) {
+	var prices utils.TokenPrices
+
+	parsePrice := func(name string) (*float64, error) {
+		value := strings.TrimSpace(app.GetEnv(name))
+		if value == "" {
+			return nil, nil
+		}
+
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil || price < 0 {
+			return nil, fmt.Errorf("invalid value '%s' for %s", value, name)
+		}
+		return &price, nil
+	}
+
+	var err error
+	prices.Input, err = parsePrice("GAI_PRICE_INPUT")
+	if err != nil {
+		return prices, err
+	}
+	prices.Output, err = parsePrice("GAI_PRICE_OUTPUT")
+	if err != nil {
+		return prices, err
+	}
+
+	return prices, nil
+}
+
+// NewAttachmentContentItem creates a new content item for a file with the
+// optional file `name`, which is no image or audio. Files, which are larger than
+// the value of `GetAttachmentMaxInline`, are converted to plain text instead
+// of inlining them as data URI. If `SaveAttachmentsDir` is defined, the
+// extracted text of each attachment is written there.
+func (app *AppContext) NewAttachmentContentItem(data []byte, mimeType string, name string) (*ConversationRepositoryConversationItemContentItem, error) {
+	maxInline, err := app.GetAttachmentMaxInline()
+	if err != nil {
+		return nil, err
+	}
+
+	size := int64(len(data))
+
+	redact, err := app.GetRedact()
+	if err != nil {
+		return nil, err
+	}
+	if redact {
+		// secrets can only be removed from text
+		text, err := utils.EnsurePlainText(data)
+		if err != nil || utils.MaybeBinary([]byte(text)) {
+			return nil, fmt.Errorf("could not extract text of attachment '%v' of type '%v' to redact it", name, mimeType)
+		}
+
+		text, err = app.RedactText(text, name)
+		if err != nil {
+			return nil, err
+		}
+
+		err = app.saveExtractedAttachmentText(name, text)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ConversationRepositoryConversationItemContentItem{
+			Content: text,
+			Type:    "text",
+		}, nil
+	}
+
+	if maxInline < 0 || size <= maxInline {
+		encoded := base64.StdEncoding.EncodeToString(data)
+
+		if strings.TrimSpace(app.SaveAttachmentsDir) != "" {
+			// the model gets the original data, but the text
+			// shows what could be extracted from it
+			text, err := utils.EnsurePlainText(data)
+			if err == nil && strings.TrimSpace(text) != "" && !utils.MaybeBinary([]byte(text)) {
+				err = app.saveExtractedAttachmentText(name, text)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				app.Dbgf("No text could be extracted from inlined attachment '%v'%v", name, app.EOL)
+			}
+		}
+
+		return &ConversationRepositoryConversationItemContentItem{
+			Content: fmt.Sprintf("data:%s;base64,%s", mimeType, encoded),
+			Type:    "attachment",
+		}, nil
+	}
+
+	app.Dbgf("Attachment of type '%v' has %v bytes and is too large for inlining, try to extract text ...%v", mimeType, size, app.EOL)
+
+	text, err := utils.EnsurePlainText(data)
+	if err == nil && strings.TrimSpace(text) != "" && !utils.MaybeBinary([]byte(text)) {
+		err = app.saveExtractedAttachmentText(name, text)
+		if err != nil {
+			return nil, err
+		}
+
+		return &ConversationRepositoryConversationItemContentItem{
+			Content: text,
+			Type:    "text",
+		}, nil
+	}
+
+	return nil, fmt.Errorf(
+		"attachment of type '%v' has %v bytes, which is more than %v bytes that can be inlined, and its text could not be extracted (increase limit with --attachment-max-inline or GAI_ATTACHMENT_MAX_INLINE)",
+		mimeType, size, maxInline,
+	)
+}
+
+// saveExtractedAttachmentText writes the extracted `text` of an attachment
+// with `name` as `<name>.txt` to `SaveAttachmentsDir`, if defined.
+// Paths inside the working directory are kept relative to it and other
+// paths are kept as a whole, so files with the same name do not collide.
+func (app *AppContext) saveExtractedAttachmentText(name string, text string) error {
+	dir := strings.TrimSpace(app.SaveAttachmentsDir)
+	if dir == "" {
+		return nil
+	}
+
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(app.WorkingDirectory, dir)
+	}
+
+	relPath := strings.TrimSpace(name)
+	if filepath.IsAbs(relPath) {
+		rel, err := filepath.Rel(app.WorkingDirectory, relPath)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			relPath = rel
+		} else {
+			relPath = relPath[len(filepath.VolumeName(relPath)):]
+		}
+	}
+
+	// cleaning an absolute path removes all `..`,
+	// so the file cannot be written outside of `dir`
+	relPath = filepath.Clean(string(filepath.Separator) + relPath)
+	if relPath == string(filepath.Separator) {
+		relPath = "attachment"
+	}
+
+	textFile := filepath.Join(dir, relPath+".txt")
+
+	err := os.MkdirAll(filepath.Dir(textFile), 0755)
+	if err != nil {
+		return err
+	}
+
+	app.Dbgf("Writing extracted text of attachment '%v' to '%v' ...%v", name, textFile, app.EOL)
+
+	return os.WriteFile(textFile, []byte(text), 0644)
+}
+
 // NewAIClient creates a new `AIClient` instance for a `provider`.
 func (app *AppContext) NewAIClient(provider string) (AIClient, error) {
 	provider = strings.TrimSpace(
@@ -172,6 +750,76 @@ func (app *AppContext) NewAIClient(provider string) (AIClient, error) {
 		return m, errors.New("could not get model format")
 	}
 
+	if provider == "azure" {
+		chatModel := ""
+		m := strings.TrimSpace(app.Model)
+		if m != "" {
+			n, err := getModelNameOnly(m)
+			if err != nil {
+				return nil, err
+			}
+
+			chatModel = n
+		}
+		if chatModel == "" {
+			return nil, NewTypedError(ErrorTypeUsage, errors.New("no Azure deployment defined, use azure:<deployment> format"))
+		}
+
+		if app.GetBaseUrl(provider) == "" {
+			return nil, NewTypedError(ErrorTypeUsage, errors.New("no Azure endpoint defined, use --base-url, GAI_BASE_URL_AZURE or GAI_BASE_URL"))
+		}
+
+		apiKey := strings.TrimSpace(app.ApiKey)
+		if apiKey == "" {
+			// now try env variable
+			apiKey = strings.TrimSpace(app.GetEnv("AZURE_OPENAI_API_KEY"))
+		}
+		if apiKey == "" {
+			return nil, NewTypedError(ErrorTypeAuth, errors.New("no API key defined"))
+		}
+
+		apiVersion := strings.TrimSpace(app.GetEnv("GAI_AZURE_API_VERSION"))
+		if apiVersion == "" {
+			apiVersion = defaultAzureApiVersion
+		}
+
+		azure := &OpenAIClient{}
+		azure.apiKey = apiKey
+		azure.app = app
+		azure.azureApiVersion = apiVersion
+		azure.chatModel = chatModel
+
+		return azure, nil
+	}
+
+	if provider == "gemini" {
+		m := strings.TrimSpace(app.Model)
+		if m == "" {
+			m = initalGeminiChatModel
+		}
+
+		chatModel, err := getModelNameOnly(m)
+		if err != nil {
+			return nil, err
+		}
+
+		apiKey := strings.TrimSpace(app.ApiKey)
+		if apiKey == "" {
+			// now try env variable
+			apiKey = strings.TrimSpace(app.GetEnv("GEMINI_API_KEY"))
+		}
+		if apiKey == "" {
+			return nil, NewTypedError(ErrorTypeAuth, errors.New("no API key defined"))
+		}
+
+		gemini := &GeminiClient{}
+		gemini.apiKey = apiKey
+		gemini.app = app
+		gemini.chatModel = chatModel
+
+		return gemini, nil
+	}
+
 	if provider == "ollama" {
 		m := strings.TrimSpace(app.Model)
 		if m == "" {
@@ -207,7 +855,7 @@ func (app *AppContext) NewAIClient(provider string) (AIClient, error) {
 			apiKey = strings.TrimSpace(app.GetEnv("OPENAI_API_KEY"))
 		}
 		if apiKey == "" {
-			return nil, errors.New("no API defined")
+			return nil, NewTypedError(ErrorTypeAuth, errors.New("no API key defined"))
 		}
 
 		openai := &OpenAIClient{}
@@ -223,14 +871,124 @@ func (app *AppContext) NewAIClient(provider string) (AIClient, error) {
 
 // OutputAIAnswer outputs an AI answer to STDOUT.
 func (app *AppContext) OutputAIAnswer(answer string) {
-	stdout := app.Stdout
-
-	if !app.NoHighlight && term.IsTerminal(int(stdout.Fd())) {
-		chroma := app.GetChromaSettings()
-		chroma.HighlightMarkdown(answer)
+	if app.JSONOutput {
+		responseSchema, _, err := app.GetResponseSchema()
+		app.CheckIfError(err)
+
+		if responseSchema != nil && !json.Valid([]byte(answer)) {
+			app.CheckIfError(NewTypedError(
+				ErrorTypeInvalidJSON,
+				errors.New("answer of the model is no valid JSON"),
+			))
+		}
+	}
 
+	if app.OutputMarkdown(answer) {
 		app.Writeln()
+	}
+
+	teeFile := app.GetTeeFile()
+	if teeFile != "" {
+		// the copy is always written without highlighting
+		app.Dbgf("Writing copy of answer to '%v' ...%v", teeFile, app.EOL)
+
+		err := os.WriteFile(teeFile, []byte(answer), 0644)
+		app.CheckIfError(err)
+	}
+}
+
+// OutputAIUsage writes token usage to STDERR, if `Verbose` is `true`.
+func (app *AppContext) OutputAIUsage(usage *AIUsage) {
+	if !app.Verbose || usage == nil {
+		return
+	}
+
+	app.WriteErrorString(fmt.Sprintf("%s%s", usage.String(), app.EOL))
+}
+
+// OutputAIUsageOf writes the token usage of the last answer in
+// `conversation` to STDERR, if `Verbose` is `true`.
+func (app *AppContext) OutputAIUsageOf(conversation ConversationRepositoryConversation) {
+	if len(conversation) == 0 {
+		return
+	}
+
+	app.OutputAIUsage(conversation[len(conversation)-1].Usage)
+}
+
+// GetApproximateContentOfFiles returns the text, which is approximately
+// submitted for `files` and additional `blobs`, like data from STDIN,
+// and the size of their binary content, like images.
+func (app *AppContext) GetApproximateContentOfFiles(files []string, blobs ...[]byte) (string, uint64, error) {
+	var text strings.Builder
+	binarySize := uint64(0)
+
+	allData := make([][]byte, 0, len(files)+len(blobs))
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return text.String(), binarySize, err
+		}
+
+		allData = append(allData, data)
+	}
+	allData = append(allData, blobs...)
+
+	for _, data := range allData {
+		mimeType := utils.DetectMime(data)
+		if strings.HasPrefix(mimeType, "image/") || strings.HasPrefix(mimeType, "audio/") {
+			binarySize += uint64(len(data))
+			continue
+		}
+
+		str, err := utils.EnsurePlainText(data)
+		if err != nil {
+			binarySize += uint64(len(data)) // no text
+			continue
+		}
+
+		text.WriteString(str)
+	}
+
+	return text.String(), binarySize, nil
+}
+
+// OutputUsageEstimate writes the approximate sizes, tokens and costs
+// of a request with `text` and binary content of `binarySize` bytes.
+func (app *AppContext) OutputUsageEstimate(text string, textSize uint64, binarySize uint64) error {
+	prices, err := app.GetTokenPrices()
+	if err != nil {
+		return err
+	}
+
+	maxOutputTokens := int64(0)
+	maxTokens, err := app.GetMaxTokens()
+	if err != nil {
+		return err
+	}
+	if maxTokens != nil {
+		maxOutputTokens = *maxTokens
+	}
+
+	estimate := utils.EstimateUsage(
+		utils.NewTextTokenizer(app.AI.ChatModel()),
+		text,
+		maxOutputTokens,
+		prices,
+	)
+
+	app.Writeln(fmt.Sprintf("Approximate size of the total text content transferred: %d", textSize))
+	app.Writeln(fmt.Sprintf("Approximate size of the total binary content transferred: %d", binarySize))
+	app.Writeln(fmt.Sprintf("Approximate GPT tokens for text content transfered: %d", estimate.InputTokens))
+
+	if estimate.InputCost != nil {
+		app.Writeln(fmt.Sprintf("Estimated costs of input: %.6f", *estimate.InputCost))
 	} else {
-		app.WriteString(answer)
+		app.Writeln("Estimated costs of input: unknown (set GAI_PRICE_INPUT)")
 	}
+	if estimate.MaxOutputCost != nil {
+		app.Writeln(fmt.Sprintf("Estimated maximum costs of output (%d tokens): %.6f", estimate.MaxOutputTokens, *estimate.MaxOutputCost))
+	}
+
+	return nil
 }
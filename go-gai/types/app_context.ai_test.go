@@ -0,0 +1,390 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestGetOpenAIApi(t *testing.T) {
+	tests := []struct {
+		flag     string
+		env      string
+		expected string
+		isValid  bool
+	}{
+		{"", "", "chat", true},
+		{"", "Responses", "responses", true},
+		{" CHAT ", "responses", "chat", true},
+		{"responses", "", "responses", true},
+		{"completions", "", "completions", false},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_OPENAI_API": test.env,
+		})
+		app.OpenAIApi = test.flag
+
+		openaiApi, err := app.GetOpenAIApi()
+		if (err == nil) != test.isValid {
+			t.Errorf("flag %q, env %q: unexpected error %v", test.flag, test.env, err)
+		}
+		if openaiApi != test.expected {
+			t.Errorf("flag %q, env %q: expected %q, got %q", test.flag, test.env, test.expected, openaiApi)
+		}
+	}
+}
+
+func TestGetAttachmentMaxInline(t *testing.T) {
+	tests := []struct {
+		flag     int64
+		env      string
+		expected int64
+		isValid  bool
+	}{
+		{0, "", defaultAttachmentMaxInline, true},
+		{0, "2048", 2048, true},
+		{0, "-1", -1, true},
+		{100, "2048", 100, true},
+		{0, "1MB", 0, false},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_ATTACHMENT_MAX_INLINE": test.env,
+		})
+		app.AttachmentMaxInline = test.flag
+
+		maxInline, err := app.GetAttachmentMaxInline()
+		if (err == nil) != test.isValid {
+			t.Errorf("flag %v, env %q: unexpected error %v", test.flag, test.env, err)
+		}
+		if test.isValid && maxInline != test.expected {
+			t.Errorf("flag %v, env %q: expected %v, got %v", test.flag, test.env, test.expected, maxInline)
+		}
+	}
+}
+
+func TestNewAttachmentContentItem(t *testing.T) {
+	pdf := newTestPDF("Quarterly report", strings.Repeat("lorem ipsum ", 20))
+	binary := bytes.Repeat([]byte{0, 1, 2, 3}, 256)
+
+	tests := []struct {
+		name         string
+		data         []byte
+		maxInline    int64
+		expectedType string
+	}{
+		{"small.pdf", pdf, int64(len(pdf)), "attachment"},
+		{"large.pdf", pdf, int64(len(pdf)) - 1, "text"},
+		{"unlimited.pdf", pdf, -1, "attachment"},
+		{"small.bin", binary, int64(len(binary)), "attachment"},
+		{"large.bin", binary, 100, ""},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+		app.AttachmentMaxInline = test.maxInline
+
+		mimeType := "application/octet-stream"
+		if strings.HasSuffix(test.name, ".pdf") {
+			mimeType = "application/pdf"
+		}
+
+		item, err := app.NewAttachmentContentItem(test.data, mimeType, test.name)
+		if test.expectedType == "" {
+			// cannot be inlined and has no text
+			if err == nil || !strings.Contains(err.Error(), "--attachment-max-inline") {
+				t.Errorf("%v: expected error with guidance, got %v", test.name, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+
+		if item.Type != test.expectedType {
+			t.Errorf("%v: expected type %q, got %q", test.name, test.expectedType, item.Type)
+		}
+
+		switch test.expectedType {
+		case "attachment":
+			expected := "data:" + mimeType + ";base64," + base64.StdEncoding.EncodeToString(test.data)
+			if item.Content != expected {
+				t.Errorf("%v: expected data URI, got %q", test.name, item.Content)
+			}
+		case "text":
+			if strings.Contains(item.Content, "base64") || !strings.Contains(item.Content, "Quarterly report") {
+				t.Errorf("%v: expected extracted text, got %q", test.name, item.Content)
+			}
+		}
+	}
+}
+
+func TestNewAttachmentContentItemSavesText(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.SaveAttachmentsDir = "extracted"
+
+	outsideDir := t.TempDir()
+	pdf := newTestPDF("Quarterly report")
+
+	names := map[string]string{
+		filepath.Join(app.WorkingDirectory, "docs", "a.pdf"): filepath.Join("docs", "a.pdf.txt"),
+		filepath.Join(outsideDir, "a.pdf"):                   filepath.Join(outsideDir, "a.pdf.txt"),
+		"../../b.pdf":                                        "b.pdf.txt",
+		"":                                                   "attachment.txt",
+	}
+
+	// inlined and extracted attachments are saved
+	for _, maxInline := range []int64{-1, 1} {
+		app.AttachmentMaxInline = maxInline
+
+		for name, expected := range names {
+			_, err := app.NewAttachmentContentItem(pdf, "application/pdf", name)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			textFile := filepath.Join(app.WorkingDirectory, "extracted", expected)
+
+			data, err := os.ReadFile(textFile)
+			if err != nil {
+				t.Errorf("%q (%v): %v", name, maxInline, err)
+				continue
+			}
+			if !strings.Contains(string(data), "Quarterly report") {
+				t.Errorf("%q (%v): expected extracted text, got %q", name, maxInline, string(data))
+			}
+
+			os.Remove(textFile)
+		}
+	}
+}
+
+func TestGetResponseSchemaSanitizesName(t *testing.T) {
+	tests := []struct {
+		schemaName   string
+		strict       bool
+		expectedName string
+		isWarned     bool
+	}{
+		{"", false, "GaiResponseSchema", false},
+		{"Commit-Message_1", false, "Commit-Message_1", false},
+		{"My commit: message!", false, "My_commit_message", true},
+		{"My commit: message!", true, "", false},
+		{"!!!", false, "", false},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+		writeTestFile(t, app, "schema.json", `{"type": "object"}`)
+
+		app.JSONSchemaStrictName = test.strict
+		app.SchemaFile = "schema.json"
+		app.SchemaName = test.schemaName
+
+		schema, schemaName, err := app.GetResponseSchema()
+		if test.expectedName == "" {
+			if GetErrorType(err) != ErrorTypeUsage {
+				t.Errorf("%q: expected usage error, got %v", test.schemaName, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", test.schemaName, err)
+			continue
+		}
+
+		if schema == nil || (*schema)["type"] != "object" {
+			t.Errorf("%q: unexpected schema %v", test.schemaName, schema)
+		}
+		if schemaName != test.expectedName {
+			t.Errorf("%q: expected name %q, got %q", test.schemaName, test.expectedName, schemaName)
+		}
+
+		warning := readTestOutput(t, app.Stderr)
+		if strings.HasPrefix(warning, "WARN: schema name") != test.isWarned {
+			t.Errorf("%q: unexpected warning %q", test.schemaName, warning)
+		}
+	}
+}
+
+func TestGetConversationFormat(t *testing.T) {
+	tests := []struct {
+		flag     string
+		env      string
+		expected string
+		isValid  bool
+	}{
+		{"", "", "yaml", true},
+		{"", "JSON", "json", true},
+		{"yml", "json", "yaml", true},
+		{"", "toml", "toml", false},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_CONVERSATION_FORMAT": test.env,
+		})
+		app.ConversationFormat = test.flag
+
+		conversationFormat, err := app.GetConversationFormat()
+		if (err == nil) != test.isValid {
+			t.Errorf("flag %q, env %q: unexpected error %v", test.flag, test.env, err)
+		}
+		if conversationFormat != test.expected {
+			t.Errorf("flag %q, env %q: expected %q, got %q", test.flag, test.env, test.expected, conversationFormat)
+		}
+	}
+}
+
+func TestOutputAIAnswerWithTee(t *testing.T) {
+	const answer = "# Result\n\nThis is a long line, which is wrapped for the terminal only."
+
+	tests := []struct {
+		flag string
+		env  string
+	}{
+		{"answer.md", ""},
+		{"", "answer.md"},
+		{"answer.md", "other.md"},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_TEE_FILE": test.env,
+		})
+		app.TeeFile = test.flag
+		app.Wrap = 20
+
+		app.OutputAIAnswer(answer)
+
+		expected := "# Result\n\nThis is a long line,\nwhich is wrapped for\nthe terminal only."
+		if output := readTestOutput(t, app.Stdout); output != expected {
+			t.Errorf("flag %q, env %q: expected output %q, got %q", test.flag, test.env, expected, output)
+		}
+
+		// the copy is not wrapped
+		data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, "answer.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(data) != answer {
+			t.Errorf("flag %q, env %q: expected copy %q, got %q", test.flag, test.env, answer, data)
+		}
+
+		_, err = os.Stat(filepath.Join(app.WorkingDirectory, "other.md"))
+		if !os.IsNotExist(err) {
+			t.Errorf("flag %q, env %q: expected no other.md", test.flag, test.env)
+		}
+	}
+}
+
+func TestVerbosity(t *testing.T) {
+	tests := []struct {
+		name              string
+		verbosity         int64
+		brief             bool
+		detailed          bool
+		envVars           map[string]string
+		expectedSuffix    string
+		expectedMaxTokens int64
+	}{
+		{"level 0", 0, false, false, nil, "Answer as briefly as possible, in one or two sentences and without explanations.", 256},
+		{"level 1", 1, false, false, nil, "Keep your answer short and focus on the essentials.", 1024},
+		{"level 2", 2, false, false, nil, "", 0},
+		{"level 3", 3, false, false, nil, "Answer thoroughly and in detail, with explanations and examples where useful.", 0},
+		{"brief", -1, true, false, nil, "Keep your answer short and focus on the essentials.", 1024},
+		{"detailed", -1, false, true, nil, "Answer thoroughly and in detail, with explanations and examples where useful.", 0},
+		{"env", -1, false, false, map[string]string{"GAI_VERBOSITY": "0"}, "Answer as briefly as possible, in one or two sentences and without explanations.", 256},
+		{"explicit max tokens", 0, false, false, map[string]string{"GAI_MAX_TOKENS": "50"}, "Answer as briefly as possible, in one or two sentences and without explanations.", 50},
+		{"not defined", -1, false, false, nil, "", 0},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, test.envVars)
+		app.Brief = test.brief
+		app.Detailed = test.detailed
+		app.Verbosity = test.verbosity
+
+		expectedPrompt := "Be helpful."
+		if test.expectedSuffix != "" {
+			expectedPrompt += "\n\n" + test.expectedSuffix
+		}
+		if systemPrompt := app.GetSystemPrompt("Be helpful."); systemPrompt != expectedPrompt {
+			t.Errorf("%v: expected system prompt %q, got %q", test.name, expectedPrompt, systemPrompt)
+		}
+
+		// without any other system prompt
+		if systemPrompt := app.GetSystemPrompt(""); systemPrompt != test.expectedSuffix {
+			t.Errorf("%v: expected system prompt %q, got %q", test.name, test.expectedSuffix, systemPrompt)
+		}
+
+		maxTokens, err := app.GetMaxTokens()
+		if err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+
+		if test.expectedMaxTokens == 0 {
+			if maxTokens != nil {
+				t.Errorf("%v: expected no token limit, got %d", test.name, *maxTokens)
+			}
+		} else if maxTokens == nil || *maxTokens != test.expectedMaxTokens {
+			t.Errorf("%v: expected token limit %d, got %v", test.name, test.expectedMaxTokens, maxTokens)
+		}
+	}
+}
+
+func TestGetVerbosityInvalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		verbosity int64
+		brief     bool
+		detailed  bool
+		envVars   map[string]string
+	}{
+		{"too high", 4, false, false, nil},
+		{"brief and detailed", -1, true, true, nil},
+		{"brief and verbosity", 2, true, false, nil},
+		{"invalid env", -1, false, false, map[string]string{"GAI_VERBOSITY": "high"}},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, test.envVars)
+		app.Brief = test.brief
+		app.Detailed = test.detailed
+		app.Verbosity = test.verbosity
+
+		_, err := app.GetVerbosity()
+		if GetErrorType(err) != ErrorTypeUsage {
+			t.Errorf("%v: expected usage error, got %v", test.name, err)
+		}
+	}
+}
@@ -0,0 +1,166 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+const defaultMaxAttachSize int64 = 25 * 1024 * 1024
+
+// UrlAttachment stores a downloaded remote file.
+type UrlAttachment struct {
+	// Data stores the downloaded data.
+	Data []byte
+	// MimeType stores the detected MIME type of `Data`.
+	MimeType string
+	// Name stores the file name, taken from the URL.
+	Name string
+	// Url stores the URL.
+	Url string
+}
+
+// NewReader creates a new `NamedReader` for the data of the attachment.
+func (a *UrlAttachment) NewReader() *NamedReader {
+	return NewNamedReader(bytes.NewReader(a.Data), a.Name)
+}
+
+// GetMaxAttachSize returns the maximum size in bytes of a file,
+// which is downloaded with `--attach-url`. A value below `0` means no limit.
+func (app *AppContext) GetMaxAttachSize() (int64, error) {
+	GAI_MAX_ATTACH_SIZE := strings.TrimSpace(app.GetEnv("GAI_MAX_ATTACH_SIZE"))
+	if GAI_MAX_ATTACH_SIZE == "" {
+		return defaultMaxAttachSize, nil
+	}
+
+	maxSize, err := strconv.ParseInt(GAI_MAX_ATTACH_SIZE, 10, 64)
+	if err != nil {
+		return 0, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid value for GAI_MAX_ATTACH_SIZE", GAI_MAX_ATTACH_SIZE))
+	}
+
+	return maxSize, nil
+}
+
+// GetUrlAttachments downloads the files of `AttachUrls`.
+func (app *AppContext) GetUrlAttachments() ([]*UrlAttachment, error) {
+	attachments := make([]*UrlAttachment, 0)
+
+	urls := make([]string, 0)
+	for _, u := range app.AttachUrls {
+		if strings.TrimSpace(u) != "" {
+			urls = append(urls, strings.TrimSpace(u))
+		}
+	}
+	urls = utils.RemoveDuplicateStrings(urls)
+
+	if len(urls) == 0 {
+		return attachments, nil
+	}
+
+	maxSize, err := app.GetMaxAttachSize()
+	if err != nil {
+		return attachments, err
+	}
+
+	for _, u := range urls {
+		attachment, err := app.downloadUrlAttachment(u, maxSize)
+		if err != nil {
+			return attachments, err
+		}
+
+		attachments = append(attachments, attachment)
+	}
+
+	return attachments, nil
+}
+
+func (app *AppContext) downloadUrlAttachment(rawUrl string, maxSize int64) (*UrlAttachment, error) {
+	parsedUrl, err := url.Parse(rawUrl)
+	if err != nil || (parsedUrl.Scheme != "http" && parsedUrl.Scheme != "https") {
+		return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid HTTP(S) URL", rawUrl))
+	}
+
+	app.Dbgf("Downloading '%v' ...%v", rawUrl, app.EOL)
+
+	req, err := app.NewHttpRequest("GET", rawUrl, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := app.SendHttpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	err = utils.CheckForHttpResponseError(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	tooLargeError := func() error {
+		return fmt.Errorf("'%v' is larger than %v bytes (change with GAI_MAX_ATTACH_SIZE)", rawUrl, maxSize)
+	}
+
+	var reader io.Reader = resp.Body
+	if maxSize > -1 {
+		if resp.ContentLength > maxSize {
+			return nil, tooLargeError()
+		}
+
+		// one more byte to find out if limit is exceeded
+		reader = io.LimitReader(resp.Body, maxSize+1)
+	}
+
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize > -1 && int64(len(data)) > maxSize {
+		return nil, tooLargeError()
+	}
+
+	name := path.Base(parsedUrl.Path)
+	if name == "" || name == "." || name == "/" {
+		name = parsedUrl.Hostname()
+	}
+
+	mimeType := utils.DetectMime(data)
+
+	app.Dbgf("Downloaded %v bytes of type '%v' from '%v'%v", len(data), mimeType, rawUrl, app.EOL)
+
+	return &UrlAttachment{
+		Data:     data,
+		MimeType: mimeType,
+		Name:     name,
+		Url:      rawUrl,
+	}, nil
+}
@@ -0,0 +1,140 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+// GetBudget returns the maximum costs in dollars of all requests
+// of the current command or `nil` if there is no limit.
+func (app *AppContext) GetBudget() (*float64, error) {
+	budget := app.Budget // first try flag
+	if budget <= 0 {
+		GAI_BUDGET := strings.TrimSpace(app.GetEnv("GAI_BUDGET")) // now try env variable
+		if GAI_BUDGET != "" {
+			value, err := strconv.ParseFloat(GAI_BUDGET, 64)
+			if err != nil || value < 0 {
+				return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("invalid value '%s' for GAI_BUDGET", GAI_BUDGET))
+			}
+
+			budget = value
+		}
+	}
+
+	if budget > 0 {
+		return &budget, nil
+	}
+	return nil, nil
+}
+
+// getBudgetPrices returns the budget and token prices, which are
+// required to calculate costs, or `nil` if there is no budget.
+func (app *AppContext) getBudgetPrices() (*float64, utils.TokenPrices, error) {
+	budget, err := app.GetBudget()
+	if err != nil || budget == nil {
+		return budget, utils.TokenPrices{}, err
+	}
+
+	prices, err := app.GetTokenPrices()
+	if err != nil {
+		return budget, prices, NewTypedError(ErrorTypeUsage, err)
+	}
+	if prices.Input == nil {
+		return budget, prices, NewTypedError(ErrorTypeUsage, errors.New("--budget requires price of input tokens in GAI_PRICE_INPUT"))
+	}
+
+	return budget, prices, nil
+}
+
+// CheckBudget checks if a request with `text` as input can be sent without
+// exceeding the budget together with the costs of all previous requests.
+// The estimate includes the maximum costs of the output, if `--max-tokens`
+// and GAI_PRICE_OUTPUT are defined.
+func (app *AppContext) CheckBudget(text string) error {
+	budget, prices, err := app.getBudgetPrices()
+	if err != nil || budget == nil {
+		return err
+	}
+
+	maxOutputTokens := int64(0)
+	maxTokens, err := app.GetMaxTokens()
+	if err != nil {
+		return err
+	}
+	if maxTokens != nil {
+		maxOutputTokens = *maxTokens
+	}
+
+	estimate := utils.EstimateUsage(
+		utils.NewTextTokenizer(app.AI.ChatModel()),
+		text,
+		maxOutputTokens,
+		prices,
+	)
+
+	costs := *estimate.InputCost
+	if estimate.MaxOutputCost != nil {
+		costs += *estimate.MaxOutputCost
+	}
+
+	app.usageMutex.Lock()
+	spentCosts := app.spentCosts
+	app.usageMutex.Unlock()
+
+	if spentCosts+costs > *budget {
+		return NewTypedError(
+			ErrorTypeBudgetExceeded,
+			fmt.Errorf("estimated costs of %.6f (%.6f spent before) exceed the budget of %.6f", costs, spentCosts, *budget),
+		)
+	}
+
+	return nil
+}
+
+// CheckSpentBudget checks if the costs of all previous requests
+// have already reached the budget.
+func (app *AppContext) CheckSpentBudget() error {
+	budget, _, err := app.getBudgetPrices()
+	if err != nil || budget == nil {
+		return err
+	}
+
+	app.usageMutex.Lock()
+	spentCosts := app.spentCosts
+	app.usageMutex.Unlock()
+
+	if spentCosts >= *budget {
+		return NewTypedError(
+			ErrorTypeBudgetExceeded,
+			fmt.Errorf("spent costs of %.6f reached the budget of %.6f", spentCosts, *budget),
+		)
+	}
+
+	return nil
+}
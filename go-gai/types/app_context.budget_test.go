@@ -0,0 +1,126 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestGetBudget(t *testing.T) {
+	tests := []struct {
+		flag          float64
+		env           string
+		expected      float64
+		expectedError bool
+	}{
+		{0, "", 0, false},
+		{0.05, "1", 0.05, false},
+		{0, " 0.5 ", 0.5, false},
+		{0, "abc", 0, true},
+		{0, "-1", 0, true},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_BUDGET": test.env,
+		})
+		app.Budget = test.flag
+
+		budget, err := app.GetBudget()
+		if test.expectedError {
+			if GetErrorType(err) != ErrorTypeUsage {
+				t.Errorf("%v/%q: expected usage error, got %v", test.flag, test.env, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%v/%q: %v", test.flag, test.env, err)
+		}
+
+		value := 0.0
+		if budget != nil {
+			value = *budget
+		}
+		if value != test.expected {
+			t.Errorf("%v/%q: expected budget %v, got %v", test.flag, test.env, test.expected, value)
+		}
+	}
+}
+
+func TestCheckBudget(t *testing.T) {
+	tests := []struct {
+		envVars      map[string]string
+		expectedType string
+	}{
+		{map[string]string{}, ""},
+		{map[string]string{"GAI_BUDGET": "0.5"}, ErrorTypeUsage},
+		{map[string]string{"GAI_BUDGET": "0.5", "GAI_PRICE_INPUT": "0.001"}, ""},
+		{map[string]string{"GAI_BUDGET": "0.5", "GAI_PRICE_INPUT": "1000"}, ErrorTypeBudgetExceeded},
+	}
+
+	for i, test := range tests {
+		app := newTestApp(t, test.envVars)
+		newTestChatContext(t, app)
+
+		err := app.CheckBudget("Tell me a joke.")
+
+		if test.expectedType == "" && err != nil {
+			t.Errorf("%d: %v", i, err)
+		} else if test.expectedType != "" && GetErrorType(err) != test.expectedType {
+			t.Errorf("%d: expected error of type %v, got %v", i, test.expectedType, err)
+		}
+	}
+}
+
+func TestCheckSpentBudget(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_BUDGET":       "1",
+		"GAI_PRICE_INPUT":  "0.5",
+		"GAI_PRICE_OUTPUT": "1",
+	})
+	newTestChatContext(t, app)
+
+	app.trackUsage(&AIUsage{CompletionTokens: 250, PromptTokens: 1000}, nil)
+	err := app.CheckSpentBudget()
+	if err != nil {
+		t.Fatalf("expected budget left after costs of 0.75, got %v", err)
+	}
+
+	// only requests, which fit into the rest of 0.25, are allowed ...
+	err = app.CheckBudget("Hi")
+	if err != nil {
+		t.Errorf("expected cheap request to be allowed, got %v", err)
+	}
+	err = app.CheckBudget(strings.Repeat("word ", 1000))
+	if GetErrorType(err) != ErrorTypeBudgetExceeded {
+		t.Errorf("expected budget error for costs over 0.25, got %v", err)
+	}
+
+	// ... and nothing is left after the next one
+	app.trackUsage(&AIUsage{CompletionTokens: 250}, nil)
+	err = app.CheckSpentBudget()
+	if GetErrorType(err) != ErrorTypeBudgetExceeded {
+		t.Errorf("expected budget error after costs of 1, got %v", err)
+	}
+}
@@ -38,7 +38,8 @@ func (app *AppContext) NewChatContext(opts ...NewChatContextOptions) (*ChatConte
 	}
 
 	chat := &ChatContext{
-		App: app,
+		App:          app,
+		startedEmpty: startEmpty,
 	}
 
 	chat.SwitchContext(app.Context)
@@ -22,7 +22,13 @@
 
 package types
 
-import "github.com/spf13/cobra"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+	"github.com/spf13/cobra"
+)
 
 // GetFileFlags returns the values of `--file` and “
 func (app *AppContext) GetFileFlags() ([]string, []string) {
@@ -39,11 +45,45 @@ func (app *AppContext) GetFileFlags() ([]string, []string) {
 	return file, files
 }
 
+// GetExcludePatterns returns the non-empty and unique patterns
+// of `--exclude` flags or the defaults of the RC file.
+func (app *AppContext) GetExcludePatterns() []string {
+	exclude := app.ExcludePatterns
+	if len(exclude) == 0 {
+		exclude = append(exclude, app.RCFile.Defaults.Flags.Exclude...)
+	}
+
+	patterns := make([]string, 0)
+	for _, p := range exclude {
+		if strings.TrimSpace(p) != "" {
+			patterns = append(patterns, p)
+		}
+	}
+
+	return utils.RemoveDuplicateStrings(patterns)
+}
+
+// WithAttachUrlCLIFlags sets up `cmd` for remote attachment based CLI flags.
+func (app *AppContext) WithAttachUrlCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&app.AttachUrls, "attach-url", "", []string{}, "one or more URLs of remote files to download and attach")
+}
+
+// WithBudgetCLIFlags sets up `cmd` for budget based CLI flags.
+func (app *AppContext) WithBudgetCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64VarP(&app.Budget, "budget", "", 0, "maximum costs in dollars of all requests, based on GAI_PRICE_INPUT and GAI_PRICE_OUTPUT (0 for no limit)")
+}
+
 // WithChatCLIFlags sets up `cmd` for chat based CLI flags.
 func (app *AppContext) WithChatCLIFlags(cmd *cobra.Command) {
 	app.WithPromptCLIFlags(cmd)
 }
 
+// WithCurlCLIFlags sets up `cmd` for `curl` based CLI flags.
+func (app *AppContext) WithCurlCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&app.DumpRequestCurl, "dump-request-curl", "", false, "output request as curl command instead of sending it")
+	cmd.Flags().BoolVarP(&app.UnsafeShowKey, "unsafe-show-key", "", false, "do not mask API keys in curl command")
+}
+
 // WithDryRunCliFlags sets up `cmd` for dry run based CLI flags.
 func (app *AppContext) WithDryRunCliFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&app.DryRun, "dry-run", "", false, "do a dry run")
@@ -63,6 +103,15 @@ func (app *AppContext) WithEditorCLIFlags(cmd *cobra.Command) {
 // WithHighlightCLIFlags sets up `cmd` for highlight based CLI flags.
 func (app *AppContext) WithHighlightCLIFlags(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&app.NoHighlight, "no-highlight", "", false, "do not highlight output")
+	cmd.Flags().BoolVarP(&app.Raw, "raw", "", false, "output answers verbatim without wrapping and highlighting")
+	cmd.Flags().IntVarP(&app.Wrap, "wrap", "", 0, "wrap lines of answers, which are longer than this number of columns, before highlighting (0 for no wrapping)")
+}
+
+// WithHistoryCLIFlags sets up `cmd` for conversation history based CLI flags.
+func (app *AppContext) WithHistoryCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().Int64VarP(&app.HistoryLimit, "history-limit", "", -1, "maximum number of previous turns to send (0 for no limit)")
+	cmd.Flags().BoolVarP(&app.HistorySummary, "history-summary", "", false, "send older turns beyond --history-limit or --max-history-tokens as rolling summary")
+	cmd.Flags().Int64VarP(&app.MaxHistoryTokens, "max-history-tokens", "", -1, "maximum number of tokens of previous turns to send (0 for no limit)")
 }
 
 // WithLanguageCLIFlags sets up `cmd` for language based CLI flags.
@@ -70,17 +119,58 @@ func (app *AppContext) WithLanguageCLIFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&app.OutputLanguage, "language", "", "", "custom output language")
 }
 
+// WithMermaidCLIFlags sets up `cmd` for Mermaid diagram based CLI flags.
+func (app *AppContext) WithMermaidCLIFlags(cmd *cobra.Command, renderDir *string) {
+	cmd.Flags().StringVarP(renderDir, "render-mermaid", "", "", "render Mermaid diagrams of the answer into this directory")
+	cmd.Flags().StringVarP(&app.MermaidFormat, "render-mermaid-format", "", "", "format of rendered Mermaid diagrams: svg (default) or png")
+}
+
+// WithOutputDirCLIFlags sets up `cmd` for CLI flags of output files per processed file.
+func (app *AppContext) WithOutputDirCLIFlags(cmd *cobra.Command, defaultTemplate string) {
+	cmd.Flags().StringVarP(&app.OutputDir, "output-dir", "", "", "write the output of each file into its own file inside this directory")
+	cmd.Flags().StringVarP(&app.OutputTemplate, "output-template", "", "", fmt.Sprintf("template for the names of the files of --output-dir with {{.Name}}, {{.Ext}}, {{.Filename}}, {{.Dir}} and {{.Index}} (default: %s)", defaultTemplate))
+}
+
 // WithPromptCLIFlags sets up `cmd` for prompt based CLI flags.
 func (app *AppContext) WithPromptCLIFlags(cmd *cobra.Command) {
+	app.WithCurlCLIFlags(cmd)
 	app.WithEditorCLIFlags(cmd)
 	app.WithHighlightCLIFlags(cmd)
 	app.WithSchemaCLIFlags(cmd)
 }
 
+// WithPseudoConversationCLIFlags sets up `cmd` for pseudo conversation based CLI flags.
+func (app *AppContext) WithPseudoConversationCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().StringArrayVarP(&app.PseudoAnswers, "pseudo-answer", "", []string{}, "one or more answers of the assistant in pseudo conversations, which are used in turn")
+	cmd.Flags().StringVarP(&app.PseudoMode, "pseudo-mode", "", "", "how to submit files: turns (with assistant answers) or single (one long user message)")
+}
+
 // WithSchemaCLIFlags sets up `cmd` for (response) format based CLI flags.
 func (app *AppContext) WithSchemaCLIFlags(cmd *cobra.Command) {
 	cmd.Flags().StringVarP(&app.SchemaFile, "schema", "", "", "file with response format/schema")
-	cmd.Flags().StringVarP(&app.SchemaName, "schema-name", "", "", "name of the response format/schema")
+	cmd.Flags().StringVarP(&app.SchemaName, "schema-name", "", "", "name of the response format/schema or of a preset in the schemas directory of the app, if --schema is not defined")
+	cmd.Flags().BoolVarP(&app.JSONSchemaStrictName, "json-schema-strict-name", "", false, "fail instead of sanitizing invalid schema names")
+	app.WithValidationCLIFlags(cmd)
+}
+
+// WithStdinBinaryCLIFlags sets up `cmd` for binary STDIN based CLI flags.
+func (app *AppContext) WithStdinBinaryCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&app.StdinBinary, "stdin-binary", "", false, "read STDIN as binary data, like an image, and attach it instead of using it as input")
+}
+
+// WithTeeCLIFlags sets up `cmd` for tee based CLI flags.
+func (app *AppContext) WithTeeCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&app.TeeFile, "tee", "", "", "print output and write a plain copy to this file")
+}
+
+// WithToolsCLIFlags sets up `cmd` for tool calling based CLI flags.
+func (app *AppContext) WithToolsCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSliceVarP(&app.AllowTools, "allow-tools", "", []string{}, fmt.Sprintf("one or more built-in tools, the AI is allowed to call: %s", strings.Join(GetBuiltInToolNames(), ", ")))
+}
+
+// WithValidationCLIFlags sets up `cmd` for response validation based CLI flags.
+func (app *AppContext) WithValidationCLIFlags(cmd *cobra.Command) {
+	cmd.Flags().BoolVarP(&app.NoValidate, "no-validate", "", false, "do not validate answers against the response schema")
 }
 
 // WithYesCliFlags sets up `cmd` for "yes" based CLI flags.
@@ -0,0 +1,62 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// completionHttpTimeout is the HTTP timeout, which is used while
+// completing values in a shell, if no other one is defined.
+const completionHttpTimeout = "5s"
+
+// CompleteModelNames returns the names of the models of all providers
+// in `provider:model` format, which start with `toComplete`.
+// Lists of remote providers are taken from the models cache, if possible.
+func (app *AppContext) CompleteModelNames(toComplete string) ([]string, cobra.ShellCompDirective) {
+	// PersistentPreRun is not executed while completing
+	app.initHomeDir()
+	app.initWorkingDirectory()
+	app.loadEnvFilesIfExist()
+	app.loadRCFile()
+
+	if strings.TrimSpace(app.HttpTimeout) == "" && strings.TrimSpace(app.GetEnv("GAI_HTTP_TIMEOUT")) == "" {
+		// do not let the shell hang
+		app.HttpTimeout = completionHttpTimeout
+	}
+
+	names := make([]string, 0)
+	for _, m := range app.GetAllModels() {
+		name := m.String()
+		if strings.HasPrefix(name, toComplete) {
+			names = append(names, name)
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, cobra.ShellCompDirectiveNoFileComp
+}
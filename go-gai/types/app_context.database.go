@@ -24,12 +24,18 @@ package types
 
 import (
 	"database/sql"
+	"errors"
+	"fmt"
 	"path/filepath"
 	"strings"
+	"time"
 
-	_ "github.com/mattn/go-sqlite3"
+	"github.com/mattn/go-sqlite3"
 )
 
+const sqliteBusyTimeout = 5000
+const sqliteMaxWriteRetries = 5
+
 // OpenSQLDatabase opens an SQL based database.
 func (app *AppContext) OpenSQLDatabase() (*sql.DB, error) {
 	databaseFile := strings.TrimSpace(app.Database) // first try flags
@@ -46,5 +52,86 @@ func (app *AppContext) OpenSQLDatabase() (*sql.DB, error) {
 		databaseFile = filepath.Join(app.WorkingDirectory, databaseFile)
 	}
 
-	return sql.Open("sqlite3", databaseFile)
+	// WAL and busy timeout are set for each new connection
+	dsn := fmt.Sprintf("file:%s?_busy_timeout=%d&_journal_mode=WAL", databaseFile, sqliteBusyTimeout)
+
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		return db, err
+	}
+
+	// SQLite allows only one writer at a time
+	db.SetMaxOpenConns(1)
+
+	return db, nil
+}
+
+// EnsureSQLColumn adds the column `column` with the type definition `definition`
+// to the table `table` in `db`, if it does not exist yet.
+func (app *AppContext) EnsureSQLColumn(db *sql.DB, table string, column string, definition string) error {
+	rows, err := db.Query(fmt.Sprintf("PRAGMA table_info(%s);", table))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var cid int
+		var name string
+		var columnType string
+		var notNull int
+		var defaultValue any
+		var pk int
+
+		err := rows.Scan(&cid, &name, &columnType, &notNull, &defaultValue, &pk)
+		if err != nil {
+			return err
+		}
+
+		if strings.EqualFold(name, column) {
+			return nil // already exists
+		}
+	}
+	err = rows.Err()
+	if err != nil {
+		return err
+	}
+	rows.Close()
+
+	app.Dbgf("Adding column '%v' to table '%v' ...%v", column, table, app.EOL)
+
+	_, err = app.ExecSQLWithRetry(db, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s;", table, column, definition))
+	return err
+}
+
+// ExecSQLWithRetry executes a write statement in `db` and retries it,
+// if SQLite reports that the database is busy or locked.
+func (app *AppContext) ExecSQLWithRetry(db *sql.DB, query string, args ...any) (sql.Result, error) {
+	var result sql.Result
+	var err error
+
+	for i := 0; i <= sqliteMaxWriteRetries; i++ {
+		if i > 0 {
+			wait := time.Duration(i*100) * time.Millisecond
+
+			app.Dbgf("Database is busy, retrying in %v ...%v", wait, app.EOL)
+			time.Sleep(wait)
+		}
+
+		result, err = db.Exec(query, args...)
+		if !isSQLiteBusyError(err) {
+			break
+		}
+	}
+
+	return result, err
+}
+
+func isSQLiteBusyError(err error) bool {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) {
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	}
+
+	return false
 }
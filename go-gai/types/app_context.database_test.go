@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+)
+
+func openTestDatabase(t *testing.T, app *AppContext) *sql.DB {
+	t.Helper()
+
+	db, err := app.OpenSQLDatabase()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if db == nil {
+		t.Fatal("no database opened")
+	}
+	t.Cleanup(func() {
+		db.Close()
+	})
+
+	return db
+}
+
+func TestOpenSQLDatabase(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_DATABASE": "gai.sqlite",
+	})
+
+	db := openTestDatabase(t, app)
+
+	var journalMode string
+	err := db.QueryRow("PRAGMA journal_mode;").Scan(&journalMode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if journalMode != "wal" {
+		t.Errorf("expected journal mode 'wal', got %q", journalMode)
+	}
+
+	var busyTimeout int
+	err = db.QueryRow("PRAGMA busy_timeout;").Scan(&busyTimeout)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if busyTimeout != sqliteBusyTimeout {
+		t.Errorf("expected busy timeout %d, got %d", sqliteBusyTimeout, busyTimeout)
+	}
+
+	if db.Stats().MaxOpenConnections != 1 {
+		t.Errorf("expected 1 max open connection, got %d", db.Stats().MaxOpenConnections)
+	}
+}
+
+func TestOpenSQLDatabaseWithoutFile(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	db, err := app.OpenSQLDatabase()
+	if err != nil || db != nil {
+		t.Errorf("expected no database and no error, got %v, %v", db, err)
+	}
+}
+
+func TestExecSQLWithRetryConcurrentWriters(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.Database = "gai.sqlite"
+
+	setupDb := openTestDatabase(t, app)
+	_, err := app.ExecSQLWithRetry(setupDb, "CREATE TABLE images (file TEXT, writer INTEGER);")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	const writers = 8
+	const rowsPerWriter = 25
+
+	// each writer has its own connection to the same file,
+	// like different processes
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*rowsPerWriter)
+	for w := 0; w < writers; w++ {
+		db := openTestDatabase(t, app)
+
+		wg.Add(1)
+		go func(writer int) {
+			defer wg.Done()
+
+			for i := 0; i < rowsPerWriter; i++ {
+				_, err := app.ExecSQLWithRetry(db, "INSERT INTO images (file, writer) VALUES (?, ?);", fmt.Sprintf("%d.png", i), writer)
+				if err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Error(err)
+	}
+
+	var count int
+	err = setupDb.QueryRow("SELECT COUNT(*) FROM images;").Scan(&count)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != writers*rowsPerWriter {
+		t.Errorf("expected %d rows, got %d", writers*rowsPerWriter, count)
+	}
+}
+
+func TestEnsureSQLColumn(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.Database = "gai.sqlite"
+
+	db := openTestDatabase(t, app)
+	_, err := db.Exec("CREATE TABLE images (file TEXT);")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// second call must not fail
+	for i := 0; i < 2; i++ {
+		err := app.EnsureSQLColumn(db, "images", "hash", "TEXT")
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	_, err = db.Exec("INSERT INTO images (file, hash) VALUES ('a.png', 'abc');")
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestIsSQLiteBusyError(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected bool
+	}{
+		{nil, false},
+		{errors.New("database is locked"), false},
+		{sqlite3.Error{Code: sqlite3.ErrBusy}, true},
+		{fmt.Errorf("insert: %w", sqlite3.Error{Code: sqlite3.ErrLocked}), true},
+		{sqlite3.Error{Code: sqlite3.ErrConstraint}, false},
+	}
+
+	for _, test := range tests {
+		if actual := isSQLiteBusyError(test.err); actual != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.err, test.expected, actual)
+		}
+	}
+}
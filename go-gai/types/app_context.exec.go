@@ -23,11 +23,94 @@
 package types
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"os/exec"
 	"runtime"
 	"strings"
 )
 
+// ShellCommandResult stores the result of `RunShellCommand`.
+type ShellCommandResult struct {
+	// ExitCode stores the exit code of the command.
+	ExitCode int
+	// Output stores the combined output of STDOUT and STDERR.
+	Output string
+	// Truncated is `true` if output has been cut.
+	Truncated bool
+}
+
+// cappedBuffer stores only the first `max` bytes written to it.
+type cappedBuffer struct {
+	buffer    bytes.Buffer
+	max       int
+	truncated bool
+}
+
+func (b *cappedBuffer) Write(p []byte) (int, error) {
+	left := b.max - b.buffer.Len()
+	if b.max >= 0 && len(p) > left {
+		b.truncated = true
+		b.buffer.Write(p[:max(left, 0)])
+	} else {
+		b.buffer.Write(p)
+	}
+
+	return len(p), nil // do not stop the command
+}
+
+// CreateExecCommand creates a new command, which runs in the working directory of the app.
+func (app *AppContext) CreateExecCommand(f string, args ...string) *exec.Cmd {
+	ctx := app.RequestContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	cmd := exec.CommandContext(ctx, f, args...)
+	cmd.Dir = app.WorkingDirectory
+
+	return cmd
+}
+
+// RunShellCommand runs `command` with the shell of the system and captures the first
+// `maxBytes` bytes (-1 for no limit) of its STDOUT and STDERR.
+// A non-zero exit code is no error and returned as part of the result.
+func (app *AppContext) RunShellCommand(command string, maxBytes int) (ShellCommandResult, error) {
+	result := ShellCommandResult{}
+
+	var cmd *exec.Cmd
+	if runtime.GOOS == "windows" {
+		cmd = app.CreateExecCommand("cmd", "/C", command)
+	} else {
+		cmd = app.CreateExecCommand("sh", "-c", command)
+	}
+
+	output := &cappedBuffer{
+		max: maxBytes,
+	}
+	cmd.Stdout = output
+	cmd.Stderr = output
+
+	app.Dbgf("Running '%v' ...%v", command, app.EOL)
+
+	err := cmd.Run()
+
+	result.Output = output.buffer.String()
+	result.Truncated = output.truncated
+
+	if err != nil {
+		var exitErr *exec.ExitError
+		if !errors.As(err, &exitErr) {
+			return result, err
+		}
+
+		result.ExitCode = exitErr.ExitCode()
+	}
+
+	return result, nil
+}
+
 // TryGetBestOpenEditorCommand tries to find and return the best command to open a file for editing with the given file path.
 // It returns the command and its arguments as a slice of strings.
 // If no suitable editor is found, it returns an empty string and an empty slice.
@@ -0,0 +1,68 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestRunShellCommand(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("commands require a POSIX shell")
+	}
+
+	tests := []struct {
+		command           string
+		maxBytes          int
+		expectedExitCode  int
+		expectedOutput    string
+		expectedTruncated bool
+	}{
+		{"echo out; echo err >&2", -1, 0, "out\nerr\n", false},
+		{"pwd | grep -q work && echo inside", -1, 0, "inside\n", false},
+		{"printf 'FAIL: test'; exit 3", -1, 3, "FAIL: test", false},
+		{"printf 0123456789", 4, 0, "0123", true},
+		{"printf 0123", 4, 0, "0123", false},
+		{"printf 0123", 0, 0, "", true},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+
+		result, err := app.RunShellCommand(test.command, test.maxBytes)
+		if err != nil {
+			t.Fatalf("%v: %v", test.command, err)
+		}
+
+		if result.ExitCode != test.expectedExitCode {
+			t.Errorf("%v: expected exit code %d, got %d", test.command, test.expectedExitCode, result.ExitCode)
+		}
+		if result.Output != test.expectedOutput {
+			t.Errorf("%v: expected output %q, got %q", test.command, test.expectedOutput, result.Output)
+		}
+		if result.Truncated != test.expectedTruncated {
+			t.Errorf("%v: expected truncated %v, got %v", test.command, test.expectedTruncated, result.Truncated)
+		}
+	}
+}
@@ -36,7 +36,7 @@ func (app *AppContext) NewGitClient() (*GitClient, error) {
 	dir := filepath.Dir(gitDir)
 
 	return &GitClient{
-		app: *app,
+		app: app,
 		dir: dir,
 	}, nil
 }
@@ -23,14 +23,21 @@
 package types
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/fs"
 	"log"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"regexp"
 	"slices"
 	"sort"
 	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/mkloubert/gai/utils"
@@ -42,20 +49,38 @@ import (
 type AppContext struct {
 	// AI is the default AI client.
 	AI AIClient
+	// AllowTools stores the names of the built-in tools, the AI is allowed to call.
+	AllowTools []string
 	// AlwaysYes is `true` if command should answer any user interactions with "yes".
 	AlwaysYes bool
 	// ApiKey stores a global API key.
 	ApiKey string
+	// AttachmentMaxInline stores the maximum size in bytes of attachments, which can be inlined as data URI.
+	AttachmentMaxInline int64
+	// AttachUrls stores one or more URLs of remote files to attach.
+	AttachUrls []string
 	// BaseUrl stores base URL.
 	BaseUrl string
+	// Brief is `true` if answers should be short.
+	Brief bool
+	// Budget stores the maximum costs in dollars of the requests of a command.
+	Budget float64
 	// CommandPath stores full path of current command.
 	CommandPath []string
 	// Context stores the name of the current context.
 	Context string
+	// ConversationFormat stores the format of the conversation file, like `yaml` or `json`.
+	ConversationFormat string
 	// Database stores the path or URI to the database, usually a SQLite database.
 	Database string
+	// Dedent is `true` if common indentation and repeating empty lines should be removed from inputs.
+	Dedent bool
+	// Detailed is `true` if answers should be thorough.
+	Detailed bool
 	// DryRun is `true` if command should be run in "dry run mode".
 	DryRun bool
+	// DumpRequestCurl is `true` if AI requests should be written as `curl` commands instead of sending them.
+	DumpRequestCurl bool
 	// Editor stores the command for the custom editor to use.
 	Editor string
 	// EnvFiles stores list of additional .env files that should be loaded in this direction.
@@ -64,46 +89,106 @@ type AppContext struct {
 	EnvVars map[string]string
 	// EnvFiles stores string representing new line.
 	EOL string
+	// ErrorFormat stores the format of error output, like `text` or `json`.
+	ErrorFormat string
+	// ExcludePatterns stores list of glob patterns of files, which should be excluded from `FilePatterns`.
+	ExcludePatterns []string
 	// FilePatterns stores list of additional files as glob patterns to use for the current operation.
 	FilePatterns []string
 	// Files stores list of additional files to use for the current operation.
 	Files []string
+	// FrequencyPenalty stores the custom frequency penalty, if defined.
+	FrequencyPenalty *float64
+	// HistoryLimit stores the maximum number of previous turns to send with a chat request.
+	HistoryLimit int64
+	// HistorySummary is `true` if turns beyond `HistoryLimit` should be sent as rolling summary.
+	HistorySummary bool
 	// HomeDirectory is the absolute path to the user's home directory.
 	HomeDirectory string
+	// HttpTimeout stores the timeout for HTTP requests, like `90s` or `300`.
+	HttpTimeout string
+	// JSONOutput is `true` if answers should be output as raw and valid JSON.
+	JSONOutput bool
+	// JSONSchemaStrictName is `true` if invalid schema names should not be sanitized but rejected.
+	JSONSchemaStrictName bool
 	// Log is the logger the app should use.
 	Log *log.Logger
+	// MaxHistoryTokens stores the maximum number of tokens of the previous turns to send with a chat request.
+	MaxHistoryTokens int64
+	// MaxRequestBytes stores the maximum size of the body of a request in bytes.
+	MaxRequestBytes int64
 	// MaxTokens stores the maximum number of tokens.
 	MaxTokens int64
+	// MermaidFormat stores the output format of rendered Mermaid diagrams, like `svg` or `png`.
+	MermaidFormat string
 	// Model is the default chat model to use.
 	Model string
+	// NoColor is `true` if output should NOT contain any ANSI colors.
+	NoColor bool
+	// NoGitignore is `true` if `.gitignore` of the repository should not be honored when expanding `FilePatterns`.
+	NoGitignore bool
 	// NoHighlight is `true` if output should NOT be highlighted and formatted.
 	NoHighlight bool
+	// NoValidate is `true` if AI answers should not be validated against the response schema.
+	NoValidate bool
+	// OpenAIApi stores the name of the OpenAI API to use, like `chat` or `responses`.
+	OpenAIApi string
 	// OpenEditor is `true` if editor should be opened.
 	OpenEditor bool
+	// OutputDir stores the directory, where the output of each processed file should be written to.
+	OutputDir string
 	// OutputFile stores where to store the ouput of the app to.
 	OutputFile string
 	// OutputLanguage stores the output language.
 	OutputLanguage string
+	// OutputTemplate stores the template for the names of the files in `OutputDir`, like `{{.Name}}.json`.
+	OutputTemplate string
+	// PresencePenalty stores the custom presence penalty, if defined.
+	PresencePenalty *float64
+	// PseudoAnswers stores custom answers of the assistant in pseudo conversations.
+	PseudoAnswers []string
+	// PseudoMode stores how pseudo conversations are built, like `turns` or `single`.
+	PseudoMode string
+	// Raw is `true` if answers should be written verbatim, without wrapping and highlighting.
+	Raw bool
 	// RCFile stores current `.gairc` file.
 	RCFile *GAIRCFile
+	// Redact is `true` if secrets should be removed from files and STDIN before sending them.
+	Redact bool
+	// RefreshModels is `true` if lists of models should be loaded from the providers instead of the cache.
+	RefreshModels bool
+	// RequestContext stores the context for requests, which is cancelled on SIGINT.
+	RequestContext context.Context
+	// RetryBudget stores the maximum time for retries of HTTP requests, like `30s` or `2m`.
+	RetryBudget string
 	// RootCommand stores the root command.
 	RootCommand *cobra.Command
+	// SaveAttachmentsDir stores the directory, where the extracted text of attachments is written to.
+	SaveAttachmentsDir string
 	// SchemaFile stores the path to the file with the response format/schema.
 	SchemaFile string
 	// SchemaFile stores the name of the response format/schema.
 	SchemaName string
+	// ShowCost is `true` if the accumulated token usage and costs of all requests should be written to STDERR at the end.
+	ShowCost bool
 	// SkipDefaultEnvFiles indicates not to use default .env files, if `true`.
 	SkipDefaultEnvFiles bool
 	// Stderr stores the stream for error outputs.
 	Stderr *os.File
 	// Stdin stores the stream for default inputs.
 	Stdin *os.File
+	// StdinBinary is `true` if STDIN should be read as raw bytes and used as attachment instead of input.
+	StdinBinary bool
 	// Stdout stores the stream for default outputs.
 	Stdout *os.File
 	// SystemPrompt stores the custom system prompt for AI operations.
 	SystemPrompt string
+	// SystemPromptFile stores the path to a file with a custom system prompt for AI operations.
+	SystemPromptFile string
 	// SystemRole custom name of the system role.
 	SystemRole string
+	// TeeFile stores the path of the file where to write a plain copy of the AI answer to.
+	TeeFile string
 	// TempDirectory stores the path of the custom temp directory.
 	TempDirectory string
 	// Temperature stores the temperature for AI operations.
@@ -112,17 +197,64 @@ type AppContext struct {
 	TerminalFormatter string
 	// TerminalFormatter defines the custom terminal style.
 	TerminalStyle string
+	// TopP stores the custom nucleus sampling value, if defined.
+	TopP *float64
+	// UnsafeShowKey is `true` if API keys should not be masked in outputs.
+	UnsafeShowKey bool
 	// Verbose indicates if application should also output debug messages.
 	Verbose bool
+	// VerboseTiming is `true` if the durations of the phases of a command should be written to STDERR.
+	VerboseTiming bool
+	// Verbosity stores the level of detail of answers, from `0` (very brief) to `3` (detailed).
+	Verbosity int64
 	// WorkingDirectory stores the current root directory.
 	WorkingDirectory string
+	// Wrap stores the number of columns, after which long lines of answers are wrapped, if greater than `0`.
+	Wrap int
+
+	redactPatterns      []*regexp.Regexp
+	redactPatternsErr   error
+	redactPatternsOnce  sync.Once
+	sessionCostsPrinted sync.Once
+	sessionRequests     int
+	sessionUsage        AIUsage
+	sessionUsageUnknown bool
+	spentCosts          float64
+	timings             map[string]time.Duration
+	timingsMutex        sync.Mutex
+	usageMutex          sync.Mutex
 }
 
 // CheckIfError checks if `err` is not `nil` and exists in this case.
 func (app *AppContext) CheckIfError(err error) {
+	if errors.Is(err, ErrRequestDumped) {
+		// request has been written to STDOUT as intended
+		os.Exit(0)
+	}
+
 	if err != nil {
+		exitCode := GetExitCode(err)
+
+		// costs of failed commands are also of interest
+		app.OutputSessionCosts()
+
+		if app.GetErrorFormat() == "json" {
+			errorObj := map[string]any{
+				"error": map[string]any{
+					"message": err.Error(),
+					"type":    GetErrorType(err),
+				},
+			}
+
+			data, err2 := json.Marshal(&errorObj)
+			if err2 == nil {
+				app.WriteErrorString(fmt.Sprintf("%s%s", data, app.EOL))
+				os.Exit(exitCode)
+			}
+		}
+
 		app.WriteErrorString(fmt.Sprintf("%s%s", err.Error(), app.EOL))
-		os.Exit(1)
+		os.Exit(exitCode)
 	}
 }
 
@@ -138,6 +270,19 @@ func (app *AppContext) Dbgf(format string, v ...any) {
 	}
 }
 
+// GetErrorFormat returns the format of error output, which is `text` (default) or `json`.
+func (app *AppContext) GetErrorFormat() string {
+	errorFormat := strings.TrimSpace(strings.ToLower(app.ErrorFormat)) // first try flag
+	if errorFormat == "" {
+		errorFormat = strings.TrimSpace(strings.ToLower(app.GetEnv("GAI_ERROR_FORMAT"))) // now try env variable
+	}
+
+	if errorFormat == "json" {
+		return "json"
+	}
+	return "text"
+}
+
 // EnsureAppDir ensures that the root directory for this app inside
 // `HomeDirectory` exists and returns its path.
 func (app *AppContext) EnsureAppDir() (string, error) {
@@ -173,27 +318,14 @@ func (app *AppContext) GetCurrentContext() string {
 	return strings.TrimSpace(app.GetEnv("GAI_CONTEXT"))
 }
 
-// GetFiles() returns the cleaned up and unsorted list of files as
-// full paths from `Files`.
-func (app *AppContext) GetFiles() ([]string, error) {
-	fileFlag, filesFlag := app.GetFileFlags()
-
+// FindFiles returns the full paths of the files inside the working directory,
+// which match the gitignore-like `patterns` and not `--exclude`.
+// Files ignored by git are skipped, if not disabled by `--no-gitignore`.
+func (app *AppContext) FindFiles(patterns ...string) ([]string, error) {
 	files := make([]string, 0)
 
-	// first check for explicit file pathes
-	for _, f := range fileFlag {
-		file := f
-		if !filepath.IsAbs(file) {
-			file = filepath.Join(app.WorkingDirectory, file)
-		}
-
-		files = append(files, file)
-	}
-
-	// now the ones with patterns ...
-
 	globPatterns := make([]string, 0)
-	for _, fp := range filesFlag {
+	for _, fp := range patterns {
 		if strings.TrimSpace(fp) != "" {
 			globPatterns = append(globPatterns, fp)
 		}
@@ -202,19 +334,32 @@ func (app *AppContext) GetFiles() ([]string, error) {
 
 	if len(globPatterns) > 0 {
 		gitignore := ignore.CompileIgnoreLines(globPatterns...)
+		excludes := ignore.CompileIgnoreLines(app.GetExcludePatterns()...)
 
-		err := filepath.WalkDir(app.WorkingDirectory, func(path string, d fs.DirEntry, err error) error {
+		isGitIgnored, err := app.newGitIgnorePredicate()
+		if err != nil {
+			return files, err
+		}
+
+		err = filepath.WalkDir(app.WorkingDirectory, func(path string, d fs.DirEntry, err error) error {
 			if err != nil {
 				return err
 			}
 
+			if isGitIgnored(path, d.IsDir()) {
+				if d.IsDir() {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+
 			if !d.IsDir() {
 				relPath, err := filepath.Rel(app.WorkingDirectory, path)
 				if err != nil {
 					return err
 				}
 
-				if gitignore.MatchesPath(relPath) {
+				if gitignore.MatchesPath(relPath) && !excludes.MatchesPath(relPath) {
 					files = append(files, path)
 				}
 			}
@@ -227,6 +372,33 @@ func (app *AppContext) GetFiles() ([]string, error) {
 		}
 	}
 
+	return files, nil
+}
+
+// GetFiles() returns the cleaned up and unsorted list of files as
+// full paths from `Files`.
+func (app *AppContext) GetFiles() ([]string, error) {
+	fileFlag, filesFlag := app.GetFileFlags()
+
+	files := make([]string, 0)
+
+	// first check for explicit file pathes
+	for _, f := range fileFlag {
+		file := f
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(app.WorkingDirectory, file)
+		}
+
+		files = append(files, file)
+	}
+
+	// now the ones with patterns ...
+	filesOfPatterns, err := app.FindFiles(filesFlag...)
+	if err != nil {
+		return files, err
+	}
+	files = append(files, filesOfPatterns...)
+
 	// remove duplicates ...
 	files = utils.RemoveDuplicateStrings(files)
 
@@ -265,6 +437,49 @@ func (app *AppContext) GetNow() time.Time {
 	return time.Now().UTC()
 }
 
+// newGitIgnorePredicate returns a function, which checks if a path
+// should be skipped because of the `.gitignore` file of the repository.
+func (app *AppContext) newGitIgnorePredicate() (func(p string, isDir bool) bool, error) {
+	if app.NoGitignore {
+		return func(p string, isDir bool) bool {
+			return false
+		}, nil
+	}
+
+	git, err := app.NewGitClient()
+	if err != nil {
+		// no repository
+		return func(p string, isDir bool) bool {
+			return false
+		}, nil
+	}
+
+	gitignore, err := git.GetGitIgnore()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(p string, isDir bool) bool {
+		if isDir && filepath.Base(p) == ".git" {
+			return true
+		}
+		if gitignore == nil {
+			return false
+		}
+
+		relPath, err := filepath.Rel(git.dir, p)
+		if err != nil || relPath == "." {
+			return false
+		}
+
+		relPath = filepath.ToSlash(relPath)
+		if isDir {
+			return gitignore.MatchesPath(relPath) || gitignore.MatchesPath(relPath+"/")
+		}
+		return gitignore.MatchesPath(relPath)
+	}, nil
+}
+
 // NewFilePredicate creates a new function that checks if a file path matches a specific pattern.
 func (app *AppContext) NewFilePredicate() func(f string) (bool, error) {
 	fileFlag, filesFlag := app.GetFileFlags()
@@ -289,7 +504,9 @@ func (app *AppContext) NewFilePredicate() func(f string) (bool, error) {
 	}
 	globPatterns = utils.RemoveDuplicateStrings(globPatterns)
 
-	if len(explicitFiles) == 0 && len(globPatterns) == 0 {
+	excludePatterns := app.GetExcludePatterns()
+
+	if len(explicitFiles) == 0 && len(globPatterns) == 0 && len(excludePatterns) == 0 {
 		// nothing defined => allow all
 
 		return func(f string) (bool, error) {
@@ -298,22 +515,30 @@ func (app *AppContext) NewFilePredicate() func(f string) (bool, error) {
 	}
 
 	gitignore := ignore.CompileIgnoreLines(globPatterns...)
+	excludes := ignore.CompileIgnoreLines(excludePatterns...)
 
 	return func(f string) (bool, error) {
 		absPath := f
 		if !filepath.IsAbs(absPath) {
-			absPath = filepath.Join(app.WorkingDirectory)
+			absPath = filepath.Join(app.WorkingDirectory, absPath)
 		}
 
 		if slices.Contains(explicitFiles, absPath) {
 			return true, nil // explicit file found
 		}
 
-		relPath, err := filepath.Rel(app.WorkingDirectory, f)
+		relPath, err := filepath.Rel(app.WorkingDirectory, absPath)
 		if err != nil {
 			return false, err
 		}
 
+		if excludes.MatchesPath(relPath) {
+			return false, nil
+		}
+		if len(explicitFiles) == 0 && len(globPatterns) == 0 {
+			return true, nil // only excludes defined
+		}
+
 		// check glob patterns in .gitignore format
 		return gitignore.MatchesPath(relPath), nil
 	}
@@ -321,7 +546,29 @@ func (app *AppContext) NewFilePredicate() func(f string) (bool, error) {
 
 // Run runs the application.
 func (app *AppContext) Run() {
-	app.CheckIfError(
-		app.RootCommand.Execute(),
-	)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	app.RequestContext = ctx
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-signals
+
+		// abort running requests cleanly ...
+		cancel()
+
+		// ... but do not hang, if we are waiting for something else
+		time.Sleep(2 * time.Second)
+		os.Exit(ExitCodeCancelled)
+	}()
+
+	err := app.RootCommand.Execute()
+	if err != nil {
+		// commands handle their own errors, so these are from parsing flags and arguments
+		app.CheckIfError(NewTypedError(ErrorTypeUsage, err))
+	}
+
+	app.OutputSessionCosts()
 }
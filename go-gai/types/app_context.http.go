@@ -0,0 +1,324 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+const defaultHttpRetries = 3
+const defaultHttpRetryDelay = 1 * time.Second
+const defaultHttpTimeout = 10 * time.Minute
+const maxHttpRetryDelay = 2 * time.Minute
+
+// retryableHttpStatusCodes stores status codes of transient errors,
+// for which requests are sent again.
+var retryableHttpStatusCodes = []int{429, 500, 502, 503, 504}
+
+// ErrRequestDumped is returned instead of sending a request,
+// which has been written as `curl` command.
+var ErrRequestDumped = errors.New("request has been written as curl command")
+
+// DumpRequestAsCurlIfNeeded writes `req` with `body` as `curl` command to `Stdout`
+// and returns `ErrRequestDumped`, if `DumpRequestCurl` is `true`.
+func (app *AppContext) DumpRequestAsCurlIfNeeded(req *http.Request, body []byte) error {
+	if !app.DumpRequestCurl {
+		return nil
+	}
+
+	app.Writeln(utils.BuildCurlCommand(req, body, app.UnsafeShowKey))
+
+	return ErrRequestDumped
+}
+
+// GetHttpRetries returns the maximum number of retries for
+// HTTP requests with transient errors.
+func (app *AppContext) GetHttpRetries() (int, error) {
+	GAI_HTTP_RETRIES := strings.TrimSpace(app.GetEnv("GAI_HTTP_RETRIES"))
+	if GAI_HTTP_RETRIES == "" {
+		return defaultHttpRetries, nil
+	}
+
+	retries, err := strconv.ParseUint(GAI_HTTP_RETRIES, 10, 16)
+	if err != nil {
+		return 0, fmt.Errorf("'%v' is no valid number of HTTP retries", GAI_HTTP_RETRIES)
+	}
+
+	return int(retries), nil
+}
+
+// GetHttpRetryBudget returns the maximum time for retries of HTTP requests,
+// which replaces the number of `GetHttpRetries`.
+// A value of `0` means that there is no budget.
+func (app *AppContext) GetHttpRetryBudget() (time.Duration, error) {
+	retryBudget := strings.TrimSpace(app.RetryBudget) // first try flag
+	if retryBudget == "" {
+		retryBudget = strings.TrimSpace(app.GetEnv("GAI_RETRY_BUDGET")) // now try env variable
+	}
+
+	if retryBudget == "" {
+		return 0, nil
+	}
+
+	budget, err := parseHttpDuration(retryBudget)
+	if err != nil {
+		return 0, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid retry budget", retryBudget))
+	}
+
+	return budget, nil
+}
+
+// GetHttpRetryDelay returns the base delay for retries of HTTP requests,
+// which is doubled with each attempt.
+func (app *AppContext) GetHttpRetryDelay() (time.Duration, error) {
+	GAI_HTTP_RETRY_DELAY := strings.TrimSpace(app.GetEnv("GAI_HTTP_RETRY_DELAY"))
+	if GAI_HTTP_RETRY_DELAY == "" {
+		return defaultHttpRetryDelay, nil
+	}
+
+	delay, err := parseHttpDuration(GAI_HTTP_RETRY_DELAY)
+	if err != nil {
+		return 0, fmt.Errorf("'%v' is no valid HTTP retry delay", GAI_HTTP_RETRY_DELAY)
+	}
+
+	return delay, nil
+}
+
+// GetHttpTimeout returns the timeout for HTTP requests.
+// A value of `0` means that there is no timeout.
+func (app *AppContext) GetHttpTimeout() (time.Duration, error) {
+	httpTimeout := strings.TrimSpace(app.HttpTimeout) // first try flag
+	if httpTimeout == "" {
+		httpTimeout = strings.TrimSpace(app.GetEnv("GAI_HTTP_TIMEOUT")) // now try env variable
+	}
+
+	if httpTimeout == "" {
+		return defaultHttpTimeout, nil
+	}
+
+	timeout, err := parseHttpDuration(httpTimeout)
+	if err != nil {
+		return 0, fmt.Errorf("'%v' is no valid HTTP timeout", httpTimeout)
+	}
+
+	return timeout, nil
+}
+
+// GetMaxRequestBytes returns the maximum size of the body of a request in bytes,
+// from `--max-request-bytes` or `GAI_MAX_REQUEST_BYTES`. A value of `0` means
+// that there is no limit.
+func (app *AppContext) GetMaxRequestBytes() (int64, error) {
+	maxRequestBytes := app.MaxRequestBytes // first try flag
+	if maxRequestBytes == 0 {
+		GAI_MAX_REQUEST_BYTES := strings.TrimSpace(app.GetEnv("GAI_MAX_REQUEST_BYTES")) // now try env variable
+		if GAI_MAX_REQUEST_BYTES != "" {
+			num, err := strconv.ParseInt(GAI_MAX_REQUEST_BYTES, 10, 64)
+			if err != nil {
+				return 0, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid maximum request size", GAI_MAX_REQUEST_BYTES))
+			}
+
+			maxRequestBytes = num
+		}
+	}
+
+	if maxRequestBytes < 0 {
+		return 0, NewTypedError(ErrorTypeUsage, fmt.Errorf("maximum request size cannot be negative: %v", maxRequestBytes))
+	}
+
+	return maxRequestBytes, nil
+}
+
+// NewHttpRequest creates a new HTTP request, which is bound to `RequestContext`.
+func (app *AppContext) NewHttpRequest(method string, url string, body io.Reader) (*http.Request, error) {
+	ctx := app.RequestContext
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return http.NewRequestWithContext(ctx, method, url, body)
+}
+
+// SendHttpRequest sends `req` with the timeout from `GetHttpTimeout`
+// and returns a clear error if request timed out or has been cancelled.
+// Requests, which fail with transient errors like 429 or 503, are sent
+// again with exponential backoff, as long as there are retries left or,
+// if defined, the next attempt starts within `GetHttpRetryBudget`.
+// Requests with bodies larger than `GetMaxRequestBytes` are not sent.
+func (app *AppContext) SendHttpRequest(req *http.Request) (*http.Response, error) {
+	defer app.StartTiming(TimingPhaseNetwork)()
+
+	maxRequestBytes, err := app.GetMaxRequestBytes()
+	if err != nil {
+		return nil, err
+	}
+	if maxRequestBytes > 0 && req.ContentLength > maxRequestBytes {
+		return nil, NewTypedError(
+			ErrorTypeRequestTooLarge,
+			fmt.Errorf(
+				"body of request to '%v' has %d bytes, which is more than the maximum of %d bytes (s. --max-request-bytes or GAI_MAX_REQUEST_BYTES): %s",
+				req.URL, req.ContentLength, maxRequestBytes, utils.RequestTooLargeHint,
+			),
+		)
+	}
+
+	timeout, err := app.GetHttpTimeout()
+	if err != nil {
+		return nil, err
+	}
+
+	retries, err := app.GetHttpRetries()
+	if err != nil {
+		return nil, err
+	}
+
+	retryDelay, err := app.GetHttpRetryDelay()
+	if err != nil {
+		return nil, err
+	}
+
+	retryBudget, err := app.GetHttpRetryBudget()
+	if err != nil {
+		return nil, err
+	}
+
+	startTime := time.Now()
+
+	client := &http.Client{
+		Timeout: timeout,
+	}
+
+	for attempt := 0; ; attempt++ {
+		currentReq := req
+		if attempt > 0 {
+			// body has been consumed by previous attempt
+			currentReq = req.Clone(req.Context())
+			if req.GetBody != nil {
+				currentReq.Body, err = req.GetBody()
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+
+		resp, err := client.Do(currentReq)
+		if err != nil {
+			if errors.Is(req.Context().Err(), context.Canceled) {
+				return resp, NewTypedError(ErrorTypeCancelled, fmt.Errorf("request to '%v' has been cancelled", req.URL))
+			}
+
+			if os.IsTimeout(err) {
+				return resp, NewTypedError(ErrorTypeTimeout, fmt.Errorf("request to '%v' timed out after %v (change with --http-timeout or GAI_HTTP_TIMEOUT)", req.URL, timeout))
+			}
+
+			return resp, NewTypedError(ErrorTypeNetwork, err)
+		}
+
+		if !slices.Contains(retryableHttpStatusCodes, resp.StatusCode) {
+			return resp, nil
+		}
+		if retryBudget <= 0 && attempt >= retries {
+			return resp, nil
+		}
+		if req.Body != nil && req.GetBody == nil {
+			return resp, nil // cannot send body again
+		}
+
+		wait := getHttpRetryWait(resp, retryDelay, attempt)
+
+		if retryBudget > 0 && time.Since(startTime)+wait > retryBudget {
+			app.Dbgf("Request to '%v' failed with status %v, retry budget of %v is exhausted%v", req.URL, resp.StatusCode, retryBudget, app.EOL)
+			return resp, nil
+		}
+
+		resp.Body.Close()
+
+		if retryBudget > 0 {
+			app.Dbgf("Request to '%v' failed with status %v, retrying in %v (%v of %v retry budget left) ...%v", req.URL, resp.StatusCode, wait, (retryBudget - time.Since(startTime)).Round(time.Millisecond), retryBudget, app.EOL)
+		} else {
+			app.Dbgf("Request to '%v' failed with status %v, retrying in %v (%v/%v) ...%v", req.URL, resp.StatusCode, wait, attempt+1, retries, app.EOL)
+		}
+
+		select {
+		case <-time.After(wait):
+		case <-req.Context().Done():
+			return nil, NewTypedError(ErrorTypeCancelled, fmt.Errorf("request to '%v' has been cancelled", req.URL))
+		}
+	}
+}
+
+// getHttpRetryWait returns the time to wait before the next attempt, which is
+// the value of `Retry-After` header or exponential backoff with jitter.
+func getHttpRetryWait(resp *http.Response, retryDelay time.Duration, attempt int) time.Duration {
+	retryAfter := strings.TrimSpace(resp.Header.Get("Retry-After"))
+	if retryAfter != "" {
+		if seconds, err := strconv.ParseUint(retryAfter, 10, 32); err == nil {
+			return min(time.Duration(seconds)*time.Second, maxHttpRetryDelay)
+		}
+		if date, err := http.ParseTime(retryAfter); err == nil {
+			return min(max(time.Until(date), 0), maxHttpRetryDelay)
+		}
+	}
+
+	// stop doubling at the maximum, because `attempt` is
+	// unbounded with a retry budget and would overflow
+	wait := retryDelay
+	for i := 0; i < attempt && wait < maxHttpRetryDelay; i++ {
+		wait *= 2
+	}
+	if retryDelay > 0 && wait < maxHttpRetryDelay {
+		wait += time.Duration(rand.Int63n(int64(retryDelay)))
+	}
+
+	return min(max(wait, 0), maxHttpRetryDelay)
+}
+
+// parseHttpDuration parses a plain number of seconds
+// or a duration like `90s` or `5m`.
+func parseHttpDuration(s string) (time.Duration, error) {
+	seconds, err := strconv.ParseUint(s, 10, 32)
+	if err == nil {
+		return time.Duration(seconds) * time.Second, nil
+	}
+
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, err
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("'%v' is negative", s)
+	}
+
+	return duration, nil
+}
@@ -0,0 +1,317 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+func TestDumpRequestAsCurl(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"OPENAI_API_KEY": "sk-secret",
+	})
+	app.DumpRequestCurl = true
+	app.Model = "openai:gpt-4o"
+
+	requests := 0
+	server := newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+	})
+
+	client, err := app.NewAIClient("openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = client.Prompt("Hello, it's me")
+	if !errors.Is(err, ErrRequestDumped) {
+		t.Fatalf("expected ErrRequestDumped, got %v", err)
+	}
+	if requests != 0 {
+		t.Errorf("expected no request, got %d", requests)
+	}
+
+	curl := readTestOutput(t, app.Stdout)
+	for _, expected := range []string{
+		"curl -X POST '" + server.URL + "/v1/chat/completions'",
+		"-H 'Authorization: Bearer ***'",
+		"-H 'Content-Type: application/json'",
+		`"model":"gpt-4o"`,
+		`Hello, it'\''s me`,
+	} {
+		if !strings.Contains(curl, expected) {
+			t.Errorf("expected %s in\n%s", expected, curl)
+		}
+	}
+	if strings.Contains(curl, "sk-secret") {
+		t.Errorf("API key is not masked in\n%s", curl)
+	}
+}
+
+func TestDumpRequestAsCurlIfNotNeeded(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	req, err := http.NewRequest("GET", "https://example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.DumpRequestAsCurlIfNeeded(req, nil)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if output := readTestOutput(t, app.Stdout); output != "" {
+		t.Errorf("expected no output, got %q", output)
+	}
+}
+
+// sendTestFlakyRequest sends a request to a server, which fails
+// `failures` times with status 503, and returns the final status
+// code and the number of received requests.
+func sendTestFlakyRequest(t *testing.T, app *AppContext, failures int, retryAfter string) (int, int) {
+	t.Helper()
+
+	requests := 0
+	server := newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+
+		if requests <= failures {
+			if retryAfter != "" {
+				w.Header().Set("Retry-After", retryAfter)
+			}
+
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Write([]byte("OK"))
+	})
+
+	req, err := app.NewHttpRequest("GET", server.URL, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	resp, err := app.SendHttpRequest(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp.Body.Close()
+
+	return resp.StatusCode, requests
+}
+
+func TestSendHttpRequestRetryBudget(t *testing.T) {
+	tests := []struct {
+		name             string
+		retryBudget      string
+		retryAfter       string
+		expectedStatus   int
+		expectedRequests int
+	}{
+		// more retries than GAI_HTTP_RETRIES
+		{"within budget", "5s", "", http.StatusOK, 5},
+		{"Retry-After exceeds budget", "500ms", "1", http.StatusServiceUnavailable, 1},
+		{"no budget", "", "", http.StatusServiceUnavailable, 2},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_HTTP_RETRIES":     "1",
+			"GAI_HTTP_RETRY_DELAY": "5ms",
+		})
+		app.RetryBudget = test.retryBudget
+
+		status, requests := sendTestFlakyRequest(t, app, 4, test.retryAfter)
+
+		if status != test.expectedStatus {
+			t.Errorf("%v: expected status %d, got %d", test.name, test.expectedStatus, status)
+		}
+		if requests != test.expectedRequests {
+			t.Errorf("%v: expected %d requests, got %d", test.name, test.expectedRequests, requests)
+		}
+	}
+}
+
+func TestSendHttpRequestRetryBudgetExhausted(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_HTTP_RETRY_DELAY": "20ms",
+		"GAI_RETRY_BUDGET":     "50ms",
+	})
+
+	start := time.Now()
+	status, requests := sendTestFlakyRequest(t, app, 10, "")
+	duration := time.Since(start)
+
+	// waits are 20-40ms, 40-60ms, ... so only one or two retries fit
+	if status != http.StatusServiceUnavailable {
+		t.Errorf("expected status %d, got %d", http.StatusServiceUnavailable, status)
+	}
+	if requests < 2 || requests > 3 {
+		t.Errorf("expected 2 or 3 requests, got %d", requests)
+	}
+	if duration > time.Second {
+		t.Errorf("expected to fail fast, took %v", duration)
+	}
+}
+
+func TestGetHttpRetryBudget(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"90", 90 * time.Second},
+		{"2m", 2 * time.Minute},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+		app.RetryBudget = test.value
+
+		budget, err := app.GetHttpRetryBudget()
+		if err != nil || budget != test.expected {
+			t.Errorf("%q: expected %v, got %v (%v)", test.value, test.expected, budget, err)
+		}
+	}
+
+	app := newTestApp(t, map[string]string{
+		"GAI_RETRY_BUDGET": "soon",
+	})
+
+	_, err := app.GetHttpRetryBudget()
+	if GetErrorType(err) != ErrorTypeUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
+
+func TestSendHttpRequestTooLarge(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.MaxRequestBytes = 10
+
+	requests := 0
+	server := newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("OK"))
+	})
+
+	for _, body := range []string{"0123456789", "0123456789A"} {
+		req, err := app.NewHttpRequest("POST", server.URL, strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		resp, err := app.SendHttpRequest(req)
+		if len(body) <= 10 {
+			if err != nil {
+				t.Fatalf("%q: expected request to be sent, got %v", body, err)
+			}
+			resp.Body.Close()
+			continue
+		}
+
+		if GetErrorType(err) != ErrorTypeRequestTooLarge {
+			t.Fatalf("%q: expected error of type %v, got %v", body, ErrorTypeRequestTooLarge, err)
+		}
+		if GetExitCode(err) != ExitCodeRequestTooLarge {
+			t.Errorf("%q: expected exit code %d, got %d", body, ExitCodeRequestTooLarge, GetExitCode(err))
+		}
+		if !strings.Contains(err.Error(), "has 11 bytes, which is more than the maximum of 10 bytes") ||
+			!strings.Contains(err.Error(), utils.RequestTooLargeHint) {
+			t.Errorf("%q: expected helpful error message, got %q", body, err.Error())
+		}
+	}
+
+	// oversized request has not been sent
+	if requests != 1 {
+		t.Errorf("expected 1 request, got %d", requests)
+	}
+}
+
+func TestGetMaxRequestBytes(t *testing.T) {
+	tests := []struct {
+		flag     int64
+		env      string
+		expected int64
+	}{
+		{0, "", 0},
+		{0, "1024", 1024},
+		{512, "1024", 512},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_MAX_REQUEST_BYTES": test.env,
+		})
+		app.MaxRequestBytes = test.flag
+
+		maxRequestBytes, err := app.GetMaxRequestBytes()
+		if err != nil || maxRequestBytes != test.expected {
+			t.Errorf("%d/%q: expected %d, got %d (%v)", test.flag, test.env, test.expected, maxRequestBytes, err)
+		}
+	}
+
+	for _, env := range []string{"1MB", "-1"} {
+		app := newTestApp(t, map[string]string{
+			"GAI_MAX_REQUEST_BYTES": env,
+		})
+
+		_, err := app.GetMaxRequestBytes()
+		if GetErrorType(err) != ErrorTypeUsage {
+			t.Errorf("%q: expected usage error, got %v", env, err)
+		}
+	}
+}
+
+func TestGetHttpRetryWait(t *testing.T) {
+	resp := &http.Response{Header: http.Header{}}
+
+	tests := []struct {
+		retryDelay time.Duration
+		attempt    int
+		minWait    time.Duration
+		maxWait    time.Duration
+	}{
+		{0, 0, 0, 0},
+		{time.Second, 0, time.Second, 2 * time.Second},
+		{time.Second, 2, 4 * time.Second, 5 * time.Second},
+		{time.Second, 7, maxHttpRetryDelay, maxHttpRetryDelay},
+		// would overflow without limit
+		{time.Second, 40, maxHttpRetryDelay, maxHttpRetryDelay},
+		{time.Second, 1000, maxHttpRetryDelay, maxHttpRetryDelay},
+		{time.Duration(1 << 62), 3, maxHttpRetryDelay, maxHttpRetryDelay},
+	}
+
+	for _, test := range tests {
+		wait := getHttpRetryWait(resp, test.retryDelay, test.attempt)
+		if wait < test.minWait || wait > test.maxWait {
+			t.Errorf("%v/%d: expected wait between %v and %v, got %v", test.retryDelay, test.attempt, test.minWait, test.maxWait, wait)
+		}
+	}
+}
@@ -26,12 +26,16 @@ import (
 	"bufio"
 	"errors"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path"
 	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/mkloubert/gai/utils"
+	"golang.org/x/term"
 )
 
 // CreateTemp creates a new temporary file.
@@ -85,6 +89,18 @@ func (app *AppContext) getBestChromaStyleName() string {
 	return "dracula"
 }
 
+// ColorEnabled returns `true` if output may contain ANSI colors,
+// which can be disabled by `--no-color` or the `NO_COLOR` env variable.
+// All renderers, which output colors, have to consult this.
+func (app *AppContext) ColorEnabled() bool {
+	if app.NoColor {
+		return false
+	}
+
+	// s. https://no-color.org/
+	return app.GetEnv("NO_COLOR") == ""
+}
+
 // GetChromaSettings returns an instance of `ChromaSettings`.
 func (app *AppContext) GetChromaSettings() *ChromaSettings {
 	return &ChromaSettings{
@@ -94,6 +110,31 @@ func (app *AppContext) GetChromaSettings() *ChromaSettings {
 	}
 }
 
+// IsInputFromStdin returns `true`, if `GetInput` reads STDIN, because it
+// has been piped and is part of `GAI_INPUT_ORDER`, so it cannot be used
+// for other input, like confirmations, anymore.
+func (app *AppContext) IsInputFromStdin() bool {
+	stdinStat, err := app.Stdin.Stat()
+	if err != nil || (stdinStat.Mode()&os.ModeCharDevice) != 0 {
+		return false
+	}
+
+	hasOrder := false
+	for _, item := range strings.Split(app.GetEnv("GAI_INPUT_ORDER"), ",") {
+		item = strings.TrimSpace(strings.ToLower(item))
+		if item == "" {
+			continue
+		}
+
+		hasOrder = true
+		if item == "in" || item == "stdin" {
+			return true
+		}
+	}
+
+	return !hasOrder // default order contains STDIN
+}
+
 // GetInput retrieves user input from the command-line arguments, standard input, or an editor.
 func (app *AppContext) GetInput(args []string) (string, error) {
 	stdin := app.Stdin
@@ -112,7 +153,12 @@ func (app *AppContext) GetInput(args []string) (string, error) {
 
 	// addPart function trims the white spaces and appends the non-empty strings to the parts slice
 	addPart := func(val string) {
-		val = strings.TrimSpace(val)
+		if app.Dedent {
+			// keep indentation of first line relative to the others
+			val = strings.TrimRight(utils.DedentText(val), " \t\r\n")
+		} else {
+			val = strings.TrimSpace(val)
+		}
 		if val != "" {
 			parts = append(parts, val)
 		}
@@ -184,6 +230,14 @@ func (app *AppContext) GetInput(args []string) (string, error) {
 				temp := ""
 				for scanner.Scan() {
 					temp += scanner.Text()
+					if app.Dedent {
+						temp += "\n" // keep lines for dedenting
+					}
+				}
+
+				temp, err := app.RedactText(temp, "STDIN")
+				if err != nil {
+					return err
 				}
 
 				dataFromStdin = &temp
@@ -230,9 +284,41 @@ func (app *AppContext) GetInput(args []string) (string, error) {
 		}
 	}
 
-	return strings.TrimSpace(
-		strings.Join(parts, *GAI_INPUT_SEPARATOR),
-	), nil
+	input := strings.Join(parts, *GAI_INPUT_SEPARATOR)
+	if app.Dedent {
+		return strings.TrimRight(input, " \t\r\n"), nil
+	}
+
+	return strings.TrimSpace(input), nil
+}
+
+// GetLastOutput returns the last output of a previous run, if available.
+func (app *AppContext) GetLastOutput() (*string, error) {
+	lastOutputFile, err := app.getLastOutputFilePath()
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(lastOutputFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil // no previous output
+		}
+
+		return nil, err
+	}
+
+	lastOutput := string(data)
+	return &lastOutput, nil
+}
+
+func (app *AppContext) getLastOutputFilePath() (string, error) {
+	appDir, err := app.EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(appDir, ".last-output"), nil
 }
 
 // GetOutputFile returns the path to the file where to write output to
@@ -252,6 +338,65 @@ func (app *AppContext) GetOutputFile() string {
 	return outputFile
 }
 
+// GetTeeFile returns the path to the file where to write a plain copy of output to
+func (app *AppContext) GetTeeFile() string {
+	teeFile := strings.TrimSpace(app.TeeFile) // first try flags
+	if teeFile == "" {
+		teeFile = strings.TrimSpace(app.GetEnv("GAI_TEE_FILE")) // now try env var
+	}
+
+	if teeFile != "" {
+		// ensure its absolute
+		if !filepath.IsAbs(teeFile) {
+			teeFile = filepath.Join(app.WorkingDirectory, teeFile)
+		}
+	}
+
+	return teeFile
+}
+
+// ReadBinaryStdin reads all data from STDIN as raw bytes and returns them
+// with their detected MIME type and a file name like `stdin.png`.
+func (app *AppContext) ReadBinaryStdin() ([]byte, string, string, error) {
+	if term.IsTerminal(int(app.Stdin.Fd())) {
+		return nil, "", "", NewTypedError(ErrorTypeUsage, errors.New("no data piped to STDIN"))
+	}
+
+	data, err := io.ReadAll(app.Stdin)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if len(data) == 0 {
+		return nil, "", "", NewTypedError(ErrorTypeUsage, errors.New("no data piped to STDIN"))
+	}
+
+	mimeType := utils.DetectMime(data)
+
+	ext := utils.GetImageFileExtension(mimeType)
+	if ext == "" {
+		ext = utils.GetAudioFileExtension(mimeType)
+	}
+	if ext == "" && strings.HasPrefix(mimeType, "application/pdf") {
+		ext = ".pdf"
+	}
+
+	app.Dbgf("Read %v bytes of type '%v' from STDIN%v", len(data), mimeType, app.EOL)
+
+	return data, mimeType, "stdin" + ext, nil
+}
+
+// UpdateLastOutput stores `output` as last output for following runs.
+func (app *AppContext) UpdateLastOutput(output string) error {
+	lastOutputFile, err := app.getLastOutputFilePath()
+	if err != nil {
+		return err
+	}
+
+	app.Dbg(fmt.Sprintf("Writing last output to '%v' ...", lastOutputFile))
+
+	return os.WriteFile(lastOutputFile, []byte(output), 0644)
+}
+
 // Write writes `b` to `Stdout`.
 func (app *AppContext) Write(b []byte) (n int, err error) {
 	return app.Stdout.Write(b)
@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestLastOutput(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	lastOutput, err := app.GetLastOutput()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lastOutput != nil {
+		t.Fatalf("expected no last output, got %q", *lastOutput)
+	}
+
+	for _, output := range []string{"first answer", "second\nanswer with \"quotes\""} {
+		err = app.UpdateLastOutput(output)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		lastOutput, err = app.GetLastOutput()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lastOutput == nil || *lastOutput != output {
+			t.Fatalf("expected last output %q, got %v", output, lastOutput)
+		}
+	}
+}
+
+func TestGetInputDedent(t *testing.T) {
+	for _, dedent := range []bool{false, true} {
+		app := newTestApp(t, nil)
+		app.Dedent = dedent
+
+		_, err := app.Stdin.WriteString("\n    func a() {\n\n\n        return\n    }\n\n")
+		if err == nil {
+			_, err = app.Stdin.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		input, err := app.GetInput([]string{"Explain", "this:"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// lines of STDIN are joined without dedenting
+		expected := "Explain this: func a() {        return    }"
+		if dedent {
+			expected = "Explain this: func a() {\n\n    return\n}"
+		}
+
+		if input != expected {
+			t.Errorf("dedent=%v: expected %q, got %q", dedent, expected, input)
+		}
+	}
+}
+
+func TestReadBinaryStdin(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR\x00\x00\x00\x01\x00\x00\x00\x01\x08\x02\x00\x00\x00")
+
+	tests := []struct {
+		data         []byte
+		expectedMime string
+		expectedName string
+	}{
+		{png, "image/png", "stdin.png"},
+		{newTestPDF("Report"), "application/pdf", "stdin.pdf"},
+		{[]byte{0, 1, 2, 3}, "application/octet-stream", "stdin"},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+
+		_, err := app.Stdin.Write(test.data)
+		if err == nil {
+			_, err = app.Stdin.Seek(0, io.SeekStart)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, mimeType, name, err := app.ReadBinaryStdin()
+		if err != nil {
+			t.Fatalf("%v: %v", test.expectedName, err)
+		}
+
+		if !bytes.Equal(data, test.data) {
+			t.Errorf("%v: data has been changed", test.expectedName)
+		}
+		if !strings.HasPrefix(mimeType, test.expectedMime) {
+			t.Errorf("%v: expected type %v, got %v", test.expectedName, test.expectedMime, mimeType)
+		}
+		if name != test.expectedName {
+			t.Errorf("expected name %v, got %v", test.expectedName, name)
+		}
+	}
+
+	// nothing piped
+	app := newTestApp(t, nil)
+
+	_, _, _, err := app.ReadBinaryStdin()
+	if GetErrorType(err) != ErrorTypeUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
+
+func TestIsInputFromStdin(t *testing.T) {
+	tests := []struct {
+		inputOrder string
+		expected   bool
+	}{
+		{"", true},
+		{"args,stdin", true},
+		{" IN ", true},
+		{"args", false},
+		{"args,editor", false},
+	}
+
+	for _, test := range tests {
+		// STDIN of tests is a file, which is handled like piped data
+		app := newTestApp(t, map[string]string{
+			"GAI_INPUT_ORDER": test.inputOrder,
+		})
+
+		if isInputFromStdin := app.IsInputFromStdin(); isInputFromStdin != test.expected {
+			t.Errorf("%q: expected %v, got %v", test.inputOrder, test.expected, isInputFromStdin)
+		}
+	}
+
+	// character device like a terminal
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer devNull.Close()
+
+	app := newTestApp(t, nil)
+	app.Stdin = devNull
+
+	if app.IsInputFromStdin() {
+		t.Errorf("expected no input from %v", os.DevNull)
+	}
+}
+
+func TestColorEnabled(t *testing.T) {
+	tests := []struct {
+		name     string
+		noColor  bool
+		envVars  map[string]string
+		expected bool
+	}{
+		{"default", false, nil, true},
+		{"flag", true, nil, false},
+		{"env", false, map[string]string{"NO_COLOR": "1"}, false},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, test.envVars)
+		app.NoColor = test.noColor
+
+		if enabled := app.ColorEnabled(); enabled != test.expected {
+			t.Errorf("%v: expected %v, got %v", test.name, test.expected, enabled)
+		}
+
+		app.GetChromaSettings().HighlightMarkdown("# Title\n\n**bold**")
+
+		output := readTestOutput(t, app.Stdout)
+		if hasEscapes := strings.Contains(output, "\x1b["); hasEscapes != test.expected {
+			t.Errorf("%v: expected ANSI escapes %v, got %q", test.name, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+// GetMermaidFormat returns the output format of rendered Mermaid diagrams,
+// which is `svg` (default) or `png`.
+func (app *AppContext) GetMermaidFormat() (string, error) {
+	format := strings.TrimSpace(strings.ToLower(app.MermaidFormat)) // first try flag
+	if format == "" {
+		format = strings.TrimSpace(strings.ToLower(app.GetEnv("GAI_MERMAID_FORMAT"))) // now try env variable
+	}
+
+	switch format {
+	case "", "svg":
+		return "svg", nil
+	case "png":
+		return "png", nil
+	}
+
+	return "", NewTypedError(ErrorTypeUsage, fmt.Errorf("'%v' is no valid Mermaid format, use svg or png", format))
+}
+
+// RenderMermaidDiagrams extracts all Mermaid diagrams from the fenced code
+// blocks of `markdown` and renders them into `outDir` as `diagram-<N>.<format>`.
+// It uses a local Mermaid CLI (`mmdc` or `GAI_MERMAID_CLI`) or a Kroki
+// compatible API, defined by `GAI_MERMAID_API_URL`. If there is no renderer,
+// the diagrams are skipped with a warning. Returns the paths of the written files.
+func (app *AppContext) RenderMermaidDiagrams(markdown string, outDir string) ([]string, error) {
+	files := make([]string, 0)
+
+	diagrams := utils.ExtractFencedCodeBlocks(markdown, "mermaid")
+	if len(diagrams) == 0 {
+		return files, nil
+	}
+
+	format, err := app.GetMermaidFormat()
+	if err != nil {
+		return files, err
+	}
+
+	cliPath := strings.TrimSpace(app.GetEnv("GAI_MERMAID_CLI"))
+	if cliPath == "" {
+		cliPath = "mmdc"
+	}
+	cliPath = app.TryGetExecutablePath(cliPath)
+
+	apiUrl := strings.TrimSuffix(strings.TrimSpace(app.GetEnv("GAI_MERMAID_API_URL")), "/")
+
+	if cliPath == "" && apiUrl == "" {
+		app.WriteErrorString(fmt.Sprintf("WARN: no Mermaid renderer found, install mmdc or define GAI_MERMAID_API_URL to render %v diagram(s)%v", len(diagrams), app.EOL))
+		return files, nil
+	}
+
+	if !filepath.IsAbs(outDir) {
+		outDir = filepath.Join(app.WorkingDirectory, outDir)
+	}
+
+	err = os.MkdirAll(outDir, 0755)
+	if err != nil {
+		return files, err
+	}
+
+	for i, diagram := range diagrams {
+		outFile := filepath.Join(outDir, fmt.Sprintf("diagram-%d.%s", i+1, format))
+
+		if cliPath != "" {
+			err = app.renderMermaidWithCLI(cliPath, diagram, outFile)
+		} else {
+			err = app.renderMermaidWithAPI(apiUrl, diagram, format, outFile)
+		}
+		if err != nil {
+			return files, fmt.Errorf("could not render Mermaid diagram %d: %w", i+1, err)
+		}
+
+		app.Dbgf("Rendered Mermaid diagram %d to '%v'%v", i+1, outFile, app.EOL)
+
+		files = append(files, outFile)
+	}
+
+	return files, nil
+}
+
+func (app *AppContext) renderMermaidWithAPI(apiUrl string, diagram string, format string, outFile string) error {
+	url := fmt.Sprintf("%s/mermaid/%s", apiUrl, format)
+
+	req, err := app.NewHttpRequest("POST", url, bytes.NewBufferString(diagram))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain")
+
+	resp, err := app.SendHttpRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = utils.CheckForHttpResponseError(resp)
+	if err != nil {
+		return err
+	}
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(outFile, data, 0644)
+}
+
+func (app *AppContext) renderMermaidWithCLI(cliPath string, diagram string, outFile string) error {
+	inFile, err := os.CreateTemp("", "gai-*.mmd")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(inFile.Name())
+
+	_, err = inFile.WriteString(diagram)
+	inFile.Close()
+	if err != nil {
+		return err
+	}
+
+	cmd := app.CreateExecCommand(cliPath, "--input", inFile.Name(), "--output", outFile)
+
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(output)))
+	}
+
+	return nil
+}
@@ -0,0 +1,150 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+const testMermaidAnswer = "Architecture:\n\n```mermaid\ngraph TD\n  A --> B\n```\n\n```go\nfunc main() {}\n```\n\n```Mermaid\nsequenceDiagram\n  A->>B: Hi\n```\n"
+
+func TestRenderMermaidDiagramsWithCLI(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("mock of Mermaid CLI is a shell script")
+	}
+
+	app := newTestApp(t, nil)
+
+	// mock, which writes its input to the output file
+	cliPath := writeTestFile(t, app, "bin/mmdc", "#!/bin/sh\ncat \"$2\" > \"$4\"\n")
+	err := os.Chmod(cliPath, 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.EnvVars["GAI_MERMAID_CLI"] = cliPath
+
+	files, err := app.RenderMermaidDiagrams(testMermaidAnswer, "diagrams")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		filepath.Join(app.WorkingDirectory, "diagrams", "diagram-1.svg"): "graph TD\n  A --> B",
+		filepath.Join(app.WorkingDirectory, "diagrams", "diagram-2.svg"): "sequenceDiagram\n  A->>B: Hi",
+	}
+	if len(files) != len(expected) {
+		t.Fatalf("expected %d files, got %v", len(expected), files)
+	}
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != expected[f] {
+			t.Errorf("%v: expected %q, got %q", f, expected[f], string(data))
+		}
+	}
+}
+
+func TestRenderMermaidDiagramsWithAPI(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.MermaidFormat = "PNG"
+
+	paths := make([]string, 0)
+	server := newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		paths = append(paths, r.URL.Path)
+
+		body, _ := io.ReadAll(r.Body)
+		w.Write([]byte("png:" + string(body)))
+	})
+
+	app.EnvVars["GAI_MERMAID_CLI"] = filepath.Join(app.WorkingDirectory, "not-installed")
+	app.EnvVars["GAI_MERMAID_API_URL"] = server.URL + "/"
+
+	files, err := app.RenderMermaidDiagrams(testMermaidAnswer, filepath.Join(app.WorkingDirectory, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 2 || filepath.Base(files[1]) != "diagram-2.png" {
+		t.Fatalf("expected 2 PNG files, got %v", files)
+	}
+	if strings.Join(paths, ",") != "/mermaid/png,/mermaid/png" {
+		t.Errorf("expected requests to /mermaid/png, got %v", paths)
+	}
+
+	data, err := os.ReadFile(files[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "png:graph TD\n  A --> B" {
+		t.Errorf("expected rendered diagram, got %q", string(data))
+	}
+}
+
+func TestRenderMermaidDiagramsWithoutRenderer(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.EnvVars["GAI_MERMAID_CLI"] = filepath.Join(app.WorkingDirectory, "not-installed")
+
+	files, err := app.RenderMermaidDiagrams(testMermaidAnswer, "diagrams")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(files) != 0 {
+		t.Errorf("expected no files, got %v", files)
+	}
+	if _, err := os.Stat(filepath.Join(app.WorkingDirectory, "diagrams")); !os.IsNotExist(err) {
+		t.Errorf("expected no output directory, got %v", err)
+	}
+
+	output := readTestOutput(t, app.Stderr)
+	if !strings.Contains(output, "WARN: no Mermaid renderer found") {
+		t.Errorf("expected warning, got %q", output)
+	}
+}
+
+func TestGetMermaidFormat(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_MERMAID_FORMAT": "png",
+	})
+
+	format, err := app.GetMermaidFormat()
+	if err != nil || format != "png" {
+		t.Errorf("expected png, got %q (%v)", format, err)
+	}
+
+	app.MermaidFormat = "pdf"
+
+	_, err = app.GetMermaidFormat()
+	if GetErrorType(err) != ErrorTypeUsage {
+		t.Errorf("expected usage error, got %v", err)
+	}
+}
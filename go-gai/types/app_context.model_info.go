@@ -0,0 +1,182 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// knownModelCapabilities stores the capabilities and approximate list prices
+// in dollars per 1,000 tokens of well known models, which are found by the
+// longest matching name prefix of a provider. Prices of `0` are unknown.
+var knownModelCapabilities = []struct {
+	audio         bool
+	contextWindow int64
+	inputPrice    float64
+	jsonSchema    bool
+	outputPrice   float64
+	prefix        string
+	provider      string
+	vision        bool
+}{
+	{provider: "gemini", prefix: "gemini-1.5-flash", audio: true, contextWindow: 1048576, jsonSchema: true, vision: true, inputPrice: 0.000075, outputPrice: 0.0003},
+	{provider: "gemini", prefix: "gemini-1.5-pro", audio: true, contextWindow: 2097152, jsonSchema: true, vision: true, inputPrice: 0.00125, outputPrice: 0.005},
+	{provider: "gemini", prefix: "gemini-2.0-flash", audio: true, contextWindow: 1048576, jsonSchema: true, vision: true, inputPrice: 0.0001, outputPrice: 0.0004},
+	{provider: "gemini", prefix: "gemini-2.5", audio: true, contextWindow: 1048576, jsonSchema: true, vision: true, inputPrice: 0.00125, outputPrice: 0.01},
+	{provider: "gemini", prefix: "gemini-2.5-flash", audio: true, contextWindow: 1048576, jsonSchema: true, vision: true, inputPrice: 0.0003, outputPrice: 0.0025},
+	{provider: "ollama", prefix: "gemma3", contextWindow: 131072, jsonSchema: true, vision: true},
+	{provider: "ollama", prefix: "llama3.1", contextWindow: 131072, jsonSchema: true},
+	{provider: "ollama", prefix: "llama3.2", contextWindow: 131072, jsonSchema: true},
+	{provider: "ollama", prefix: "llama3.2-vision", contextWindow: 131072, jsonSchema: true, vision: true},
+	{provider: "ollama", prefix: "llava", contextWindow: 4096, jsonSchema: true, vision: true},
+	{provider: "ollama", prefix: "mistral", contextWindow: 32768, jsonSchema: true},
+	{provider: "ollama", prefix: "qwen2.5", contextWindow: 32768, jsonSchema: true},
+	{provider: "openai", prefix: "gpt-3.5-turbo", contextWindow: 16385, inputPrice: 0.0005, outputPrice: 0.0015},
+	{provider: "openai", prefix: "gpt-4-turbo", contextWindow: 128000, vision: true, inputPrice: 0.01, outputPrice: 0.03},
+	{provider: "openai", prefix: "gpt-4.1", contextWindow: 1047576, jsonSchema: true, vision: true, inputPrice: 0.002, outputPrice: 0.008},
+	{provider: "openai", prefix: "gpt-4.1-mini", contextWindow: 1047576, jsonSchema: true, vision: true, inputPrice: 0.0004, outputPrice: 0.0016},
+	{provider: "openai", prefix: "gpt-4.1-nano", contextWindow: 1047576, jsonSchema: true, vision: true, inputPrice: 0.0001, outputPrice: 0.0004},
+	{provider: "openai", prefix: "gpt-4o", contextWindow: 128000, jsonSchema: true, vision: true, inputPrice: 0.0025, outputPrice: 0.01},
+	{provider: "openai", prefix: "gpt-4o-audio", audio: true, contextWindow: 128000, inputPrice: 0.0025, outputPrice: 0.01},
+	{provider: "openai", prefix: "gpt-4o-mini", contextWindow: 128000, jsonSchema: true, vision: true, inputPrice: 0.00015, outputPrice: 0.0006},
+	{provider: "openai", prefix: "gpt-5", contextWindow: 400000, jsonSchema: true, vision: true, inputPrice: 0.00125, outputPrice: 0.01},
+	{provider: "openai", prefix: "gpt-5-mini", contextWindow: 400000, jsonSchema: true, vision: true, inputPrice: 0.00025, outputPrice: 0.002},
+	{provider: "openai", prefix: "gpt-5-nano", contextWindow: 400000, jsonSchema: true, vision: true, inputPrice: 0.00005, outputPrice: 0.0004},
+	{provider: "openai", prefix: "o1", contextWindow: 200000, jsonSchema: true, vision: true, inputPrice: 0.015, outputPrice: 0.06},
+	{provider: "openai", prefix: "o1-mini", contextWindow: 128000, inputPrice: 0.0011, outputPrice: 0.0044},
+	{provider: "openai", prefix: "o3", contextWindow: 200000, jsonSchema: true, vision: true, inputPrice: 0.002, outputPrice: 0.008},
+	{provider: "openai", prefix: "o3-mini", contextWindow: 200000, jsonSchema: true, inputPrice: 0.0011, outputPrice: 0.0044},
+	{provider: "openai", prefix: "o4-mini", contextWindow: 200000, jsonSchema: true, vision: true, inputPrice: 0.0011, outputPrice: 0.0044},
+}
+
+// GetModelInfo returns the information about `model` of `client`, which
+// is based on the table of well known models, the live information of
+// the provider and the capabilities found by `list models --probe`.
+// Prices of `GAI_PRICE_INPUT` and `GAI_PRICE_OUTPUT` overwrite the ones
+// of the table. Live information, which cannot be loaded, is skipped with a warning.
+func (app *AppContext) GetModelInfo(client AIClient, model string) (AIModelInfo, error) {
+	provider := client.Provider()
+	if provider == "azure" {
+		provider = "openai" // deployments are usually named like the models
+	}
+
+	info := AIModelInfo{}
+	known := false
+
+	// first take the values of the longest matching prefix
+	longestPrefix := ""
+	for _, c := range knownModelCapabilities {
+		if c.provider != provider || !strings.HasPrefix(strings.ToLower(model), c.prefix) || len(c.prefix) <= len(longestPrefix) {
+			continue
+		}
+
+		longestPrefix = c.prefix
+
+		info.Audio = &c.audio
+		info.ContextWindow = &c.contextWindow
+		info.JSONSchema = &c.jsonSchema
+		info.Vision = &c.vision
+		info.InputPrice = nil
+		info.OutputPrice = nil
+		if c.inputPrice > 0 {
+			info.InputPrice = &c.inputPrice
+		}
+		if c.outputPrice > 0 {
+			info.OutputPrice = &c.outputPrice
+		}
+		known = true
+	}
+
+	if provider == "ollama" {
+		// local models have no costs
+		noCosts := 0.0
+		info.InputPrice = &noCosts
+		info.OutputPrice = &noCosts
+	}
+
+	prices, err := app.GetTokenPrices()
+	if err != nil {
+		return info, NewTypedError(ErrorTypeUsage, err)
+	}
+	if prices.Input != nil {
+		info.InputPrice = prices.Input
+	}
+	if prices.Output != nil {
+		info.OutputPrice = prices.Output
+	}
+
+	// now overwrite with live data of the provider
+	liveInfo, err := client.GetModelInfo(model)
+	if err == nil {
+		if liveInfo.Audio != nil {
+			info.Audio = liveInfo.Audio
+		}
+		if liveInfo.ContextWindow != nil {
+			info.ContextWindow = liveInfo.ContextWindow
+		}
+		if liveInfo.JSONSchema != nil {
+			info.JSONSchema = liveInfo.JSONSchema
+		}
+		if liveInfo.Vision != nil {
+			info.Vision = liveInfo.Vision
+		}
+
+		info.Created = liveInfo.Created
+		info.Description = liveInfo.Description
+		info.DisplayName = liveInfo.DisplayName
+		info.Family = liveInfo.Family
+		info.MaxOutputTokens = liveInfo.MaxOutputTokens
+		info.OwnedBy = liveInfo.OwnedBy
+		info.ParameterSize = liveInfo.ParameterSize
+		info.Quantization = liveInfo.Quantization
+
+		known = true
+	} else {
+		app.WriteErrorString(fmt.Sprintf("WARN: could not load information of '%v:%v' from provider: %v%v", client.Provider(), model, err.Error(), app.EOL))
+	}
+
+	// ... and finally with really probed capabilities
+	capabilities, err := app.GetModelCapabilities()
+	if err != nil {
+		return info, err
+	}
+
+	probed, ok := capabilities[fmt.Sprintf("%v:%v", client.Provider(), model)]
+	if ok && slices.Contains(probed, "text") {
+		audio := slices.Contains(probed, "audio")
+		vision := slices.Contains(probed, "image")
+
+		info.Audio = &audio
+		info.Vision = &vision
+
+		known = true
+	}
+
+	if !known {
+		return info, fmt.Errorf("no information about model '%v:%v' found", client.Provider(), model)
+	}
+
+	return info, nil
+}
@@ -0,0 +1,220 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/goccy/go-yaml"
+)
+
+// ModelCapabilities stores capabilities of models by their full name, like `openai:gpt-4.1`.
+type ModelCapabilities = map[string][]string
+
+// GetAllModels returns the models of all supported providers,
+// which can be loaded. Providers, which are not configured
+// or not available, are skipped.
+func (app *AppContext) GetAllModels() []AIModel {
+	clients := make([]AIClient, 0)
+
+	gemini, geminiErr := app.NewAIClient("gemini")
+	if geminiErr != nil {
+		app.Dbgf("WARN: could not create Gemini client: %s%s", geminiErr.Error(), app.EOL)
+	} else {
+		clients = append(clients, gemini)
+	}
+
+	ollama, ollamaErr := app.NewAIClient("ollama")
+	if ollamaErr != nil {
+		app.Dbgf("WARN: could not create Ollama client: %s%s", ollamaErr.Error(), app.EOL)
+	} else {
+		clients = append(clients, ollama)
+	}
+
+	openai, openaiErr := app.NewAIClient("openai")
+	if openaiErr != nil {
+		app.Dbgf("WARN: could not create OpenAI client: %s%s", openaiErr.Error(), app.EOL)
+	} else {
+		clients = append(clients, openai)
+	}
+
+	modelList := make([]AIModel, 0)
+
+	for _, c := range clients {
+		loadedModels, err := c.GetModels()
+		if err != nil {
+			app.Dbgf("WARN: Could not load models: %s", err.Error())
+			continue
+		}
+
+		modelList = append(modelList, loadedModels...)
+	}
+
+	return modelList
+}
+
+// GetModelCapabilities loads the cached model capabilities.
+func (app *AppContext) GetModelCapabilities() (ModelCapabilities, error) {
+	capabilities := ModelCapabilities{}
+
+	capabilitiesFile, err := app.getModelCapabilitiesFilePath()
+	if err != nil {
+		return capabilities, err
+	}
+
+	data, err := os.ReadFile(capabilitiesFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return capabilities, nil
+		}
+
+		return capabilities, err
+	}
+
+	err = yaml.Unmarshal(data, &capabilities)
+	if capabilities == nil {
+		capabilities = ModelCapabilities{}
+	}
+
+	return capabilities, err
+}
+
+func (app *AppContext) getModelCapabilitiesFilePath() (string, error) {
+	appDir, err := app.EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(appDir, ".model-capabilities.yaml"), nil
+}
+
+// ProbeModelCapabilities does cheap requests with tiny text, image and audio
+// data to find out which capabilities a model really supports.
+func (app *AppContext) ProbeModelCapabilities(client AIClient, model string) ([]string, error) {
+	capabilities := make([]string, 0)
+
+	err := client.SetChatModel(model)
+	if err != nil {
+		return capabilities, err
+	}
+
+	probeImage, err := createProbeImage()
+	if err != nil {
+		return capabilities, err
+	}
+
+	probes := []struct {
+		capability string
+		file       []byte
+	}{
+		{capability: "text"},
+		{capability: "image", file: probeImage},
+		{capability: "audio", file: createProbeAudio()},
+	}
+
+	for _, p := range probes {
+		opts := AIClientPromptOptions{}
+		if p.file != nil {
+			files := []io.Reader{bytes.NewReader(p.file)}
+			opts.Files = &files
+		}
+
+		app.Dbgf("Probing '%v' capability of '%v:%v' ...%v", p.capability, client.Provider(), model, app.EOL)
+
+		_, err := client.Prompt("Reply with OK.", opts)
+		if err != nil {
+			app.Dbgf("Probing '%v' capability of '%v:%v' failed: %v%v", p.capability, client.Provider(), model, err, app.EOL)
+
+			if p.capability == "text" {
+				// model cannot even handle simple text
+				break
+			}
+			continue
+		}
+
+		capabilities = append(capabilities, p.capability)
+	}
+
+	return capabilities, nil
+}
+
+// UpdateModelCapabilities writes model capabilities to cache.
+func (app *AppContext) UpdateModelCapabilities(capabilities ModelCapabilities) error {
+	capabilitiesFile, err := app.getModelCapabilitiesFilePath()
+	if err != nil {
+		return err
+	}
+
+	for _, list := range capabilities {
+		sort.Strings(list)
+	}
+
+	data, err := yaml.Marshal(&capabilities)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(capabilitiesFile, data, 0644)
+}
+
+// createProbeImage creates a PNG with a single white pixel.
+func createProbeImage() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, 1, 1))
+	img.Set(0, 0, color.White)
+
+	var buff bytes.Buffer
+	err := png.Encode(&buff, img)
+
+	return buff.Bytes(), err
+}
+
+// createProbeAudio creates a WAV file with 100ms of silence (8 kHz, mono, 16-bit).
+func createProbeAudio() []byte {
+	const sampleRate = 8000
+	const dataSize = sampleRate / 10 * 2
+
+	var buff bytes.Buffer
+	buff.WriteString("RIFF")
+	binary.Write(&buff, binary.LittleEndian, uint32(36+dataSize))
+	buff.WriteString("WAVE")
+	buff.WriteString("fmt ")
+	binary.Write(&buff, binary.LittleEndian, uint32(16))           // size of chunk
+	binary.Write(&buff, binary.LittleEndian, uint16(1))            // PCM
+	binary.Write(&buff, binary.LittleEndian, uint16(1))            // mono
+	binary.Write(&buff, binary.LittleEndian, uint32(sampleRate))   // sample rate
+	binary.Write(&buff, binary.LittleEndian, uint32(sampleRate*2)) // byte rate
+	binary.Write(&buff, binary.LittleEndian, uint16(2))            // block align
+	binary.Write(&buff, binary.LittleEndian, uint16(16))           // bits per sample
+	buff.WriteString("data")
+	binary.Write(&buff, binary.LittleEndian, uint32(dataSize))
+	buff.Write(make([]byte, dataSize))
+
+	return buff.Bytes()
+}
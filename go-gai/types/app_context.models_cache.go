@@ -0,0 +1,172 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const defaultModelsCacheTTL = 1 * time.Hour
+
+// modelsCache stores the cached model lists by provider and base URL.
+type modelsCache = map[string]*modelsCacheEntry
+
+type modelsCacheEntry struct {
+	Models    []modelsCacheItem `json:"models"`
+	UpdatedAt time.Time         `json:"updated_at"`
+}
+
+type modelsCacheItem struct {
+	Name string `json:"name"`
+	Type string `json:"type,omitempty"`
+}
+
+// GetModelsCacheTTL returns the time, how long cached model lists are valid.
+// A value of `0` means that there is no cache.
+func (app *AppContext) GetModelsCacheTTL() (time.Duration, error) {
+	GAI_MODELS_CACHE_TTL := strings.TrimSpace(app.GetEnv("GAI_MODELS_CACHE_TTL"))
+	if GAI_MODELS_CACHE_TTL == "" {
+		return defaultModelsCacheTTL, nil
+	}
+
+	ttl, err := parseHttpDuration(GAI_MODELS_CACHE_TTL)
+	if err != nil {
+		return 0, fmt.Errorf("'%v' is no valid TTL for cached models", GAI_MODELS_CACHE_TTL)
+	}
+
+	return ttl, nil
+}
+
+// getCachedModels returns the models of `client` for `baseUrl` from the
+// cache, or loads them with `load` if there is no valid entry and updates
+// the cache. `RefreshModels` forces loading them.
+func (app *AppContext) getCachedModels(client AIClient, baseUrl string, load func() ([]AIModel, error)) ([]AIModel, error) {
+	ttl, err := app.GetModelsCacheTTL()
+	if err != nil {
+		return nil, err
+	}
+	if ttl == 0 {
+		return load()
+	}
+
+	key := fmt.Sprintf("%s|%s", client.Provider(), baseUrl)
+
+	cache, err := app.loadModelsCache()
+	if err != nil {
+		app.Dbgf("WARN: Could not load cached models: %v%v", err.Error(), app.EOL)
+		cache = modelsCache{}
+	}
+
+	entry, ok := cache[key]
+	if ok && entry != nil && !app.RefreshModels && time.Since(entry.UpdatedAt) < ttl {
+		app.Dbgf("Taking models of '%v' from cache%v", key, app.EOL)
+
+		models := make([]AIModel, 0, len(entry.Models))
+		for _, m := range entry.Models {
+			models = append(models, AIModel{
+				client:    client,
+				modelType: m.Type,
+				name:      m.Name,
+			})
+		}
+
+		return models, nil
+	}
+
+	models, err := load()
+	if err != nil {
+		return models, err
+	}
+
+	entry = &modelsCacheEntry{
+		Models:    make([]modelsCacheItem, 0, len(models)),
+		UpdatedAt: time.Now().UTC(),
+	}
+	for _, m := range models {
+		entry.Models = append(entry.Models, modelsCacheItem{
+			Name: m.Name(),
+			Type: m.ModelType(),
+		})
+	}
+	cache[key] = entry
+
+	err = app.updateModelsCache(cache)
+	if err != nil {
+		app.Dbgf("WARN: Could not update cached models: %v%v", err.Error(), app.EOL)
+	}
+
+	return models, nil
+}
+
+func (app *AppContext) getModelsCacheFilePath() (string, error) {
+	appDir, err := app.EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(appDir, "models-cache.json"), nil
+}
+
+func (app *AppContext) loadModelsCache() (modelsCache, error) {
+	cache := modelsCache{}
+
+	cacheFile, err := app.getModelsCacheFilePath()
+	if err != nil {
+		return cache, err
+	}
+
+	data, err := os.ReadFile(cacheFile)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cache, nil
+		}
+
+		return cache, err
+	}
+
+	err = json.Unmarshal(data, &cache)
+	if cache == nil {
+		cache = modelsCache{}
+	}
+
+	return cache, err
+}
+
+func (app *AppContext) updateModelsCache(cache modelsCache) error {
+	cacheFile, err := app.getModelsCacheFilePath()
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(&cache)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(cacheFile, data, 0644)
+}
@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestProbeModelCapabilities(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"OPENAI_API_KEY": "test",
+	})
+	app.Model = "openai:gpt-4o"
+
+	// content types, which are rejected by each model
+	rejectedTypes := map[string][]string{
+		"audio-model":  {"image_url"},
+		"broken-model": {"text"},
+		"text-model":   {"image_url", "input_audio"},
+		"vision-model": {"input_audio"},
+	}
+
+	newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content []struct {
+					Type string `json:"type"`
+				} `json:"content"`
+			} `json:"messages"`
+			Model string `json:"model"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		for _, m := range body.Messages {
+			for _, c := range m.Content {
+				for _, rejected := range rejectedTypes[body.Model] {
+					if c.Type == rejected {
+						http.Error(w, "unsupported content", http.StatusBadRequest)
+						return
+					}
+				}
+			}
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model": "` + body.Model + `", "choices": [{"message": {"role": "assistant", "content": "OK"}}]}`))
+	})
+
+	client, err := app.NewAIClient("openai")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := ModelCapabilities{
+		"openai:audio-model":  {"audio", "text"},
+		"openai:broken-model": {},
+		"openai:text-model":   {"text"},
+		"openai:vision-model": {"image", "text"},
+	}
+
+	capabilities := ModelCapabilities{}
+	for model := range rejectedTypes {
+		modelCapabilities, err := app.ProbeModelCapabilities(client, model)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		capabilities["openai:"+model] = modelCapabilities
+	}
+
+	// cache and load them again
+	err = app.UpdateModelCapabilities(capabilities)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cachedCapabilities, err := app.GetModelCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for model, list := range expected {
+		if !reflect.DeepEqual(cachedCapabilities[model], list) {
+			t.Errorf("expected capabilities %v of %v, got %v", list, model, cachedCapabilities[model])
+		}
+	}
+}
+
+func TestGetModelCapabilitiesWithoutCache(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	capabilities, err := app.GetModelCapabilities()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(capabilities) != 0 {
+		t.Errorf("expected no capabilities, got %v", capabilities)
+	}
+}
+
+func TestCreateProbeData(t *testing.T) {
+	img, err := createProbeImage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(img), "\x89PNG") {
+		t.Error("probe image is no PNG")
+	}
+
+	audio := createProbeAudio()
+	if len(audio) != 44+1600 || string(audio[0:4]) != "RIFF" || string(audio[8:12]) != "WAVE" {
+		t.Errorf("probe audio is no valid WAV file (%d bytes)", len(audio))
+	}
+}
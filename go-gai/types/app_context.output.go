@@ -0,0 +1,113 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+	"golang.org/x/term"
+)
+
+// GetOutputRaw returns `true` if answers should be written verbatim,
+// based on `--raw` flag or `GAI_OUTPUT_RAW` environment variable.
+func (app *AppContext) GetOutputRaw() (bool, error) {
+	if app.Raw {
+		return true, nil // flag
+	}
+
+	GAI_OUTPUT_RAW := strings.TrimSpace(app.GetEnv("GAI_OUTPUT_RAW")) // now try env variable
+	if GAI_OUTPUT_RAW != "" {
+		return strconv.ParseBool(GAI_OUTPUT_RAW)
+	}
+
+	return false, nil
+}
+
+// GetWrap returns the number of columns, after which long lines
+// of answers are wrapped, based on `--wrap` flag or `GAI_WRAP`
+// environment variable. `0` means no wrapping.
+func (app *AppContext) GetWrap() (int, error) {
+	wrap := app.Wrap // flag
+	if wrap == 0 {
+		GAI_WRAP := strings.TrimSpace(app.GetEnv("GAI_WRAP")) // now try env variable
+		if GAI_WRAP != "" {
+			value, err := strconv.Atoi(GAI_WRAP)
+			if err != nil {
+				return 0, NewTypedError(
+					ErrorTypeUsage,
+					fmt.Errorf("invalid value '%v' for GAI_WRAP: %w", GAI_WRAP, err),
+				)
+			}
+
+			wrap = value
+		}
+	}
+
+	if wrap < 0 {
+		return 0, NewTypedError(
+			ErrorTypeUsage,
+			fmt.Errorf("number of columns for wrapping cannot be negative: %v", wrap),
+		)
+	}
+
+	return wrap, nil
+}
+
+// OutputMarkdown writes `text` to STDOUT, which is wrapped, based on
+// `GetWrap()`, if no JSON is output, and highlighted as Markdown, if
+// `--no-highlight` is not set and STDOUT is a terminal. Nothing of this
+// is done in raw mode.
+// Returns `true` if `text` has been highlighted.
+func (app *AppContext) OutputMarkdown(text string) bool {
+	raw, err := app.GetOutputRaw()
+	app.CheckIfError(err)
+
+	if raw {
+		app.WriteString(text)
+		return false
+	}
+
+	// JSON is machine output, which must not be wrapped
+	isJSON := app.JSONOutput ||
+		strings.TrimSpace(app.SchemaFile) != "" ||
+		strings.TrimSpace(app.SchemaName) != ""
+	if !isJSON {
+		wrap, err := app.GetWrap()
+		app.CheckIfError(err)
+
+		text = utils.WrapText(text, wrap)
+	}
+
+	if !app.NoHighlight && term.IsTerminal(int(app.Stdout.Fd())) {
+		chroma := app.GetChromaSettings()
+		chroma.HighlightMarkdown(text)
+
+		return true
+	}
+
+	app.WriteString(text)
+	return false
+}
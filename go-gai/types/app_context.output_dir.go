@@ -0,0 +1,142 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// OutputFileTemplateData stores the data, which is available
+// in templates of `--output-template`.
+type OutputFileTemplateData struct {
+	// Dir is the directory of the source file, relative to the working directory.
+	Dir string
+	// Ext is the extension of the source file, like `.jpg`.
+	Ext string
+	// Filename is the name of the source file with extension.
+	Filename string
+	// Index is the zero-based index of the source file.
+	Index int
+	// Name is the name of the source file without extension.
+	Name string
+}
+
+// GetOutputDir returns the full path of the directory, where
+// output of processed files should be written to, or an empty string,
+// if not defined.
+func (app *AppContext) GetOutputDir() string {
+	outputDir := strings.TrimSpace(app.OutputDir) // first try flags
+	if outputDir == "" {
+		outputDir = strings.TrimSpace(app.GetEnv("GAI_OUTPUT_DIR")) // now try env var
+	}
+
+	if outputDir != "" {
+		outputDir = app.GetFullPath(outputDir)
+	}
+
+	return outputDir
+}
+
+// GetOutputFileTemplate returns the template for the names of the files of
+// `GetOutputDir()` from `--output-template`, `GAI_OUTPUT_TEMPLATE` or
+// `defaultTemplate`, or `nil` if no output directory is defined.
+func (app *AppContext) GetOutputFileTemplate(defaultTemplate string) (*template.Template, error) {
+	outputTemplate := app.OutputTemplate // first try flags
+	if strings.TrimSpace(outputTemplate) == "" {
+		outputTemplate = app.GetEnv("GAI_OUTPUT_TEMPLATE") // now try env var
+	}
+
+	if app.GetOutputDir() == "" {
+		if strings.TrimSpace(app.OutputTemplate) != "" {
+			return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("--output-template requires --output-dir"))
+		}
+		return nil, nil
+	}
+
+	if strings.TrimSpace(outputTemplate) == "" {
+		outputTemplate = defaultTemplate
+	}
+
+	tmpl, err := template.New("output").Option("missingkey=error").Parse(outputTemplate)
+	if err != nil {
+		return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("invalid output template: %w", err))
+	}
+
+	// fail before any file is processed
+	err = tmpl.Execute(io.Discard, OutputFileTemplateData{})
+	if err != nil {
+		return nil, NewTypedError(ErrorTypeUsage, fmt.Errorf("invalid output template: %w", err))
+	}
+
+	return tmpl, nil
+}
+
+// WriteOutputFile writes `data` to the file inside `GetOutputDir()`, which
+// is built from `tmpl` for the source file `source` with the zero-based
+// index `index`, and returns its full path.
+func (app *AppContext) WriteOutputFile(tmpl *template.Template, source string, index int, data []byte) (string, error) {
+	outputDir := app.GetOutputDir()
+
+	relSource, err := filepath.Rel(app.WorkingDirectory, app.GetFullPath(source))
+	if err != nil || strings.HasPrefix(relSource, "..") {
+		relSource = filepath.Base(source)
+	}
+
+	filename := filepath.Base(relSource)
+	ext := filepath.Ext(filename)
+
+	var outputName bytes.Buffer
+	err = tmpl.Execute(&outputName, OutputFileTemplateData{
+		Dir:      filepath.Dir(relSource),
+		Ext:      ext,
+		Filename: filename,
+		Index:    index,
+		Name:     strings.TrimSuffix(filename, ext),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	outputFile := filepath.Join(outputDir, strings.TrimSpace(outputName.String()))
+
+	// do not write outside of output directory
+	requiredPrefix := fmt.Sprintf("%s%c", outputDir, os.PathSeparator)
+	if !strings.HasPrefix(outputFile, requiredPrefix) {
+		return outputFile, fmt.Errorf("invalid output file path '%s'", outputFile)
+	}
+
+	err = os.MkdirAll(filepath.Dir(outputFile), 0755)
+	if err != nil {
+		return outputFile, err
+	}
+
+	app.Dbgf("Writing output of '%v' to '%v' ...%v", source, outputFile, app.EOL)
+
+	return outputFile, os.WriteFile(outputFile, data, 0644)
+}
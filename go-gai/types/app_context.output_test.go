@@ -0,0 +1,58 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"testing"
+)
+
+func TestOutputMarkdownJSON(t *testing.T) {
+	answer := `{"title": "This is a long title, which is not wrapped"}`
+
+	tests := []struct {
+		name       string
+		jsonOutput bool
+		schemaFile string
+		schemaName string
+		expected   string
+	}{
+		{"markdown", false, "", "", "{\"title\": \"This is a\nlong title, which is\nnot wrapped\"}"},
+		{"--json", true, "", "", answer},
+		{"--schema", false, "schema.json", "", answer},
+		{"--schema-name", false, "", "TitleSchema", answer},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+		app.JSONOutput = test.jsonOutput
+		app.SchemaFile = test.schemaFile
+		app.SchemaName = test.schemaName
+		app.Wrap = 20
+
+		app.OutputMarkdown(answer)
+
+		if output := readTestOutput(t, app.Stdout); output != test.expected {
+			t.Errorf("%v: expected output %q, got %q", test.name, test.expected, output)
+		}
+	}
+}
@@ -0,0 +1,143 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"io/fs"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// maxProjectMarkerDepth is the maximum depth of sub directories,
+// which are scanned for project markers.
+const maxProjectMarkerDepth = 3
+
+// projectMarkers stores the names of files, which mark a project type.
+var projectMarkers = map[string]string{
+	"build.gradle":     "Java (Gradle)",
+	"build.gradle.kts": "Kotlin (Gradle)",
+	"Cargo.toml":       "Rust",
+	"CMakeLists.txt":   "C/C++ (CMake)",
+	"composer.json":    "PHP",
+	"Dockerfile":       "Docker",
+	"Gemfile":          "Ruby",
+	"go.mod":           "Go",
+	"mix.exs":          "Elixir",
+	"package.json":     "Node.js",
+	"Package.swift":    "Swift",
+	"Pipfile":          "Python",
+	"pom.xml":          "Java (Maven)",
+	"pubspec.yaml":     "Dart/Flutter",
+	"pyproject.toml":   "Python",
+	"requirements.txt": "Python",
+	"setup.py":         "Python",
+}
+
+// projectMarkerExtensions stores the extensions of files, which mark a project type.
+var projectMarkerExtensions = map[string]string{
+	".csproj": ".NET",
+	".fsproj": ".NET",
+	".sln":    ".NET",
+	".tf":     "Terraform",
+}
+
+// ProjectType stores a detected type of project.
+type ProjectType struct {
+	// Markers stores the relative paths of the files, which mark the project type.
+	Markers []string
+	// Name stores the name of the project type, like `Go`.
+	Name string
+}
+
+// DetectProjectTypes scans the working directory and its sub directories
+// for marker files, like `go.mod` or `package.json`, and returns the
+// project types sorted by name. Files ignored by git are skipped.
+func (app *AppContext) DetectProjectTypes() ([]*ProjectType, error) {
+	projectTypes := make([]*ProjectType, 0)
+
+	isGitIgnored, err := app.newGitIgnorePredicate()
+	if err != nil {
+		return projectTypes, err
+	}
+
+	typesByName := map[string]*ProjectType{}
+
+	err = filepath.WalkDir(app.WorkingDirectory, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(app.WorkingDirectory, path)
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			if relPath == "." {
+				return nil
+			}
+
+			name := d.Name()
+			if isGitIgnored(path, true) || name == "node_modules" || name == "vendor" || strings.HasPrefix(name, ".") ||
+				strings.Count(filepath.ToSlash(relPath), "/") >= maxProjectMarkerDepth {
+				return filepath.SkipDir
+			}
+
+			return nil
+		}
+
+		if isGitIgnored(path, false) {
+			return nil
+		}
+
+		typeName, ok := projectMarkers[d.Name()]
+		if !ok {
+			typeName, ok = projectMarkerExtensions[strings.ToLower(filepath.Ext(d.Name()))]
+		}
+		if !ok {
+			return nil
+		}
+
+		projectType, ok := typesByName[typeName]
+		if !ok {
+			projectType = &ProjectType{
+				Markers: []string{},
+				Name:    typeName,
+			}
+
+			typesByName[typeName] = projectType
+			projectTypes = append(projectTypes, projectType)
+		}
+
+		projectType.Markers = append(projectType.Markers, filepath.ToSlash(relPath))
+
+		return nil
+	})
+
+	sort.Slice(projectTypes, func(x, y int) bool {
+		return projectTypes[x].Name < projectTypes[y].Name
+	})
+
+	return projectTypes, err
+}
@@ -0,0 +1,69 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDetectProjectTypes(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	for _, name := range []string{
+		"go.mod",
+		"web/package.json",
+		"web/node_modules/left-pad/package.json",
+		"api/requirements.txt",
+		"api/pyproject.toml",
+		"infra/main.TF",
+		"a/b/c/Cargo.toml",
+		"a/b/c/d/Gemfile",
+		".cache/Gemfile",
+		"README.md",
+	} {
+		writeTestFile(t, app, name, "")
+	}
+
+	projectTypes, err := app.DetectProjectTypes()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	found := make([]string, 0)
+	for _, pt := range projectTypes {
+		found = append(found, fmt.Sprintf("%s: %s", pt.Name, strings.Join(pt.Markers, ", ")))
+	}
+
+	expected := []string{
+		"Go: go.mod",
+		"Node.js: web/package.json",
+		"Python: api/pyproject.toml, api/requirements.txt",
+		"Rust: a/b/c/Cargo.toml",
+		"Terraform: infra/main.TF",
+	}
+	if strings.Join(found, "\n") != strings.Join(expected, "\n") {
+		t.Errorf("expected %q, got %q", expected, found)
+	}
+}
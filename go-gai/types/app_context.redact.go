@@ -0,0 +1,163 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// redactedText is the replacement of redacted secrets.
+const redactedText = "[REDACTED]"
+
+// defaultRedactPatterns contains the patterns of secrets, which are always
+// redacted with `--redact`. If a pattern has a group with the name `secret`,
+// only this group is replaced.
+var defaultRedactPatterns = []*regexp.Regexp{
+	// private key blocks, like of RSA or SSH keys
+	regexp.MustCompile(`(?s)-----BEGIN [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----.*?-----END [A-Z0-9 ]*PRIVATE KEY( BLOCK)?-----`),
+	// AWS access key IDs
+	regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	// bearer tokens, like in HTTP headers
+	regexp.MustCompile(`(?i)\bbearer\s+(?P<secret>[A-Za-z0-9\-._~+/]{8,}=*)`),
+	// API keys of known providers, like OpenAI or GitHub
+	regexp.MustCompile(`\bsk-[A-Za-z0-9_\-]{20,}`),
+	regexp.MustCompile(`\bgh[pousr]_[A-Za-z0-9]{36,}\b`),
+	// assignments of secrets, like in .env files
+	regexp.MustCompile(`(?im)^\s*(?:export\s+)?[A-Z0-9_.\-]*(?:KEY|SECRET|TOKEN|PASSWORD|PASSWD|PWD|CREDENTIALS?)[A-Z0-9_.\-]*\s*[=:]\s*(?P<secret>[^\s#].*?)\s*$`),
+}
+
+// GetRedact returns `true` if secrets in files and STDIN should
+// be redacted, by `--redact` or `GAI_REDACT`.
+func (app *AppContext) GetRedact() (bool, error) {
+	if app.Redact {
+		return true, nil // flag
+	}
+
+	GAI_REDACT := strings.TrimSpace(app.GetEnv("GAI_REDACT")) // now try env variable
+	if GAI_REDACT != "" {
+		return strconv.ParseBool(GAI_REDACT)
+	}
+
+	return false, nil
+}
+
+// GetRedactPatterns returns the default patterns of secrets and the
+// custom ones of the file of `GAI_REDACT_PATTERNS`, which contains
+// one regular expression per line. Empty lines and lines starting
+// with `#` are ignored.
+func (app *AppContext) GetRedactPatterns() ([]*regexp.Regexp, error) {
+	app.redactPatternsOnce.Do(func() {
+		patterns := make([]*regexp.Regexp, 0)
+		patterns = append(patterns, defaultRedactPatterns...)
+
+		GAI_REDACT_PATTERNS := strings.TrimSpace(app.GetEnv("GAI_REDACT_PATTERNS"))
+		if GAI_REDACT_PATTERNS != "" {
+			patternFile := app.GetFullPath(GAI_REDACT_PATTERNS)
+
+			app.Dbgf("Reading redact patterns from '%v' ...%v", patternFile, app.EOL)
+
+			data, err := os.ReadFile(patternFile)
+			if err != nil {
+				app.redactPatternsErr = err
+				return
+			}
+
+			lineNr := 0
+			scanner := bufio.NewScanner(bytes.NewReader(data))
+			for scanner.Scan() {
+				lineNr++
+
+				line := strings.TrimSpace(scanner.Text())
+				if line == "" || strings.HasPrefix(line, "#") {
+					continue
+				}
+
+				re, err := regexp.Compile(line)
+				if err != nil {
+					app.redactPatternsErr = NewTypedError(ErrorTypeUsage, fmt.Errorf("invalid pattern in line %v of '%v': %w", lineNr, patternFile, err))
+					return
+				}
+
+				patterns = append(patterns, re)
+			}
+		}
+
+		app.redactPatterns = patterns
+	})
+
+	return app.redactPatterns, app.redactPatternsErr
+}
+
+// RedactText replaces the secrets in `text` of the source `name`, like
+// a file or STDIN, with `[REDACTED]`, if redaction is enabled.
+func (app *AppContext) RedactText(text string, name string) (string, error) {
+	redact, err := app.GetRedact()
+	if err != nil || !redact {
+		return text, err
+	}
+
+	patterns, err := app.GetRedactPatterns()
+	if err != nil {
+		return text, err
+	}
+
+	count := 0
+	for _, re := range patterns {
+		secretIndex := re.SubexpIndex("secret")
+
+		var result strings.Builder
+		last := 0
+		for _, match := range re.FindAllStringSubmatchIndex(text, -1) {
+			start, end := match[0], match[1]
+			if secretIndex > -1 && match[2*secretIndex] > -1 {
+				// replace only the secret of the match
+				start, end = match[2*secretIndex], match[2*secretIndex+1]
+			}
+
+			if text[start:end] == redactedText {
+				continue // already redacted
+			}
+
+			result.WriteString(text[last:start])
+			result.WriteString(redactedText)
+			last = end
+
+			count++
+		}
+		result.WriteString(text[last:])
+
+		text = result.String()
+	}
+
+	if count > 0 {
+		app.Dbgf("Redacted %v secret(s) of '%v'%v", count, name, app.EOL)
+	}
+
+	return text, nil
+}
@@ -0,0 +1,272 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+// SchemaPreset stores information about a named response
+// schema inside the directory of `GetSchemasDir()`.
+type SchemaPreset struct {
+	// Name is the name of the schema, which is the file name without `.json`.
+	Name string `json:"name"`
+	// Path is the full path of the file.
+	Path string `json:"path"`
+}
+
+// ChatAndValidate does a chat with `AI` and validates the answer against the
+// response schema in `opts`, if defined. If the answer does not match, the
+// request is repeated once with the list of violations.
+// No request is sent, if the costs of previous ones reached the budget.
+func (app *AppContext) ChatAndValidate(ctx *ChatContext, msg string, opts ...AIClientChatOptions) (string, ConversationRepositoryConversation, error) {
+	type chatResult struct {
+		answer       string
+		conversation ConversationRepositoryConversation
+	}
+
+	var schema *map[string]any
+	files := make([]io.Reader, 0)
+	for _, o := range opts {
+		if o.Files != nil {
+			files = append(files, *o.Files...)
+		}
+		if o.ResponseSchema != nil {
+			schema = o.ResponseSchema
+		}
+	}
+
+	result, err := sendAndValidate(app, msg, schema, files, func(m string) (chatResult, string, error) {
+		answer, conversation, err := app.AI.Chat(ctx, m, opts...)
+		app.trackUsageOf(conversation, err)
+
+		return chatResult{answer: answer, conversation: conversation}, answer, err
+	})
+
+	return result.answer, result.conversation, err
+}
+
+func (app *AppContext) getSchemaRetryMessage(msg string, violations []string) string {
+	return fmt.Sprintf(`%s
+
+Your previous answer did not match the required JSON schema:
+- %s
+Answer again and follow the schema exactly.`,
+		msg,
+		strings.Join(violations, fmt.Sprintf("%s- ", app.EOL)),
+	)
+}
+
+// PromptAndValidate sends a prompt to `AI` and validates the answer against the
+// response schema in `opts`, if defined. If the answer does not match, the
+// request is repeated once with the list of violations.
+// No request is sent, if the costs of previous ones reached the budget.
+func (app *AppContext) PromptAndValidate(prompt string, opts ...AIClientPromptOptions) (AIClientPromptResponse, error) {
+	var schema *map[string]any
+	files := make([]io.Reader, 0)
+	for _, o := range opts {
+		if o.Files != nil {
+			files = append(files, *o.Files...)
+		}
+		if o.ResponseSchema != nil {
+			schema = o.ResponseSchema
+		}
+	}
+
+	return sendAndValidate(app, prompt, schema, files, func(m string) (AIClientPromptResponse, string, error) {
+		response, err := app.AI.Prompt(m, opts...)
+		app.trackUsage(response.Usage, err)
+
+		return response, response.Content, err
+	})
+}
+
+// sendAndValidate invokes `send` with `msg`, which returns a result and its
+// answer, and validates the answer against `schema`, if defined. If the answer
+// does not match, `files` are rewound and `send` is invoked once more with the
+// list of violations. Each request is checked against the budget and timed.
+func sendAndValidate[T any](app *AppContext, msg string, schema *map[string]any, files []io.Reader, send func(msg string) (T, string, error)) (T, error) {
+	var result T
+
+	doRequest := func(m string) (T, string, error) {
+		err := app.CheckSpentBudget()
+		if err != nil {
+			return result, "", err
+		}
+
+		defer app.StartTiming(TimingPhaseRequest)()
+
+		return send(m)
+	}
+
+	result, answer, err := doRequest(msg)
+	if err != nil || schema == nil || app.NoValidate {
+		return result, err
+	}
+
+	violations, err := utils.ValidateJSONSchema(*schema, answer)
+	if err != nil || len(violations) == 0 {
+		return result, err
+	}
+
+	app.Dbgf("Answer does not match response schema, trying again ...%v", app.EOL)
+
+	// files have been read by the first request
+	for _, f := range files {
+		if seeker, ok := f.(io.Seeker); ok {
+			_, err := seeker.Seek(0, io.SeekStart)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	result, answer, err = doRequest(app.getSchemaRetryMessage(msg, violations))
+	if err != nil {
+		return result, err
+	}
+
+	return result, app.ValidateAIAnswer(answer, schema)
+}
+
+// ValidateAIAnswer checks if `answer` matches `schema` and returns an error
+// with the list of violations, if not.
+func (app *AppContext) ValidateAIAnswer(answer string, schema *map[string]any) error {
+	if schema == nil || app.NoValidate {
+		return nil
+	}
+
+	violations, err := utils.ValidateJSONSchema(*schema, answer)
+	if err != nil {
+		return err
+	}
+	if len(violations) > 0 {
+		return NewTypedError(ErrorTypeSchemaValidation, fmt.Errorf(
+			"answer does not match response schema:%s- %s",
+			app.EOL,
+			strings.Join(violations, fmt.Sprintf("%s- ", app.EOL)),
+		))
+	}
+
+	return nil
+}
+
+// GetSchemaPreset returns the named response schema `name` inside
+// `GetSchemasDir()`, whose file extension is matched case-insensitively,
+// or `nil` if there is no such preset.
+func (app *AppContext) GetSchemaPreset(name string) (*SchemaPreset, error) {
+	presets, err := app.GetSchemaPresets()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range presets {
+		if p.Name == name {
+			return &p, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// GetSchemaPresets returns the named response schemas inside
+// `GetSchemasDir()`, sorted by name. Files, whose names are no
+// valid schema names, are skipped.
+func (app *AppContext) GetSchemaPresets() ([]SchemaPreset, error) {
+	presets := make([]SchemaPreset, 0)
+
+	schemasDir, err := app.GetSchemasDir()
+	if err != nil {
+		return presets, err
+	}
+
+	entries, err := os.ReadDir(schemasDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return presets, nil // no presets yet
+		}
+		return presets, err
+	}
+
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+
+		name := entry.Name()
+		if !strings.EqualFold(filepath.Ext(name), ".json") {
+			continue
+		}
+
+		presetName := strings.TrimSuffix(name, filepath.Ext(name))
+		if utils.SanitizeJSONSchemaName(presetName) != presetName {
+			continue // cannot be used with `--schema-name`
+		}
+
+		presets = append(presets, SchemaPreset{
+			Name: presetName,
+			Path: filepath.Join(schemasDir, name),
+		})
+	}
+
+	sort.Slice(presets, func(i, j int) bool {
+		return presets[i].Name < presets[j].Name
+	})
+
+	return presets, nil
+}
+
+// GetSchemasDir returns the path of the directory with the
+// named response schemas, which is `schemas` inside the app directory.
+func (app *AppContext) GetSchemasDir() (string, error) {
+	appDir, err := app.EnsureAppDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(appDir, "schemas"), nil
+}
+
+// loadResponseSchemaFile loads a response schema from the JSON file `schemaFile`.
+func loadResponseSchemaFile(schemaFile string) (*map[string]any, error) {
+	data, err := os.ReadFile(schemaFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var schema map[string]any
+	err = json.Unmarshal(data, &schema)
+	if err != nil {
+		return nil, err
+	}
+
+	return &schema, nil
+}
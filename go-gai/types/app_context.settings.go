@@ -31,9 +31,21 @@ import (
 	"github.com/goccy/go-yaml"
 )
 
-func (app *AppContext) loadRCFile() {
-	rcFile := &GAIRCFile{}
+// GetRCFilePath returns the path of the existing `.gairc` file of the
+// working directory or the path of a new `.gairc.yaml` file, if there is none.
+func (app *AppContext) GetRCFilePath() (string, error) {
+	existingRCFiles, err := app.findRCFiles()
+	if err != nil {
+		return "", err
+	}
+
+	if len(existingRCFiles) == 1 {
+		return existingRCFiles[0], nil
+	}
+	return filepath.Join(app.WorkingDirectory, ".gairc.yaml"), nil
+}
 
+func (app *AppContext) findRCFiles() ([]string, error) {
 	possibleRCFiles := make([]string, 0)
 	possibleRCFiles = append(possibleRCFiles, filepath.Join(app.WorkingDirectory, ".gairc"))
 	possibleRCFiles = append(possibleRCFiles, filepath.Join(app.WorkingDirectory, ".gairc.yaml"))
@@ -47,7 +59,7 @@ func (app *AppContext) loadRCFile() {
 				continue
 			}
 
-			app.CheckIfError(err)
+			return existingRCFiles, err
 		}
 
 		if !stat.IsDir() {
@@ -58,8 +70,19 @@ func (app *AppContext) loadRCFile() {
 	if len(existingRCFiles) > 1 {
 		// 0 or 1, not more
 
-		app.CheckIfError(fmt.Errorf("there are more than 1 possible files: %s", strings.Join(existingRCFiles, ",")))
-	} else if len(existingRCFiles) == 1 {
+		return existingRCFiles, fmt.Errorf("there are more than 1 possible files: %s", strings.Join(existingRCFiles, ","))
+	}
+
+	return existingRCFiles, nil
+}
+
+func (app *AppContext) loadRCFile() {
+	rcFile := &GAIRCFile{}
+
+	existingRCFiles, err := app.findRCFiles()
+	app.CheckIfError(err)
+
+	if len(existingRCFiles) == 1 {
 		data, err := os.ReadFile(existingRCFiles[0])
 		app.CheckIfError(err)
 
@@ -69,3 +92,67 @@ func (app *AppContext) loadRCFile() {
 
 	app.RCFile = rcFile
 }
+
+// SetRCFileValue sets `value` at the path of `keys`, like `defaults`, `flags`
+// and `model`, in the `.gairc` file of the working directory and keeps the
+// order and comments of all other settings. The file is created, if it does not exist.
+func (app *AppContext) SetRCFileValue(value any, keys ...string) error {
+	rcFilePath, err := app.GetRCFilePath()
+	if err != nil {
+		return err
+	}
+
+	var settings yaml.MapSlice
+	comments := yaml.CommentMap{}
+
+	data, err := os.ReadFile(rcFilePath)
+	if err == nil {
+		err = yaml.UnmarshalWithOptions(data, &settings, yaml.UseOrderedMap(), yaml.CommentToMap(comments))
+		if err != nil {
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+
+	settings = setMapSliceValue(settings, value, keys...)
+
+	data, err = yaml.MarshalWithOptions(settings, yaml.WithComment(comments))
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(rcFilePath, data, 0644)
+	if err != nil {
+		return err
+	}
+
+	app.loadRCFile()
+	return nil
+}
+
+func setMapSliceValue(m yaml.MapSlice, value any, keys ...string) yaml.MapSlice {
+	if len(keys) == 0 {
+		return m
+	}
+
+	key := keys[0]
+	for i, item := range m {
+		if fmt.Sprint(item.Key) != key {
+			continue
+		}
+
+		if len(keys) == 1 {
+			m[i].Value = value
+		} else {
+			child, _ := item.Value.(yaml.MapSlice) // overwrite other values
+			m[i].Value = setMapSliceValue(child, value, keys[1:]...)
+		}
+		return m
+	}
+
+	if len(keys) == 1 {
+		return append(m, yaml.MapItem{Key: key, Value: value})
+	}
+	return append(m, yaml.MapItem{Key: key, Value: setMapSliceValue(nil, value, keys[1:]...)})
+}
@@ -0,0 +1,100 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSetRCFileValue(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	rcFilePath := filepath.Join(app.WorkingDirectory, ".gairc")
+	err := os.WriteFile(rcFilePath, []byte(`# settings of the project
+defaults:
+  flags:
+    # always send these files
+    files:
+      - README.md
+    model: openai:gpt-4o
+`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = app.SetRCFileValue("ollama:llama3.1", "defaults", "flags", "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = app.SetRCFileValue(int64(5), "defaults", "flags", "history-limit")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(rcFilePath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `# settings of the project
+defaults:
+  flags:
+    # always send these files
+    files:
+    - README.md
+    model: ollama:llama3.1
+    history-limit: 5
+`
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+
+	// file has been reloaded
+	if app.RCFile.Defaults.Flags.Model != "ollama:llama3.1" {
+		t.Errorf("expected model ollama:llama3.1, got %q", app.RCFile.Defaults.Flags.Model)
+	}
+	if limit := app.RCFile.Defaults.Flags.HistoryLimit; limit == nil || *limit != 5 {
+		t.Errorf("expected history limit 5, got %v", limit)
+	}
+}
+
+func TestSetRCFileValueCreatesFile(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	err := app.SetRCFileValue("openai:gpt-4o-mini", "defaults", "flags", "model")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(app.WorkingDirectory, ".gairc.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "defaults:\n  flags:\n    model: openai:gpt-4o-mini\n"
+	if string(data) != expected {
+		t.Errorf("expected %q, got %q", expected, data)
+	}
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"time"
+)
+
+// phases, which are measured with `StartTiming`
+const (
+	// TimingPhaseFiles is the phase of reading and extracting attached files.
+	TimingPhaseFiles = "files"
+	// TimingPhaseInput is the phase of gathering the input, like STDIN or context of commands.
+	TimingPhaseInput = "input"
+	// TimingPhaseNetwork is the phase of sending HTTP requests and waiting for their responses.
+	TimingPhaseNetwork = "network"
+	// TimingPhaseRender is the phase of rendering and writing the output.
+	TimingPhaseRender = "render"
+	// TimingPhaseRequest is the phase of complete AI requests, including files and network.
+	TimingPhaseRequest = "request"
+)
+
+// StartTiming starts measuring the duration of `phase`, if `VerboseTiming`
+// is `true`, and returns the function, which stops it. Durations of the
+// same phase are summed up.
+func (app *AppContext) StartTiming(phase string) func() {
+	if !app.VerboseTiming {
+		return func() {}
+	}
+
+	start := time.Now()
+
+	return func() {
+		duration := time.Since(start)
+
+		app.timingsMutex.Lock()
+		defer app.timingsMutex.Unlock()
+
+		if app.timings == nil {
+			app.timings = map[string]time.Duration{}
+		}
+		app.timings[phase] += duration
+	}
+}
+
+// OutputTimings writes the durations of all phases to STDERR,
+// if `VerboseTiming` is `true`.
+func (app *AppContext) OutputTimings() {
+	if !app.VerboseTiming {
+		return
+	}
+
+	app.timingsMutex.Lock()
+	defer app.timingsMutex.Unlock()
+
+	files := app.timings[TimingPhaseFiles]
+	network := app.timings[TimingPhaseNetwork]
+
+	// the rest of the requests is building their data
+	build := max(app.timings[TimingPhaseRequest]-files-network, 0)
+
+	phases := []struct {
+		duration time.Duration
+		name     string
+	}{
+		{app.timings[TimingPhaseInput], "input gathering"},
+		{files, "file reading/extraction"},
+		{build, "request build"},
+		{network, "network round-trip"},
+		{app.timings[TimingPhaseRender], "rendering"},
+	}
+
+	for _, p := range phases {
+		app.WriteErrorString(fmt.Sprintf("%-24s %v%s", p.name+":", p.duration.Round(time.Microsecond), app.EOL))
+	}
+}
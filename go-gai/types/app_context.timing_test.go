@@ -0,0 +1,84 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStartTiming(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.VerboseTiming = true
+
+	for range 2 {
+		stop := app.StartTiming(TimingPhaseInput)
+		time.Sleep(5 * time.Millisecond)
+		stop()
+	}
+
+	if d := app.timings[TimingPhaseInput]; d < 10*time.Millisecond {
+		t.Errorf("expected durations of the same phase to be summed up, got %v", d)
+	}
+}
+
+func TestOutputTimingsDisabled(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	app.StartTiming(TimingPhaseInput)()
+	app.OutputTimings()
+
+	if app.timings != nil {
+		t.Errorf("expected no timings, got %v", app.timings)
+	}
+
+	output := readTestOutput(t, app.Stderr)
+	if output != "" {
+		t.Errorf("expected no output, got %q", output)
+	}
+}
+
+func TestOutputTimingsRequestBuild(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.VerboseTiming = true
+	app.timings = map[string]time.Duration{
+		TimingPhaseFiles:   2 * time.Second,
+		TimingPhaseNetwork: 3 * time.Second,
+		TimingPhaseRequest: 6 * time.Second,
+	}
+
+	app.OutputTimings()
+
+	output := readTestOutput(t, app.Stderr)
+	expected := []string{
+		"input gathering:         0s\n",
+		"file reading/extraction: 2s\n",
+		"request build:           1s\n",
+		"network round-trip:      3s\n",
+		"rendering:               0s\n",
+	}
+	if output != strings.Join(expected, "") {
+		t.Errorf("expected %q, got %q", strings.Join(expected, ""), output)
+	}
+}
@@ -0,0 +1,130 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxShellToolOutput is the maximum number of bytes of the output
+// of the `shell` tool, which are sent back to the AI.
+const maxShellToolOutput = 65536
+
+// Tool is a built-in tool, which can be provided to the AI.
+type Tool struct {
+	// Definition stores the definition, which is submitted to the AI.
+	Definition ToolDefinition
+	// Handler executes a call of the tool.
+	Handler ToolHandler
+}
+
+// ToolHandler executes a call of a tool with its JSON encoded `arguments`
+// and returns the output, which is sent back to the AI.
+type ToolHandler func(app *AppContext, arguments string) (string, error)
+
+var builtInTools = map[string]*Tool{
+	"shell": {
+		Definition: ToolDefinition{
+			Description: "Runs a command with the shell of the system in the current working directory and returns its exit code and output.",
+			Name:        "shell",
+			Parameters: map[string]any{
+				"type": "object",
+				"properties": map[string]any{
+					"command": map[string]any{
+						"type":        "string",
+						"description": "The command to run.",
+					},
+				},
+				"required":             []string{"command"},
+				"additionalProperties": false,
+			},
+		},
+		Handler: runShellTool,
+	},
+}
+
+// GetAllowedTools returns the built-in tools, which are defined by
+// `--allow-tools` or `GAI_ALLOW_TOOLS`.
+func (app *AppContext) GetAllowedTools() ([]*Tool, error) {
+	names := app.AllowTools
+	if len(names) == 0 {
+		names = strings.Split(app.GetEnv("GAI_ALLOW_TOOLS"), ",")
+	}
+
+	tools := make([]*Tool, 0)
+	for _, n := range names {
+		name := strings.TrimSpace(strings.ToLower(n))
+		if name == "" {
+			continue
+		}
+
+		tool, ok := builtInTools[name]
+		if !ok {
+			return tools, NewTypedError(ErrorTypeUsage, fmt.Errorf("unknown tool '%s', use one of: %s", n, strings.Join(GetBuiltInToolNames(), ", ")))
+		}
+		if !slices.Contains(tools, tool) {
+			tools = append(tools, tool)
+		}
+	}
+
+	return tools, nil
+}
+
+// GetBuiltInToolNames returns the sorted names of all built-in tools.
+func GetBuiltInToolNames() []string {
+	names := make([]string, 0)
+	for name := range builtInTools {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func runShellTool(app *AppContext, arguments string) (string, error) {
+	var args struct {
+		Command string `json:"command"`
+	}
+	err := json.Unmarshal([]byte(arguments), &args)
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(args.Command) == "" {
+		return "", fmt.Errorf("no command defined")
+	}
+
+	result, err := app.RunShellCommand(args.Command, maxShellToolOutput)
+	if err != nil {
+		return "", err
+	}
+
+	output := fmt.Sprintf("exit code: %d%s%s", result.ExitCode, app.EOL, result.Output)
+	if result.Truncated {
+		output += fmt.Sprintf("%s[output truncated after %d bytes]", app.EOL, maxShellToolOutput)
+	}
+
+	return output, nil
+}
@@ -0,0 +1,111 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestGetAllowedTools(t *testing.T) {
+	tests := []struct {
+		flag     []string
+		env      string
+		expected []string
+	}{
+		{nil, "", []string{}},
+		{[]string{"shell"}, "", []string{"shell"}},
+		{nil, " Shell ,", []string{"shell"}},
+		{[]string{"shell", "SHELL"}, "", []string{"shell"}},
+		{[]string{""}, "shell", []string{}},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_ALLOW_TOOLS": test.env,
+		})
+		app.AllowTools = test.flag
+
+		tools, err := app.GetAllowedTools()
+		if err != nil {
+			t.Fatalf("%v/%q: %v", test.flag, test.env, err)
+		}
+
+		names := make([]string, 0)
+		for _, tool := range tools {
+			names = append(names, tool.Definition.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(test.expected, ",") {
+			t.Errorf("%v/%q: expected tools %v, got %v", test.flag, test.env, test.expected, names)
+		}
+	}
+
+	app := newTestApp(t, nil)
+	app.AllowTools = []string{"shell", "read_file"}
+
+	_, err := app.GetAllowedTools()
+	if GetErrorType(err) != ErrorTypeUsage || !strings.Contains(err.Error(), "unknown tool 'read_file', use one of: shell") {
+		t.Errorf("expected usage error about unknown tool, got %v", err)
+	}
+}
+
+func TestRunShellTool(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("test uses POSIX shell")
+	}
+
+	tests := []struct {
+		arguments string
+		expected  string
+	}{
+		{`{"command":"echo hello"}`, "exit code: 0\nhello\n"},
+		{`{"command":"echo failed >&2; exit 3"}`, "exit code: 3\nfailed\n"},
+		{
+			fmt.Sprintf(`{"command":"yes a | head -c %d"}`, maxShellToolOutput+10),
+			fmt.Sprintf("exit code: 0\n%s\n[output truncated after %d bytes]", strings.Repeat("a\n", maxShellToolOutput/2), maxShellToolOutput),
+		},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+
+		output, err := runShellTool(app, test.arguments)
+		if err != nil {
+			t.Fatalf("%v: %v", test.arguments, err)
+		}
+		if output != test.expected {
+			t.Errorf("%v: expected output of %d bytes, got %d bytes: %.100q", test.arguments, len(test.expected), len(output), output)
+		}
+	}
+
+	for _, arguments := range []string{`{"command":" "}`, `{"cmd":"echo hello"}`, `no JSON`} {
+		app := newTestApp(t, nil)
+
+		_, err := runShellTool(app, arguments)
+		if err == nil {
+			t.Errorf("%v: expected an error", arguments)
+		}
+	}
+}
@@ -0,0 +1,101 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+)
+
+// OutputSessionCosts writes the accumulated token usage and costs of all
+// requests of this invocation to STDERR, if `ShowCost` is `true`.
+// The summary is written only once.
+func (app *AppContext) OutputSessionCosts() {
+	if !app.ShowCost {
+		return
+	}
+
+	app.sessionCostsPrinted.Do(func() {
+		app.usageMutex.Lock()
+		defer app.usageMutex.Unlock()
+
+		costs := "unknown (set GAI_PRICE_INPUT)"
+		prices, err := app.GetTokenPrices()
+		if err == nil && prices.Input != nil {
+			costs = fmt.Sprintf("%.6f", app.spentCosts)
+			if prices.Output == nil {
+				costs += " (without output, set GAI_PRICE_OUTPUT)"
+			}
+		}
+
+		app.WriteErrorString(fmt.Sprintf("Requests: %d%s", app.sessionRequests, app.EOL))
+		app.WriteErrorString(fmt.Sprintf("Token usage: %s%s", app.sessionUsage.String(), app.EOL))
+		if app.sessionUsageUnknown {
+			app.WriteErrorString(fmt.Sprintf("Token usage of some requests is unknown and not included%s", app.EOL))
+		}
+		app.WriteErrorString(fmt.Sprintf("Estimated costs: %s%s", costs, app.EOL))
+	})
+}
+
+// trackUsage adds `usage` of a request, which failed with `err`,
+// to the usage and costs of all requests of this invocation.
+func (app *AppContext) trackUsage(usage *AIUsage, err error) {
+	if err != nil && usage == nil {
+		return // no answer
+	}
+
+	app.usageMutex.Lock()
+	defer app.usageMutex.Unlock()
+
+	app.sessionRequests++
+
+	if usage == nil {
+		app.sessionUsageUnknown = true
+		return
+	}
+
+	app.sessionUsage.CompletionTokens += usage.CompletionTokens
+	app.sessionUsage.PromptTokens += usage.PromptTokens
+	app.sessionUsage.TotalTokens += usage.TotalTokens
+
+	prices, err := app.GetTokenPrices()
+	if err != nil || prices.Input == nil {
+		return
+	}
+
+	app.spentCosts += float64(usage.PromptTokens) / 1000 * *prices.Input
+	if prices.Output != nil {
+		app.spentCosts += float64(usage.CompletionTokens) / 1000 * *prices.Output
+	}
+
+	app.Dbgf("Spent costs: %.6f%v", app.spentCosts, app.EOL)
+}
+
+// trackUsageOf adds the usage of the last answer in `conversation`
+// of a chat request, which failed with `err`.
+func (app *AppContext) trackUsageOf(conversation ConversationRepositoryConversation, err error) {
+	if err != nil || len(conversation) == 0 {
+		return
+	}
+
+	app.trackUsage(conversation[len(conversation)-1].Usage, nil)
+}
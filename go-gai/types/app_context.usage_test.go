@@ -0,0 +1,72 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestOutputSessionCosts(t *testing.T) {
+	tests := []struct {
+		envVars  map[string]string
+		showCost bool
+		expected string
+	}{
+		{map[string]string{"GAI_PRICE_INPUT": "1"}, false, ""},
+		{
+			map[string]string{},
+			true,
+			"Requests: 3\nToken usage: prompt=3000 completion=1000 total=4000\nToken usage of some requests is unknown and not included\nEstimated costs: unknown (set GAI_PRICE_INPUT)\n",
+		},
+		{
+			map[string]string{"GAI_PRICE_INPUT": "0.5"},
+			true,
+			"Requests: 3\nToken usage: prompt=3000 completion=1000 total=4000\nToken usage of some requests is unknown and not included\nEstimated costs: 1.500000 (without output, set GAI_PRICE_OUTPUT)\n",
+		},
+		{
+			map[string]string{"GAI_PRICE_INPUT": "0.5", "GAI_PRICE_OUTPUT": "2"},
+			true,
+			"Requests: 3\nToken usage: prompt=3000 completion=1000 total=4000\nToken usage of some requests is unknown and not included\nEstimated costs: 3.500000\n",
+		},
+	}
+
+	for i, test := range tests {
+		app := newTestApp(t, test.envVars)
+		app.ShowCost = test.showCost
+
+		app.trackUsage(&AIUsage{CompletionTokens: 1000, PromptTokens: 1000, TotalTokens: 2000}, nil)
+		app.trackUsage(&AIUsage{PromptTokens: 2000, TotalTokens: 2000}, errors.New("invalid answer"))
+		app.trackUsage(nil, nil)
+		app.trackUsage(nil, errors.New("no answer")) // not counted
+
+		// written only once
+		app.OutputSessionCosts()
+		app.OutputSessionCosts()
+
+		output := readTestOutput(t, app.Stderr)
+		if output != test.expected {
+			t.Errorf("%d: expected %q, got %q", i, test.expected, output)
+		}
+	}
+}
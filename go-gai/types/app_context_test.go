@@ -0,0 +1,357 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"testing"
+)
+
+// newTestApp creates an `AppContext` with temporary home and working
+// directories, whose environment only contains `envVars`.
+// Outputs are written to temporary files, s. `readTestOutput`.
+func newTestApp(t *testing.T, envVars map[string]string) *AppContext {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	homeDir := filepath.Join(dir, "home")
+	workDir := filepath.Join(dir, "work")
+	for _, d := range []string{homeDir, workDir} {
+		err := os.MkdirAll(d, 0755)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app := &AppContext{
+		EOL:                 "\n",
+		HomeDirectory:       homeDir,
+		Log:                 log.New(io.Discard, "", 0),
+		SkipDefaultEnvFiles: true,
+		Stderr:              newTestFile(t, dir, "stderr"),
+		Stdin:               newTestFile(t, dir, "stdin"),
+		Stdout:              newTestFile(t, dir, "stdout"),
+		Temperature:         -1,
+		Verbosity:           -1,
+		WorkingDirectory:    workDir,
+	}
+
+	app.Init()
+
+	// do not depend on the environment of the test
+	app.EnvVars = map[string]string{}
+	for k, v := range envVars {
+		app.EnvVars[k] = v
+	}
+
+	return app
+}
+
+// runCheckIfError runs `CheckIfError` in a new process, because it exits,
+// and returns its STDERR and exit code.
+func runCheckIfError(t *testing.T, errorName string, errorFormat string) (string, int) {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^TestCheckIfError$")
+	cmd.Env = append(os.Environ(), "GAI_TEST_CHECK_IF_ERROR="+errorName, "GAI_TEST_ERROR_FORMAT="+errorFormat)
+
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stderr.String(), exitErr.ExitCode()
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return stderr.String(), 0
+}
+
+func newTestFile(t *testing.T, dir string, name string) *os.File {
+	t.Helper()
+
+	file, err := os.Create(filepath.Join(dir, name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		file.Close()
+	})
+
+	return file
+}
+
+// newTestServer starts an HTTP server with `handler` and
+// uses it as base URL of `app`.
+func newTestServer(t *testing.T, app *AppContext, handler http.HandlerFunc) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	app.BaseUrl = server.URL
+
+	return server
+}
+
+// readTestOutput returns everything, which has been written to `file`.
+func readTestOutput(t *testing.T, file *os.File) string {
+	t.Helper()
+
+	data, err := os.ReadFile(file.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
+
+// writeTestFile writes `content` to `name` inside the working directory
+// of `app` and returns the full path.
+func writeTestFile(t *testing.T, app *AppContext, name string, content string) string {
+	t.Helper()
+
+	fullPath := filepath.Join(app.WorkingDirectory, name)
+
+	err := os.MkdirAll(filepath.Dir(fullPath), 0755)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = os.WriteFile(fullPath, []byte(content), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return fullPath
+}
+
+// newTestPDF creates a PDF document with a single page,
+// which contains each of `lines` as text.
+func newTestPDF(lines ...string) []byte {
+	content := &bytes.Buffer{}
+	content.WriteString("BT /F1 12 Tf 72 720 Td 14 TL\n")
+	for _, l := range lines {
+		fmt.Fprintf(content, "(%s) Tj T*\n", l)
+	}
+	content.WriteString("ET")
+
+	objects := []string{
+		"<< /Type /Catalog /Pages 2 0 R >>",
+		"<< /Type /Pages /Kids [3 0 R] /Count 1 >>",
+		"<< /Type /Page /Parent 2 0 R /MediaBox [0 0 612 792] /Contents 4 0 R /Resources << /Font << /F1 5 0 R >> >> >>",
+		fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", content.Len(), content.String()),
+		"<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>",
+	}
+
+	pdf := &bytes.Buffer{}
+	pdf.WriteString("%PDF-1.4\n")
+
+	offsets := make([]int, 0, len(objects))
+	for i, o := range objects {
+		offsets = append(offsets, pdf.Len())
+		fmt.Fprintf(pdf, "%d 0 obj\n%s\nendobj\n", i+1, o)
+	}
+
+	xrefOffset := pdf.Len()
+	fmt.Fprintf(pdf, "xref\n0 %d\n0000000000 65535 f \n", len(objects)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(pdf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(pdf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(objects)+1, xrefOffset)
+
+	return pdf.Bytes()
+}
+
+func TestCheckIfError(t *testing.T) {
+	if errorName := os.Getenv("GAI_TEST_CHECK_IF_ERROR"); errorName != "" {
+		// inside process of `runCheckIfError`
+
+		app := newTestApp(t, map[string]string{
+			"GAI_ERROR_FORMAT": os.Getenv("GAI_TEST_ERROR_FORMAT"),
+		})
+		app.Stderr = os.Stderr
+
+		testErrors := map[string]error{
+			"dumped":  fmt.Errorf("prompt: %w", ErrRequestDumped),
+			"error":   errors.New("something \"failed\""),
+			"nil":     nil,
+			"typed":   NewTypedError(ErrorTypeUsage, errors.New("invalid flag")),
+			"wrapped": fmt.Errorf("chat: %w", NewTypedError(ErrorTypeRateLimit, errors.New("slow down"))),
+		}
+
+		app.CheckIfError(testErrors[errorName])
+		os.Exit(42) // no exit by `CheckIfError`
+	}
+
+	tests := []struct {
+		errorName        string
+		errorFormat      string
+		expectedOutput   string
+		expectedExitCode int
+	}{
+		{"nil", "json", "", 42},
+		{"dumped", "json", "", 0},
+		{"error", "", "something \"failed\"\n", 1},
+		{"error", "json", `{"error":{"message":"something \"failed\"","type":"error"}}` + "\n", 1},
+		{"typed", "text", "invalid flag\n", 2},
+		{"typed", "JSON", `{"error":{"message":"invalid flag","type":"usage"}}` + "\n", 2},
+		{"wrapped", "json", `{"error":{"message":"chat: slow down","type":"rate_limit"}}` + "\n", 4},
+	}
+
+	for _, test := range tests {
+		output, exitCode := runCheckIfError(t, test.errorName, test.errorFormat)
+
+		if output != test.expectedOutput {
+			t.Errorf("%v (%v): expected output %q, got %q", test.errorName, test.errorFormat, test.expectedOutput, output)
+		}
+		if exitCode != test.expectedExitCode {
+			t.Errorf("%v (%v): expected exit code %d, got %d", test.errorName, test.errorFormat, test.expectedExitCode, exitCode)
+		}
+	}
+}
+
+// newTestFileTree writes Go files with tests into the working directory of `app`.
+func newTestFileTree(t *testing.T, app *AppContext) {
+	t.Helper()
+
+	for _, name := range []string{"main.go", "main_test.go", "cmd/run.go", "cmd/run_test.go", "README.md"} {
+		writeTestFile(t, app, name, "")
+	}
+}
+
+// getTestRelPaths returns the sorted `files` relative to the working directory of `app`.
+func getTestRelPaths(t *testing.T, app *AppContext, files []string) []string {
+	t.Helper()
+
+	relPaths := make([]string, 0, len(files))
+	for _, f := range files {
+		relPath, err := filepath.Rel(app.WorkingDirectory, f)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		relPaths = append(relPaths, filepath.ToSlash(relPath))
+	}
+	sort.Strings(relPaths)
+
+	return relPaths
+}
+
+func TestGetFilesExclude(t *testing.T) {
+	tests := []struct {
+		name     string
+		rcFile   string
+		exclude  []string
+		expected []string
+	}{
+		{"no excludes", "", nil, []string{"cmd/run.go", "cmd/run_test.go", "main.go", "main_test.go"}},
+		{"flag", "", []string{"**/*_test.go"}, []string{"cmd/run.go", "main.go"}},
+		{"RC file", "defaults:\n  flags:\n    exclude:\n      - \"**/*_test.go\"\n", nil, []string{"cmd/run.go", "main.go"}},
+		{"flag instead of RC file", "defaults:\n  flags:\n    exclude:\n      - \"**/*_test.go\"\n", []string{"cmd/"}, []string{"main.go", "main_test.go"}},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+		newTestFileTree(t, app)
+
+		if test.rcFile != "" {
+			writeTestFile(t, app, ".gairc.yaml", test.rcFile)
+			app.loadRCFile()
+		}
+
+		app.FilePatterns = []string{"**/*.go"}
+		app.ExcludePatterns = test.exclude
+
+		files, err := app.GetFiles()
+		if err != nil {
+			t.Fatalf("%v: %v", test.name, err)
+		}
+
+		if relPaths := getTestRelPaths(t, app, files); !reflect.DeepEqual(relPaths, test.expected) {
+			t.Errorf("%v: expected files %v, got %v", test.name, test.expected, relPaths)
+		}
+	}
+}
+
+func TestNewFilePredicate(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    []string
+		patterns []string
+		exclude  []string
+		expected []string
+	}{
+		{"nothing defined", nil, nil, nil, []string{"README.md", "cmd/run.go", "cmd/run_test.go", "main.go", "main_test.go"}},
+		// like `commit` uses it
+		{"only excludes", nil, nil, []string{"**/*_test.go", "*.md"}, []string{"cmd/run.go", "main.go"}},
+		{"patterns and excludes", nil, []string{"cmd/"}, []string{"**/*_test.go"}, []string{"cmd/run.go"}},
+		{"explicit files", []string{"README.md"}, []string{"**/*.go"}, []string{"**/*_test.go"}, []string{"README.md", "cmd/run.go", "main.go"}},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, nil)
+
+		app.Files = test.files
+		app.FilePatterns = test.patterns
+		app.ExcludePatterns = test.exclude
+
+		isMatching := app.NewFilePredicate()
+
+		matches := make([]string, 0)
+		for _, relPath := range []string{"README.md", "cmd/run.go", "cmd/run_test.go", "main.go", "main_test.go"} {
+			f := relPath
+			if relPath == "main_test.go" {
+				f = filepath.Join(app.WorkingDirectory, relPath) // absolute paths are supported, too
+			}
+
+			ok, err := isMatching(f)
+			if err != nil {
+				t.Fatalf("%v: %v", test.name, err)
+			}
+			if ok {
+				matches = append(matches, relPath)
+			}
+		}
+
+		if !reflect.DeepEqual(matches, test.expected) {
+			t.Errorf("%v: expected files %v, got %v", test.name, test.expected, matches)
+		}
+	}
+}
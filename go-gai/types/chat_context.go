@@ -23,10 +23,13 @@
 package types
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
 	"strings"
 
 	"github.com/goccy/go-yaml"
@@ -45,6 +48,17 @@ type AppendSimplePseudoUserConversationOptions struct {
 	Time *string
 }
 
+// AppendTextFilesAsPseudoConversationOptions stores custom options for
+// `AppendTextFilesAsPseudoConversation` of `ChatContext`
+type AppendTextFilesAsPseudoConversationOptions struct {
+	// ChunkStrategy stores the strategy how to split files,
+	// which are larger than `MaxFileTokens`.
+	ChunkStrategy *utils.ChunkStrategy
+	// MaxFileTokens stores the maximum number of tokens of a single message,
+	// files with more tokens are submitted in parts.
+	MaxFileTokens *int
+}
+
 // ChatContext handles chat context
 type ChatContext struct {
 	// App stores the underlying application context.
@@ -52,8 +66,28 @@ type ChatContext struct {
 	// Conversations stores the repository with all conversations usually grouped by directory.
 	Conversations  *ConversationRepository
 	currentContext string
+	// submittedFiles stores paths of files, which have already been submitted
+	// to the current conversation, by the SHA-256 hash of their content
+	submittedFiles map[[sha256.Size]byte]string
+	// pseudoAnswerCount stores the number of answers in pseudo conversations
+	pseudoAnswerCount int
+	// startedEmpty is `true` if the context has not been loaded from the conversation file
+	startedEmpty bool
 }
 
+// isSystemMessageRole returns `true` if `role` is the one of system messages,
+// which is `systemRole` or one of the known `system` and `developer` roles.
+func isSystemMessageRole(role string, systemRole string) bool {
+	return role == systemRole || role == "system" || role == "developer"
+}
+
+// messageTokenOverhead is the estimated number of tokens, which each message
+// needs in addition to its content.
+const messageTokenOverhead = 4
+
+// pseudoAnswerInstructionRegex matches lines with instructions to answer with 'OK'.
+var pseudoAnswerInstructionRegex = regexp.MustCompile(`(?m)^Answer with 'OK'.*$`)
+
 // UpdateConversationWith stores options for `UpdateConversationWith` method.
 type UpdateConversationWithOptions struct {
 	// NoSave is `true` if conversion file should not be updated.
@@ -75,7 +109,14 @@ func (ctx *ChatContext) AppendConversationItem(item *ConversationRepositoryConve
 func (ctx *ChatContext) AppendSimplePseudoUserConversation(um string, opts ...AppendSimplePseudoUserConversationOptions) []*ConversationRepositoryConversationItem {
 	app := ctx.App
 
-	answer := "OK"
+	pseudoMode, err := app.GetPseudoMode()
+	app.CheckIfError(err)
+
+	answers := app.GetPseudoAnswers()
+
+	answer := answers[ctx.pseudoAnswerCount%len(answers)]
+	ctx.pseudoAnswerCount++
+
 	model := app.AI.ChatModel()
 	time := app.GetISOTime()
 	for _, o := range opts {
@@ -90,10 +131,31 @@ func (ctx *ChatContext) AppendSimplePseudoUserConversation(um string, opts ...Ap
 		}
 	}
 
-	conversationContext := ctx.ensureConversation()
+	// instructions of the callers expect the default answer
+	if pseudoMode == "single" {
+		um = strings.TrimSpace(pseudoAnswerInstructionRegex.ReplaceAllString(um, ""))
+	} else if answer != "OK" {
+		um = strings.ReplaceAll(um, "Answer with 'OK'", fmt.Sprintf("Answer with '%s'", answer))
+	}
 
 	newItems := make([]*ConversationRepositoryConversationItem, 0)
 
+	if pseudoMode == "single" {
+		// no answers, so continue last user message, if possible
+		conversationContext := ctx.ensureConversation()
+		conversation := conversationContext.Conversation
+
+		if len(conversation) > 0 && conversation[len(conversation)-1].Role == "user" {
+			lastUserMessage := conversation[len(conversation)-1]
+			lastUserMessage.Contents = append(lastUserMessage.Contents, &ConversationRepositoryConversationItemContentItem{
+				Content: um,
+				Type:    "text",
+			})
+
+			return append(newItems, lastUserMessage)
+		}
+	}
+
 	// user message
 	{
 		userMessage := &ConversationRepositoryConversationItem{
@@ -112,7 +174,7 @@ func (ctx *ChatContext) AppendSimplePseudoUserConversation(um string, opts ...Ap
 	}
 
 	// simulate answer
-	{
+	if pseudoMode != "single" {
 		assistantMessage := &ConversationRepositoryConversationItem{
 			Contents: make(ConversationRepositoryConversationItemContents, 0),
 			Model:    model,
@@ -127,16 +189,30 @@ func (ctx *ChatContext) AppendSimplePseudoUserConversation(um string, opts ...Ap
 		newItems = append(newItems, ctx.AppendConversationItem(assistantMessage))
 	}
 
-	conversationContext.Conversation = append(conversationContext.Conversation, newItems...)
-
 	return newItems
 }
 
 // AppendTextFilesAsPseudoConversation reads content of `files` and add
 // pseudo conversation entries for each of them without updating the conversation file.
-func (ctx *ChatContext) AppendTextFilesAsPseudoConversation(files []string) ([]string, []*ConversationRepositoryConversationItem, error) {
+func (ctx *ChatContext) AppendTextFilesAsPseudoConversation(files []string, opts ...AppendTextFilesAsPseudoConversationOptions) ([]string, []*ConversationRepositoryConversationItem, error) {
 	app := ctx.App
 
+	chunkStrategy := utils.ChunkStrategyAuto
+	maxFileTokens := 0
+	for _, o := range opts {
+		if o.ChunkStrategy != nil {
+			chunkStrategy = *o.ChunkStrategy
+		}
+		if o.MaxFileTokens != nil {
+			maxFileTokens = *o.MaxFileTokens
+		}
+	}
+
+	var tokenizer *utils.TextTokenizer
+	if maxFileTokens > 0 {
+		tokenizer = utils.NewTextTokenizer(app.AI.ChatModel())
+	}
+
 	newItems := make([]*ConversationRepositoryConversationItem, 0)
 	relPaths := make([]string, 0)
 
@@ -156,21 +232,59 @@ func (ctx *ChatContext) AppendTextFilesAsPseudoConversation(files []string) ([]s
 			return relPaths, newItems, err
 		}
 
+		if ctx.submittedFiles == nil {
+			ctx.submittedFiles = map[[sha256.Size]byte]string{}
+		}
+
+		hash := sha256.Sum256(data)
+		if sameRelPath, ok := ctx.submittedFiles[hash]; ok {
+			// do not submit same content twice
+
+			app.Dbgf("File '%v' has same content as '%v'%v", relPath, sameRelPath, app.EOL)
+
+			added := ctx.AppendSimplePseudoUserConversation(fmt.Sprintf(
+				`The file with the path '%s' has exactly the same content as the file with the path '%s'.
+Answer with 'OK' if you analyzed it.`,
+				relPath,
+				sameRelPath,
+			))
+
+			newItems = append(newItems, added...)
+			relPaths = append(relPaths, relPath)
+			continue
+		}
+		ctx.submittedFiles[hash] = relPath
+
 		strData, err := utils.EnsurePlainText(data)
 		if err != nil {
 			return relPaths, newItems, err
 		}
 
-		jsonData, err := json.Marshal(strData)
+		strData, err = app.RedactText(strData, relPath)
 		if err != nil {
 			return relPaths, newItems, err
 		}
 
-		// user message with file and content
-		{
-			messageSuffix := ""
-			if i > 0 {
-				messageSuffix = " and integrate it with the context of the other files"
+		chunks := []string{strData}
+		if tokenizer != nil {
+			chunks = utils.Chunk(strData, utils.ChunkOptions{
+				MaxSize:   maxFileTokens,
+				Name:      relPath,
+				Strategy:  chunkStrategy,
+				Tokenizer: tokenizer,
+			})
+		}
+
+		messageSuffix := ""
+		if i > 0 {
+			messageSuffix = " and integrate it with the context of the other files"
+		}
+
+		if len(chunks) == 1 {
+			// user message with file and content
+			jsonData, err := json.Marshal(strData)
+			if err != nil {
+				return relPaths, newItems, err
 			}
 
 			added := ctx.AppendSimplePseudoUserConversation(fmt.Sprintf(
@@ -182,6 +296,33 @@ Answer with 'OK' if you analyzed it%v.`,
 			))
 
 			newItems = append(newItems, added...)
+		} else {
+			app.Dbgf("Submitting '%v' in %v parts ...%v", relPath, len(chunks), app.EOL)
+
+			// user messages with file and parts of content
+			for j, chunk := range chunks {
+				jsonData, err := json.Marshal(chunk)
+				if err != nil {
+					return relPaths, newItems, err
+				}
+
+				partSuffix := ""
+				if j == len(chunks)-1 {
+					partSuffix = messageSuffix
+				}
+
+				added := ctx.AppendSimplePseudoUserConversation(fmt.Sprintf(
+					`This is part %d of %d of the content of the file with the path '%s': %s.
+Answer with 'OK' if you analyzed it%v.`,
+					j+1,
+					len(chunks),
+					relPath,
+					jsonData,
+					partSuffix,
+				))
+
+				newItems = append(newItems, added...)
+			}
 		}
 
 		relPaths = append(relPaths, relPath)
@@ -190,6 +331,72 @@ Answer with 'OK' if you analyzed it%v.`,
 	return relPaths, newItems, nil
 }
 
+// CurrentContext returns the name of the current context.
+func (ctx *ChatContext) CurrentContext() string {
+	return ctx.currentContext
+}
+
+// DeleteAllContexts removes all contexts of the current directory
+// without updating the underyling conversation file.
+func (ctx *ChatContext) DeleteAllContexts() []string {
+	app := ctx.App
+
+	app.Dbg(fmt.Sprintf("Deleting all contexts of %v ...", app.WorkingDirectory))
+
+	deletedContexts := make([]string, 0)
+
+	repo := ctx.Conversations
+	if repo != nil && repo.Conversations != nil {
+		for name := range repo.Conversations[app.WorkingDirectory] {
+			deletedContexts = append(deletedContexts, name)
+		}
+
+		delete(repo.Conversations, app.WorkingDirectory)
+	}
+
+	ctx.currentContext = ""
+	ctx.submittedFiles = nil
+
+	return deletedContexts
+}
+
+// DeleteContext removes the context with the name `c` of the current directory
+// without updating the underyling conversation file and returns `false` if it does not exist.
+func (ctx *ChatContext) DeleteContext(c string) bool {
+	app := ctx.App
+	contextName := toContextName(c)
+
+	app.Dbg(fmt.Sprintf("Deleting context '%v' of %v ...", contextName, app.WorkingDirectory))
+
+	repo := ctx.Conversations
+	if repo == nil || repo.Conversations == nil {
+		return false
+	}
+
+	conversations, ok := repo.Conversations[app.WorkingDirectory]
+	if !ok || conversations == nil {
+		return false
+	}
+
+	_, ok = conversations[contextName]
+	if !ok {
+		return false
+	}
+
+	delete(conversations, contextName)
+	if len(conversations) == 0 {
+		delete(repo.Conversations, app.WorkingDirectory)
+	}
+
+	if ctx.currentContext == contextName {
+		// do not recreate deleted context
+		ctx.currentContext = ""
+		ctx.submittedFiles = nil
+	}
+
+	return true
+}
+
 func (ctx *ChatContext) ensureConversation() *ConversationRepositoryConversationContext {
 	var app = ctx.App
 	var cwd = app.WorkingDirectory
@@ -227,6 +434,51 @@ func (ctx *ChatContext) ensureConversation() *ConversationRepositoryConversation
 	return context
 }
 
+// EnsureSystemPrompt replaces the leading system messages of the current
+// conversation with a single message of `role` with `prompt` or inserts it,
+// if there are none, without updating the conversation file.
+func (ctx *ChatContext) EnsureSystemPrompt(prompt string, role string) *ConversationRepositoryConversationItem {
+	conversationContext := ctx.ensureConversation()
+	conversation := conversationContext.Conversation
+
+	// skip all leading system messages
+	start := 0
+	for start < len(conversation) && isSystemMessageRole(conversation[start].Role, role) {
+		start++
+	}
+
+	systemMessage := ctx.newSystemMessage(prompt, role)
+
+	newConversation := make(ConversationRepositoryConversation, 0, len(conversation)-start+1)
+	newConversation = append(newConversation, systemMessage)
+	newConversation = append(newConversation, conversation[start:]...)
+
+	conversationContext.Conversation = newConversation
+
+	return systemMessage
+}
+
+// ExportConversation serializes the conversation of the current context
+// in the format `format`, which can be `json` or `yaml`.
+func (ctx *ChatContext) ExportConversation(format string) ([]byte, error) {
+	conversationContext := ctx.ensureConversation()
+
+	export := &ConversationExport{
+		Context:      ctx.currentContext,
+		Conversation: conversationContext.Conversation,
+		Time:         ctx.App.GetISOTime(),
+	}
+
+	switch strings.TrimSpace(strings.ToLower(format)) {
+	case "", "yaml", "yml":
+		return yaml.Marshal(export)
+	case "json":
+		return json.MarshalIndent(export, "", "  ")
+	}
+
+	return nil, fmt.Errorf("format '%v' is not supported", format)
+}
+
 // GetConversation returns conversation for the current directory.
 func (ctx *ChatContext) GetConversation() (ConversationRepositoryConversation, error) {
 	conversationContext := ctx.ensureConversation()
@@ -237,23 +489,59 @@ func (ctx *ChatContext) GetConversation() (ConversationRepositoryConversation, e
 func (ctx *ChatContext) getConversaionsFilePath() (string, error) {
 	app := ctx.App
 
+	conversationFormat, err := app.GetConversationFormat()
+	if err != nil {
+		return "", err
+	}
+
+	return ctx.getConversaionsFilePathOf(conversationFormat)
+}
+
+func (ctx *ChatContext) getConversaionsFilePathOf(conversationFormat string) (string, error) {
+	app := ctx.App
+
 	appDir, err := app.EnsureAppDir()
 	if err != nil {
 		return "", err
 	}
 
-	return filepath.Join(appDir, ".conversations.yaml"), nil
+	return filepath.Join(appDir, fmt.Sprintf(".conversations.%s", conversationFormat)), nil
 }
 
 // ReloadAllConversations reloads the conversation file with all conversations
 // and writes it to `Conversations`.
 func (ctx *ChatContext) ReloadAllConversations() error {
-	conversationFile, err := ctx.getConversaionsFilePath()
+	app := ctx.App
+
+	conversationFormat, err := app.GetConversationFormat()
 	if err != nil {
 		return err
 	}
 
-	app := ctx.App
+	conversationFile, err := ctx.getConversaionsFilePathOf(conversationFormat)
+	if err != nil {
+		return err
+	}
+
+	if _, err := os.Stat(conversationFile); os.IsNotExist(err) {
+		// maybe there is a file in the other format
+		// which should be taken over
+
+		otherFormat := "json"
+		if conversationFormat == "json" {
+			otherFormat = "yaml"
+		}
+
+		otherConversationFile, err := ctx.getConversaionsFilePathOf(otherFormat)
+		if err != nil {
+			return err
+		}
+
+		if _, err := os.Stat(otherConversationFile); err == nil {
+			conversationFormat = otherFormat
+			conversationFile = otherConversationFile
+		}
+	}
 
 	var repo ConversationRepository
 
@@ -267,9 +555,11 @@ func (ctx *ChatContext) ReloadAllConversations() error {
 
 		defer file.Close()
 
-		dec := yaml.NewDecoder(file)
-
-		err = dec.Decode(&repo)
+		if conversationFormat == "json" {
+			err = json.NewDecoder(file).Decode(&repo)
+		} else {
+			err = yaml.NewDecoder(file).Decode(&repo)
+		}
 		if err != nil {
 			return err
 		}
@@ -284,6 +574,290 @@ func (ctx *ChatContext) ReloadAllConversations() error {
 	return nil
 }
 
+// ImportConversation appends the conversation of an export in `data`, which
+// is stored as JSON or YAML, to the current context without updating
+// the underyling conversation file and returns the number of imported items.
+func (ctx *ChatContext) ImportConversation(data []byte) (int, error) {
+	var export ConversationExport
+
+	// JSON is a subset of YAML
+	err := yaml.Unmarshal(data, &export)
+	if err != nil {
+		return 0, err
+	}
+
+	err = export.Validate()
+	if err != nil {
+		return 0, err
+	}
+
+	conversationContext := ctx.ensureConversation()
+	conversationContext.Conversation = append(conversationContext.Conversation, export.Conversation...)
+
+	return len(export.Conversation), nil
+}
+
+// LimitConversationHistory returns the part of `conversation`, which should
+// be sent with a request: all system messages and the last N turns, as
+// defined by `GetHistoryLimit()`, which fit into `GetMaxHistoryTokens()`.
+// Older turns are sent as rolling summary, if `GetHistorySummary()` is `true`.
+// The stored history is not changed.
+func (ctx *ChatContext) LimitConversationHistory(conversation ConversationRepositoryConversation, model string) (ConversationRepositoryConversation, error) {
+	if ctx.startedEmpty {
+		// conversations, which are built for a single task,
+		// like pseudo conversations with files, are sent completely
+		return conversation, nil
+	}
+
+	historyLimit, err := ctx.App.GetHistoryLimit()
+	if err != nil {
+		return conversation, err
+	}
+	maxTokens, err := ctx.App.GetMaxHistoryTokens()
+	if err != nil {
+		return conversation, err
+	}
+
+	start := 0
+	if historyLimit > 0 {
+		// find the beginning of the last N turns,
+		// which always start with a user message
+		var turns int64 = 0
+		for i := len(conversation) - 1; i >= 0; i-- {
+			if conversation[i].Role == "user" {
+				turns++
+
+				if turns == historyLimit {
+					start = i
+					break
+				}
+			}
+		}
+
+		if start > 0 {
+			ctx.App.Dbgf("Sending only the last %v turn(s) of the conversation%v", historyLimit, ctx.App.EOL)
+		}
+	}
+	if maxTokens > 0 {
+		tokenStart := ctx.getTokenBudgetStart(conversation, model, maxTokens)
+		if tokenStart > start {
+			start = tokenStart
+		}
+	}
+
+	return ctx.limitConversationFrom(conversation, start, model)
+}
+
+// TrimToTokenBudget returns the part of `conversation`, which should be sent
+// with a request to `model`: all system messages and as many of the latest
+// turns as fit into `maxTokens`. Older turns are sent as rolling summary,
+// if `GetHistorySummary()` is `true`. The stored history is not changed.
+func (ctx *ChatContext) TrimToTokenBudget(conversation ConversationRepositoryConversation, model string, maxTokens int) (ConversationRepositoryConversation, error) {
+	if maxTokens <= 0 {
+		return conversation, nil // no limit
+	}
+
+	start := ctx.getTokenBudgetStart(conversation, model, maxTokens)
+
+	return ctx.limitConversationFrom(conversation, start, model)
+}
+
+// getTokenBudgetStart returns the index of the first turn of `conversation`,
+// from which all turns, together with all system messages, fit into `maxTokens`.
+func (ctx *ChatContext) getTokenBudgetStart(conversation ConversationRepositoryConversation, model string, maxTokens int) int {
+	tokenizer := utils.NewTextTokenizer(model)
+
+	countTokens := func(item *ConversationRepositoryConversationItem) int {
+		tokens := messageTokenOverhead
+		for _, c := range item.Contents {
+			if c.Type == "text" {
+				tokens += tokenizer.CountTokens(c.Content)
+			}
+		}
+		return tokens
+	}
+
+	// system messages are always sent
+	tokens := 0
+	for _, item := range conversation {
+		if ctx.isSystemMessage(item) {
+			tokens += countTokens(item)
+		}
+	}
+
+	start := len(conversation)
+	for i := len(conversation) - 1; i >= 0; i-- {
+		item := conversation[i]
+		if ctx.isSystemMessage(item) {
+			continue
+		}
+
+		tokens += countTokens(item)
+		if tokens > maxTokens {
+			break
+		}
+
+		if item.Role == "user" {
+			start = i // complete turn fits
+		}
+	}
+	if start == 0 {
+		return 0 // everything fits
+	}
+
+	ctx.App.Dbgf("Sending only the turns of the conversation, which fit into %v token(s)%v", maxTokens, ctx.App.EOL)
+
+	return start
+}
+
+func (ctx *ChatContext) isSystemMessage(item *ConversationRepositoryConversationItem) bool {
+	return isSystemMessageRole(item.Role, ctx.App.GetSystemRole())
+}
+
+// limitConversationFrom returns all system messages of `conversation` before
+// `start`, an optional rolling summary of the other items before `start`
+// and all items from `start`.
+func (ctx *ChatContext) limitConversationFrom(conversation ConversationRepositoryConversation, start int, model string) (ConversationRepositoryConversation, error) {
+	if start <= 0 {
+		return conversation, nil
+	}
+
+	limitedConversation := make(ConversationRepositoryConversation, 0)
+	for _, item := range conversation[:start] {
+		if ctx.isSystemMessage(item) {
+			limitedConversation = append(limitedConversation, item)
+		}
+	}
+
+	historySummary, err := ctx.App.GetHistorySummary()
+	if err != nil {
+		return conversation, err
+	}
+	if historySummary {
+		// older turns are sent as system note
+		summary, err := ctx.updateHistorySummary(conversation, start, ctx.isSystemMessage)
+		if err != nil {
+			return conversation, err
+		}
+
+		if summary != "" {
+			summaryMessage := &ConversationRepositoryConversationItem{
+				Contents: make(ConversationRepositoryConversationItemContents, 0),
+				Model:    model,
+				Role:     ctx.App.GetSystemRole(),
+				Time:     ctx.App.GetISOTime(),
+			}
+			summaryMessage.Contents = append(summaryMessage.Contents, &ConversationRepositoryConversationItemContentItem{
+				Content: fmt.Sprintf("This is a summary of the earlier part of our conversation:%s%s", ctx.App.EOL, summary),
+				Type:    "text",
+			})
+
+			limitedConversation = append(limitedConversation, summaryMessage)
+		}
+	}
+
+	limitedConversation = append(limitedConversation, conversation[start:]...)
+
+	return limitedConversation, nil
+}
+
+func (ctx *ChatContext) newSystemMessage(prompt string, role string) *ConversationRepositoryConversationItem {
+	app := ctx.App
+
+	systemMessage := &ConversationRepositoryConversationItem{
+		Contents: make(ConversationRepositoryConversationItemContents, 0),
+		Model:    app.AI.ChatModel(),
+		Role:     role,
+		Time:     app.GetISOTime(),
+	}
+	systemMessage.Contents = append(systemMessage.Contents, &ConversationRepositoryConversationItemContentItem{
+		Content: prompt,
+		Type:    "text",
+	})
+
+	return systemMessage
+}
+
+// PrependSystemPrompt inserts a system message of `role` with `prompt` at the
+// beginning of the current conversation, before existing system messages,
+// without updating the conversation file. Nothing is inserted, if one of
+// the leading system messages already has the same content.
+func (ctx *ChatContext) PrependSystemPrompt(prompt string, role string) *ConversationRepositoryConversationItem {
+	conversationContext := ctx.ensureConversation()
+	conversation := conversationContext.Conversation
+
+	for _, item := range conversation {
+		if !isSystemMessageRole(item.Role, role) {
+			break
+		}
+
+		if len(item.Contents) == 1 && item.Contents[0].Type == "text" && item.Contents[0].Content == prompt {
+			return item // already there
+		}
+	}
+
+	systemMessage := ctx.newSystemMessage(prompt, role)
+
+	conversationContext.Conversation = append(ConversationRepositoryConversation{systemMessage}, conversation...)
+
+	return systemMessage
+}
+
+// ReplaceFilesWithReferences replaces the contents of `files`, which have been attached
+// to the last user message of `conversation`, with lightweight references
+// (path and SHA-256 hash), so that they are not stored in history.
+func (ctx *ChatContext) ReplaceFilesWithReferences(conversation ConversationRepositoryConversation, files []string) error {
+	app := ctx.App
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	var userMessage *ConversationRepositoryConversationItem
+	for i := len(conversation) - 1; i >= 0; i-- {
+		if conversation[i].Role == "user" {
+			userMessage = conversation[i]
+			break
+		}
+	}
+
+	if userMessage == nil {
+		return errors.New("no user message found in conversation")
+	}
+
+	// each file has been appended as one content item at the end
+	firstFileIndex := len(userMessage.Contents) - len(files)
+	if firstFileIndex < 0 {
+		return fmt.Errorf("user message contains less than %v files", len(files))
+	}
+
+	for i, f := range files {
+		fullPath := app.GetFullPath(f)
+
+		relPath, err := filepath.Rel(app.WorkingDirectory, fullPath)
+		if err != nil {
+			relPath = fullPath
+		}
+
+		data, err := os.ReadFile(fullPath)
+		if err != nil {
+			return err
+		}
+
+		hash := sha256.Sum256(data)
+
+		userMessage.Contents[firstFileIndex+i] = &ConversationRepositoryConversationItemContentItem{
+			Content: fmt.Sprintf(
+				"The file '%s' (SHA-256: %x) has been attached here, but its content is not part of this history.",
+				relPath, hash,
+			),
+			Type: "text",
+		}
+	}
+
+	return nil
+}
+
 // ResetConversation resets conversation of the current directory.
 func (ctx *ChatContext) ResetConversation() error {
 	app := ctx.App
@@ -292,23 +866,114 @@ func (ctx *ChatContext) ResetConversation() error {
 
 	conversationContext := ctx.ensureConversation()
 	conversationContext.Conversation = make(ConversationRepositoryConversation, 0)
+	conversationContext.Summary = nil
+
+	ctx.submittedFiles = nil
 
 	return nil
 }
 
 // SwitchContext switches the context.
 func (ctx *ChatContext) SwitchContext(c string) string {
-	newContextName := strings.TrimSpace(
+	newContextName := toContextName(c)
+
+	ctx.currentContext = newContextName
+	ctx.submittedFiles = nil
+
+	ctx.ensureConversation()
+
+	return newContextName
+}
+
+func toContextName(c string) string {
+	return strings.TrimSpace(
 		strings.ToLower(
 			slug.MakeLang(c, "en"),
 		),
 	)
+}
 
-	ctx.currentContext = newContextName
+// updateHistorySummary updates the rolling summary of the current context,
+// so that it covers all non-system items of `conversation` before `end`,
+// and returns it.
+func (ctx *ChatContext) updateHistorySummary(conversation ConversationRepositoryConversation, end int, isSystemMessage func(item *ConversationRepositoryConversationItem) bool) (string, error) {
+	app := ctx.App
 
-	ctx.ensureConversation()
+	conversationContext := ctx.ensureConversation()
 
-	return newContextName
+	summary := conversationContext.Summary
+	if summary == nil || summary.Items > end {
+		// nothing summarized yet or conversation has been changed
+		summary = &ConversationRepositoryConversationSummary{}
+	}
+
+	if summary.Items == end {
+		return summary.Content, nil // up-to-date
+	}
+
+	// collect the text of the items, which are not summarized yet
+	type messageToSummarize struct {
+		Content string `json:"content"`
+		Role    string `json:"role"`
+	}
+	messages := make([]messageToSummarize, 0)
+	for _, item := range conversation[summary.Items:end] {
+		if isSystemMessage(item) {
+			continue
+		}
+
+		texts := make([]string, 0)
+		for _, c := range item.Contents {
+			if c.Type == "text" {
+				texts = append(texts, c.Content)
+			} else {
+				texts = append(texts, fmt.Sprintf("[%s]", c.Type))
+			}
+		}
+
+		messages = append(messages, messageToSummarize{
+			Content: strings.Join(texts, app.EOL),
+			Role:    item.Role,
+		})
+	}
+
+	if len(messages) > 0 {
+		app.Dbgf("Summarizing %v older message(s) of the conversation ...%v", len(messages), app.EOL)
+
+		jsonSummary, err := json.Marshal(summary.Content)
+		if err != nil {
+			return "", err
+		}
+		jsonMessages, err := json.Marshal(messages)
+		if err != nil {
+			return "", err
+		}
+
+		prompt := fmt.Sprintf(`This is the current summary of an earlier conversation between a user and an AI assistant as serialized JSON string: %s
+These are the following messages of this conversation as serialized JSON array: %s
+Update the summary with these messages.
+Keep all facts, decisions, names and open questions, which could be important for the further conversation.
+Answer only with the updated summary without any introduction.`,
+			jsonSummary,
+			jsonMessages,
+		)
+
+		response, err := app.AI.Prompt(prompt)
+		app.trackUsage(response.Usage, err)
+		if err != nil {
+			return "", err
+		}
+
+		summary.Content = strings.TrimSpace(response.Content)
+	}
+
+	summary.Items = end
+	summary.Time = app.GetISOTime()
+
+	// is stored with the next update of the conversation
+	conversationContext.Summary = summary
+
+	return summary.Content, nil
 }
 
 // UpdateConversation updates the conversation file with all conversations.
@@ -321,11 +986,22 @@ func (ctx *ChatContext) UpdateConversation() error {
 	app := ctx.App
 
 	app.Dbg(fmt.Sprintf("Will write conversations to '%v' ...", conversationFile))
-	app.Dbg("Creating YAML data ...")
 
-	ctx.ensureConversation()
+	// do not recreate deleted contexts
+	if ctx.Conversations == nil {
+		ctx.Conversations = &ConversationRepository{}
+	}
 
-	data, err := yaml.Marshal(&ctx.Conversations)
+	var data []byte
+	if strings.HasSuffix(conversationFile, ".json") {
+		app.Dbg("Creating JSON data ...")
+
+		data, err = json.MarshalIndent(&ctx.Conversations, "", "  ")
+	} else {
+		app.Dbg("Creating YAML data ...")
+
+		data, err = yaml.Marshal(&ctx.Conversations)
+	}
 	if err != nil {
 		return err
 	}
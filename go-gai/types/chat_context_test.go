@@ -0,0 +1,522 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// newTestChatContext creates a `ChatContext` for `app` with an AI client,
+// which is not expected to send any request.
+func newTestChatContext(t *testing.T, app *AppContext) *ChatContext {
+	t.Helper()
+
+	app.EnvVars["OPENAI_API_KEY"] = "test"
+	app.Model = "openai:gpt-4o"
+	app.InitAI()
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return chat
+}
+
+// newTestChatCompletionsServer starts a server for chat completions of the
+// OpenAI API, which answers with `answer`, and returns the submitted messages
+// of all requests in `role: text` format.
+func newTestChatCompletionsServer(t *testing.T, app *AppContext, answer string) *[][]string {
+	t.Helper()
+
+	requests := make([][]string, 0)
+	newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Messages []struct {
+				Content json.RawMessage `json:"content"`
+				Role    string          `json:"role"`
+			} `json:"messages"`
+		}
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil || r.URL.Path != "/v1/chat/completions" {
+			http.Error(w, "invalid request", http.StatusBadRequest)
+			return
+		}
+
+		messages := make([]string, 0)
+		for _, m := range body.Messages {
+			text := ""
+			if json.Unmarshal(m.Content, &text) != nil {
+				var parts []struct {
+					Text string `json:"text"`
+				}
+				json.Unmarshal(m.Content, &parts)
+
+				texts := make([]string, 0)
+				for _, p := range parts {
+					texts = append(texts, p.Text)
+				}
+				text = strings.Join(texts, "\n")
+			}
+
+			messages = append(messages, fmt.Sprintf("%v: %v", m.Role, text))
+		}
+		requests = append(requests, messages)
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]any{
+			"model": "gpt-4o",
+			"choices": []any{
+				map[string]any{
+					"message": map[string]any{
+						"content": answer,
+						"role":    "assistant",
+					},
+				},
+			},
+		})
+	})
+
+	return &requests
+}
+
+// appendTestTurns appends `count` turns with numbered user messages
+// and answers to the conversation of `chat`.
+func appendTestTurns(chat *ChatContext, count int) {
+	for i := 1; i <= count; i++ {
+		for _, role := range []string{"user", "assistant"} {
+			chat.AppendConversationItem(&ConversationRepositoryConversationItem{
+				Contents: ConversationRepositoryConversationItemContents{
+					&ConversationRepositoryConversationItemContentItem{
+						Content: fmt.Sprintf("%v %d", role, i),
+						Type:    "text",
+					},
+				},
+				Role: role,
+			})
+		}
+	}
+}
+
+func TestAppendTextFilesAsPseudoConversationDeduplicates(t *testing.T) {
+	app := newTestApp(t, nil)
+	chat := newTestChatContext(t, app)
+
+	writeTestFile(t, app, "a.go", "package a")
+	writeTestFile(t, app, "copy/a.go", "package a")
+	writeTestFile(t, app, "b.go", "package b")
+
+	relPaths, _, err := chat.AppendTextFilesAsPseudoConversation([]string{"a.go", "copy/a.go", "b.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(relPaths, ",") != "a.go,copy/a.go,b.go" {
+		t.Errorf("unexpected paths %v", relPaths)
+	}
+
+	// same content in a later call
+	_, _, err = chat.AppendTextFilesAsPseudoConversation([]string{"b.go"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conversation, err := chat.GetConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one user message and one answer per file
+	if len(conversation) != 8 {
+		t.Fatalf("expected 8 conversation items, got %d", len(conversation))
+	}
+
+	userMessages := make([]string, 0)
+	for i, item := range conversation {
+		expectedRole := "user"
+		if i%2 == 1 {
+			expectedRole = "assistant"
+		}
+		if item.Role != expectedRole {
+			t.Errorf("expected role %q of item %d, got %q", expectedRole, i, item.Role)
+		}
+
+		if item.Role == "user" {
+			userMessages = append(userMessages, item.Contents[0].Content)
+		}
+	}
+
+	expected := []string{
+		`This is the content of the file with the path 'a.go': "package a".`,
+		`The file with the path 'copy/a.go' has exactly the same content as the file with the path 'a.go'.`,
+		`This is the content of the file with the path 'b.go': "package b".`,
+		`The file with the path 'b.go' has exactly the same content as the file with the path 'b.go'.`,
+	}
+	for i, e := range expected {
+		if !strings.HasPrefix(userMessages[i], e) {
+			t.Errorf("expected user message %d to start with %q, got %q", i, e, userMessages[i])
+		}
+	}
+
+	submitCount := 0
+	for _, m := range userMessages {
+		if strings.Contains(m, `"package a"`) {
+			submitCount++
+		}
+	}
+	if submitCount != 1 {
+		t.Errorf("expected content of a.go to be submitted once, got %d", submitCount)
+	}
+}
+
+func TestConversationFormats(t *testing.T) {
+	for _, format := range []string{"yaml", "json"} {
+		app := newTestApp(t, map[string]string{
+			"GAI_CONVERSATION_FORMAT": format,
+		})
+
+		chat, err := app.NewChatContext()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		chat.AppendConversationItem(&ConversationRepositoryConversationItem{
+			Contents: ConversationRepositoryConversationItemContents{
+				&ConversationRepositoryConversationItemContentItem{
+					Content: "Hello, " + format,
+					Type:    "text",
+				},
+			},
+			Model: "gpt-4o",
+			Role:  "user",
+		})
+
+		err = chat.UpdateConversation()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		appDir, err := app.EnsureAppDir()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		data, err := os.ReadFile(filepath.Join(appDir, ".conversations."+format))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if json.Valid(data) != (format == "json") {
+			t.Errorf("%v: unexpected file content %q", format, data)
+		}
+
+		// load it again
+		reloadedChat, err := app.NewChatContext()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		conversation, err := reloadedChat.GetConversation()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conversation) != 1 || conversation[0].Contents[0].Content != "Hello, "+format {
+			t.Errorf("%v: unexpected conversation %+v", format, conversation)
+		}
+	}
+}
+
+func TestConversationFormatIsTakenOver(t *testing.T) {
+	app := newTestApp(t, nil)
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	chat.AppendConversationItem(&ConversationRepositoryConversationItem{
+		Contents: ConversationRepositoryConversationItemContents{
+			&ConversationRepositoryConversationItemContentItem{
+				Content: "from YAML",
+				Type:    "text",
+			},
+		},
+		Role: "user",
+	})
+
+	err = chat.UpdateConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// existing YAML file is loaded with JSON format ...
+	app.ConversationFormat = "json"
+
+	jsonChat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conversation, err := jsonChat.GetConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(conversation) != 1 || conversation[0].Contents[0].Content != "from YAML" {
+		t.Fatalf("unexpected conversation %+v", conversation)
+	}
+
+	// ... and written as JSON
+	err = jsonChat.UpdateConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	appDir, err := app.EnsureAppDir()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(appDir, ".conversations.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), `"from YAML"`) {
+		t.Errorf("unexpected JSON file %q", data)
+	}
+}
+
+func TestAppendSimplePseudoUserConversationTurns(t *testing.T) {
+	app := newTestApp(t, nil)
+	app.PseudoAnswers = []string{"Got it", " ", "Understood"}
+	chat := newTestChatContext(t, app)
+
+	for _, um := range []string{"First\nAnswer with 'OK' only.", "Second", "Third"} {
+		chat.AppendSimplePseudoUserConversation(um)
+	}
+
+	conversation, err := chat.GetConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		content string
+		role    string
+	}{
+		{"First\nAnswer with 'Got it' only.", "user"},
+		{"Got it", "assistant"},
+		{"Second", "user"},
+		{"Understood", "assistant"},
+		{"Third", "user"},
+		{"Got it", "assistant"},
+	}
+	if len(conversation) != len(expected) {
+		t.Fatalf("expected %d conversation items, got %d", len(expected), len(conversation))
+	}
+	for i, e := range expected {
+		item := conversation[i]
+
+		if item.Role != e.role || item.Contents[0].Content != e.content {
+			t.Errorf("expected item %d to be %q by %v, got %q by %v", i, e.content, e.role, item.Contents[0].Content, item.Role)
+		}
+	}
+}
+
+func TestAppendSimplePseudoUserConversationSingle(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_PSEUDO_MODE": "single",
+	})
+	chat := newTestChatContext(t, app)
+
+	for _, um := range []string{"First\nAnswer with 'OK' only.", "Second"} {
+		chat.AppendSimplePseudoUserConversation(um)
+	}
+
+	conversation, err := chat.GetConversation()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// one long user message without answers
+	if len(conversation) != 1 {
+		t.Fatalf("expected 1 conversation item, got %d", len(conversation))
+	}
+	if conversation[0].Role != "user" {
+		t.Errorf("expected role user, got %v", conversation[0].Role)
+	}
+
+	contents := make([]string, 0)
+	for _, c := range conversation[0].Contents {
+		contents = append(contents, c.Content)
+	}
+	if strings.Join(contents, "|") != "First|Second" {
+		t.Errorf("unexpected contents %q", contents)
+	}
+}
+
+func TestGetPseudoMode(t *testing.T) {
+	tests := []struct {
+		flag          string
+		env           string
+		expectedMode  string
+		expectedError bool
+	}{
+		{"", "", "turns", false},
+		{"", "SINGLE", "single", false},
+		{"turns", "single", "turns", false},
+		{"both", "", "both", true},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_PSEUDO_MODE": test.env,
+		})
+		app.PseudoMode = test.flag
+
+		pseudoMode, err := app.GetPseudoMode()
+		if (err != nil) != test.expectedError {
+			t.Errorf("%q/%q: unexpected error %v", test.flag, test.env, err)
+		}
+		if pseudoMode != test.expectedMode {
+			t.Errorf("%q/%q: expected mode %v, got %v", test.flag, test.env, test.expectedMode, pseudoMode)
+		}
+	}
+}
+
+func TestChatHistoryLimit(t *testing.T) {
+	tests := []struct {
+		flag             int64
+		env              string
+		rc               *int64
+		expectedMessages []string
+	}{
+		{-1, "", nil, []string{"system: Be brief.", "user: user 1", "assistant: assistant 1", "user: user 2", "assistant: assistant 2", "user: user 3", "assistant: assistant 3", "user: new"}},
+		{1, "", nil, []string{"system: Be brief.", "user: user 3", "assistant: assistant 3", "user: new"}},
+		{-1, "2", nil, []string{"system: Be brief.", "user: user 2", "assistant: assistant 2", "user: user 3", "assistant: assistant 3", "user: new"}},
+		{-1, "", &[]int64{1}[0], []string{"system: Be brief.", "user: user 3", "assistant: assistant 3", "user: new"}},
+		{0, "1", nil, []string{"system: Be brief.", "user: user 1", "assistant: assistant 1", "user: user 2", "assistant: assistant 2", "user: user 3", "assistant: assistant 3", "user: new"}},
+		{5, "", nil, []string{"system: Be brief.", "user: user 1", "assistant: assistant 1", "user: user 2", "assistant: assistant 2", "user: user 3", "assistant: assistant 3", "user: new"}},
+	}
+
+	for i, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_HISTORY_LIMIT": test.env,
+		})
+		app.HistoryLimit = test.flag
+		app.RCFile.Defaults.Flags.HistoryLimit = test.rc
+		requests := newTestChatCompletionsServer(t, app, "OK")
+
+		chat := newTestChatContext(t, app)
+		chat.AppendConversationItem(&ConversationRepositoryConversationItem{
+			Contents: ConversationRepositoryConversationItemContents{
+				&ConversationRepositoryConversationItemContentItem{
+					Content: "Be brief.",
+					Type:    "text",
+				},
+			},
+			Role: "system",
+		})
+		appendTestTurns(chat, 3)
+
+		_, _, err := app.AI.Chat(chat, "new")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(*requests) != 1 {
+			t.Fatalf("%d: expected 1 request, got %d", i, len(*requests))
+		}
+
+		messages := strings.Join((*requests)[0], "|")
+		if messages != strings.Join(test.expectedMessages, "|") {
+			t.Errorf("%d: unexpected messages %q", i, messages)
+		}
+
+		// stored history is not changed
+		conversation, err := chat.GetConversation()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(conversation) != 9 {
+			t.Errorf("%d: expected 9 stored items, got %d", i, len(conversation))
+		}
+	}
+}
+
+func TestChatHistorySummary(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"GAI_HISTORY_SUMMARY": "true",
+	})
+	app.HistoryLimit = 1
+	requests := newTestChatCompletionsServer(t, app, "Summarized")
+
+	chat := newTestChatContext(t, app)
+	appendTestTurns(chat, 3)
+
+	_, _, err := app.AI.Chat(chat, "new")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// summary of older turns and request with recent ones
+	if len(*requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(*requests))
+	}
+
+	summaryPrompt := strings.Join((*requests)[0], "|")
+	if !strings.Contains(summaryPrompt, `[{"content":"user 1","role":"user"},{"content":"assistant 1","role":"assistant"},{"content":"user 2","role":"user"},{"content":"assistant 2","role":"assistant"}]`) ||
+		strings.Contains(summaryPrompt, "user 3") {
+		t.Errorf("unexpected summary prompt %q", summaryPrompt)
+	}
+
+	expected := []string{
+		"system: This is a summary of the earlier part of our conversation:\nSummarized",
+		"user: user 3",
+		"assistant: assistant 3",
+		"user: new",
+	}
+	messages := strings.Join((*requests)[1], "|")
+	if messages != strings.Join(expected, "|") {
+		t.Errorf("unexpected messages %q", messages)
+	}
+
+	// only the turn, which is beyond the limit now, is summarized
+	_, _, err = app.AI.Chat(chat, "newer")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(*requests) != 4 {
+		t.Fatalf("expected 4 requests, got %d", len(*requests))
+	}
+
+	summaryPrompt = strings.Join((*requests)[2], "|")
+	if !strings.Contains(summaryPrompt, `serialized JSON string: "Summarized"`) ||
+		!strings.Contains(summaryPrompt, `[{"content":"user 3","role":"user"},{"content":"assistant 3","role":"assistant"}]`) {
+		t.Errorf("unexpected second summary prompt %q", summaryPrompt)
+	}
+}
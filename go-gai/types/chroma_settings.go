@@ -15,6 +15,11 @@ type ChromaSettings struct {
 
 // Highlight outputs a string highlighted in the defined language.
 func (cs *ChromaSettings) Highlight(s string, language string) {
+	if !cs.App.ColorEnabled() {
+		cs.App.Write([]byte(s))
+		return
+	}
+
 	err := quick.Highlight(cs.App, s, language, cs.Formatter, cs.Style)
 	if err != nil {
 		cs.App.Write([]byte(s))
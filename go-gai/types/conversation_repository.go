@@ -22,11 +22,27 @@
 
 package types
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ConversationExport stores a self-contained, exported conversation.
+type ConversationExport struct {
+	// Context stores the name of the context, the conversation has been exported from.
+	Context string `json:"context" yaml:"context"`
+	// Conversation stores the exported conversation.
+	Conversation ConversationRepositoryConversation `json:"conversation" yaml:"conversation"`
+	// Time stores timestamp of the export in ISO 8601 format.
+	Time string `json:"time" yaml:"time"`
+}
+
 // ConversationRepository represents a file of an AI conversation.
 type ConversationRepository struct {
 	// Conversations stores the conversation for each directory.
 	// The key is the ID, usually the current directory, of the conversation.
-	Conversations map[string]ConversationRepositoryConversationContextes `yaml:"conversations"`
+	Conversations map[string]ConversationRepositoryConversationContextes `json:"conversations" yaml:"conversations"`
 }
 
 // ConversationRepositoryConversation is a list of `ConversationRepositoryConversationItem`.
@@ -35,15 +51,21 @@ type ConversationRepositoryConversation = []*ConversationRepositoryConversationI
 // ConversationRepositoryConversationItem is an item in a `ConversationRepositoryConversation`.
 type ConversationRepositoryConversationItem struct {
 	// Content stores the usually Markdown content of the item.
-	Contents ConversationRepositoryConversationItemContents `yaml:"contents"`
+	Contents ConversationRepositoryConversationItemContents `json:"contents" yaml:"contents"`
 	// Model stores the model that is/has been used.
-	Model string `yaml:"model"`
+	Model string `json:"model" yaml:"model"`
 	// ResponseFormat stores the response format.
-	ResponseFormat string
+	ResponseFormat string `json:"responseformat" yaml:"responseformat"`
 	// Role stores the role like `assistant`, `system` or `user`
-	Role string `yaml:"role"`
+	Role string `json:"role" yaml:"role"`
 	// Time stores timestamp in ISO 8601 format.
-	Time string `yaml:"time"`
+	Time string `json:"time" yaml:"time"`
+	// ToolCallId stores the ID of the tool call, a `tool` message is the result of.
+	ToolCallId string `json:"toolcallid,omitempty" yaml:"toolcallid,omitempty"`
+	// ToolCalls stores the calls of tools, which have been requested by the assistant.
+	ToolCalls []AIToolCall `json:"toolcalls,omitempty" yaml:"toolcalls,omitempty"`
+	// Usage stores the used tokens of an answer, if known.
+	Usage *AIUsage `json:"usage,omitempty" yaml:"usage,omitempty"`
 }
 
 // ConversationRepositoryConversationItemContents stores list of `ConversationRepositoryConversationItemContentItem`s.
@@ -52,16 +74,60 @@ type ConversationRepositoryConversationItemContents = []*ConversationRepositoryC
 // ConversationRepositoryConversationItemContentItem is an item inside a `ConversationRepositoryConversationItemContentItem`.
 type ConversationRepositoryConversationItemContentItem struct {
 	// Content stores the string serialized content.
-	Content string `yaml:"content"`
+	Content string `json:"content" yaml:"content"`
+	// Name stores the optional file name of an attachment.
+	Name string `json:"name,omitempty" yaml:"name,omitempty"`
 	// Type stores the type like `text` or `image`.
-	Type string `yaml:"type"`
+	Type string `json:"type" yaml:"type"`
 }
 
 // ConversationRepositoryConversationContext stores a conversation in a specific context.
 type ConversationRepositoryConversationContext struct {
 	// Conversation stores the underlying conversation.
-	Conversation ConversationRepositoryConversation `yaml:"conversation"`
+	Conversation ConversationRepositoryConversation `json:"conversation" yaml:"conversation"`
+	// Summary stores the rolling summary of older turns, which are not sent anymore.
+	Summary *ConversationRepositoryConversationSummary `json:"summary,omitempty" yaml:"summary,omitempty"`
+}
+
+// ConversationRepositoryConversationSummary stores a rolling summary of the older part of a conversation.
+type ConversationRepositoryConversationSummary struct {
+	// Content stores the summary.
+	Content string `json:"content" yaml:"content"`
+	// Items stores the number of items at the beginning of the conversation, which are covered by the summary.
+	Items int `json:"items" yaml:"items"`
+	// Time stores timestamp of the last update in ISO 8601 format.
+	Time string `json:"time" yaml:"time"`
 }
 
 // ConversationRepositoryConversationContextes stores contextes grouped by their name/ID.
 type ConversationRepositoryConversationContextes map[string]*ConversationRepositoryConversationContext
+
+// Validate checks if the exported conversation is well-formed.
+func (e *ConversationExport) Validate() error {
+	if e.Conversation == nil {
+		return errors.New("no conversation found")
+	}
+
+	for i, item := range e.Conversation {
+		if item == nil {
+			return fmt.Errorf("conversation item #%v is empty", i+1)
+		}
+
+		role := strings.TrimSpace(item.Role)
+		if role != "assistant" && role != "system" && role != "tool" && role != "user" {
+			return fmt.Errorf("conversation item #%v has invalid role '%v'", i+1, item.Role)
+		}
+
+		if len(item.Contents) == 0 && len(item.ToolCalls) == 0 {
+			return fmt.Errorf("conversation item #%v has no contents", i+1)
+		}
+
+		for j, content := range item.Contents {
+			if content == nil || strings.TrimSpace(content.Type) == "" {
+				return fmt.Errorf("content #%v of conversation item #%v has no type", j+1, i+1)
+			}
+		}
+	}
+
+	return nil
+}
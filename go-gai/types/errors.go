@@ -0,0 +1,204 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+// ErrorTypeAuth is the type of errors of missing or rejected credentials.
+const ErrorTypeAuth = "auth"
+
+// ErrorTypeBudgetExceeded is the type of errors of requests, which would exceed the budget.
+const ErrorTypeBudgetExceeded = "budget_exceeded"
+
+// ErrorTypeCancelled is the type of errors of cancelled operations.
+const ErrorTypeCancelled = "cancelled"
+
+// ErrorTypeDefault is the type of errors without a specific type.
+const ErrorTypeDefault = "error"
+
+// ErrorTypeEmptyResponse is the type of errors of empty AI answers.
+const ErrorTypeEmptyResponse = "empty_response"
+
+// ErrorTypeHttp is the type of errors of failed HTTP responses.
+const ErrorTypeHttp = "http"
+
+// ErrorTypeInvalidJSON is the type of errors of AI answers, which are no valid JSON.
+const ErrorTypeInvalidJSON = "invalid_json"
+
+// ErrorTypeModelNotFound is the type of errors of unknown models.
+const ErrorTypeModelNotFound = "model_not_found"
+
+// ErrorTypeNetwork is the type of errors of failed connections.
+const ErrorTypeNetwork = "network"
+
+// ErrorTypeRateLimit is the type of errors of exceeded rate limits.
+const ErrorTypeRateLimit = "rate_limit"
+
+// ErrorTypeRequestTooLarge is the type of errors of requests with bodies, which are too large.
+const ErrorTypeRequestTooLarge = "request_too_large"
+
+// ErrorTypeSchemaValidation is the type of errors of AI answers, which do not match the response schema.
+const ErrorTypeSchemaValidation = "schema_validation"
+
+// ErrorTypeTimeout is the type of errors of timed out operations.
+const ErrorTypeTimeout = "timeout"
+
+// ErrorTypeUsage is the type of errors of invalid CLI usage.
+const ErrorTypeUsage = "usage"
+
+// exit codes of the app by error class
+const (
+	// ExitCodeError is the exit code of errors without a specific class.
+	ExitCodeError = 1
+	// ExitCodeUsage is the exit code of invalid CLI usage.
+	ExitCodeUsage = 2
+	// ExitCodeAuth is the exit code of missing or rejected credentials.
+	ExitCodeAuth = 3
+	// ExitCodeRateLimit is the exit code of exceeded rate limits.
+	ExitCodeRateLimit = 4
+	// ExitCodeNetwork is the exit code of failed connections and timeouts.
+	ExitCodeNetwork = 5
+	// ExitCodeModelNotFound is the exit code of unknown models.
+	ExitCodeModelNotFound = 6
+	// ExitCodeEmptyResponse is the exit code of empty AI answers.
+	ExitCodeEmptyResponse = 7
+	// ExitCodeInvalidJSON is the exit code of AI answers, which are no valid JSON or do not match the response schema.
+	ExitCodeInvalidJSON = 8
+	// ExitCodeBudgetExceeded is the exit code of requests, which would exceed the budget of `--budget`.
+	ExitCodeBudgetExceeded = 9
+	// ExitCodeRequestTooLarge is the exit code of requests with bodies, which are too large.
+	ExitCodeRequestTooLarge = 10
+	// ExitCodeCancelled is the exit code of operations cancelled by SIGINT or SIGTERM.
+	ExitCodeCancelled = 130
+)
+
+// TypedError is an error, which provides a type for structured output.
+type TypedError interface {
+	error
+
+	// ErrorType returns the type of the error like `http` or `timeout`.
+	ErrorType() string
+}
+
+type typedError struct {
+	err       error
+	errorType string
+}
+
+func (e *typedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *typedError) ErrorType() string {
+	return e.errorType
+}
+
+func (e *typedError) Unwrap() error {
+	return e.err
+}
+
+// checkForModelHttpResponseError works like `utils.CheckForHttpResponseError`
+// for endpoints of a specific model, where status `404` means,
+// that the model does not exist.
+func checkForModelHttpResponseError(resp *http.Response) error {
+	err := utils.CheckForHttpResponseError(resp)
+
+	var httpErr *utils.HttpResponseError
+	if errors.As(err, &httpErr) && httpErr.StatusCode == 404 {
+		return NewTypedError(ErrorTypeModelNotFound, err)
+	}
+
+	return err
+}
+
+// GetErrorType returns the type of `err`, which is `error` if it is
+// no `TypedError` or failed HTTP response.
+func GetErrorType(err error) string {
+	var typedErr TypedError
+	if errors.As(err, &typedErr) {
+		return typedErr.ErrorType()
+	}
+
+	var httpErr *utils.HttpResponseError
+	if errors.As(err, &httpErr) {
+		return getHttpErrorType(httpErr.StatusCode)
+	}
+
+	return ErrorTypeDefault
+}
+
+// GetExitCode returns the exit code of the app for `err`.
+func GetExitCode(err error) int {
+	switch GetErrorType(err) {
+	case ErrorTypeAuth:
+		return ExitCodeAuth
+	case ErrorTypeBudgetExceeded:
+		return ExitCodeBudgetExceeded
+	case ErrorTypeCancelled:
+		return ExitCodeCancelled
+	case ErrorTypeEmptyResponse:
+		return ExitCodeEmptyResponse
+	case ErrorTypeInvalidJSON, ErrorTypeSchemaValidation:
+		return ExitCodeInvalidJSON
+	case ErrorTypeModelNotFound:
+		return ExitCodeModelNotFound
+	case ErrorTypeNetwork, ErrorTypeTimeout:
+		return ExitCodeNetwork
+	case ErrorTypeRateLimit:
+		return ExitCodeRateLimit
+	case ErrorTypeRequestTooLarge:
+		return ExitCodeRequestTooLarge
+	case ErrorTypeUsage:
+		return ExitCodeUsage
+	}
+
+	return ExitCodeError
+}
+
+// getHttpErrorType returns the type of errors of failed HTTP responses
+// with `statusCode`.
+func getHttpErrorType(statusCode int) string {
+	switch statusCode {
+	case 401, 403:
+		return ErrorTypeAuth
+	case 413:
+		return ErrorTypeRequestTooLarge
+	case 429:
+		return ErrorTypeRateLimit
+	}
+
+	return ErrorTypeHttp
+}
+
+// NewTypedError wraps `err` into a `TypedError` with the type `errorType`.
+func NewTypedError(errorType string, err error) TypedError {
+	return &typedError{
+		err:       err,
+		errorType: errorType,
+	}
+}
@@ -0,0 +1,97 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// newTestHttpResponseError returns the error of a failed HTTP response
+// with `statusCode` for the endpoint of a model.
+func newTestHttpResponseError(statusCode int) error {
+	return checkForModelHttpResponseError(&http.Response{
+		Body:       io.NopCloser(strings.NewReader("failed")),
+		StatusCode: statusCode,
+	})
+}
+
+func TestGetExitCode(t *testing.T) {
+	tests := []struct {
+		name             string
+		err              error
+		expectedType     string
+		expectedExitCode int
+	}{
+		{"untyped", errors.New("failed"), ErrorTypeDefault, ExitCodeError},
+		{"auth", NewTypedError(ErrorTypeAuth, errors.New("failed")), ErrorTypeAuth, ExitCodeAuth},
+		{"budget", NewTypedError(ErrorTypeBudgetExceeded, errors.New("failed")), ErrorTypeBudgetExceeded, ExitCodeBudgetExceeded},
+		{"cancelled", NewTypedError(ErrorTypeCancelled, errors.New("failed")), ErrorTypeCancelled, ExitCodeCancelled},
+		{"empty response", NewTypedError(ErrorTypeEmptyResponse, errors.New("failed")), ErrorTypeEmptyResponse, ExitCodeEmptyResponse},
+		{"invalid JSON", NewTypedError(ErrorTypeInvalidJSON, errors.New("failed")), ErrorTypeInvalidJSON, ExitCodeInvalidJSON},
+		{"schema validation", NewTypedError(ErrorTypeSchemaValidation, errors.New("failed")), ErrorTypeSchemaValidation, ExitCodeInvalidJSON},
+		{"model not found", NewTypedError(ErrorTypeModelNotFound, errors.New("failed")), ErrorTypeModelNotFound, ExitCodeModelNotFound},
+		{"network", NewTypedError(ErrorTypeNetwork, errors.New("failed")), ErrorTypeNetwork, ExitCodeNetwork},
+		{"timeout", NewTypedError(ErrorTypeTimeout, errors.New("failed")), ErrorTypeTimeout, ExitCodeNetwork},
+		{"rate limit", NewTypedError(ErrorTypeRateLimit, errors.New("failed")), ErrorTypeRateLimit, ExitCodeRateLimit},
+		{"request too large", NewTypedError(ErrorTypeRequestTooLarge, errors.New("failed")), ErrorTypeRequestTooLarge, ExitCodeRequestTooLarge},
+		{"usage", NewTypedError(ErrorTypeUsage, errors.New("failed")), ErrorTypeUsage, ExitCodeUsage},
+		{"wrapped typed", fmt.Errorf("chat: %w", NewTypedError(ErrorTypeUsage, errors.New("failed"))), ErrorTypeUsage, ExitCodeUsage},
+		{"HTTP 400", newTestHttpResponseError(400), ErrorTypeHttp, ExitCodeError},
+		{"HTTP 401", newTestHttpResponseError(401), ErrorTypeAuth, ExitCodeAuth},
+		{"HTTP 403", newTestHttpResponseError(403), ErrorTypeAuth, ExitCodeAuth},
+		{"HTTP 404", newTestHttpResponseError(404), ErrorTypeModelNotFound, ExitCodeModelNotFound},
+		{"HTTP 413", newTestHttpResponseError(413), ErrorTypeRequestTooLarge, ExitCodeRequestTooLarge},
+		{"HTTP 429", newTestHttpResponseError(429), ErrorTypeRateLimit, ExitCodeRateLimit},
+		{"HTTP 500", newTestHttpResponseError(500), ErrorTypeHttp, ExitCodeError},
+		{"wrapped HTTP 429", fmt.Errorf("chat: %w", newTestHttpResponseError(429)), ErrorTypeRateLimit, ExitCodeRateLimit},
+	}
+
+	for _, test := range tests {
+		errorType := GetErrorType(test.err)
+		if errorType != test.expectedType {
+			t.Errorf("%v: expected error type %v, got %v", test.name, test.expectedType, errorType)
+		}
+
+		exitCode := GetExitCode(test.err)
+		if exitCode != test.expectedExitCode {
+			t.Errorf("%v: expected exit code %d, got %d", test.name, test.expectedExitCode, exitCode)
+		}
+	}
+}
+
+func TestCheckForModelHttpResponseError(t *testing.T) {
+	err := newTestHttpResponseError(200)
+	if err != nil {
+		t.Errorf("expected no error for status 200, got %v", err)
+	}
+
+	err = newTestHttpResponseError(429)
+	if !strings.Contains(err.Error(), "failed") {
+		t.Errorf("expected response body in error message, got %q", err.Error())
+	}
+}
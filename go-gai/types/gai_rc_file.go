@@ -22,6 +22,14 @@
 
 package types
 
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
 // GAIRCFile stores the structure of an `.gairc.yaml` file.
 type GAIRCFile struct {
 	// Defaults stores default setting.
@@ -36,8 +44,121 @@ type GAIRCFileDefaults struct {
 
 // GAIRCFileDefaultsFlags stores `flags` parts in a `GAIRCFileDefaults` object.
 type GAIRCFileDefaultsFlags struct {
+	// Exclude stores default settings for CLI flag `--exclude`.
+	Exclude []string `yaml:"exclude,omitempty"`
 	// File stores default settings for CLI flag `--file`.
 	File []string `yaml:"file,omitempty"`
 	// Files stores default settings for CLI flag `--files`.
 	Files []string `yaml:"files,omitempty"`
+	// HistoryLimit stores default settings for CLI flag `--history-limit`.
+	HistoryLimit *int64 `yaml:"history-limit,omitempty"`
+	// HistorySummary stores default settings for CLI flag `--history-summary`.
+	HistorySummary *bool `yaml:"history-summary,omitempty"`
+	// MaxHistoryTokens stores default settings for CLI flag `--max-history-tokens`.
+	MaxHistoryTokens *int64 `yaml:"max-history-tokens,omitempty"`
+	// Model stores the default chat model in `provider:model` format, which is used without `--model`.
+	Model string `yaml:"model,omitempty"`
+}
+
+// rcFileFlagsPathPrefix is the prefix of the paths of the
+// settings of `GAIRCFileDefaultsFlags`.
+const rcFileFlagsPathPrefix = "defaults.flags."
+
+// GetRCFileFlagKeys returns the sorted keys of all settings of
+// `GAIRCFileDefaultsFlags`, like `model` or `history-limit`.
+func GetRCFileFlagKeys() []string {
+	keys := make([]string, 0)
+
+	t := reflect.TypeOf(GAIRCFileDefaultsFlags{})
+	for i := 0; i < t.NumField(); i++ {
+		keys = append(keys, getRCFileFlagKeyOf(t.Field(i)))
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+func getRCFileFlagKeyOf(field reflect.StructField) string {
+	return strings.Split(field.Tag.Get("yaml"), ",")[0]
+}
+
+// findRCFileFlagField returns the index of the field of `GAIRCFileDefaultsFlags`
+// for `path`, which can be a full path like `defaults.flags.model` or
+// only the key like `model`, and the key itself.
+func findRCFileFlagField(path string) (int, string, error) {
+	key := strings.TrimSpace(strings.ToLower(path))
+	key = strings.TrimPrefix(key, rcFileFlagsPathPrefix)
+
+	t := reflect.TypeOf(GAIRCFileDefaultsFlags{})
+	for i := 0; i < t.NumField(); i++ {
+		if getRCFileFlagKeyOf(t.Field(i)) == key {
+			return i, key, nil
+		}
+	}
+
+	return -1, key, NewTypedError(ErrorTypeUsage, fmt.Errorf("unknown setting '%s', use one of: %s", path, strings.Join(GetRCFileFlagKeys(), ", ")))
+}
+
+// GetFlagValue returns the value of the setting `path` inside `defaults.flags`
+// or `nil`, if it is not defined.
+func (rc *GAIRCFile) GetFlagValue(path string) (any, error) {
+	i, _, err := findRCFileFlagField(path)
+	if err != nil {
+		return nil, err
+	}
+
+	v := reflect.ValueOf(rc.Defaults.Flags).Field(i)
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return nil, nil
+		}
+		return v.Elem().Interface(), nil
+	case reflect.Slice, reflect.String:
+		if v.Len() == 0 {
+			return nil, nil
+		}
+	}
+
+	return v.Interface(), nil
+}
+
+// ParseRCFileFlagValue converts `values` into the type of the setting `path`
+// inside `defaults.flags` and returns it together with its key.
+// Lists take all `values`, all other types exactly one.
+func ParseRCFileFlagValue(path string, values []string) (any, string, error) {
+	i, key, err := findRCFileFlagField(path)
+	if err != nil {
+		return nil, key, err
+	}
+
+	t := reflect.TypeOf(GAIRCFileDefaultsFlags{}).Field(i).Type
+	if t.Kind() == reflect.Slice {
+		return values, key, nil
+	}
+
+	if len(values) != 1 {
+		return nil, key, NewTypedError(ErrorTypeUsage, fmt.Errorf("setting '%s' requires exactly one value", key))
+	}
+	value := strings.TrimSpace(values[0])
+
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, key, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%s' is no valid boolean value for '%s'", value, key))
+		}
+		return b, key, nil
+	case reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, key, NewTypedError(ErrorTypeUsage, fmt.Errorf("'%s' is no valid integer value for '%s'", value, key))
+		}
+		return n, key, nil
+	}
+
+	return value, key, nil
 }
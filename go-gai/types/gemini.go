@@ -0,0 +1,99 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+// GeminiContent stores data of an item inside `contents` property
+// of a Gemini `generateContent` request or response.
+type GeminiContent struct {
+	// Parts stores the list of parts.
+	Parts []GeminiContentPart `json:"parts"`
+	// Role stores the role like `user` or `model`.
+	Role string `json:"role,omitempty"`
+}
+
+// GeminiContentPart is an item inside `parts` property of a `GeminiContent` object.
+type GeminiContentPart struct {
+	// InlineData stores binary data like images.
+	InlineData *GeminiContentPartInlineData `json:"inlineData,omitempty"`
+	// Text stores the text content.
+	Text string `json:"text,omitempty"`
+}
+
+// GeminiContentPartInlineData stores data for `inlineData` property
+// of a `GeminiContentPart` object.
+type GeminiContentPartInlineData struct {
+	// Data stores the data in Base64 format.
+	Data string `json:"data"`
+	// MimeType stores the MIME type of the data.
+	MimeType string `json:"mimeType"`
+}
+
+// GeminiGenerateContentResponse stores data of a successful
+// Gemini `generateContent` API response.
+type GeminiGenerateContentResponse struct {
+	// Candidates contains list of candidates.
+	Candidates []GeminiGenerateContentResponseCandidate `json:"candidates"`
+	// ModelVersion stores the used model.
+	ModelVersion string `json:"modelVersion"`
+	// UsageMetadata stores the used resources.
+	UsageMetadata GeminiGenerateContentResponseUsageMetadata `json:"usageMetadata"`
+}
+
+func (r *GeminiGenerateContentResponse) getAnswer() string {
+	answer := ""
+	if len(r.Candidates) > 0 {
+		for _, p := range r.Candidates[0].Content.Parts {
+			answer += p.Text
+		}
+	}
+
+	return answer
+}
+
+// GeminiGenerateContentResponseCandidate is an item inside `candidates` property
+// of a `GeminiGenerateContentResponse` object.
+type GeminiGenerateContentResponseCandidate struct {
+	// Content stores the content.
+	Content GeminiContent `json:"content"`
+	// FinishReason stores the reason why generation stopped.
+	FinishReason string `json:"finishReason"`
+}
+
+func (r *GeminiGenerateContentResponse) getUsage() *AIUsage {
+	return &AIUsage{
+		CompletionTokens: int64(r.UsageMetadata.CandidatesTokenCount),
+		PromptTokens:     int64(r.UsageMetadata.PromptTokenCount),
+		TotalTokens:      int64(r.UsageMetadata.TotalTokenCount),
+	}
+}
+
+// GeminiGenerateContentResponseUsageMetadata contains data for `usageMetadata` property
+// of a `GeminiGenerateContentResponse` object.
+type GeminiGenerateContentResponseUsageMetadata struct {
+	// CandidatesTokenCount stores number of completion tokens.
+	CandidatesTokenCount int32 `json:"candidatesTokenCount"`
+	// PromptTokenCount stores number of prompt tokens.
+	PromptTokenCount int32 `json:"promptTokenCount"`
+	// TotalTokenCount stores number of total used tokens.
+	TotalTokenCount int32 `json:"totalTokenCount"`
+}
@@ -0,0 +1,733 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/mkloubert/gai/utils"
+)
+
+// GeminiClient is an `AIClient` implementation for Google Gemini.
+type GeminiClient struct {
+	apiKey    string
+	app       *AppContext
+	chatModel string
+}
+
+type geminiGetModelListResponse struct {
+	Models []geminiGetModelListItem `json:"models"`
+}
+
+type geminiGetModelListItem struct {
+	Description                string   `json:"description"`
+	DisplayName                string   `json:"displayName"`
+	InputTokenLimit            int64    `json:"inputTokenLimit"`
+	Name                       string   `json:"name"`
+	OutputTokenLimit           int64    `json:"outputTokenLimit"`
+	SupportedGenerationMethods []string `json:"supportedGenerationMethods"`
+}
+
+func (c *GeminiClient) appendConversationItemTo(systemInstruction *GeminiContent, contents []GeminiContent, item *ConversationRepositoryConversationItem) ([]GeminiContent, error) {
+	if item.Contents == nil {
+		return contents, nil
+	}
+
+	app := c.app
+
+	parts := make([]GeminiContentPart, 0)
+
+	for _, content := range item.Contents {
+		if content.Type == "text" {
+			parts = append(parts, GeminiContentPart{
+				Text: content.Content,
+			})
+		} else {
+			// image, audio or any other file as data URI
+
+			base64Data, mimeType, err := utils.GetPartsOfDataURI(content.Content)
+			if err != nil {
+				return contents, err
+			}
+
+			parts = append(parts, GeminiContentPart{
+				InlineData: &GeminiContentPartInlineData{
+					Data:     base64Data,
+					MimeType: mimeType,
+				},
+			})
+		}
+	}
+
+	role := item.Role
+	if role == app.GetSystemRole() || role == "system" {
+		// system prompt is submitted in `systemInstruction`
+		systemInstruction.Parts = append(systemInstruction.Parts, parts...)
+
+		return contents, nil
+	}
+
+	if role == "assistant" {
+		role = "model"
+	}
+
+	contents = append(contents, GeminiContent{
+		Parts: parts,
+		Role:  role,
+	})
+
+	return contents, nil
+}
+
+func (c *GeminiClient) appendFilesTo(item *ConversationRepositoryConversationItem, files []io.Reader) error {
+	defer c.app.StartTiming(TimingPhaseFiles)()
+
+	for _, f := range files {
+		if f != nil {
+			data, err := io.ReadAll(f)
+			if err != nil {
+				return err
+			}
+
+			mimeType := utils.DetectMime(data)
+
+			if strings.HasPrefix(mimeType, "image/") {
+				dataURI, err := c.AsSupportedImageFormatString(data)
+				if err != nil {
+					return err
+				}
+
+				newUserImageItem := &ConversationRepositoryConversationItemContentItem{
+					Content: dataURI,
+					Type:    "image",
+				}
+				item.Contents = append(item.Contents, newUserImageItem)
+			} else if strings.HasPrefix(mimeType, "audio/") {
+				dataURI, err := c.AsSupportedAudioFormatString(data)
+				if err != nil {
+					return err
+				}
+
+				newUserAudioItem := &ConversationRepositoryConversationItemContentItem{
+					Content: dataURI,
+					Type:    "audio",
+				}
+				item.Contents = append(item.Contents, newUserAudioItem)
+			} else {
+				newUserFileItem, err := c.app.NewAttachmentContentItem(data, mimeType, getNameOfReader(f))
+				if err != nil {
+					return err
+				}
+
+				item.Contents = append(item.Contents, newUserFileItem)
+			}
+		}
+	}
+
+	return nil
+}
+
+// AsSupportedAudioFormatString reads data as audio and tries to convert
+// it to a supported data format as data URI.
+func (c *GeminiClient) AsSupportedAudioFormatString(b []byte) (string, error) {
+	mimeType := utils.DetectMime(b)
+	encoded := base64.StdEncoding.EncodeToString(b)
+	dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, encoded)
+
+	if strings.HasPrefix(mimeType, "audio/") {
+		return dataURI, nil
+	}
+	return dataURI, fmt.Errorf("mime type '%v' is not a supported audio format", mimeType)
+}
+
+// AsSupportedImageFormatString reads data as image and tries to convert
+// it to a supported data format as data URI.
+func (c *GeminiClient) AsSupportedImageFormatString(b []byte) (string, error) {
+	mimeType := utils.DetectMime(b)
+	encoded := base64.StdEncoding.EncodeToString(b)
+	dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, encoded)
+
+	if strings.HasPrefix(mimeType, "image/") {
+		if strings.HasSuffix(mimeType, "/jpeg") || strings.HasSuffix(mimeType, "/jpg") || strings.HasSuffix(mimeType, "/png") || strings.HasSuffix(mimeType, "/webp") || strings.HasSuffix(mimeType, "/heic") || strings.HasSuffix(mimeType, "/heif") {
+			return dataURI, nil
+		}
+
+		pngData, err := utils.EnsurePNG(b)
+		if err == nil {
+			encoded = base64.StdEncoding.EncodeToString(pngData)
+			dataURI = fmt.Sprintf("data:%s;base64,%s", "image/png", encoded)
+		}
+
+		return dataURI, err
+	}
+	return dataURI, fmt.Errorf("mime type '%v' is not a supported image format", mimeType)
+}
+
+// Chat starts or continues a chat conversation with message in `msg` based on `ctx` and returns the new conversation.
+func (c *GeminiClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOptions) (string, ConversationRepositoryConversation, error) {
+	conversation, err := ctx.GetConversation()
+	if err != nil {
+		return "", conversation, err
+	}
+
+	model := strings.TrimSpace(strings.ToLower(c.chatModel))
+	if model == "" {
+		return "", conversation, fmt.Errorf("no chat ai model defined")
+	}
+
+	noSave := false
+	systemPrompt := ""
+	for _, o := range opts {
+		if o.NoSave != nil {
+			noSave = *o.NoSave
+		}
+		if o.SystemPrompt != nil {
+			systemPrompt = *o.SystemPrompt
+		}
+		if len(o.Tools) > 0 || len(o.ToolResults) > 0 {
+			return "", conversation, errors.New("tool calling is not supported by Gemini client yet")
+		}
+	}
+
+	conversation = c.setupSystemPromptIfNeeded(conversation, systemPrompt, model)
+
+	app := ctx.App
+
+	var schema *map[string]any
+	for _, o := range opts {
+		if o.ResponseSchema != nil {
+			schema = o.ResponseSchema
+		}
+	}
+
+	userMessage := &ConversationRepositoryConversationItem{
+		Contents: make(ConversationRepositoryConversationItemContents, 0),
+		Model:    model,
+		Role:     "user",
+	}
+	newUserTextItem := &ConversationRepositoryConversationItemContentItem{
+		Content: msg,
+		Type:    "text",
+	}
+	userMessage.Contents = append(userMessage.Contents, newUserTextItem)
+
+	// add response format
+	err = c.writeResponseFormatTo(userMessage, schema)
+	if err != nil {
+		return "", conversation, err
+	}
+
+	// add files
+	for _, o := range opts {
+		if o.Files == nil {
+			continue
+		}
+
+		err := c.appendFilesTo(userMessage, *o.Files)
+		if err != nil {
+			return "", conversation, err
+		}
+	}
+
+	userMessage.Time = app.GetISOTime()
+
+	history, err := ctx.LimitConversationHistory(conversation, model)
+	if err != nil {
+		return "", conversation, err
+	}
+
+	allItems := make(ConversationRepositoryConversation, 0)
+	allItems = append(allItems, history...)
+	allItems = append(allItems, userMessage)
+
+	chatResponse, err := c.generateContent(model, allItems, schema)
+	if err != nil {
+		return "", conversation, err
+	}
+
+	responseTime := app.GetISOTime()
+
+	answer := chatResponse.getAnswer()
+
+	// update conversation
+	{
+		conversation = append(conversation, userMessage)
+
+		responseModel := chatResponse.ModelVersion
+		if responseModel == "" {
+			responseModel = model
+		}
+
+		// take assistant message
+		assistantMessage := &ConversationRepositoryConversationItem{
+			Contents: make(ConversationRepositoryConversationItemContents, 0),
+			Model:    responseModel,
+			Role:     "assistant",
+			Time:     responseTime,
+			Usage:    chatResponse.getUsage(),
+		}
+		assistantMessage.Contents = append(assistantMessage.Contents, &ConversationRepositoryConversationItemContentItem{
+			Content: answer,
+			Type:    "text",
+		})
+		conversation = append(conversation, assistantMessage)
+	}
+
+	if !noSave {
+		err := ctx.UpdateConversationWith(conversation)
+		if err != nil {
+			return answer, conversation, err
+		}
+	}
+
+	return answer, conversation, nil
+}
+
+// ChatModel returns the current chat model.
+func (c *GeminiClient) ChatModel() string {
+	return c.chatModel
+}
+
+func (c *GeminiClient) generateContent(model string, conversation ConversationRepositoryConversation, schema *map[string]any) (*GeminiGenerateContentResponse, error) {
+	apiKey := strings.TrimSpace(c.apiKey)
+	if apiKey == "" {
+		return nil, NewTypedError(ErrorTypeAuth, fmt.Errorf("no Gemini api key defined"))
+	}
+
+	app := c.app
+
+	maxTokens, err := app.GetMaxTokens()
+	if err != nil {
+		return nil, err
+	}
+
+	temperature, err := app.GetTemperature()
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%v/v1beta/models/%v:generateContent", c.getBaseUrl(), model)
+
+	systemInstruction := &GeminiContent{
+		Parts: make([]GeminiContentPart, 0),
+	}
+	contents := make([]GeminiContent, 0)
+
+	for _, item := range conversation {
+		cnts, err := c.appendConversationItemTo(systemInstruction, contents, item)
+		if err != nil {
+			return nil, err
+		}
+
+		contents = cnts
+	}
+
+	generationConfig := map[string]any{
+		"temperature": temperature,
+	}
+	if maxTokens != nil {
+		generationConfig["maxOutputTokens"] = *maxTokens
+	}
+	if schema != nil {
+		// `responseSchema` only supports a subset of OpenAPI,
+		// but schemas of the app are plain JSON Schema
+		generationConfig["responseMimeType"] = "application/json"
+		generationConfig["responseJsonSchema"] = schema
+	}
+
+	body := map[string]any{
+		"contents":         contents,
+		"generationConfig": generationConfig,
+	}
+	if len(systemInstruction.Parts) > 0 {
+		body["systemInstruction"] = systemInstruction
+	}
+
+	jsonData, err := json.Marshal(&body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	if err != nil {
+		return nil, err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("x-goog-api-key", apiKey)
+
+	err = app.DumpRequestAsCurlIfNeeded(req, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	// ... and finally send the JSON data
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// load the response
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	var generateResponse GeminiGenerateContentResponse
+	err = json.Unmarshal(responseData, &generateResponse)
+	if err != nil {
+		return nil, err
+	}
+
+	return &generateResponse, nil
+}
+
+func (c *GeminiClient) getBaseUrl() string {
+	baseUrl := c.app.GetBaseUrl(c.Provider())
+	if baseUrl == "" {
+		baseUrl = "https://generativelanguage.googleapis.com" // use default
+	}
+
+	return baseUrl
+}
+
+// Embed creates embedding vectors for each of the `texts`.
+func (c *GeminiClient) Embed(texts []string, opts ...AIClientEmbedOptions) (AIClientEmbedResponse, error) {
+	return AIClientEmbedResponse{}, fmt.Errorf("embeddings are not supported by %v provider", c.Provider())
+}
+
+// GenerateImage creates one or more images from `prompt`.
+func (c *GeminiClient) GenerateImage(prompt string, opts ...AIClientGenerateImageOptions) (AIClientGenerateImageResponse, error) {
+	return AIClientGenerateImageResponse{}, fmt.Errorf("image generation is not supported by %v provider", c.Provider())
+}
+
+// GetModelInfo loads name, description and token limits of `model`
+// from `/v1beta/models/{model}`.
+func (c *GeminiClient) GetModelInfo(model string) (AIModelInfo, error) {
+	info := AIModelInfo{}
+
+	apiKey := strings.TrimSpace(c.apiKey)
+	if apiKey == "" {
+		return info, NewTypedError(ErrorTypeAuth, fmt.Errorf("no Gemini api key defined"))
+	}
+
+	url := fmt.Sprintf("%s/v1beta/models/%s", c.getBaseUrl(), strings.TrimPrefix(model, "models/"))
+
+	req, err := c.app.NewHttpRequest("GET", url, bytes.NewBuffer([]byte{}))
+	if err != nil {
+		return info, err
+	}
+
+	// setup
+	req.Header.Set("x-goog-api-key", apiKey)
+
+	// ... and finally send the request
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return info, err
+	}
+
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
+
+	var item geminiGetModelListItem
+	err = json.Unmarshal(responseData, &item)
+	if err != nil {
+		return info, err
+	}
+
+	info.Description = item.Description
+	info.DisplayName = item.DisplayName
+	if item.InputTokenLimit > 0 {
+		info.ContextWindow = &item.InputTokenLimit
+	}
+	if item.OutputTokenLimit > 0 {
+		info.MaxOutputTokens = &item.OutputTokenLimit
+	}
+
+	return info, nil
+}
+
+// Returns the list of supported Gemini models,
+// which are cached for the time of `GetModelsCacheTTL`.
+func (c *GeminiClient) GetModels() ([]AIModel, error) {
+	apiKey := strings.TrimSpace(c.apiKey)
+	if apiKey == "" {
+		return make([]AIModel, 0), NewTypedError(ErrorTypeAuth, fmt.Errorf("no Gemini api key defined"))
+	}
+
+	return c.app.getCachedModels(c, c.getBaseUrl(), func() ([]AIModel, error) {
+		return c.loadModels(apiKey)
+	})
+}
+
+func (c *GeminiClient) loadModels(apiKey string) ([]AIModel, error) {
+	models := make([]AIModel, 0)
+
+	url := fmt.Sprintf("%s/v1beta/models?pageSize=1000", c.getBaseUrl())
+
+	req, err := c.app.NewHttpRequest("GET", url, bytes.NewBuffer([]byte{}))
+	if err != nil {
+		return models, err
+	}
+
+	// setup
+	req.Header.Set("x-goog-api-key", apiKey)
+
+	// ... and finally send the request
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return models, err
+	}
+	defer resp.Body.Close()
+
+	err = utils.CheckForHttpResponseError(resp)
+	if err != nil {
+		return models, err
+	}
+
+	// load the response
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return models, err
+	}
+
+	var listResponse geminiGetModelListResponse
+	err = json.Unmarshal(responseData, &listResponse)
+	if err != nil {
+		return models, err
+	}
+
+	for _, item := range listResponse.Models {
+		canGenerateContent := false
+		for _, m := range item.SupportedGenerationMethods {
+			if m == "generateContent" {
+				canGenerateContent = true
+				break
+			}
+		}
+
+		if !canGenerateContent {
+			continue
+		}
+
+		models = append(models, AIModel{
+			client:    c,
+			modelType: "",
+			name:      strings.TrimPrefix(item.Name, "models/"),
+		})
+	}
+
+	return models, nil
+}
+
+// Prompt does a single AI prompt with a specific `msg`.
+func (c *GeminiClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClientPromptResponse, error) {
+	promptResponse := AIClientPromptResponse{
+		Content: "",
+		Model:   "",
+	}
+
+	model := strings.TrimSpace(strings.ToLower(c.chatModel))
+	if model == "" {
+		return promptResponse, fmt.Errorf("no chat ai model defined")
+	}
+
+	systemPrompt := ""
+	for _, o := range opts {
+		if o.SystemPrompt != nil {
+			systemPrompt = *o.SystemPrompt
+		}
+	}
+
+	tempConversation := make(ConversationRepositoryConversation, 0)
+	tempConversation = c.setupSystemPromptIfNeeded(tempConversation, systemPrompt, model)
+
+	promptResponse.Model = model
+
+	var schema *map[string]any
+	for _, o := range opts {
+		if o.ResponseSchema != nil {
+			schema = o.ResponseSchema
+		}
+	}
+
+	userMessage := &ConversationRepositoryConversationItem{
+		Contents: make(ConversationRepositoryConversationItemContents, 0),
+		Model:    model,
+		Role:     "user",
+	}
+	newUserTextItem := &ConversationRepositoryConversationItemContentItem{
+		Content: msg,
+		Type:    "text",
+	}
+	userMessage.Contents = append(userMessage.Contents, newUserTextItem)
+
+	// add response format
+	err := c.writeResponseFormatTo(userMessage, schema)
+	if err != nil {
+		return promptResponse, err
+	}
+
+	// add files
+	for _, o := range opts {
+		if o.Files == nil {
+			continue
+		}
+
+		err := c.appendFilesTo(userMessage, *o.Files)
+		if err != nil {
+			return promptResponse, err
+		}
+	}
+
+	tempConversation = append(tempConversation, userMessage)
+
+	generateResponse, err := c.generateContent(model, tempConversation, schema)
+	if err != nil {
+		return promptResponse, err
+	}
+
+	promptResponse.Content = generateResponse.getAnswer()
+	promptResponse.Usage = generateResponse.getUsage()
+	if generateResponse.ModelVersion != "" {
+		promptResponse.Model = generateResponse.ModelVersion
+	}
+
+	return promptResponse, nil
+}
+
+// Provider returns the name of the provider.
+func (c *GeminiClient) Provider() string {
+	return "gemini"
+}
+
+// SetChatModel sets the current chat model.
+func (c *GeminiClient) SetChatModel(m string) error {
+	c.chatModel = m
+	return nil
+}
+
+func (c *GeminiClient) setupSystemPromptIfNeeded(conversation ConversationRepositoryConversation, defaultPrompt string, model string) ConversationRepositoryConversation {
+	if len(conversation) == 0 {
+		// only if no conversation yet ...
+
+		app := c.app
+
+		systemPrompt := strings.TrimSpace(
+			app.GetSystemPrompt(defaultPrompt),
+		)
+		if systemPrompt != "" {
+			// ... system prompt is defined
+
+			systemMessage := &ConversationRepositoryConversationItem{
+				Contents: make(ConversationRepositoryConversationItemContents, 0),
+				Model:    model,
+				Role:     app.GetSystemRole(),
+				Time:     app.GetISOTime(),
+			}
+			newTextItem := &ConversationRepositoryConversationItemContentItem{
+				Content: systemPrompt,
+				Type:    "text",
+			}
+			systemMessage.Contents = append(systemMessage.Contents, newTextItem)
+
+			conversation = append(conversation, systemMessage)
+		}
+	}
+
+	return conversation
+}
+
+func (c *GeminiClient) writeResponseFormatTo(item *ConversationRepositoryConversationItem, schema *map[string]any) error {
+	if schema != nil {
+		jsonData, err := json.Marshal(schema)
+		if err != nil {
+			return err
+		}
+
+		item.ResponseFormat = string(jsonData)
+	}
+
+	return nil
+}
+
+// Transcribe converts speech of audio data from `r` to text.
+func (c *GeminiClient) Transcribe(r io.Reader, opts ...AIClientTranscribeOptions) (AIClientTranscribeResponse, error) {
+	transcribeResponse := AIClientTranscribeResponse{}
+
+	// work on a copy, so a custom model is only used
+	// for this request
+	client := *c
+
+	language := ""
+	for _, o := range opts {
+		if o.Language != nil {
+			language = strings.TrimSpace(*o.Language)
+		}
+		if o.Model != nil && strings.TrimSpace(*o.Model) != "" {
+			client.chatModel = strings.TrimSpace(*o.Model)
+		}
+	}
+
+	systemPrompt := `You are a transcriber.
+Generate a verbatim transcript of the submitted audio.
+Answer only with the transcript and nothing else.`
+	if language != "" {
+		systemPrompt += fmt.Sprintf("\nThe spoken language is '%s'.", language)
+	}
+
+	response, err := client.Prompt("Transcribe this audio.", AIClientPromptOptions{
+		Files:        &[]io.Reader{r},
+		SystemPrompt: &systemPrompt,
+	})
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	transcribeResponse.Content = response.Content
+	transcribeResponse.Model = response.Model
+
+	return transcribeResponse, nil
+}
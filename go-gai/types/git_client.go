@@ -36,7 +36,7 @@ import (
 
 // GitClient handles git operations for an `AppContext`.
 type GitClient struct {
-	app AppContext
+	app *AppContext
 	dir string
 }
 
@@ -53,6 +53,64 @@ func (g *GitClient) Dir() string {
 	return g.dir
 }
 
+// Diff returns the diff and the list of changed files of `git diff` with
+// additional `args`, like `--cached` or revisions.
+func (g *GitClient) Diff(args ...string) (string, []*GitFile, error) {
+	changedFiles := make([]*GitFile, 0)
+
+	nameStatusArgs := append([]string{"diff", "--name-status"}, args...)
+	nameStatusCmd := g.CreateExecCommand("git", nameStatusArgs...)
+
+	output, err := nameStatusCmd.Output()
+	if err != nil {
+		return "", changedFiles, err
+	}
+
+	lines := strings.SplitSeq(strings.TrimSpace(string(output)), "\n")
+	for line := range lines {
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+
+		// renamed and copied files have status like `R100` and the new name at the end
+		changedFiles = append(changedFiles, &GitFile{
+			changeStatus: strings.ToUpper(fields[0][:1]),
+			git:          g,
+			name:         fields[len(fields)-1],
+			status:       "changed",
+		})
+	}
+
+	diff, err := g.DiffFile("", args...)
+
+	return diff, changedFiles, err
+}
+
+// DiffBranches returns the diff and the list of changed files of the current
+// branch compared to the branch `base`, starting from their merge base.
+func (g *GitClient) DiffBranches(base string) (string, []*GitFile, error) {
+	return g.Diff(fmt.Sprintf("%s...HEAD", base))
+}
+
+// DiffFile returns the diff of `git diff` with additional `args`, like
+// `--cached` or revisions, limited to the file `name`, if not empty.
+func (g *GitClient) DiffFile(name string, args ...string) (string, error) {
+	diffArgs := append([]string{"diff", "--no-color"}, args...)
+	if name != "" {
+		diffArgs = append(diffArgs, "--", name)
+	}
+
+	diffCmd := g.CreateExecCommand("git", diffArgs...)
+
+	var out bytes.Buffer
+	diffCmd.Stdout = &out
+
+	err := diffCmd.Run()
+
+	return out.String(), err
+}
+
 // GetAllCommits returns all commits.
 func (g *GitClient) GetAllCommits() ([]*GitCommit, error) {
 	commits := make([]*GitCommit, 0)
@@ -118,6 +176,58 @@ func (g *GitClient) GetChangedFiles() ([]*GitFile, error) {
 	return changedFiles, nil
 }
 
+// GetCommitsBetween returns the commits, which are reachable from `to` but
+// not from `from`, starting with the newest one. If `from` is empty, all
+// commits up to `to` are returned.
+func (g *GitClient) GetCommitsBetween(from string, to string) ([]*GitCommit, error) {
+	commits := make([]*GitCommit, 0)
+
+	revisionRange := to
+	if from != "" {
+		revisionRange = fmt.Sprintf("%s..%s", from, to)
+	}
+
+	cmd := g.CreateExecCommand("git", "log", "--pretty=format:%H", revisionRange)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return commits, err
+	}
+
+	commitsHashes := strings.SplitSeq(strings.TrimSpace(string(output)), "\n")
+	for hash := range commitsHashes {
+		hash = strings.TrimSpace(strings.ToLower(hash))
+		if hash == "" {
+			continue
+		}
+
+		commits = append(commits, &GitCommit{
+			git:  g,
+			hash: hash,
+		})
+	}
+
+	return commits, nil
+}
+
+// GetDiff returns the diff of the working tree or, if `staged` is `true`,
+// of the staging area.
+func (g *GitClient) GetDiff(staged bool) (string, error) {
+	args := []string{"diff"}
+	if staged {
+		args = append(args, "--cached")
+	}
+
+	cmd := g.CreateExecCommand("git", args...)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	err := cmd.Run()
+
+	return out.String(), err
+}
+
 // GetFiles returns list of files related to this client / repository.
 func (g *GitClient) GetFiles() ([]*GitFile, error) {
 	app := g.app
@@ -212,6 +322,27 @@ func (g *GitClient) GetLatestCommit() (*GitCommit, error) {
 	return commit, nil
 }
 
+// GetLatestTag returns the name of the latest tag, which is reachable
+// from `HEAD`, or an empty string if there is none.
+func (g *GitClient) GetLatestTag() (string, error) {
+	cmd := g.CreateExecCommand("git", "describe", "--tags", "--abbrev=0")
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if strings.Contains(stderr.String(), "No names found") ||
+			strings.Contains(stderr.String(), "No tags can describe") {
+			return "", nil // no tags
+		}
+
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // GetStagedFiles returns the list of staged files.
 func (g *GitClient) GetStagedFiles() ([]*GitFile, error) {
 	gitFiles := make([]*GitFile, 0)
@@ -252,15 +383,16 @@ func (g *GitClient) GetStagedFiles() ([]*GitFile, error) {
 	return gitFiles, nil
 }
 
-// GetGitIgnore loads .gitignore file if available.
+// GetGitIgnore loads .gitignore file if available
+// or returns `nil` if there is none.
 func (g *GitClient) GetGitIgnore() (*ignore.GitIgnore, error) {
 	dir := g.dir
 	var gitignore *ignore.GitIgnore
 
 	file := filepath.Join(dir, ".gitignore")
 
-	if _, err := os.Stat(dir); os.IsNotExist(err) {
-		// file not found
+	if _, err := os.Stat(file); os.IsNotExist(err) {
+		return gitignore, nil // file not found
 	} else if err != nil {
 		// other error
 		return gitignore, err
@@ -23,6 +23,7 @@
 package types
 
 import (
+	"bytes"
 	"strings"
 )
 
@@ -37,6 +38,21 @@ func (gc *GitCommit) Diff(f *GitFile) (string, error) {
 	return f.CompareWith(gc)
 }
 
+// GetDiff returns the own diff of this commit, which are the changes
+// compared to its parent.
+func (gc *GitCommit) GetDiff() (string, error) {
+	git := gc.git
+
+	cmd := git.CreateExecCommand("git", "show", "--format=", "--patch", "--no-color", gc.hash)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+
+	err := cmd.Run()
+
+	return out.String(), err
+}
+
 // GetFiles returns the list of files of this commit.
 func (gc *GitCommit) GetFiles() ([]*GitFile, error) {
 	git := gc.git
@@ -68,6 +84,20 @@ func (gc *GitCommit) GetFiles() ([]*GitFile, error) {
 	return gitFiles, nil
 }
 
+// GetMessage returns the full message of this commit.
+func (gc *GitCommit) GetMessage() (string, error) {
+	git := gc.git
+
+	cmd := git.CreateExecCommand("git", "log", "-1", "--format=%B", gc.hash)
+
+	output, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
 // Hash returns the current hash of the commit.
 func (gc *GitCommit) Hash() string {
 	return gc.hash
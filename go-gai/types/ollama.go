@@ -33,12 +33,24 @@ type OllamaAIChatMessage struct {
 	Role string `json:"role,omitempty"`
 }
 
+// OllamaApiEmbedResponse is the data of a successful embed response.
+type OllamaApiEmbedResponse struct {
+	// Embeddings stores the vectors in the same order as the input.
+	Embeddings [][]float32 `json:"embeddings,omitempty"`
+	// Model stores the model that has been used.
+	Model string `json:"model,omitempty"`
+}
+
 // OllamaApiResponse is the data of a successful chat conversation response.
 type OllamaApiChatCompletionResponse struct {
 	// Message stores the message.
 	Message OllamaAIChatMessage `json:"message,omitempty"`
 	// Model stores the model that has been used.
 	Model string `json:"model,omitempty"`
+	// EvalCount stores number of completion tokens.
+	EvalCount int64 `json:"eval_count,omitempty"`
+	// PromptEvalCount stores number of prompt tokens.
+	PromptEvalCount int64 `json:"prompt_eval_count,omitempty"`
 }
 
 // OllamaApiCompletionResponse is the data of a successful completion response.
@@ -47,4 +59,16 @@ type OllamaApiCompletionResponse struct {
 	Model string `json:"model,omitempty"`
 	// Response stores the messagefrom assistant.
 	Response string `json:"response,omitempty"`
+	// EvalCount stores number of completion tokens.
+	EvalCount int64 `json:"eval_count,omitempty"`
+	// PromptEvalCount stores number of prompt tokens.
+	PromptEvalCount int64 `json:"prompt_eval_count,omitempty"`
+}
+
+func newOllamaUsage(promptEvalCount int64, evalCount int64) *AIUsage {
+	return &AIUsage{
+		CompletionTokens: evalCount,
+		PromptTokens:     promptEvalCount,
+		TotalTokens:      promptEvalCount + evalCount,
+	}
 }
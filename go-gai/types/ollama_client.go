@@ -23,12 +23,14 @@
 package types
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
-	"net/http"
+	"slices"
 	"strings"
 
 	"github.com/mkloubert/gai/utils"
@@ -48,6 +50,30 @@ type ollamaGetModelListItem struct {
 	Name string `json:"name"`
 }
 
+type ollamaShowModelResponse struct {
+	Capabilities []string `json:"capabilities"`
+	Details      struct {
+		Family            string `json:"family"`
+		ParameterSize     string `json:"parameter_size"`
+		QuantizationLevel string `json:"quantization_level"`
+	} `json:"details"`
+	ModelInfo map[string]any `json:"model_info"`
+}
+
+// OllamaPullProgress stores a progress status of `PullModel`.
+type OllamaPullProgress struct {
+	// Completed stores the number of downloaded bytes, if available.
+	Completed int64 `json:"completed,omitempty"`
+	// Digest stores the digest of the layer, which is downloaded, if available.
+	Digest string `json:"digest,omitempty"`
+	// Error stores an error message from the server, if available.
+	Error string `json:"error,omitempty"`
+	// Status stores the status text.
+	Status string `json:"status"`
+	// Total stores the total number of bytes to download, if available.
+	Total int64 `json:"total,omitempty"`
+}
+
 func (c *OllamaClient) appendConversationItemTo(messages []OllamaAIChatMessage, item *ConversationRepositoryConversationItem) ([]OllamaAIChatMessage, error) {
 	if item.Contents != nil {
 		newMessage := &OllamaAIChatMessage{
@@ -73,6 +99,8 @@ func (c *OllamaClient) appendConversationItemTo(messages []OllamaAIChatMessage,
 }
 
 func (c *OllamaClient) appendFilesTo(item *ConversationRepositoryConversationItem, files []io.Reader) error {
+	defer c.app.StartTiming(TimingPhaseFiles)()
+
 	for _, f := range files {
 		if f != nil {
 			data, err := io.ReadAll(f)
@@ -156,6 +184,9 @@ func (c *OllamaClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 		if o.SystemPrompt != nil {
 			systemPrompt = *o.SystemPrompt
 		}
+		if len(o.Tools) > 0 || len(o.ToolResults) > 0 {
+			return "", conversation, errors.New("tool calling is not supported by Ollama client yet")
+		}
 	}
 
 	conversation = c.setupSystemPromptIfNeeded(conversation, systemPrompt, model)
@@ -167,7 +198,7 @@ func (c *OllamaClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 		return "", conversation, err
 	}
 
-	baseUrl := app.GetBaseUrl()
+	baseUrl := app.GetBaseUrl(c.Provider())
 	if baseUrl == "" {
 		baseUrl = "http://localhost:11434" // use default
 	}
@@ -210,10 +241,15 @@ func (c *OllamaClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 		}
 	}
 
+	history, err := ctx.LimitConversationHistory(conversation, model)
+	if err != nil {
+		return "", conversation, err
+	}
+
 	messages := []OllamaAIChatMessage{}
 
 	// add previous conversation
-	for _, item := range conversation {
+	for _, item := range history {
 		m, err := c.appendConversationItemTo(messages, item)
 		if err != nil {
 			return "", conversation, err
@@ -247,16 +283,21 @@ func (c *OllamaClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 
 	userMessage.Time = app.GetISOTime()
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
 	if err != nil {
 		return "", conversation, err
 	}
 
 	// setup ...
 	req.Header.Set("Content-Type", "application/json")
+
+	err = app.DumpRequestAsCurlIfNeeded(req, jsonData)
+	if err != nil {
+		return "", conversation, err
+	}
+
 	// ... and finally send the JSON data
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.app.SendHttpRequest(req)
 	if err != nil {
 		return "", conversation, err
 	}
@@ -264,7 +305,7 @@ func (c *OllamaClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 
 	responseTime := app.GetISOTime()
 
-	err = utils.CheckForHttpResponseError(resp)
+	err = checkForModelHttpResponseError(resp)
 	if err != nil {
 		return "", conversation, err
 	}
@@ -293,6 +334,7 @@ func (c *OllamaClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 			Model:    chatResponse.Model,
 			Role:     "assistant",
 			Time:     responseTime,
+			Usage:    newOllamaUsage(chatResponse.PromptEvalCount, chatResponse.EvalCount),
 		}
 		assistantMessage.Contents = append(assistantMessage.Contents, &ConversationRepositoryConversationItemContentItem{
 			Content: answer,
@@ -316,27 +358,189 @@ func (c *OllamaClient) ChatModel() string {
 	return c.chatModel
 }
 
+// Embed creates embedding vectors for each of the `texts`.
+func (c *OllamaClient) Embed(texts []string, opts ...AIClientEmbedOptions) (AIClientEmbedResponse, error) {
+	embedResponse := AIClientEmbedResponse{}
+
+	app := c.app
+
+	model := "nomic-embed-text"
+	for _, o := range opts {
+		if o.Model != nil && strings.TrimSpace(*o.Model) != "" {
+			model = strings.TrimSpace(*o.Model)
+		}
+	}
+
+	embedResponse.Model = model
+
+	baseUrl := app.GetBaseUrl(c.Provider())
+	if baseUrl == "" {
+		baseUrl = "http://localhost:11434" // use default
+	}
+
+	url := fmt.Sprintf("%v/api/embed", baseUrl)
+
+	body := map[string]any{
+		"input": texts,
+		"model": model,
+	}
+
+	jsonData, err := json.Marshal(&body)
+	if err != nil {
+		return embedResponse, err
+	}
+
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	if err != nil {
+		return embedResponse, err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", "application/json")
+
+	err = app.DumpRequestAsCurlIfNeeded(req, jsonData)
+	if err != nil {
+		return embedResponse, err
+	}
+
+	// ... and finally send the JSON data
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return embedResponse, err
+	}
+	defer resp.Body.Close()
+
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return embedResponse, err
+	}
+
+	// load the response
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return embedResponse, err
+	}
+
+	var ollamaResponse OllamaApiEmbedResponse
+	err = json.Unmarshal(responseData, &ollamaResponse)
+	if err != nil {
+		return embedResponse, err
+	}
+
+	if len(ollamaResponse.Embeddings) != len(texts) {
+		return embedResponse, fmt.Errorf("expected %v embeddings, but got %v", len(texts), len(ollamaResponse.Embeddings))
+	}
+
+	embedResponse.Embeddings = ollamaResponse.Embeddings
+	if strings.TrimSpace(ollamaResponse.Model) != "" {
+		embedResponse.Model = ollamaResponse.Model
+	}
+
+	return embedResponse, nil
+}
+
+// GenerateImage creates one or more images from `prompt`.
+func (c *OllamaClient) GenerateImage(prompt string, opts ...AIClientGenerateImageOptions) (AIClientGenerateImageResponse, error) {
+	return AIClientGenerateImageResponse{}, fmt.Errorf("image generation is not supported by %v provider", c.Provider())
+}
+
+// GetModelInfo loads family, size, quantization, context length and capabilities
+// of a local `model` from `/api/show`.
+func (c *OllamaClient) GetModelInfo(model string) (AIModelInfo, error) {
+	app := c.app
+
+	info := AIModelInfo{}
+
+	baseUrl := app.GetBaseUrl(c.Provider())
+	if baseUrl == "" {
+		baseUrl = "http://localhost:11434" // use default
+	}
+
+	url := fmt.Sprintf("%v/api/show", baseUrl)
+
+	jsonData, err := json.Marshal(map[string]any{
+		"model": model,
+	})
+	if err != nil {
+		return info, err
+	}
+
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	if err != nil {
+		return info, err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", "application/json")
+
+	// ... and finally send the JSON data
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return info, err
+	}
+	defer resp.Body.Close()
+
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return info, err
+	}
+
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
+
+	var showResponse ollamaShowModelResponse
+	err = json.Unmarshal(responseData, &showResponse)
+	if err != nil {
+		return info, err
+	}
+
+	info.Family = showResponse.Details.Family
+	info.ParameterSize = showResponse.Details.ParameterSize
+	info.Quantization = showResponse.Details.QuantizationLevel
+
+	for key, value := range showResponse.ModelInfo {
+		// like `llama.context_length`
+		if !strings.HasSuffix(key, ".context_length") {
+			continue
+		}
+
+		if f64, ok := value.(float64); ok {
+			contextWindow := int64(f64)
+			info.ContextWindow = &contextWindow
+		}
+	}
+
+	if len(showResponse.Capabilities) > 0 {
+		// older servers do not return capabilities
+		vision := slices.Contains(showResponse.Capabilities, "vision")
+		info.Vision = &vision
+	}
+
+	return info, nil
+}
+
 // Returns the list of supported Ollama models.
 func (c *OllamaClient) GetModels() ([]AIModel, error) {
 	app := c.app
 
 	models := make([]AIModel, 0)
 
-	baseUrl := app.GetBaseUrl()
+	baseUrl := app.GetBaseUrl(c.Provider())
 	if baseUrl == "" {
 		baseUrl = "http://localhost:11434" // use default
 	}
 
 	url := fmt.Sprintf("%s/api/tags", baseUrl)
 
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer([]byte{}))
+	req, err := c.app.NewHttpRequest("GET", url, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return models, err
 	}
 
 	// ... and finally send the JSON data
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.app.SendHttpRequest(req)
 	if err != nil {
 		return models, err
 	}
@@ -370,6 +574,85 @@ func (c *OllamaClient) GetModels() ([]AIModel, error) {
 	return models, nil
 }
 
+// PullModel downloads `model` to the Ollama server and
+// calls `onProgress` for each status of the NDJSON stream.
+func (c *OllamaClient) PullModel(model string, onProgress func(progress OllamaPullProgress)) error {
+	app := c.app
+
+	model = strings.TrimSpace(model)
+	if model == "" {
+		return fmt.Errorf("no model defined")
+	}
+
+	baseUrl := app.GetBaseUrl(c.Provider())
+	if baseUrl == "" {
+		baseUrl = "http://localhost:11434" // use default
+	}
+
+	url := fmt.Sprintf("%v/api/pull", baseUrl)
+
+	body := map[string]any{
+		"model":  model,
+		"stream": true,
+	}
+
+	jsonData, err := json.Marshal(&body)
+	if err != nil {
+		return err
+	}
+
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	if err != nil {
+		return err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", "application/json")
+
+	err = app.DumpRequestAsCurlIfNeeded(req, jsonData)
+	if err != nil {
+		return err
+	}
+
+	// ... and finally send the JSON data
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	err = utils.CheckForHttpResponseError(resp)
+	if err != nil {
+		return err
+	}
+
+	// each line is a JSON object
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var progress OllamaPullProgress
+		err = json.Unmarshal([]byte(line), &progress)
+		if err != nil {
+			return err
+		}
+
+		if progress.Error != "" {
+			return fmt.Errorf("could not pull model '%v': %v", model, progress.Error)
+		}
+
+		if onProgress != nil {
+			onProgress(progress)
+		}
+	}
+
+	return scanner.Err()
+}
+
 // Prompt does a single AI prompt with a specific `msg`.
 func (c *OllamaClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClientPromptResponse, error) {
 	promptResponse := AIClientPromptResponse{
@@ -401,7 +684,7 @@ func (c *OllamaClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 		return promptResponse, err
 	}
 
-	baseUrl := app.GetBaseUrl()
+	baseUrl := app.GetBaseUrl(c.Provider())
 	if baseUrl == "" {
 		baseUrl = "http://localhost:11434" // use default
 	}
@@ -488,22 +771,27 @@ func (c *OllamaClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 		return promptResponse, err
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
 	if err != nil {
 		return promptResponse, err
 	}
 
 	// setup ...
 	req.Header.Set("Content-Type", "application/json")
+
+	err = app.DumpRequestAsCurlIfNeeded(req, jsonData)
+	if err != nil {
+		return promptResponse, err
+	}
+
 	// ... and finally send the JSON data
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.app.SendHttpRequest(req)
 	if err != nil {
 		return promptResponse, err
 	}
 	defer resp.Body.Close()
 
-	err = utils.CheckForHttpResponseError(resp)
+	err = checkForModelHttpResponseError(resp)
 	if err != nil {
 		return promptResponse, err
 	}
@@ -524,6 +812,7 @@ func (c *OllamaClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 
 	promptResponse.Content = answer
 	promptResponse.Model = completionResponse.Model
+	promptResponse.Usage = newOllamaUsage(completionResponse.PromptEvalCount, completionResponse.EvalCount)
 
 	return promptResponse, nil
 }
@@ -587,3 +876,8 @@ func (c *OllamaClient) writeResponseFormatTo(item *ConversationRepositoryConvers
 
 	return responseFormat, nil
 }
+
+// Transcribe converts speech of audio data from `r` to text.
+func (c *OllamaClient) Transcribe(r io.Reader, opts ...AIClientTranscribeOptions) (AIClientTranscribeResponse, error) {
+	return AIClientTranscribeResponse{}, fmt.Errorf("transcription is not supported by %v provider", c.Provider())
+}
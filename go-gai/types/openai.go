@@ -49,6 +49,8 @@ type OpenAIChatCompletionResponseV1ChoiceMessage struct {
 	Content string `json:"content"`
 	// Stores the role like 'system' , 'user' or 'assistant'
 	Role string `json:"role"`
+	// ToolCalls stores the calls of tools, the assistant requests.
+	ToolCalls []OpenAIChatToolCall `json:"tool_calls,omitempty"`
 }
 
 // OpenAIChatCompletionResponseV1Usage contains data for `usage` property
@@ -68,6 +70,10 @@ type OpenAIChatMessage struct {
 	Content OpenAIChatMessageContent `json:"content,omitempty"`
 	// Role stores the role.
 	Role string `json:"role,omitempty"`
+	// ToolCallId stores the ID of the tool call, a `tool` message is the result of.
+	ToolCallId string `json:"tool_call_id,omitempty"`
+	// ToolCalls stores the calls of tools, an `assistant` message has requested.
+	ToolCalls []OpenAIChatToolCall `json:"tool_calls,omitempty"`
 }
 
 // OpenAIChatMessageContent stores list of `OpenAIChatMessageContentItem`s.
@@ -136,3 +142,168 @@ type OpenAIChatMessageContentItemFile struct {
 	// Filename stores the name of the file.
 	Filename string `json:"filename,omitempty"`
 }
+
+// OpenAIChatTool stores the definition of a tool inside `tools` property
+// of a chat completion request.
+type OpenAIChatTool struct {
+	// Function stores the definition of the function.
+	Function OpenAIChatToolFunction `json:"function"`
+	// Type stores the value `function`.
+	Type string `json:"type"`
+}
+
+// OpenAIChatToolFunction stores data for `function` property
+// of an `OpenAIChatTool` object.
+type OpenAIChatToolFunction struct {
+	// Description stores the description of the function.
+	Description string `json:"description,omitempty"`
+	// Name stores the name of the function.
+	Name string `json:"name"`
+	// Parameters stores the JSON schema of the arguments.
+	Parameters map[string]any `json:"parameters,omitempty"`
+}
+
+// OpenAIChatToolCall stores a call of a tool inside `tool_calls` property
+// of an assistant message.
+type OpenAIChatToolCall struct {
+	// Function stores name and arguments of the function.
+	Function OpenAIChatToolCallFunction `json:"function"`
+	// Id stores the ID of the call.
+	Id string `json:"id"`
+	// Type stores the value `function`.
+	Type string `json:"type"`
+}
+
+// OpenAIChatToolCallFunction stores data for `function` property
+// of an `OpenAIChatToolCall` object.
+type OpenAIChatToolCallFunction struct {
+	// Arguments stores the arguments as JSON string.
+	Arguments string `json:"arguments"`
+	// Name stores the name of the function.
+	Name string `json:"name"`
+}
+
+// OpenAIEmbeddingsResponseV1 stores data of a successful
+// OpenAI embeddings response (version 1).
+type OpenAIEmbeddingsResponseV1 struct {
+	// Data contains the list of embeddings.
+	Data []OpenAIEmbeddingsResponseV1Item `json:"data"`
+	// Model stores the used model.
+	Model string `json:"model"`
+}
+
+// OpenAIEmbeddingsResponseV1Item is an item inside `data` property
+// of an `OpenAIEmbeddingsResponseV1` object.
+type OpenAIEmbeddingsResponseV1Item struct {
+	// Embedding stores the vector.
+	Embedding []float32 `json:"embedding"`
+	// Index stores the zero-based index of the input.
+	Index int `json:"index"`
+}
+
+// OpenAIImageGenerationResponseV1 stores data of a successful
+// OpenAI image generation response (version 1).
+type OpenAIImageGenerationResponseV1 struct {
+	// Data contains the list of generated images.
+	Data []OpenAIImageGenerationResponseV1Item `json:"data"`
+}
+
+// OpenAIImageGenerationResponseV1Item is an item inside `data` property
+// of an `OpenAIImageGenerationResponseV1` object.
+type OpenAIImageGenerationResponseV1Item struct {
+	// B64Json stores the image data in Base64 format.
+	B64Json string `json:"b64_json"`
+}
+
+// OpenAIResponsesInputMessage stores data of an item inside `input` property
+// of an OpenAI Responses API request.
+type OpenAIResponsesInputMessage struct {
+	// Content stores the list of content items.
+	Content []OpenAIChatMessageContentItem `json:"content"`
+	// Role stores the role.
+	Role string `json:"role"`
+}
+
+// OpenAIResponsesInputFileItem represents a content item of type `input_file`.
+type OpenAIResponsesInputFileItem struct {
+	// FileData stores the data as data URI.
+	FileData string `json:"file_data"`
+	// Filename stores the name of the file.
+	Filename string `json:"filename"`
+	// Type stores the value `input_file`.
+	Type string `json:"type"`
+}
+
+// OpenAIResponsesInputImageItem represents a content item of type `input_image`.
+type OpenAIResponsesInputImageItem struct {
+	// ImageUrl stores the URL or data URI of the image.
+	ImageUrl string `json:"image_url"`
+	// Type stores the value `input_image`.
+	Type string `json:"type"`
+}
+
+// OpenAIResponsesTextItem represents a content item of type `input_text` or `output_text`.
+type OpenAIResponsesTextItem struct {
+	// Text stores the text content.
+	Text string `json:"text"`
+	// Type stores the value `input_text` or `output_text`.
+	Type string `json:"type"`
+}
+
+// OpenAIResponsesResponseV1 stores data of a successful
+// OpenAI Responses API response (version 1).
+type OpenAIResponsesResponseV1 struct {
+	// Model stores the used model.
+	Model string `json:"model"`
+	// Output contains the list of output items.
+	Output []OpenAIResponsesResponseV1OutputItem `json:"output"`
+	// Usage stores the used resources.
+	Usage OpenAIResponsesResponseV1Usage `json:"usage"`
+}
+
+// GetOutputText returns the concatenated text of all output messages.
+func (r *OpenAIResponsesResponseV1) GetOutputText() string {
+	text := ""
+	for _, item := range r.Output {
+		if item.Type != "message" {
+			continue
+		}
+
+		for _, content := range item.Content {
+			if content.Type == "output_text" {
+				text += content.Text
+			}
+		}
+	}
+
+	return text
+}
+
+// OpenAIResponsesResponseV1OutputItem is an item inside `output` property
+// of an `OpenAIResponsesResponseV1` object.
+type OpenAIResponsesResponseV1OutputItem struct {
+	// Content contains the list of content items.
+	Content []OpenAIResponsesTextItem `json:"content"`
+	// Role stores the role like `assistant`.
+	Role string `json:"role"`
+	// Type stores the type like `message` or `reasoning`.
+	Type string `json:"type"`
+}
+
+// OpenAIResponsesResponseV1Usage contains data for `usage` property
+// of an `OpenAIResponsesResponseV1` object.
+type OpenAIResponsesResponseV1Usage struct {
+	// InputTokens stores number of input tokens.
+	InputTokens int32 `json:"input_tokens"`
+	// OutputTokens stores number of output tokens.
+	OutputTokens int32 `json:"output_tokens"`
+	// TotalTokens stores number of total used tokens.
+	TotalTokens int32 `json:"total_tokens"`
+}
+
+// OpenAITranscriptionResponseV1 stores data of a successful
+// OpenAI transcription response (version 1) in JSON format.
+type OpenAITranscriptionResponseV1 struct {
+	// Text stores the transcript.
+	Text string `json:"text"`
+}
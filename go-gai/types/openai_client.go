@@ -26,11 +26,15 @@ import (
 	"bytes"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"mime"
+	"mime/multipart"
 	"net/http"
+	"net/url"
 	"strings"
+	"time"
 
 	"github.com/mkloubert/gai/utils"
 )
@@ -40,6 +44,9 @@ type OpenAIClient struct {
 	apiKey    string
 	app       *AppContext
 	chatModel string
+	// azureApiVersion is the API version of an Azure OpenAI resource
+	// and is only set, if the client talks to Azure
+	azureApiVersion string
 }
 
 type openaiGetModelListResponse struct {
@@ -47,16 +54,29 @@ type openaiGetModelListResponse struct {
 }
 
 type openaiGetModelListItem struct {
+	Created int64  `json:"created"`
 	Id      string `json:"id"`
 	Object  string `json:"object"`
 	OwnedBy string `json:"owned_by"`
 }
 
 func (c *OpenAIClient) appendConversationItemTo(messages []OpenAIChatMessage, item *ConversationRepositoryConversationItem) ([]OpenAIChatMessage, error) {
-	if item.Contents != nil {
+	if item.Contents != nil || len(item.ToolCalls) > 0 {
 		newMessage := &OpenAIChatMessage{
-			Content: make(OpenAIChatMessageContent, 0),
-			Role:    item.Role,
+			Content:    make(OpenAIChatMessageContent, 0),
+			Role:       item.Role,
+			ToolCallId: item.ToolCallId,
+		}
+
+		for _, call := range item.ToolCalls {
+			newMessage.ToolCalls = append(newMessage.ToolCalls, OpenAIChatToolCall{
+				Function: OpenAIChatToolCallFunction{
+					Arguments: call.Arguments,
+					Name:      call.Name,
+				},
+				Id:   call.Id,
+				Type: "function",
+			})
 		}
 
 		for i, content := range item.Contents {
@@ -84,7 +104,7 @@ func (c *OpenAIClient) appendConversationItemTo(messages []OpenAIChatMessage, it
 
 				if strings.HasSuffix(mimeType, "mp3") || strings.HasSuffix(mimeType, "mpeg") {
 					format = "mp3"
-				} else if strings.HasSuffix(mimeType, "wav") {
+				} else if strings.HasSuffix(mimeType, "wav") || strings.HasSuffix(mimeType, "wave") {
 					format = "wav"
 				}
 
@@ -112,10 +132,15 @@ func (c *OpenAIClient) appendConversationItemTo(messages []OpenAIChatMessage, it
 					return messages, err
 				}
 
+				filename := content.Name
+				if filename == "" {
+					filename = fmt.Sprintf("file_%d%s", i+1, fileExt)
+				}
+
 				newItem = &OpenAIChatMessageContentFileItem{
 					File: OpenAIChatMessageContentItemFile{
 						FileData: content.Content,
-						Filename: fmt.Sprintf("file_%d%s", i+1, fileExt),
+						Filename: filename,
 					},
 					Type: "file",
 				}
@@ -134,7 +159,71 @@ func (c *OpenAIClient) appendConversationItemTo(messages []OpenAIChatMessage, it
 	return messages, nil
 }
 
+func (c *OpenAIClient) appendConversationItemToInput(input []OpenAIResponsesInputMessage, item *ConversationRepositoryConversationItem) ([]OpenAIResponsesInputMessage, error) {
+	if item.Contents != nil {
+		newMessage := &OpenAIResponsesInputMessage{
+			Content: make([]OpenAIChatMessageContentItem, 0),
+			Role:    item.Role,
+		}
+
+		textType := "input_text"
+		if item.Role == "assistant" {
+			textType = "output_text"
+		}
+
+		for i, content := range item.Contents {
+			var newItem any
+
+			if content.Type == "text" {
+				newItem = &OpenAIResponsesTextItem{
+					Text: content.Content,
+					Type: textType,
+				}
+			} else if content.Type == "image" {
+				newItem = &OpenAIResponsesInputImageItem{
+					ImageUrl: content.Content,
+					Type:     "input_image",
+				}
+			} else if content.Type == "audio" {
+				return input, fmt.Errorf("content type '%v' is not supported by Responses API", content.Type)
+			} else {
+				// handle as file attachment
+
+				_, mimeType, err := utils.GetPartsOfDataURI(content.Content)
+				if err != nil {
+					return input, err
+				}
+
+				fileExt := ""
+				fileExts, err := mime.ExtensionsByType(mimeType)
+				if err == nil && len(fileExts) > 0 {
+					fileExt = fileExts[0]
+				}
+
+				filename := content.Name
+				if filename == "" {
+					filename = fmt.Sprintf("file_%d%s", i+1, fileExt)
+				}
+
+				newItem = &OpenAIResponsesInputFileItem{
+					FileData: content.Content,
+					Filename: filename,
+					Type:     "input_file",
+				}
+			}
+
+			newMessage.Content = append(newMessage.Content, newItem)
+		}
+
+		input = append(input, *newMessage)
+	}
+
+	return input, nil
+}
+
 func (c *OpenAIClient) appendFilesTo(item *ConversationRepositoryConversationItem, files []io.Reader) error {
+	defer c.app.StartTiming(TimingPhaseFiles)()
+
 	for _, f := range files {
 		if f != nil {
 			data, err := io.ReadAll(f)
@@ -167,15 +256,16 @@ func (c *OpenAIClient) appendFilesTo(item *ConversationRepositoryConversationIte
 				}
 				item.Contents = append(item.Contents, newUserImageItem)
 			} else {
-				mimeType := utils.DetectMime(data)
-				encoded := base64.StdEncoding.EncodeToString(data)
-				dataURI := fmt.Sprintf("data:%s;base64,%s", mimeType, encoded)
+				newUserFileItem, err := c.app.NewAttachmentContentItem(data, mimeType, getNameOfReader(f))
+				if err != nil {
+					return err
+				}
 
-				newUserImageItem := &ConversationRepositoryConversationItemContentItem{
-					Content: dataURI,
-					Type:    "attachment",
+				if namedReader, ok := f.(*NamedReader); ok {
+					newUserFileItem.Name = namedReader.Name()
 				}
-				item.Contents = append(item.Contents, newUserImageItem)
+
+				item.Contents = append(item.Contents, newUserFileItem)
 			}
 		}
 	}
@@ -226,46 +316,40 @@ func (c *OpenAIClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 		return "", conversation, err
 	}
 
-	apiKey := strings.TrimSpace(c.apiKey)
-	if apiKey == "" {
-		return "", conversation, fmt.Errorf("no OpenAI api key defined")
-	}
-
 	model := strings.TrimSpace(strings.ToLower(c.chatModel))
 	if model == "" {
 		return "", conversation, fmt.Errorf("no chat ai model defined")
 	}
 
 	noSave := false
+	sampling := samplingParameters{}
 	systemPrompt := ""
+	toolResults := make([]AIToolResult, 0)
+	tools := make([]ToolDefinition, 0)
 	for _, o := range opts {
+		if o.FrequencyPenalty != nil {
+			sampling.frequencyPenalty = o.FrequencyPenalty
+		}
 		if o.NoSave != nil {
 			noSave = *o.NoSave
 		}
+		if o.PresencePenalty != nil {
+			sampling.presencePenalty = o.PresencePenalty
+		}
 		if o.SystemPrompt != nil {
 			systemPrompt = *o.SystemPrompt
 		}
+		toolResults = append(toolResults, o.ToolResults...)
+		tools = append(tools, o.Tools...)
+		if o.TopP != nil {
+			sampling.topP = o.TopP
+		}
 	}
 
 	conversation = c.setupSystemPromptIfNeeded(conversation, systemPrompt, model)
 
 	app := ctx.App
 
-	maxTokens, err := app.GetMaxTokens()
-	if err != nil {
-		return "", conversation, err
-	}
-
-	temperature, err := app.GetTemperature()
-	if err != nil {
-		return "", conversation, err
-	}
-
-	baseUrl := app.GetBaseUrl()
-	if baseUrl == "" {
-		baseUrl = "https://api.openai.com" // use default
-	}
-
 	var schema *map[string]any
 	schemaName := ""
 	for _, o := range opts {
@@ -277,8 +361,6 @@ func (c *OpenAIClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 		}
 	}
 
-	url := fmt.Sprintf("%v/v1/chat/completions", baseUrl)
-
 	userMessage := &ConversationRepositoryConversationItem{
 		Contents: make(ConversationRepositoryConversationItemContents, 0),
 		Model:    model,
@@ -291,7 +373,7 @@ func (c *OpenAIClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 	userMessage.Contents = append(userMessage.Contents, newUserTextItem)
 
 	// add response format
-	responseFormat, err := c.writeResponseFormatTo(userMessage, schema, schemaName)
+	_, err = c.writeResponseFormatTo(userMessage, schema, schemaName)
 	if err != nil {
 		return "", conversation, err
 	}
@@ -308,144 +390,438 @@ func (c *OpenAIClient) Chat(ctx *ChatContext, msg string, opts ...AIClientChatOp
 		}
 	}
 
-	messages := []OpenAIChatMessage{}
+	userMessage.Time = app.GetISOTime()
+
+	// results of the tool calls of the last answer
+	// and the new user message, if there is one
+	newItems := make(ConversationRepositoryConversation, 0)
+	for _, r := range toolResults {
+		newItems = append(newItems, &ConversationRepositoryConversationItem{
+			Contents: ConversationRepositoryConversationItemContents{
+				&ConversationRepositoryConversationItemContentItem{
+					Content: r.Content,
+					Type:    "text",
+				},
+			},
+			Model:      model,
+			Role:       "tool",
+			Time:       userMessage.Time,
+			ToolCallId: r.ToolCallId,
+		})
+	}
+	if msg != "" || len(toolResults) == 0 {
+		newItems = append(newItems, userMessage)
+	}
+
+	// previous conversation with new items
+	history, err := ctx.LimitConversationHistory(conversation, model)
+	if err != nil {
+		return "", conversation, err
+	}
+
+	allItems := make(ConversationRepositoryConversation, 0)
+	allItems = append(allItems, history...)
+	allItems = append(allItems, newItems...)
+
+	answer, responseModel, usage, toolCalls, err := c.sendRequest(model, allItems, schema, schemaName, sampling, tools)
+	if err != nil {
+		return "", conversation, err
+	}
+
+	responseTime := app.GetISOTime()
+
+	// update conversation
+	{
+		conversation = append(conversation, newItems...)
+
+		// take assistant message
+		assistantMessage := &ConversationRepositoryConversationItem{
+			Contents:  make(ConversationRepositoryConversationItemContents, 0),
+			Model:     responseModel,
+			Role:      "assistant",
+			Time:      responseTime,
+			ToolCalls: toolCalls,
+			Usage:     usage,
+		}
+		if answer != "" || len(toolCalls) == 0 {
+			assistantMessage.Contents = append(assistantMessage.Contents, &ConversationRepositoryConversationItemContentItem{
+				Content: answer,
+				Type:    "text",
+			})
+		}
+		conversation = append(conversation, assistantMessage)
+	}
+
+	if !noSave {
+		err := ctx.UpdateConversationWith(conversation)
+		if err != nil {
+			return answer, conversation, err
+		}
+	}
+
+	return answer, conversation, nil
+}
+
+// ChatModel returns the current chat model.
+func (c *OpenAIClient) ChatModel() string {
+	return c.chatModel
+}
 
-	// add previous conversation
+func (c *OpenAIClient) createChatCompletion(url string, body map[string]any, maxTokens *int64, conversation ConversationRepositoryConversation, responseFormat *map[string]any, tools []ToolDefinition) (string, string, *AIUsage, []AIToolCall, error) {
+	messages := []OpenAIChatMessage{}
 	for _, item := range conversation {
 		m, err := c.appendConversationItemTo(messages, item)
 		if err != nil {
-			return "", conversation, err
+			return "", "", nil, nil, err
 		}
 
 		messages = m
 	}
 
-	// add user message
-	m, err := c.appendConversationItemTo(messages, userMessage)
+	body["messages"] = messages
+	body["stream"] = false
+	body["max_completion_tokens"] = maxTokens
+	body["response_format"] = responseFormat
+
+	if len(tools) > 0 {
+		chatTools := make([]OpenAIChatTool, 0)
+		for _, t := range tools {
+			chatTools = append(chatTools, OpenAIChatTool{
+				Function: OpenAIChatToolFunction{
+					Description: t.Description,
+					Name:        t.Name,
+					Parameters:  t.Parameters,
+				},
+				Type: "function",
+			})
+		}
+
+		body["tools"] = chatTools
+	}
+
+	responseData, err := c.postJSON(url, body)
 	if err != nil {
-		return "", conversation, err
+		return "", "", nil, nil, err
 	}
 
-	messages = m
+	var chatResponse OpenAIChatCompletionResponseV1
+	err = json.Unmarshal(responseData, &chatResponse)
+	if err != nil {
+		return "", "", nil, nil, err
+	}
 
-	body := map[string]any{
-		"model":                 model,
-		"messages":              messages,
-		"stream":                false,
-		"temperature":           temperature,
-		"max_completion_tokens": maxTokens,
-		"response_format":       responseFormat,
+	answer := ""
+	var toolCalls []AIToolCall
+	if len(chatResponse.Choices) > 0 {
+		message := chatResponse.Choices[0].Message
+
+		answer = message.Content
+		for _, call := range message.ToolCalls {
+			toolCalls = append(toolCalls, AIToolCall{
+				Arguments: call.Function.Arguments,
+				Id:        call.Id,
+				Name:      call.Function.Name,
+			})
+		}
 	}
 
-	jsonData, err := json.Marshal(&body)
-	if err != nil {
-		return "", conversation, err
+	usage := &AIUsage{
+		CompletionTokens: int64(chatResponse.Usage.CompletionTokens),
+		PromptTokens:     int64(chatResponse.Usage.PromptTokens),
+		TotalTokens:      int64(chatResponse.Usage.TotalTokens),
 	}
 
-	userMessage.Time = app.GetISOTime()
+	return answer, chatResponse.Model, usage, toolCalls, nil
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
-	if err != nil {
-		return "", conversation, err
+func (c *OpenAIClient) createResponse(url string, body map[string]any, maxTokens *int64, conversation ConversationRepositoryConversation, schema *map[string]any, schemaName string) (string, string, *AIUsage, error) {
+	input := []OpenAIResponsesInputMessage{}
+	for _, item := range conversation {
+		i, err := c.appendConversationItemToInput(input, item)
+		if err != nil {
+			return "", "", nil, err
+		}
+
+		input = i
 	}
 
-	// setup ...
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-	// ... and finally send the JSON data
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	body["input"] = input
+	body["store"] = false
+	if maxTokens != nil {
+		body["max_output_tokens"] = *maxTokens
+	}
+
+	if schema != nil {
+		body["text"] = map[string]any{
+			"format": map[string]any{
+				"type":   "json_schema",
+				"name":   utils.SanitizeJSONSchemaName(schemaName),
+				"schema": schema,
+			},
+		}
+	}
+
+	responseData, err := c.postJSON(url, body)
 	if err != nil {
-		return "", conversation, err
+		return "", "", nil, err
 	}
-	defer resp.Body.Close()
 
-	err = utils.CheckForHttpResponseError(resp)
+	var responsesResponse OpenAIResponsesResponseV1
+	err = json.Unmarshal(responseData, &responsesResponse)
 	if err != nil {
-		return "", conversation, err
+		return "", "", nil, err
 	}
 
-	responseTime := app.GetISOTime()
+	usage := &AIUsage{
+		CompletionTokens: int64(responsesResponse.Usage.OutputTokens),
+		PromptTokens:     int64(responsesResponse.Usage.InputTokens),
+		TotalTokens:      int64(responsesResponse.Usage.TotalTokens),
+	}
 
-	// load the response
-	responseData, err := io.ReadAll(resp.Body)
+	return responsesResponse.GetOutputText(), responsesResponse.Model, usage, nil
+}
+
+// Embed creates embedding vectors for each of the `texts`.
+func (c *OpenAIClient) Embed(texts []string, opts ...AIClientEmbedOptions) (AIClientEmbedResponse, error) {
+	embedResponse := AIClientEmbedResponse{}
+
+	model := "text-embedding-3-small"
+	for _, o := range opts {
+		if o.Model != nil && strings.TrimSpace(*o.Model) != "" {
+			model = strings.TrimSpace(*o.Model)
+		}
+	}
+
+	embedResponse.Model = model
+
+	baseUrl := c.getBaseUrl()
+
+	url := c.getEndpointUrl(baseUrl, "embeddings", model)
+
+	responseData, err := c.postJSON(url, map[string]any{
+		"input": texts,
+		"model": model,
+	})
 	if err != nil {
-		return "", conversation, err
+		return embedResponse, err
 	}
 
-	var chatResponse OpenAIChatCompletionResponseV1
-	err = json.Unmarshal(responseData, &chatResponse)
+	var embeddingsResponse OpenAIEmbeddingsResponseV1
+	err = json.Unmarshal(responseData, &embeddingsResponse)
 	if err != nil {
-		return "", conversation, err
+		return embedResponse, err
 	}
 
-	answer := ""
-	if len(chatResponse.Choices) > 0 {
-		answer = chatResponse.Choices[0].Message.Content
+	if len(embeddingsResponse.Data) != len(texts) {
+		return embedResponse, fmt.Errorf("expected %v embeddings, but got %v", len(texts), len(embeddingsResponse.Data))
 	}
 
-	// update conversation
-	{
-		conversation = append(conversation, userMessage)
+	embedResponse.Embeddings = make([][]float32, len(texts))
+	for _, item := range embeddingsResponse.Data {
+		if item.Index < 0 || item.Index >= len(texts) {
+			return embedResponse, fmt.Errorf("invalid embedding index %v", item.Index)
+		}
 
-		// take assistant message
-		assistantMessage := &ConversationRepositoryConversationItem{
-			Contents: make(ConversationRepositoryConversationItemContents, 0),
-			Model:    chatResponse.Model,
-			Role:     "assistant",
-			Time:     responseTime,
-		}
-		assistantMessage.Contents = append(assistantMessage.Contents, &ConversationRepositoryConversationItemContentItem{
-			Content: answer,
-			Type:    "text",
-		})
-		conversation = append(conversation, assistantMessage)
+		embedResponse.Embeddings[item.Index] = item.Embedding
 	}
 
-	if !noSave {
-		err := ctx.UpdateConversationWith(conversation)
+	if strings.TrimSpace(embeddingsResponse.Model) != "" {
+		embedResponse.Model = embeddingsResponse.Model
+	}
+
+	return embedResponse, nil
+}
+
+// GenerateImage creates one or more images from `prompt`.
+func (c *OpenAIClient) GenerateImage(prompt string, opts ...AIClientGenerateImageOptions) (AIClientGenerateImageResponse, error) {
+	generateResponse := AIClientGenerateImageResponse{}
+
+	count := 1
+	model := "dall-e-3"
+	size := ""
+	for _, o := range opts {
+		if o.Count != nil {
+			count = *o.Count
+		}
+		if o.Model != nil && strings.TrimSpace(*o.Model) != "" {
+			model = strings.TrimSpace(*o.Model)
+		}
+		if o.Size != nil {
+			size = strings.TrimSpace(*o.Size)
+		}
+	}
+
+	if count < 1 {
+		return generateResponse, fmt.Errorf("invalid number of images: %v", count)
+	}
+
+	generateResponse.Model = model
+
+	body := map[string]any{
+		"model":  model,
+		"n":      count,
+		"prompt": prompt,
+	}
+	if !strings.HasPrefix(model, "gpt-image-") {
+		// GPT image models always return Base64 data
+		// and do not accept this parameter
+		body["response_format"] = "b64_json"
+	}
+	if size != "" {
+		body["size"] = size
+	}
+
+	baseUrl := c.getBaseUrl()
+
+	url := c.getEndpointUrl(baseUrl, "images/generations", model)
+
+	responseData, err := c.postJSON(url, body)
+	if err != nil {
+		return generateResponse, err
+	}
+
+	var imageResponse OpenAIImageGenerationResponseV1
+	err = json.Unmarshal(responseData, &imageResponse)
+	if err != nil {
+		return generateResponse, err
+	}
+
+	for _, item := range imageResponse.Data {
+		data, err := base64.StdEncoding.DecodeString(item.B64Json)
 		if err != nil {
-			return answer, conversation, err
+			return generateResponse, err
 		}
+
+		generateResponse.Images = append(generateResponse.Images, data)
 	}
 
-	return answer, conversation, nil
+	return generateResponse, nil
 }
 
-// ChatModel returns the current chat model.
-func (c *OpenAIClient) ChatModel() string {
-	return c.chatModel
+func (c *OpenAIClient) getBaseUrl() string {
+	baseUrl := c.app.GetBaseUrl(c.Provider())
+	if baseUrl == "" && !c.isAzure() {
+		baseUrl = "https://api.openai.com" // use default
+	}
+
+	return baseUrl
 }
 
-// Returns the list of supported OpenAI models.
-func (c *OpenAIClient) GetModels() ([]AIModel, error) {
-	models := make([]AIModel, 0)
+// getEndpointUrl returns the full URL of an API `endpoint`, like `chat/completions`.
+// For Azure the `model` is the name of the deployment.
+func (c *OpenAIClient) getEndpointUrl(baseUrl string, endpoint string, model string) string {
+	if !c.isAzure() {
+		return fmt.Sprintf("%v/v1/%v", baseUrl, endpoint)
+	}
+
+	// endpoints in the Azure portal are usually shown with a trailing slash
+	baseUrl = strings.TrimSuffix(baseUrl, "/")
+	apiVersion := url.QueryEscape(c.azureApiVersion)
+
+	if endpoint == "responses" || endpoint == "models" {
+		// these endpoints are not bound to a deployment
+		return fmt.Sprintf("%v/openai/%v?api-version=%v", baseUrl, endpoint, apiVersion)
+	}
+
+	return fmt.Sprintf(
+		"%v/openai/deployments/%v/%v?api-version=%v",
+		baseUrl, url.PathEscape(model), endpoint, apiVersion,
+	)
+}
+
+// GetModelInfo loads the owner and creation time of `model` from `/v1/models/{model}`.
+func (c *OpenAIClient) GetModelInfo(model string) (AIModelInfo, error) {
+	info := AIModelInfo{}
 
 	apiKey := strings.TrimSpace(c.apiKey)
 	if apiKey == "" {
-		return models, fmt.Errorf("no OpenAI api key defined")
+		return info, NewTypedError(ErrorTypeAuth, fmt.Errorf("no OpenAI api key defined"))
 	}
 
-	app := c.app
+	if c.isAzure() {
+		return info, fmt.Errorf("loading model information is not supported by Azure")
+	}
 
-	baseUrl := app.GetBaseUrl()
-	if baseUrl == "" {
-		baseUrl = "https://api.openai.com" // use default
+	modelUrl := c.getEndpointUrl(c.getBaseUrl(), fmt.Sprintf("models/%s", url.PathEscape(model)), "")
+
+	req, err := c.app.NewHttpRequest("GET", modelUrl, bytes.NewBuffer([]byte{}))
+	if err != nil {
+		return info, err
+	}
+
+	// setup
+	c.setAuthorizationHeader(req, apiKey)
+
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return info, err
 	}
+	defer resp.Body.Close()
 
-	url := fmt.Sprintf("%s/v1/models", baseUrl)
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return info, err
+	}
+
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return info, err
+	}
 
-	req, err := http.NewRequest("GET", url, bytes.NewBuffer([]byte{}))
+	var item openaiGetModelListItem
+	err = json.Unmarshal(responseData, &item)
+	if err != nil {
+		return info, err
+	}
+
+	info.OwnedBy = item.OwnedBy
+	if item.Created > 0 {
+		created := time.Unix(item.Created, 0).UTC()
+		info.Created = &created
+	}
+
+	return info, nil
+}
+
+// Returns the list of supported OpenAI models,
+// which are cached for the time of `GetModelsCacheTTL`.
+func (c *OpenAIClient) GetModels() ([]AIModel, error) {
+	apiKey := strings.TrimSpace(c.apiKey)
+	if apiKey == "" {
+		return make([]AIModel, 0), NewTypedError(ErrorTypeAuth, fmt.Errorf("no OpenAI api key defined"))
+	}
+
+	if c.isAzure() {
+		// deployments are managed by Azure and cannot be listed with the API key
+		return make([]AIModel, 0), fmt.Errorf("listing models is not supported by Azure, use the names of your deployments instead")
+	}
+
+	baseUrl := c.getBaseUrl()
+
+	return c.app.getCachedModels(c, baseUrl, func() ([]AIModel, error) {
+		return c.loadModels(apiKey, baseUrl)
+	})
+}
+
+func (c *OpenAIClient) isAzure() bool {
+	return c.azureApiVersion != ""
+}
+
+func (c *OpenAIClient) loadModels(apiKey string, baseUrl string) ([]AIModel, error) {
+	models := make([]AIModel, 0)
+
+	url := c.getEndpointUrl(baseUrl, "models", "")
+
+	req, err := c.app.NewHttpRequest("GET", url, bytes.NewBuffer([]byte{}))
 	if err != nil {
 		return models, err
 	}
 
 	// setup
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	c.setAuthorizationHeader(req, apiKey)
 
 	// ... and finally send the JSON data
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := c.app.SendHttpRequest(req)
 	if err != nil {
 		return models, err
 	}
@@ -483,6 +859,49 @@ func (c *OpenAIClient) GetModels() ([]AIModel, error) {
 	return models, nil
 }
 
+func (c *OpenAIClient) postJSON(url string, body map[string]any) ([]byte, error) {
+	apiKey := strings.TrimSpace(c.apiKey)
+	if apiKey == "" {
+		return nil, NewTypedError(ErrorTypeAuth, fmt.Errorf("no OpenAI api key defined"))
+	}
+
+	app := c.app
+
+	jsonData, err := json.Marshal(&body)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+	if err != nil {
+		return nil, err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", "application/json")
+	c.setAuthorizationHeader(req, apiKey)
+
+	err = app.DumpRequestAsCurlIfNeeded(req, jsonData)
+	if err != nil {
+		return nil, err
+	}
+
+	// ... and finally send the JSON data
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	// load the response
+	return io.ReadAll(resp.Body)
+}
+
 // Prompt does a single AI prompt with a specific `msg`.
 func (c *OpenAIClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClientPromptResponse, error) {
 	promptResponse := AIClientPromptResponse{
@@ -490,21 +909,26 @@ func (c *OpenAIClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 		Model:   "",
 	}
 
-	apiKey := strings.TrimSpace(c.apiKey)
-	if apiKey == "" {
-		return promptResponse, fmt.Errorf("no OpenAI api key defined")
-	}
-
 	model := strings.TrimSpace(strings.ToLower(c.chatModel))
 	if model == "" {
 		return promptResponse, fmt.Errorf("no chat ai model defined")
 	}
 
+	sampling := samplingParameters{}
 	systemPrompt := ""
 	for _, o := range opts {
+		if o.FrequencyPenalty != nil {
+			sampling.frequencyPenalty = o.FrequencyPenalty
+		}
+		if o.PresencePenalty != nil {
+			sampling.presencePenalty = o.PresencePenalty
+		}
 		if o.SystemPrompt != nil {
 			systemPrompt = *o.SystemPrompt
 		}
+		if o.TopP != nil {
+			sampling.topP = o.TopP
+		}
 	}
 
 	tempConversation := make(ConversationRepositoryConversation, 0)
@@ -512,23 +936,6 @@ func (c *OpenAIClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 
 	promptResponse.Model = model
 
-	app := c.app
-
-	maxTokens, err := app.GetMaxTokens()
-	if err != nil {
-		return promptResponse, err
-	}
-
-	temperature, err := app.GetTemperature()
-	if err != nil {
-		return promptResponse, err
-	}
-
-	baseUrl := app.GetBaseUrl()
-	if baseUrl == "" {
-		baseUrl = "https://api.openai.com" // use default
-	}
-
 	var schema *map[string]any
 	schemaName := ""
 	for _, o := range opts {
@@ -540,8 +947,6 @@ func (c *OpenAIClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 		}
 	}
 
-	url := fmt.Sprintf("%v/v1/chat/completions", baseUrl)
-
 	userMessage := &ConversationRepositoryConversationItem{
 		Contents: make(ConversationRepositoryConversationItemContents, 0),
 		Model:    model,
@@ -554,7 +959,7 @@ func (c *OpenAIClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 	userMessage.Contents = append(userMessage.Contents, newUserTextItem)
 
 	// add response format
-	responseFormat, err := c.writeResponseFormatTo(userMessage, schema, schemaName)
+	_, err := c.writeResponseFormatTo(userMessage, schema, schemaName)
 	if err != nil {
 		return promptResponse, err
 	}
@@ -571,85 +976,97 @@ func (c *OpenAIClient) Prompt(msg string, opts ...AIClientPromptOptions) (AIClie
 		}
 	}
 
-	messages := []OpenAIChatMessage{}
-	for _, item := range tempConversation {
-		m, err := c.appendConversationItemTo(messages, item)
-		if err != nil {
-			return promptResponse, err
-		}
-
-		messages = m
-	}
-
 	// add user message
-	m, err := c.appendConversationItemTo(messages, userMessage)
+	tempConversation = append(tempConversation, userMessage)
+
+	answer, responseModel, usage, _, err := c.sendRequest(model, tempConversation, schema, schemaName, sampling, nil)
 	if err != nil {
 		return promptResponse, err
 	}
 
-	messages = m
+	promptResponse.Content = answer
+	promptResponse.Model = responseModel
+	promptResponse.Usage = usage
 
-	body := map[string]any{
-		"model":                 model,
-		"messages":              messages,
-		"stream":                false,
-		"temperature":           temperature,
-		"max_completion_tokens": maxTokens,
-		"response_format":       responseFormat,
-	}
+	return promptResponse, nil
+}
 
-	jsonData, err := json.Marshal(&body)
-	if err != nil {
-		return promptResponse, err
+// Provider returns the name of the provider.
+func (c *OpenAIClient) Provider() string {
+	if c.isAzure() {
+		return "azure"
 	}
+	return "openai"
+}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer([]byte(jsonData)))
+func (c *OpenAIClient) sendRequest(model string, conversation ConversationRepositoryConversation, schema *map[string]any, schemaName string, sampling samplingParameters, tools []ToolDefinition) (string, string, *AIUsage, []AIToolCall, error) {
+	app := c.app
+
+	maxTokens, err := app.GetMaxTokens()
 	if err != nil {
-		return promptResponse, err
+		return "", "", nil, nil, err
 	}
 
-	// setup ...
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
-	// ... and finally send the JSON data
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	temperature, err := app.GetTemperature()
 	if err != nil {
-		return promptResponse, err
+		return "", "", nil, nil, err
 	}
-	defer resp.Body.Close()
 
-	err = utils.CheckForHttpResponseError(resp)
+	sampling, err = app.getSamplingParameters(sampling)
 	if err != nil {
-		return promptResponse, err
+		return "", "", nil, nil, err
 	}
 
-	// load the response
-	responseData, err := io.ReadAll(resp.Body)
+	openaiApi, err := app.GetOpenAIApi()
 	if err != nil {
-		return promptResponse, err
+		return "", "", nil, nil, err
 	}
 
-	var chatResponse OpenAIChatCompletionResponseV1
-	err = json.Unmarshal(responseData, &chatResponse)
-	if err != nil {
-		return promptResponse, err
+	baseUrl := c.getBaseUrl()
+
+	body := map[string]any{
+		"model": model,
+	}
+	if app.SupportsTemperature(model) {
+		body["temperature"] = temperature
+	} else {
+		app.Dbgf("Model '%v' does not support temperature, which is not submitted%s", model, app.EOL)
 	}
 
-	answer := ""
-	if len(chatResponse.Choices) > 0 {
-		answer = chatResponse.Choices[0].Message.Content
+	// only submit, what has been defined
+	if sampling.topP != nil {
+		body["top_p"] = *sampling.topP
+	}
+	if openaiApi == "responses" {
+		if sampling.frequencyPenalty != nil || sampling.presencePenalty != nil {
+			app.Dbgf("WARN: frequency and presence penalties are not supported by Responses API%s", app.EOL)
+		}
+	} else {
+		if sampling.frequencyPenalty != nil {
+			body["frequency_penalty"] = *sampling.frequencyPenalty
+		}
+		if sampling.presencePenalty != nil {
+			body["presence_penalty"] = *sampling.presencePenalty
+		}
 	}
 
-	promptResponse.Content = answer
-	promptResponse.Model = chatResponse.Model
+	if openaiApi == "responses" {
+		if len(tools) > 0 {
+			return "", "", nil, nil, NewTypedError(ErrorTypeUsage, errors.New("tool calling is only supported by --openai-api chat"))
+		}
 
-	return promptResponse, nil
+		answer, responseModel, usage, err := c.createResponse(c.getEndpointUrl(baseUrl, "responses", model), body, maxTokens, conversation, schema, schemaName)
+		return answer, responseModel, usage, nil, err
+	}
+	return c.createChatCompletion(c.getEndpointUrl(baseUrl, "chat/completions", model), body, maxTokens, conversation, c.toResponseFormat(schema, schemaName), tools)
 }
 
-// Provider returns the name of the provider.
-func (c *OpenAIClient) Provider() string {
-	return "openai"
+func (c *OpenAIClient) setAuthorizationHeader(req *http.Request, apiKey string) {
+	if c.isAzure() {
+		req.Header.Set("api-key", apiKey)
+	} else {
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", apiKey))
+	}
 }
 
 // SetChatModel sets the current chat model.
@@ -697,12 +1114,137 @@ func (c *OpenAIClient) toResponseFormat(schema *map[string]any, schemaName strin
 	return &map[string]any{
 		"type": "json_schema",
 		"json_schema": map[string]any{
-			"name":   schemaName,
+			"name":   utils.SanitizeJSONSchemaName(schemaName),
 			"schema": schema,
 		},
 	}
 }
 
+// Transcribe converts speech of audio data from `r` to text.
+func (c *OpenAIClient) Transcribe(r io.Reader, opts ...AIClientTranscribeOptions) (AIClientTranscribeResponse, error) {
+	transcribeResponse := AIClientTranscribeResponse{}
+
+	apiKey := strings.TrimSpace(c.apiKey)
+	if apiKey == "" {
+		return transcribeResponse, NewTypedError(ErrorTypeAuth, fmt.Errorf("no OpenAI api key defined"))
+	}
+
+	app := c.app
+
+	language := ""
+	model := "whisper-1"
+	responseFormat := "json"
+	for _, o := range opts {
+		if o.Language != nil {
+			language = strings.TrimSpace(*o.Language)
+		}
+		if o.Model != nil && strings.TrimSpace(*o.Model) != "" {
+			model = strings.TrimSpace(*o.Model)
+		}
+		if o.ResponseFormat != nil && strings.TrimSpace(*o.ResponseFormat) != "" {
+			responseFormat = strings.TrimSpace(strings.ToLower(*o.ResponseFormat))
+		}
+	}
+
+	transcribeResponse.Model = model
+
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	mimeType := utils.DetectMime(data)
+
+	fileExt := utils.GetAudioFileExtension(mimeType)
+	if fileExt == "" {
+		return transcribeResponse, fmt.Errorf("mime type '%v' is not a supported audio format", mimeType)
+	}
+
+	// build form data
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+
+	fileWriter, err := writer.CreateFormFile("file", fmt.Sprintf("audio%s", fileExt))
+	if err != nil {
+		return transcribeResponse, err
+	}
+	_, err = fileWriter.Write(data)
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	fields := map[string]string{
+		"model":           model,
+		"response_format": responseFormat,
+	}
+	if language != "" {
+		fields["language"] = language
+	}
+	for name, value := range fields {
+		err = writer.WriteField(name, value)
+		if err != nil {
+			return transcribeResponse, err
+		}
+	}
+
+	err = writer.Close()
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	baseUrl := c.getBaseUrl()
+
+	url := c.getEndpointUrl(baseUrl, "audio/transcriptions", model)
+
+	bodyData := body.Bytes()
+
+	req, err := c.app.NewHttpRequest("POST", url, bytes.NewBuffer(bodyData))
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	// setup ...
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	c.setAuthorizationHeader(req, apiKey)
+
+	err = app.DumpRequestAsCurlIfNeeded(req, bodyData)
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	// ... and finally send the form data
+	resp, err := c.app.SendHttpRequest(req)
+	if err != nil {
+		return transcribeResponse, err
+	}
+	defer resp.Body.Close()
+
+	err = checkForModelHttpResponseError(resp)
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	responseData, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return transcribeResponse, err
+	}
+
+	if responseFormat == "json" {
+		var transcriptionResponse OpenAITranscriptionResponseV1
+		err = json.Unmarshal(responseData, &transcriptionResponse)
+		if err != nil {
+			return transcribeResponse, err
+		}
+
+		transcribeResponse.Content = transcriptionResponse.Text
+	} else {
+		// `text`, `srt`, `verbose_json` or `vtt`
+		transcribeResponse.Content = string(responseData)
+	}
+
+	return transcribeResponse, nil
+}
+
 func (c *OpenAIClient) writeResponseFormatTo(item *ConversationRepositoryConversationItem, schema *map[string]any, schemaName string) (*map[string]any, error) {
 	responseFormat := c.toResponseFormat(schema, schemaName)
 	if responseFormat != nil {
@@ -0,0 +1,185 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+)
+
+// testResponsesRequest is the body of a request to the Responses API.
+type testResponsesRequest struct {
+	Input []struct {
+		Content []OpenAIResponsesTextItem `json:"content"`
+		Role    string                    `json:"role"`
+	} `json:"input"`
+	Model string `json:"model"`
+	Store *bool  `json:"store"`
+}
+
+func TestOpenAIResponsesApi(t *testing.T) {
+	app := newTestApp(t, map[string]string{
+		"OPENAI_API_KEY": "test",
+	})
+	app.Model = "openai:gpt-4o"
+	app.OpenAIApi = "responses"
+	app.SystemPrompt = "Be brief."
+
+	requests := make([]testResponsesRequest, 0)
+	newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/responses" {
+			http.NotFound(w, r)
+			return
+		}
+
+		var body testResponsesRequest
+		err := json.NewDecoder(r.Body).Decode(&body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		requests = append(requests, body)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{
+  "model": "gpt-4o-2024-08-06",
+  "output": [
+    {"type": "reasoning", "content": [{"type": "reasoning_text", "text": "thinking"}]},
+    {"type": "message", "role": "assistant", "content": [
+      {"type": "output_text", "text": "Hello, "},
+      {"type": "output_text", "text": "world"}
+    ]}
+  ],
+  "usage": {"input_tokens": 5, "output_tokens": 7, "total_tokens": 12}
+}`))
+	})
+
+	app.InitAI()
+
+	chat, err := app.NewChatContext()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, msg := range []string{"Hi", "Again"} {
+		answer, conversation, err := app.AI.Chat(chat, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// reasoning is ignored and message texts are joined
+		if answer != "Hello, world" {
+			t.Errorf("expected answer 'Hello, world', got %q", answer)
+		}
+
+		last := conversation[len(conversation)-1]
+		if last.Role != "assistant" || last.Model != "gpt-4o-2024-08-06" {
+			t.Errorf("unexpected assistant message %+v", last)
+		}
+		if len(last.Contents) != 1 || last.Contents[0].Content != "Hello, world" || last.Contents[0].Type != "text" {
+			t.Errorf("unexpected contents %+v", last.Contents)
+		}
+		if last.Usage == nil || last.Usage.PromptTokens != 5 || last.Usage.CompletionTokens != 7 || last.Usage.TotalTokens != 12 {
+			t.Errorf("unexpected usage %+v", last.Usage)
+		}
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("expected 2 requests, got %d", len(requests))
+	}
+
+	body := requests[1]
+	if body.Model != "gpt-4o" {
+		t.Errorf("expected model 'gpt-4o', got %q", body.Model)
+	}
+	if body.Store == nil || *body.Store {
+		t.Error("expected 'store' to be false")
+	}
+
+	expected := []OpenAIResponsesTextItem{
+		{Text: "Be brief.", Type: "input_text"},
+		{Text: "Hi", Type: "input_text"},
+		{Text: "Hello, world", Type: "output_text"},
+		{Text: "Again", Type: "input_text"},
+	}
+	expectedRoles := []string{app.GetSystemRole(), "user", "assistant", "user"}
+	if len(body.Input) != len(expected) {
+		t.Fatalf("expected %d input messages, got %+v", len(expected), body.Input)
+	}
+	for i, message := range body.Input {
+		if message.Role != expectedRoles[i] {
+			t.Errorf("expected role %q of input %d, got %q", expectedRoles[i], i, message.Role)
+		}
+		if len(message.Content) != 1 || message.Content[0] != expected[i] {
+			t.Errorf("expected content %+v of input %d, got %+v", expected[i], i, message.Content)
+		}
+	}
+}
+
+func TestOpenAIChatOmitsTemperature(t *testing.T) {
+	tests := []struct {
+		model               string
+		noTemperatureModels string
+		expectTemperature   bool
+	}{
+		{"gpt-4o", "", true},
+		{"o3-mini", "", false},
+		{"O1", "", false},
+		{"gpt-5-nano", "", false},
+		{"o3-mini", "my-reasoner, custom-", true},
+		{"custom-model", "my-reasoner, custom-", false},
+	}
+
+	for _, test := range tests {
+		app := newTestApp(t, map[string]string{
+			"GAI_MAX_TOKENS":            "100",
+			"GAI_NO_TEMPERATURE_MODELS": test.noTemperatureModels,
+			"OPENAI_API_KEY":            "test",
+		})
+		app.Model = "openai:" + test.model
+
+		var body map[string]any
+		newTestServer(t, app, func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&body)
+
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"choices": [{"message": {"role": "assistant", "content": "OK"}}]}`))
+		})
+
+		app.InitAI()
+
+		_, err := app.AI.Prompt("Hi")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, hasTemperature := body["temperature"]
+		if hasTemperature != test.expectTemperature {
+			t.Errorf("%v (%q): expected temperature %v, got %v", test.model, test.noTemperatureModels, test.expectTemperature, body)
+		}
+		if _, ok := body["max_tokens"]; ok || body["max_completion_tokens"] != float64(100) {
+			t.Errorf("%v: expected only max_completion_tokens, got %v", test.model, body)
+		}
+	}
+}
@@ -0,0 +1,67 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package types
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// OptionalFloat64Value is a CLI flag value for a `float64`,
+// which stays `nil` as long as the flag is not set.
+type OptionalFloat64Value struct {
+	p **float64
+}
+
+// NewOptionalFloat64Value creates a new `OptionalFloat64Value` instance,
+// which writes to `p`.
+func NewOptionalFloat64Value(p **float64) *OptionalFloat64Value {
+	return &OptionalFloat64Value{
+		p: p,
+	}
+}
+
+// Set parses and stores `s`.
+func (v *OptionalFloat64Value) Set(s string) error {
+	f64, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return err
+	}
+
+	*v.p = &f64
+	return nil
+}
+
+// String returns the current value as string or an empty string, if not set.
+func (v *OptionalFloat64Value) String() string {
+	if v.p == nil || *v.p == nil {
+		return ""
+	}
+
+	return fmt.Sprint(**v.p)
+}
+
+// Type returns the name of the type.
+func (v *OptionalFloat64Value) Type() string {
+	return "float"
+}
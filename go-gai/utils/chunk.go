@@ -0,0 +1,334 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// ChunkStrategy defines how `Chunk` splits a text.
+type ChunkStrategy string
+
+const (
+	// ChunkStrategyAuto selects the strategy by the file name in `ChunkOptions`.
+	ChunkStrategyAuto ChunkStrategy = "auto"
+	// ChunkStrategyChars splits into fixed windows of characters.
+	ChunkStrategyChars ChunkStrategy = "chars"
+	// ChunkStrategyCodeSymbols splits at top-level symbols like functions or classes.
+	ChunkStrategyCodeSymbols ChunkStrategy = "code-symbols"
+	// ChunkStrategyMarkdownHeadings splits at Markdown headings and paragraphs.
+	ChunkStrategyMarkdownHeadings ChunkStrategy = "markdown-headings"
+	// ChunkStrategyTokens splits at line boundaries.
+	ChunkStrategyTokens ChunkStrategy = "tokens"
+)
+
+// ChunkOptions stores options for `Chunk`.
+type ChunkOptions struct {
+	// MaxSize is the maximum size of a chunk, which is measured in tokens
+	// if `Tokenizer` is set and in characters otherwise. Strategy `chars`
+	// always measures in characters. `0` or less means no limit.
+	MaxSize int
+	// Name is an optional file name, which is used by strategy `auto`.
+	Name string
+	// Strategy is the strategy to use (default: `auto`).
+	Strategy ChunkStrategy
+	// Tokenizer is an optional tokenizer to measure tokens.
+	Tokenizer *TextTokenizer
+}
+
+var codeFileExtensions = map[string]bool{
+	".c": true, ".cc": true, ".cpp": true, ".cs": true, ".dart": true, ".go": true,
+	".h": true, ".hpp": true, ".java": true, ".js": true, ".jsx": true, ".kt": true,
+	".lua": true, ".m": true, ".mjs": true, ".php": true, ".pl": true, ".py": true,
+	".rb": true, ".rs": true, ".scala": true, ".sh": true, ".swift": true, ".ts": true,
+	".tsx": true, ".vb": true, ".zig": true,
+}
+
+var proseFileExtensions = map[string]bool{
+	".adoc": true, ".markdown": true, ".md": true, ".mdx": true, ".rst": true, ".txt": true,
+}
+
+var codeSymbolRegex = regexp.MustCompile(`^(export\s+)?(default\s+)?(async\s+)?(abstract|class|def|enum|fn|func|function|impl|interface|module|namespace|private|protected|pub|public|sealed|static|struct|sub|trait|type)\b`)
+
+var codeCommentPrefixes = []string{"//", "/*", "*", "#", "@", "--", "'''", `"""`}
+
+var markdownHeadingRegex = regexp.MustCompile(`^#{1,6}(\s|$)`)
+
+// ParseChunkStrategy parses `s` as `ChunkStrategy`. An empty string
+// returns `ChunkStrategyAuto`.
+func ParseChunkStrategy(s string) (ChunkStrategy, error) {
+	strategy := ChunkStrategy(strings.TrimSpace(strings.ToLower(s)))
+	switch strategy {
+	case "":
+		return ChunkStrategyAuto, nil
+	case ChunkStrategyAuto, ChunkStrategyChars, ChunkStrategyCodeSymbols, ChunkStrategyMarkdownHeadings, ChunkStrategyTokens:
+		return strategy, nil
+	}
+
+	return "", fmt.Errorf("chunk strategy '%s' is not supported", s)
+}
+
+// ChunkStrategyForFile returns the strategy, which fits best for file `name`.
+func ChunkStrategyForFile(name string) ChunkStrategy {
+	ext := strings.ToLower(filepath.Ext(name))
+
+	if codeFileExtensions[ext] {
+		return ChunkStrategyCodeSymbols
+	}
+	if proseFileExtensions[ext] {
+		return ChunkStrategyMarkdownHeadings
+	}
+	return ChunkStrategyTokens
+}
+
+// Chunk splits `text` into chunks, which are not larger than `opts.MaxSize`.
+// Structure based strategies keep blocks like functions or sections together
+// and fall back to paragraphs, lines and finally fixed windows for blocks,
+// which are too large by themselves. If there is no limit or `text` is small
+// enough, it is returned as single chunk.
+func Chunk(text string, opts ChunkOptions) []string {
+	strategy := opts.Strategy
+	if strategy == "" || strategy == ChunkStrategyAuto {
+		strategy = ChunkStrategyForFile(opts.Name)
+	}
+
+	if strategy == ChunkStrategyChars {
+		if opts.MaxSize <= 0 || utf8.RuneCountInString(text) <= opts.MaxSize {
+			return []string{text}
+		}
+		return splitByChars(text, opts.MaxSize)
+	}
+
+	c := &chunker{
+		maxSize:   opts.MaxSize,
+		tokenizer: opts.Tokenizer,
+	}
+
+	if c.maxSize <= 0 || c.measure(text) <= c.maxSize {
+		return []string{text}
+	}
+
+	var blocks []string
+	switch strategy {
+	case ChunkStrategyCodeSymbols:
+		blocks = splitByCodeSymbols(text)
+	case ChunkStrategyMarkdownHeadings:
+		blocks = splitByMarkdownHeadings(text)
+	default:
+		blocks = splitByLines(text)
+	}
+
+	return c.pack(blocks, strategy != ChunkStrategyTokens)
+}
+
+type chunker struct {
+	maxSize   int
+	tokenizer *TextTokenizer
+}
+
+func (c *chunker) measure(s string) int {
+	if c.tokenizer != nil {
+		return c.tokenizer.CountTokens(s)
+	}
+	return utf8.RuneCountInString(s)
+}
+
+// pack merges consecutive `blocks` into chunks and splits blocks,
+// which are too large, into smaller ones
+func (c *chunker) pack(blocks []string, withParagraphs bool) []string {
+	chunks := make([]string, 0)
+
+	var current strings.Builder
+	currentSize := 0
+
+	flush := func() {
+		if current.Len() > 0 {
+			chunks = append(chunks, current.String())
+		}
+
+		current.Reset()
+		currentSize = 0
+	}
+
+	for _, block := range blocks {
+		if block == "" {
+			continue
+		}
+
+		blockSize := c.measure(block)
+		if blockSize > c.maxSize {
+			// block is too large for a chunk by itself
+			flush()
+
+			if withParagraphs {
+				if paragraphs := splitByParagraphs(block); len(paragraphs) > 1 {
+					chunks = append(chunks, c.pack(paragraphs, false)...)
+					continue
+				}
+			}
+
+			if lines := splitByLines(block); len(lines) > 1 {
+				chunks = append(chunks, c.pack(lines, false)...)
+				continue
+			}
+
+			chunks = append(chunks, c.splitLongLine(block)...)
+			continue
+		}
+
+		if currentSize+blockSize > c.maxSize {
+			flush()
+		}
+
+		current.WriteString(block)
+		currentSize += blockSize
+	}
+	flush()
+
+	return chunks
+}
+
+func (c *chunker) splitLongLine(line string) []string {
+	if c.tokenizer != nil {
+		return c.tokenizer.splitLongLine(line, c.maxSize)
+	}
+	return splitByChars(line, c.maxSize)
+}
+
+func splitByChars(text string, maxChars int) []string {
+	chunks := make([]string, 0)
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i += maxChars {
+		chunks = append(chunks, string(runes[i:min(i+maxChars, len(runes))]))
+	}
+
+	return chunks
+}
+
+// splitAt splits `lines` into blocks, which start at the line indexes of `starts`
+func splitAt(lines []string, starts []int) []string {
+	blocks := make([]string, 0, len(starts)+1)
+
+	last := 0
+	for _, s := range starts {
+		if s > last {
+			blocks = append(blocks, strings.Join(lines[last:s], ""))
+			last = s
+		}
+	}
+	if last < len(lines) {
+		blocks = append(blocks, strings.Join(lines[last:], ""))
+	}
+
+	return blocks
+}
+
+func splitByLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
+
+// splitByParagraphs splits `text` after each block of empty lines
+func splitByParagraphs(text string) []string {
+	lines := splitByLines(text)
+
+	starts := make([]int, 0)
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i-1]) == "" && strings.TrimSpace(lines[i]) != "" {
+			starts = append(starts, i)
+		}
+	}
+
+	return splitAt(lines, starts)
+}
+
+// splitByCodeSymbols splits `text` before each top-level declaration,
+// including its leading comments, decorators or attributes
+func splitByCodeSymbols(text string) []string {
+	lines := splitByLines(text)
+
+	isComment := func(line string) bool {
+		for _, p := range codeCommentPrefixes {
+			if strings.HasPrefix(line, p) {
+				return true
+			}
+		}
+		return strings.HasPrefix(line, " *") // inside block comments
+	}
+
+	starts := make([]int, 0)
+	for i, line := range lines {
+		if !codeSymbolRegex.MatchString(line) {
+			continue
+		}
+
+		start := i
+		for start > 0 && isComment(lines[start-1]) {
+			start--
+		}
+
+		if len(starts) == 0 || start > starts[len(starts)-1] {
+			starts = append(starts, start)
+		}
+	}
+
+	return splitAt(lines, starts)
+}
+
+// splitByMarkdownHeadings splits `text` before each heading,
+// which is not part of a fenced code block
+func splitByMarkdownHeadings(text string) []string {
+	lines := splitByLines(text)
+
+	starts := make([]int, 0)
+	fence := ""
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") {
+			fence = "```"
+		} else if strings.HasPrefix(trimmed, "~~~") {
+			fence = "~~~"
+		} else if markdownHeadingRegex.MatchString(line) {
+			starts = append(starts, i)
+		}
+	}
+
+	return splitAt(lines, starts)
+}
@@ -0,0 +1,122 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestChunk(t *testing.T) {
+	goCode := `package main
+
+// A does a.
+func A() {
+	return
+}
+
+func B() {
+}
+`
+
+	markdown := "# Title\n\nIntro.\n\n## Usage\n\n```sh\n# no heading\ngai\n```\n\n## License\n\nMIT\n"
+
+	prose := "First paragraph\nwith two lines.\n\nSecond paragraph.\n"
+
+	tests := []struct {
+		name     string
+		text     string
+		opts     ChunkOptions
+		expected []string
+	}{
+		{"no limit", goCode, ChunkOptions{Strategy: ChunkStrategyCodeSymbols}, []string{goCode}},
+		{"small enough", "abc", ChunkOptions{MaxSize: 3, Strategy: ChunkStrategyTokens}, []string{"abc"}},
+		{"chars", "abcdefghij", ChunkOptions{MaxSize: 4, Strategy: ChunkStrategyChars}, []string{"abcd", "efgh", "ij"}},
+		{"chars with runes", "äöüß", ChunkOptions{MaxSize: 2, Strategy: ChunkStrategyChars}, []string{"äö", "üß"}},
+		{"tokens", "aaa\nbbb\nccc\n", ChunkOptions{MaxSize: 8, Strategy: ChunkStrategyTokens}, []string{"aaa\nbbb\n", "ccc\n"}},
+		{"tokens with long line", "aaaaaa\nb\n", ChunkOptions{MaxSize: 4, Strategy: ChunkStrategyTokens}, []string{"aaaa", "aa\n", "b\n"}},
+		{
+			"code symbols", goCode, ChunkOptions{MaxSize: 40, Strategy: ChunkStrategyCodeSymbols},
+			[]string{"package main\n\n", "// A does a.\nfunc A() {\n\treturn\n}\n\n", "func B() {\n}\n"},
+		},
+		{
+			"code symbols by name", goCode, ChunkOptions{MaxSize: 40, Name: "main.go"},
+			[]string{"package main\n\n", "// A does a.\nfunc A() {\n\treturn\n}\n\n", "func B() {\n}\n"},
+		},
+		{
+			"markdown headings", markdown, ChunkOptions{MaxSize: 40, Strategy: ChunkStrategyMarkdownHeadings},
+			[]string{"# Title\n\nIntro.\n\n", "## Usage\n\n```sh\n# no heading\ngai\n```\n\n", "## License\n\nMIT\n"},
+		},
+		{
+			"markdown headings by name", markdown, ChunkOptions{MaxSize: 40, Name: "README.md"},
+			[]string{"# Title\n\nIntro.\n\n", "## Usage\n\n```sh\n# no heading\ngai\n```\n\n", "## License\n\nMIT\n"},
+		},
+		{
+			"paragraphs of large section", prose, ChunkOptions{MaxSize: 35, Strategy: ChunkStrategyMarkdownHeadings},
+			[]string{"First paragraph\nwith two lines.\n\n", "Second paragraph.\n"},
+		},
+		{
+			"lines by unknown name", prose, ChunkOptions{MaxSize: 35, Name: "data.csv"},
+			[]string{"First paragraph\nwith two lines.\n\n", "Second paragraph.\n"},
+		},
+	}
+
+	for _, test := range tests {
+		chunks := Chunk(test.text, test.opts)
+
+		if strings.Join(chunks, "|") != strings.Join(test.expected, "|") || len(chunks) != len(test.expected) {
+			t.Errorf("%v: expected %q, got %q", test.name, test.expected, chunks)
+		}
+		if strings.Join(chunks, "") != test.text {
+			t.Errorf("%v: chunks do not contain the complete text", test.name)
+		}
+		for _, c := range chunks {
+			if test.opts.MaxSize > 0 && len([]rune(c)) > test.opts.MaxSize {
+				t.Errorf("%v: chunk %q is larger than %d", test.name, c, test.opts.MaxSize)
+			}
+		}
+	}
+}
+
+func TestParseChunkStrategy(t *testing.T) {
+	tests := map[string]ChunkStrategy{
+		"":                  ChunkStrategyAuto,
+		"auto":              ChunkStrategyAuto,
+		" Chars ":           ChunkStrategyChars,
+		"code-symbols":      ChunkStrategyCodeSymbols,
+		"MARKDOWN-HEADINGS": ChunkStrategyMarkdownHeadings,
+		"tokens":            ChunkStrategyTokens,
+	}
+
+	for s, expected := range tests {
+		strategy, err := ParseChunkStrategy(s)
+		if err != nil || strategy != expected {
+			t.Errorf("%q: expected %v, got %v (%v)", s, expected, strategy, err)
+		}
+	}
+
+	_, err := ParseChunkStrategy("sentences")
+	if err == nil {
+		t.Error("expected error for unknown strategy")
+	}
+}
@@ -0,0 +1,76 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"sync"
+)
+
+// ProcessInOrder invokes `process` for each index from `0` to `count - 1`
+// with up to `concurrency` workers and calls `emit` with the results in
+// the order of their indexes, as soon as they are available.
+// If `emit` returns `false`, no further items are processed and emitted.
+func ProcessInOrder[T any](count int, concurrency int, process func(i int) T, emit func(i int, result T) bool) {
+	results := make([]chan T, count)
+	for i := range results {
+		results[i] = make(chan T, 1)
+	}
+
+	stop := make(chan struct{})
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+
+		for i := range count {
+			select {
+			case jobs <- i:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	var wg sync.WaitGroup
+	for range max(concurrency, 1) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := range jobs {
+				results[i] <- process(i)
+			}
+		}()
+	}
+
+	for i, r := range results {
+		if !emit(i, <-r) {
+			// results of running workers are buffered
+			// and can be dropped
+			close(stop)
+			break
+		}
+	}
+
+	wg.Wait()
+}
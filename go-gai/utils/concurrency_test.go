@@ -0,0 +1,104 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type processInOrderTestResult struct {
+	value string
+	err   error
+}
+
+func TestProcessInOrderKeepsOrder(t *testing.T) {
+	for _, concurrency := range []int{0, 1, 2, 4, 16} {
+		t.Run(fmt.Sprintf("concurrency=%d", concurrency), func(t *testing.T) {
+			const count = 20
+
+			emitted := make([]int, 0, count)
+			ProcessInOrder(count, concurrency, func(i int) processInOrderTestResult {
+				// later items finish first
+				time.Sleep(time.Duration(count-i) * time.Millisecond)
+
+				if i%5 == 3 {
+					return processInOrderTestResult{err: fmt.Errorf("item %d failed", i)}
+				}
+				return processInOrderTestResult{value: fmt.Sprint(i)}
+			}, func(i int, result processInOrderTestResult) bool {
+				emitted = append(emitted, i)
+
+				if i%5 == 3 {
+					if result.err == nil || result.err.Error() != fmt.Sprintf("item %d failed", i) {
+						t.Errorf("item %d: expected its own error, got %v", i, result.err)
+					}
+				} else if result.err != nil || result.value != fmt.Sprint(i) {
+					t.Errorf("item %d: expected value %d, got %q (%v)", i, i, result.value, result.err)
+				}
+
+				return true
+			})
+
+			if len(emitted) != count {
+				t.Fatalf("expected %d emitted items, got %d", count, len(emitted))
+			}
+			for i, e := range emitted {
+				if i != e {
+					t.Fatalf("expected item %d at position %d, got %d", i, i, e)
+				}
+			}
+		})
+	}
+}
+
+func TestProcessInOrderStopsOnFalse(t *testing.T) {
+	const count = 100
+	const failAt = 10
+
+	var processed atomic.Int32
+	emitted := 0
+	ProcessInOrder(count, 4, func(i int) error {
+		processed.Add(1)
+		time.Sleep(time.Millisecond)
+
+		if i == failAt {
+			return errors.New("failed")
+		}
+		return nil
+	}, func(i int, err error) bool {
+		emitted++
+		return err == nil
+	})
+
+	if emitted != failAt+1 {
+		t.Errorf("expected %d emitted items, got %d", failAt+1, emitted)
+	}
+	// running workers may finish some more items
+	if n := processed.Load(); n >= count {
+		t.Errorf("expected processing to stop early, but %d items were processed", n)
+	}
+}
@@ -132,6 +132,24 @@ func DetectMime(b []byte) string {
 		}
 	}
 
+	if IsRTF(b) {
+		return rtfMimeType
+	}
+
+	if len(b) >= 4 {
+		// TIFF, which is not detected by `http.DetectContentType`
+
+		header := b[0:4]
+		if bytes.Equal(header, []byte{'I', 'I', 0x2A, 0x00}) || bytes.Equal(header, []byte{'M', 'M', 0x00, 0x2A}) {
+			return "image/tiff"
+		}
+	}
+
+	zipMimeType, err := GetZipMimeType(b)
+	if err == nil && (zipMimeType == epubMimeType || strings.HasPrefix(zipMimeType, openDocumentMimePrefix)) {
+		return zipMimeType
+	}
+
 	isXLSXFile, err := IsXLSX(b)
 	if err == nil {
 		if isXLSXFile {
@@ -342,27 +360,28 @@ func EnsurePlainText(data []byte) (string, error) {
 		}
 
 		return getJoinedText(), nil
-	} else if strings.HasSuffix(mimeType, "/htm") || strings.HasSuffix(mimeType, "/html") {
-		// HTML
+	} else if strings.HasSuffix(mimeType, "/vnd.oasis.opendocument.text") {
+		// OpenDocument text, like LibreOffice Writer
 
-		r := bytes.NewReader(data)
+		return extractODTText(data)
+	} else if strings.HasSuffix(mimeType, "/vnd.oasis.opendocument.spreadsheet") {
+		// OpenDocument spreadsheet, like LibreOffice Calc
 
-		doc, err := goquery.NewDocumentFromReader(r)
-		if err == nil {
-			var sel *goquery.Selection
+		return extractODSText(data)
+	} else if mimeType == rtfMimeType || mimeType == "text/rtf" {
+		// Rich Text Format, like WordPad
 
-			body := doc.Has("body")
-			if body != nil {
-				sel = body.Contents()
-			} else {
-				sel = doc.Contents()
-			}
+		return extractRTFText(data)
+	} else if mimeType == epubMimeType {
+		// EPUB e-book
 
-			if sel != nil {
-				sanitized := bluemonday.UGCPolicy().Sanitize(sel.Text())
+		return extractEPUBText(data)
+	} else if strings.HasSuffix(mimeType, "/htm") || strings.HasSuffix(mimeType, "/html") {
+		// HTML
 
-				return strings.TrimSpace(sanitized), nil
-			}
+		text, err := htmlToPlainText(data)
+		if err == nil {
+			return text, nil
 		}
 	} else if strings.HasSuffix(mimeType, "/pdf") {
 		// PDF
@@ -394,6 +413,27 @@ func EnsurePlainText(data []byte) (string, error) {
 	return string(data), nil
 }
 
+// htmlToPlainText returns the sanitized text of the body of an HTML document.
+func htmlToPlainText(data []byte) (string, error) {
+	doc, err := goquery.NewDocumentFromReader(bytes.NewReader(data))
+	if err != nil {
+		return "", err
+	}
+
+	var sel *goquery.Selection
+
+	body := doc.Has("body")
+	if body != nil {
+		sel = body.Contents()
+	} else {
+		sel = doc.Contents()
+	}
+
+	sanitized := bluemonday.UGCPolicy().Sanitize(sel.Text())
+
+	return strings.TrimSpace(sanitized), nil
+}
+
 // EnsurePNG ensures having a image in PNG format.
 func EnsurePNG(data []byte) ([]byte, error) {
 	mimeType := DetectMime(data)
@@ -413,6 +453,52 @@ func EnsurePNG(data []byte) ([]byte, error) {
 	return ConvertImage(data, encodeImage)
 }
 
+// GetAudioFileExtension returns the file extension with leading dot
+// for a supported audio `mimeType` or an empty string if not supported.
+func GetAudioFileExtension(mimeType string) string {
+	mimeType = strings.TrimSpace(strings.ToLower(mimeType))
+
+	switch mimeType {
+	case "audio/mpeg", "audio/mp3", "audio/mpga":
+		return ".mp3"
+	case "audio/wav", "audio/wave", "audio/x-wav", "audio/vnd.wave":
+		return ".wav"
+	case "audio/mp4", "audio/m4a", "audio/x-m4a":
+		return ".m4a"
+	case "audio/webm", "video/webm":
+		return ".webm"
+	case "audio/ogg", "audio/opus":
+		return ".ogg"
+	case "audio/flac", "audio/x-flac":
+		return ".flac"
+	}
+
+	return ""
+}
+
+// GetImageFileExtension returns the file extension with leading dot
+// for a supported image `mimeType` or an empty string if not supported.
+func GetImageFileExtension(mimeType string) string {
+	mimeType = strings.TrimSpace(strings.ToLower(mimeType))
+
+	switch mimeType {
+	case "image/png":
+		return ".png"
+	case "image/jpeg", "image/jpg":
+		return ".jpg"
+	case "image/webp":
+		return ".webp"
+	case "image/gif":
+		return ".gif"
+	case "image/bmp":
+		return ".bmp"
+	case "image/tiff":
+		return ".tiff"
+	}
+
+	return ""
+}
+
 // GetPartsOfDataURI converts returns the parts of `dataURI`.
 func GetPartsOfDataURI(dataURI string) (string, string, error) {
 	parts := strings.SplitN(dataURI, ",", 2)
@@ -498,5 +584,5 @@ func IsXLSX(data []byte) (bool, error) {
 func ReadImageFromBuffer(decode ImageDecode, data []byte) (image.Image, error) {
 	reader := bytes.NewReader(data)
 
-	return webp.Decode(reader)
+	return decode(reader)
 }
@@ -0,0 +1,110 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"golang.org/x/image/bmp"
+	"golang.org/x/image/tiff"
+)
+
+// testWebP is a lossless WebP image with 1x1 pixel, because
+// there is no encoder for this format.
+var testWebP = []byte("RIFF\x1a\x00\x00\x00WEBPVP8L\x0d\x00\x00\x00\x2f\x00\x00\x00\x10\x07\x10\x11\x11\x88\x88\xfe\x07\x00")
+
+func TestEnsurePNG(t *testing.T) {
+	red := color.RGBA{R: 255, A: 255}
+
+	img := image.NewRGBA(image.Rect(0, 0, 3, 2))
+	for x := range 3 {
+		for y := range 2 {
+			img.Set(x, y, red)
+		}
+	}
+
+	encode := func(encode func(w io.Writer) error) []byte {
+		var buff bytes.Buffer
+
+		err := encode(&buff)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return buff.Bytes()
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		width  int
+		height int
+		isRed  bool
+	}{
+		{"png", encode(func(w io.Writer) error { return png.Encode(w, img) }), 3, 2, true},
+		{"jpeg", encode(func(w io.Writer) error { return jpeg.Encode(w, img, &jpeg.Options{Quality: 100}) }), 3, 2, true},
+		{"gif", encode(func(w io.Writer) error { return gif.Encode(w, img, nil) }), 3, 2, true},
+		{"bmp", encode(func(w io.Writer) error { return bmp.Encode(w, img) }), 3, 2, true},
+		{"tiff", encode(func(w io.Writer) error { return tiff.Encode(w, img, nil) }), 3, 2, true},
+		{"webp", testWebP, 1, 1, false},
+	}
+
+	for _, test := range tests {
+		data, err := EnsurePNG(test.data)
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+
+		decoded, err := png.Decode(bytes.NewReader(data))
+		if err != nil {
+			t.Errorf("%v: result is no PNG: %v", test.name, err)
+			continue
+		}
+
+		bounds := decoded.Bounds()
+		if bounds.Dx() != test.width || bounds.Dy() != test.height {
+			t.Errorf("%v: expected size %dx%d, got %dx%d", test.name, test.width, test.height, bounds.Dx(), bounds.Dy())
+		}
+
+		if test.isRed {
+			// JPEG is lossy
+			r, g, b, _ := decoded.At(1, 1).RGBA()
+			if r>>8 < 240 || g>>8 > 15 || b>>8 > 15 {
+				t.Errorf("%v: expected red pixel, got %v/%v/%v", test.name, r>>8, g>>8, b>>8)
+			}
+		}
+	}
+
+	_, err := EnsurePNG([]byte("no image"))
+	if err == nil {
+		t.Error("expected error for unsupported data")
+	}
+}
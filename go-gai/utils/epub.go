@@ -0,0 +1,149 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+)
+
+const epubMimeType = "application/epub+zip"
+
+type epubContainer struct {
+	Rootfiles []struct {
+		FullPath string `xml:"full-path,attr"`
+	} `xml:"rootfiles>rootfile"`
+}
+
+type epubPackage struct {
+	Manifest []struct {
+		Href      string `xml:"href,attr"`
+		ID        string `xml:"id,attr"`
+		MediaType string `xml:"media-type,attr"`
+	} `xml:"manifest>item"`
+	Spine []struct {
+		IDRef string `xml:"idref,attr"`
+	} `xml:"spine>itemref"`
+}
+
+// IsEPUB checks if `data` contains an e-book in EPUB format.
+func IsEPUB(data []byte) (bool, error) {
+	mimeType, err := GetZipMimeType(data)
+	return mimeType == epubMimeType, err
+}
+
+// extractEPUBText extracts the text of all chapters of an EPUB e-book
+// in the order of its spine
+func extractEPUBText(data []byte) (string, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	files := map[string]*zip.File{}
+	for _, f := range z.File {
+		files[f.Name] = f
+	}
+
+	readXML := func(name string, v any) error {
+		f, ok := files[name]
+		if !ok {
+			return fmt.Errorf("'%s' not found in EPUB", name)
+		}
+
+		content, err := readZipEntry(f)
+		if err != nil {
+			return err
+		}
+
+		return xml.Unmarshal(content, v)
+	}
+
+	// find the package document (OPF) ...
+	var container epubContainer
+	err = readXML("META-INF/container.xml", &container)
+	if err != nil {
+		return "", err
+	}
+	if len(container.Rootfiles) == 0 {
+		return "", errors.New("no package document found in EPUB")
+	}
+
+	opfPath := container.Rootfiles[0].FullPath
+
+	var pkg epubPackage
+	err = readXML(opfPath, &pkg)
+	if err != nil {
+		return "", err
+	}
+
+	// ... and read the chapters in the order of its spine
+	hrefs := map[string]string{}
+	for _, item := range pkg.Manifest {
+		hrefs[item.ID] = item.Href
+	}
+
+	texts := make([]string, 0)
+	getJoinedText := func() string {
+		return strings.Join(texts, "\n\n\n")
+	}
+
+	for _, itemRef := range pkg.Spine {
+		href, ok := hrefs[itemRef.IDRef]
+		if !ok {
+			continue
+		}
+
+		href = strings.SplitN(href, "#", 2)[0]
+		if unescaped, err := url.PathUnescape(href); err == nil {
+			href = unescaped
+		}
+
+		f, ok := files[path.Join(path.Dir(opfPath), href)]
+		if !ok {
+			continue // missing chapters are skipped
+		}
+
+		content, err := readZipEntry(f)
+		if err != nil {
+			return getJoinedText(), err
+		}
+
+		text, err := htmlToPlainText(content)
+		if err != nil {
+			return getJoinedText(), err
+		}
+
+		if text != "" {
+			texts = append(texts, text)
+		}
+	}
+
+	return getJoinedText(), nil
+}
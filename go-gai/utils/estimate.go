@@ -0,0 +1,63 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+// TokenPrices stores prices per 1,000 tokens, which are `nil` if unknown.
+type TokenPrices struct {
+	// Input stores the price per 1,000 input tokens.
+	Input *float64
+	// Output stores the price per 1,000 output tokens.
+	Output *float64
+}
+
+// UsageEstimate stores the estimated token usage and costs of a request.
+type UsageEstimate struct {
+	// InputCost stores the estimated costs of the input or `nil` if there is no price.
+	InputCost *float64
+	// InputTokens stores the approximate number of input tokens.
+	InputTokens int
+	// MaxOutputCost stores the maximum costs of the output or `nil` if there is no price or limit.
+	MaxOutputCost *float64
+	// MaxOutputTokens stores the maximum number of output tokens or `0` if there is no limit.
+	MaxOutputTokens int64
+}
+
+// EstimateUsage estimates the token usage and costs of a request with `text`
+// as input and an answer of up to `maxOutputTokens` tokens.
+func EstimateUsage(tokenizer *TextTokenizer, text string, maxOutputTokens int64, prices TokenPrices) UsageEstimate {
+	estimate := UsageEstimate{
+		InputTokens:     tokenizer.CountTokens(text),
+		MaxOutputTokens: max(maxOutputTokens, 0),
+	}
+
+	if prices.Input != nil {
+		inputCost := float64(estimate.InputTokens) / 1000 * *prices.Input
+		estimate.InputCost = &inputCost
+	}
+	if prices.Output != nil && estimate.MaxOutputTokens > 0 {
+		maxOutputCost := float64(estimate.MaxOutputTokens) / 1000 * *prices.Output
+		estimate.MaxOutputCost = &maxOutputCost
+	}
+
+	return estimate
+}
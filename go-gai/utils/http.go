@@ -26,21 +26,103 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strings"
 )
 
+// RequestTooLargeHint contains the guidance for requests, which are too large.
+const RequestTooLargeHint = "reduce the number or size of files, submit documents as extracted plain text instead of binary data or split large files into parts, like with --max-file-tokens"
+
+// HttpResponseError is an error of a failed HTTP response.
+type HttpResponseError struct {
+	// StatusCode stores the HTTP status code of the response.
+	StatusCode int
+
+	message string
+}
+
+// Error returns the error message.
+func (e *HttpResponseError) Error() string {
+	return e.message
+}
+
+// secretHttpHeaders stores the lower case names of HTTP headers with secrets.
+var secretHttpHeaders = []string{"api-key", "authorization", "x-api-key", "x-goog-api-key"}
+
+// BuildCurlCommand creates a `curl` command line that is equivalent to `req` with `body`.
+// Values of headers with secrets are masked, if `showSecrets` is `false`.
+func BuildCurlCommand(req *http.Request, body []byte, showSecrets bool) string {
+	lines := []string{
+		fmt.Sprintf("curl -X %s %s", req.Method, QuoteForShell(req.URL.String())),
+	}
+
+	headerNames := make([]string, 0)
+	for name := range req.Header {
+		headerNames = append(headerNames, name)
+	}
+	sort.Strings(headerNames)
+
+	for _, name := range headerNames {
+		for _, value := range req.Header.Values(name) {
+			if !showSecrets {
+				value = maskHttpHeaderValue(name, value)
+			}
+
+			lines = append(lines, fmt.Sprintf("-H %s", QuoteForShell(fmt.Sprintf("%s: %s", name, value))))
+		}
+	}
+
+	if len(body) > 0 {
+		lines = append(lines, fmt.Sprintf("--data-binary %s", QuoteForShell(string(body))))
+	}
+
+	return strings.Join(lines, " \\\n  ")
+}
+
 // CheckForHttpResponseError builds an error object based on the status code in `resp`.
 func CheckForHttpResponseError(resp *http.Response) error {
 	if resp.StatusCode == 200 {
 		return nil
 	}
 
+	message := fmt.Sprintf("unexpected response status code: %d", resp.StatusCode)
 	if resp.StatusCode == 400 || resp.StatusCode == 429 {
 		responseData, err := io.ReadAll(resp.Body)
 		if err == nil {
-			return fmt.Errorf("request failed with status %d: %s", resp.StatusCode, string(responseData))
+			message = fmt.Sprintf("request failed with status %d: %s", resp.StatusCode, string(responseData))
+		} else {
+			message = fmt.Sprintf("request failed with status %d and error reading response body", resp.StatusCode)
 		}
-		return fmt.Errorf("request failed with status %d and error reading response body", resp.StatusCode)
+	} else if resp.StatusCode == 413 {
+		message = fmt.Sprintf("request failed with status %d, because its body is too large: %s", resp.StatusCode, RequestTooLargeHint)
 	}
 
-	return fmt.Errorf("unexpected response status code: %d", resp.StatusCode)
+	return &HttpResponseError{
+		StatusCode: resp.StatusCode,
+		message:    message,
+	}
+}
+
+func maskHttpHeaderValue(name string, value string) string {
+	name = strings.TrimSpace(strings.ToLower(name))
+
+	for _, secretHeader := range secretHttpHeaders {
+		if name != secretHeader {
+			continue
+		}
+
+		// keep auth scheme like `Bearer`
+		sep := strings.Index(value, " ")
+		if sep > -1 {
+			return fmt.Sprintf("%s ***", value[:sep])
+		}
+		return "***"
+	}
+
+	return value
+}
+
+// QuoteForShell quotes `s` in single quotes for the use in a POSIX shell.
+func QuoteForShell(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", `'\''`))
 }
@@ -0,0 +1,98 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func newTestCurlRequest(t *testing.T, body string) *http.Request {
+	t.Helper()
+
+	req, err := http.NewRequest("POST", "https://api.example.com/v1/chat/completions?a=1&b=2", bytes.NewBufferString(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req.Header.Set("Authorization", "Bearer sk-secret")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Api-Key", "secret-key")
+	req.Header.Set("X-Goog-Api-Key", "goog-secret")
+
+	return req
+}
+
+func TestBuildCurlCommandMasksSecrets(t *testing.T) {
+	body := `{"messages":[{"content":"it's","role":"user"}]}`
+
+	curl := BuildCurlCommand(newTestCurlRequest(t, body), []byte(body), false)
+
+	expected := strings.Join([]string{
+		`curl -X POST 'https://api.example.com/v1/chat/completions?a=1&b=2'`,
+		`-H 'Authorization: Bearer ***'`,
+		`-H 'Content-Type: application/json'`,
+		`-H 'X-Api-Key: ***'`,
+		`-H 'X-Goog-Api-Key: ***'`,
+		`--data-binary '{"messages":[{"content":"it'\''s","role":"user"}]}'`,
+	}, " \\\n  ")
+	if curl != expected {
+		t.Fatalf("expected\n%s\ngot\n%s", expected, curl)
+	}
+
+	for _, secret := range []string{"sk-secret", "secret-key", "goog-secret"} {
+		if strings.Contains(curl, secret) {
+			t.Errorf("secret %q is not masked", secret)
+		}
+	}
+}
+
+func TestBuildCurlCommandShowsSecrets(t *testing.T) {
+	curl := BuildCurlCommand(newTestCurlRequest(t, ""), nil, true)
+
+	for _, header := range []string{"'Authorization: Bearer sk-secret'", "'X-Api-Key: secret-key'", "'X-Goog-Api-Key: goog-secret'"} {
+		if !strings.Contains(curl, header) {
+			t.Errorf("expected header %s in\n%s", header, curl)
+		}
+	}
+	if strings.Contains(curl, "--data-binary") {
+		t.Errorf("expected no body in\n%s", curl)
+	}
+}
+
+func TestQuoteForShell(t *testing.T) {
+	tests := map[string]string{
+		"":           `''`,
+		"abc":        `'abc'`,
+		"it's":       `'it'\''s'`,
+		"a b\n$HOME": "'a b\n$HOME'",
+	}
+
+	for input, expected := range tests {
+		if quoted := QuoteForShell(input); quoted != expected {
+			t.Errorf("%q: expected %s, got %s", input, expected, quoted)
+		}
+	}
+}
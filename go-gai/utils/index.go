@@ -25,8 +25,15 @@ package utils
 import (
 	"fmt"
 	"os"
+	"regexp"
+	"strings"
 )
 
+// maxJSONSchemaNameLength is the maximum length of a JSON schema name, allowed by OpenAI.
+const maxJSONSchemaNameLength = 64
+
+var invalidJSONSchemaNameChars = regexp.MustCompile(`[^a-zA-Z0-9_-]+`)
+
 // CheckIfError checks if `err` is not `nil` and exits the program with exit code 1 after printing the error to stderr.
 func CheckIfError(err error) {
 	if err != nil {
@@ -34,3 +41,18 @@ func CheckIfError(err error) {
 		os.Exit(1)
 	}
 }
+
+// SanitizeJSONSchemaName converts `name` to a JSON schema name,
+// which matches `^[a-zA-Z0-9_-]+$` and has a maximum length of 64 chars.
+func SanitizeJSONSchemaName(name string) string {
+	sanitized := strings.Trim(
+		invalidJSONSchemaNameChars.ReplaceAllString(strings.TrimSpace(name), "_"),
+		"_",
+	)
+
+	if len(sanitized) > maxJSONSchemaNameLength {
+		sanitized = sanitized[:maxJSONSchemaNameLength]
+	}
+
+	return sanitized
+}
@@ -0,0 +1,47 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSanitizeJSONSchemaName(t *testing.T) {
+	tests := map[string]string{
+		"GaiResponseSchema":     "GaiResponseSchema",
+		"commit-message_v2":     "commit-message_v2",
+		" My commit message ":   "My_commit_message",
+		"a.b/c:d":               "a_b_c_d",
+		"Größe":                 "Gr_e",
+		"__name__":              "name",
+		"!!!":                   "",
+		strings.Repeat("x", 70): strings.Repeat("x", 64),
+	}
+
+	for name, expected := range tests {
+		if sanitized := SanitizeJSONSchemaName(name); sanitized != expected {
+			t.Errorf("%q: expected %q, got %q", name, expected, sanitized)
+		}
+	}
+}
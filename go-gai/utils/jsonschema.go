@@ -0,0 +1,570 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ValidateJSONSchema validates `data`, which is the JSON string of an answer, against
+// `schema` and returns the list of violations, which is empty if `data` is valid.
+//
+// Supported are the keywords, which are usually used by AI providers
+// for structured outputs: `type`, `enum`, `const`, `properties`, `required`,
+// `additionalProperties`, `items`, `minItems`, `maxItems`, `minLength`,
+// `maxLength`, `pattern`, `minimum`, `maximum`, `anyOf` and `oneOf`.
+func ValidateJSONSchema(schema map[string]any, data string) ([]string, error) {
+	var value any
+	err := json.Unmarshal([]byte(data), &value)
+	if err != nil {
+		return []string{fmt.Sprintf("$: no valid JSON (%v)", err)}, nil
+	}
+
+	// ensure that all values of the schema have the types of `encoding/json`
+	// which makes it easier to compare them
+	var normalizedSchema map[string]any
+	schemaData, err := json.Marshal(schema)
+	if err != nil {
+		return nil, err
+	}
+	err = json.Unmarshal(schemaData, &normalizedSchema)
+	if err != nil {
+		return nil, err
+	}
+
+	return validateJSONSchemaValue(normalizedSchema, value, "$"), nil
+}
+
+func getJSONSchemaTypeOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return "boolean"
+	case float64:
+		if v == float64(int64(v)) {
+			return "integer"
+		}
+		return "number"
+	case string:
+		return "string"
+	case []any:
+		return "array"
+	case map[string]any:
+		return "object"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
+
+func isJSONSchemaType(value any, schemaType string) bool {
+	valueType := getJSONSchemaTypeOf(value)
+
+	return valueType == schemaType ||
+		(schemaType == "number" && valueType == "integer")
+}
+
+func validateJSONSchemaValue(schema map[string]any, value any, path string) []string {
+	violations := make([]string, 0)
+	addViolation := func(format string, a ...any) {
+		violations = append(violations, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, a...)))
+	}
+
+	// type
+	if schemaType, ok := schema["type"]; ok {
+		allowedTypes := make([]string, 0)
+		switch t := schemaType.(type) {
+		case string:
+			allowedTypes = append(allowedTypes, t)
+		case []any:
+			for _, item := range t {
+				if s, ok := item.(string); ok {
+					allowedTypes = append(allowedTypes, s)
+				}
+			}
+		}
+
+		if len(allowedTypes) > 0 && !slices.ContainsFunc(allowedTypes, func(t string) bool {
+			return isJSONSchemaType(value, t)
+		}) {
+			addViolation("expected %s, but got %s", strings.Join(allowedTypes, " or "), getJSONSchemaTypeOf(value))
+
+			return violations // other checks make no sense
+		}
+	}
+
+	// enum and const
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(e any) bool {
+			return reflect.DeepEqual(e, value)
+		}) {
+			enumData, _ := json.Marshal(enum)
+			addViolation("must be one of %s", enumData)
+		}
+	}
+	if constValue, ok := schema["const"]; ok {
+		if !reflect.DeepEqual(constValue, value) {
+			constData, _ := json.Marshal(constValue)
+			addViolation("must be %s", constData)
+		}
+	}
+
+	// anyOf and oneOf
+	for _, keyword := range []string{"anyOf", "oneOf"} {
+		subSchemas, ok := schema[keyword].([]any)
+		if !ok {
+			continue
+		}
+
+		matches := 0
+		for _, s := range subSchemas {
+			if subSchema, ok := s.(map[string]any); ok {
+				if len(validateJSONSchemaValue(subSchema, value, path)) == 0 {
+					matches++
+				}
+			}
+		}
+
+		if matches == 0 || (keyword == "oneOf" && matches > 1) {
+			addViolation("does not match %s", keyword)
+		}
+	}
+
+	switch v := value.(type) {
+	case map[string]any:
+		properties, _ := schema["properties"].(map[string]any)
+
+		if required, ok := schema["required"].([]any); ok {
+			for _, r := range required {
+				if name, ok := r.(string); ok {
+					if _, exists := v[name]; !exists {
+						addViolation("missing required property '%s'", name)
+					}
+				}
+			}
+		}
+
+		// sort for a stable output
+		names := make([]string, 0, len(v))
+		for name := range v {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			propertyPath := fmt.Sprintf("%s.%s", path, name)
+
+			if propertySchema, ok := properties[name].(map[string]any); ok {
+				violations = append(violations, validateJSONSchemaValue(propertySchema, v[name], propertyPath)...)
+				continue
+			}
+
+			switch additionalProperties := schema["additionalProperties"].(type) {
+			case bool:
+				if !additionalProperties {
+					addViolation("property '%s' is not allowed", name)
+				}
+			case map[string]any:
+				violations = append(violations, validateJSONSchemaValue(additionalProperties, v[name], propertyPath)...)
+			}
+		}
+
+	case []any:
+		if minItems, ok := schema["minItems"].(float64); ok && float64(len(v)) < minItems {
+			addViolation("must have at least %v item(s), but has %v", minItems, len(v))
+		}
+		if maxItems, ok := schema["maxItems"].(float64); ok && float64(len(v)) > maxItems {
+			addViolation("must have at most %v item(s), but has %v", maxItems, len(v))
+		}
+
+		if itemSchema, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				violations = append(violations, validateJSONSchemaValue(itemSchema, item, fmt.Sprintf("%s[%v]", path, i))...)
+			}
+		}
+
+	case string:
+		length := float64(len([]rune(v)))
+
+		if minLength, ok := schema["minLength"].(float64); ok && length < minLength {
+			addViolation("must have at least %v character(s)", minLength)
+		}
+		if maxLength, ok := schema["maxLength"].(float64); ok && length > maxLength {
+			addViolation("must have at most %v character(s)", maxLength)
+		}
+		if pattern, ok := schema["pattern"].(string); ok {
+			re, err := regexp.Compile(pattern)
+			if err == nil && !re.MatchString(v) {
+				addViolation("must match pattern '%s'", pattern)
+			}
+		}
+
+	case float64:
+		if minimum, ok := schema["minimum"].(float64); ok && v < minimum {
+			addViolation("must be >= %v", minimum)
+		}
+		if maximum, ok := schema["maximum"].(float64); ok && v > maximum {
+			addViolation("must be <= %v", maximum)
+		}
+	}
+
+	return violations
+}
+
+// jsonSchemaTypes contains the valid values of the `type` keyword.
+var jsonSchemaTypes = []string{"array", "boolean", "integer", "null", "number", "object", "string"}
+
+// maxStrictJSONSchemaDepth is the maximum nesting depth
+// of objects in OpenAI's strict mode.
+const maxStrictJSONSchemaDepth = 10
+
+// maxStrictJSONSchemaProperties is the maximum number of object
+// properties in OpenAI's strict mode.
+const maxStrictJSONSchemaProperties = 5000
+
+// strictJSONSchemaUnsupportedKeywords contains the keywords,
+// which are not supported by OpenAI's strict mode.
+var strictJSONSchemaUnsupportedKeywords = []string{
+	"allOf", "contains", "dependentRequired", "dependentSchemas", "else", "if",
+	"maxContains", "maxLength", "maxProperties", "minContains", "minLength",
+	"minProperties", "not", "oneOf", "patternProperties", "propertyNames",
+	"then", "unevaluatedItems", "unevaluatedProperties", "uniqueItems",
+}
+
+type jsonSchemaLinter struct {
+	errors         []string
+	properties     int
+	root           map[string]any
+	strictIssues   []string
+	tooDeepHandled bool
+}
+
+// LintJSONSchema checks `schema`, which is used as response schema, and
+// returns the list of errors, which violate the rules of JSON Schema,
+// and the list of issues, which violate the requirements of the strict
+// mode of OpenAI's structured outputs.
+func LintJSONSchema(schema map[string]any) ([]string, []string) {
+	l := &jsonSchemaLinter{
+		errors:       make([]string, 0),
+		root:         schema,
+		strictIssues: make([]string, 0),
+	}
+
+	if rootType, _ := schema["type"].(string); rootType != "object" {
+		l.addError("$", "type of root must be 'object'")
+	}
+	if _, ok := schema["anyOf"]; ok {
+		l.addStrictIssue("$", "root must not be 'anyOf'")
+	}
+
+	l.lint(schema, "$", 1)
+
+	if l.properties > maxStrictJSONSchemaProperties {
+		l.addStrictIssue("$", "has %v properties, but only %v are allowed", l.properties, maxStrictJSONSchemaProperties)
+	}
+
+	return l.errors, l.strictIssues
+}
+
+func (l *jsonSchemaLinter) addError(path string, format string, a ...any) {
+	l.errors = append(l.errors, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, a...)))
+}
+
+func (l *jsonSchemaLinter) addStrictIssue(path string, format string, a ...any) {
+	l.strictIssues = append(l.strictIssues, fmt.Sprintf("%s: %s", path, fmt.Sprintf(format, a...)))
+}
+
+func (l *jsonSchemaLinter) lint(schema map[string]any, path string, depth int) {
+	if depth > maxStrictJSONSchemaDepth && !l.tooDeepHandled {
+		l.addStrictIssue(path, "is nested deeper than %v levels", maxStrictJSONSchemaDepth)
+		l.tooDeepHandled = true
+	}
+
+	// sort for a stable output
+	keywords := make([]string, 0, len(schema))
+	for k := range schema {
+		keywords = append(keywords, k)
+	}
+	sort.Strings(keywords)
+
+	for _, k := range keywords {
+		if slices.Contains(strictJSONSchemaUnsupportedKeywords, k) {
+			l.addStrictIssue(path, "keyword '%s' is not supported", k)
+		}
+	}
+
+	// type
+	types := make([]string, 0)
+	if schemaType, ok := schema["type"]; ok {
+		switch t := schemaType.(type) {
+		case string:
+			types = append(types, t)
+		case []any:
+			for _, item := range t {
+				s, ok := item.(string)
+				if !ok {
+					l.addError(path, "items of 'type' must be strings")
+					continue
+				}
+				types = append(types, s)
+			}
+		default:
+			l.addError(path, "'type' must be a string or an array of strings")
+		}
+
+		for _, t := range types {
+			if !slices.Contains(jsonSchemaTypes, t) {
+				l.addError(path, "'%s' is no valid type, use one of %s", t, strings.Join(jsonSchemaTypes, ", "))
+			}
+		}
+	} else if _, ok := schema["$ref"]; !ok && !hasAnyJSONSchemaKeyword(schema, "anyOf", "oneOf", "allOf", "enum", "const") {
+		l.addStrictIssue(path, "'type' is missing")
+	}
+
+	// object
+	properties, hasProperties := schema["properties"]
+	propertyMap, _ := properties.(map[string]any)
+	if hasProperties {
+		if propertyMap == nil {
+			l.addError(path, "'properties' must be an object")
+		} else {
+			names := make([]string, 0, len(propertyMap))
+			for name := range propertyMap {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				l.properties++
+
+				propertySchema, ok := propertyMap[name].(map[string]any)
+				if !ok {
+					l.addError(fmt.Sprintf("%s.properties.%s", path, name), "must be an object")
+					continue
+				}
+				l.lint(propertySchema, fmt.Sprintf("%s.properties.%s", path, name), depth+1)
+			}
+		}
+	}
+
+	requiredNames := make([]string, 0)
+	if required, ok := schema["required"]; ok {
+		items, ok := required.([]any)
+		if !ok {
+			l.addError(path, "'required' must be an array of strings")
+		}
+
+		for _, item := range items {
+			name, ok := item.(string)
+			if !ok {
+				l.addError(path, "items of 'required' must be strings")
+				continue
+			}
+			requiredNames = append(requiredNames, name)
+
+			if _, exists := propertyMap[name]; !exists {
+				l.addError(path, "required property '%s' is not defined in 'properties'", name)
+			}
+		}
+	}
+
+	switch additionalProperties := schema["additionalProperties"].(type) {
+	case nil:
+	case bool:
+	case map[string]any:
+		l.lint(additionalProperties, fmt.Sprintf("%s.additionalProperties", path), depth+1)
+	default:
+		l.addError(path, "'additionalProperties' must be a boolean or an object")
+	}
+
+	if slices.Contains(types, "object") || hasProperties {
+		if additionalProperties, ok := schema["additionalProperties"].(bool); !ok || additionalProperties {
+			l.addStrictIssue(path, "'additionalProperties' must be false")
+		}
+
+		names := make([]string, 0, len(propertyMap))
+		for name := range propertyMap {
+			if !slices.Contains(requiredNames, name) {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			l.addStrictIssue(path, "property '%s' must be in 'required' (use a 'null' type for optional values)", name)
+		}
+	}
+
+	// array
+	switch items := schema["items"].(type) {
+	case nil:
+		if slices.Contains(types, "array") {
+			l.addStrictIssue(path, "'items' is missing")
+		}
+	case map[string]any:
+		l.lint(items, fmt.Sprintf("%s.items", path), depth+1)
+	default:
+		l.addError(path, "'items' must be an object")
+	}
+
+	// enum
+	if enum, ok := schema["enum"]; ok {
+		if values, ok := enum.([]any); !ok || len(values) == 0 {
+			l.addError(path, "'enum' must be a non-empty array")
+		}
+	}
+
+	// sub schemas
+	for _, keyword := range []string{"allOf", "anyOf", "oneOf"} {
+		subSchemas, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+
+		items, ok := subSchemas.([]any)
+		if !ok || len(items) == 0 {
+			l.addError(path, "'%s' must be a non-empty array", keyword)
+			continue
+		}
+
+		for i, item := range items {
+			subSchema, ok := item.(map[string]any)
+			if !ok {
+				l.addError(fmt.Sprintf("%s.%s[%v]", path, keyword, i), "must be an object")
+				continue
+			}
+			l.lint(subSchema, fmt.Sprintf("%s.%s[%v]", path, keyword, i), depth)
+		}
+	}
+	for _, keyword := range []string{"$defs", "definitions"} {
+		defs, ok := schema[keyword]
+		if !ok {
+			continue
+		}
+
+		defMap, ok := defs.(map[string]any)
+		if !ok {
+			l.addError(path, "'%s' must be an object", keyword)
+			continue
+		}
+
+		names := make([]string, 0, len(defMap))
+		for name := range defMap {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			defSchema, ok := defMap[name].(map[string]any)
+			if !ok {
+				l.addError(fmt.Sprintf("%s.%s.%s", path, keyword, name), "must be an object")
+				continue
+			}
+			l.lint(defSchema, fmt.Sprintf("%s.%s.%s", path, keyword, name), depth)
+		}
+	}
+
+	if ref, ok := schema["$ref"]; ok {
+		refPath, ok := ref.(string)
+		if !ok {
+			l.addError(path, "'$ref' must be a string")
+		} else if !l.canResolveRef(refPath) {
+			l.addError(path, "'$ref' '%s' cannot be resolved, only local references like '#/$defs/name' are supported", refPath)
+		}
+	}
+
+	// limits
+	for _, keyword := range []string{"minItems", "maxItems", "minLength", "maxLength", "minProperties", "maxProperties"} {
+		if value, ok := schema[keyword]; ok {
+			if n, ok := value.(float64); !ok || n < 0 || n != float64(int64(n)) {
+				l.addError(path, "'%s' must be a non-negative integer", keyword)
+			}
+		}
+	}
+	for _, keyword := range []string{"minimum", "maximum", "exclusiveMinimum", "exclusiveMaximum", "multipleOf"} {
+		if value, ok := schema[keyword]; ok {
+			if _, ok := value.(float64); !ok {
+				l.addError(path, "'%s' must be a number", keyword)
+			}
+		}
+	}
+	for _, keywords := range [][]string{{"minItems", "maxItems"}, {"minLength", "maxLength"}, {"minimum", "maximum"}} {
+		minValue, hasMin := schema[keywords[0]].(float64)
+		maxValue, hasMax := schema[keywords[1]].(float64)
+		if hasMin && hasMax && minValue > maxValue {
+			l.addError(path, "'%s' must not be greater than '%s'", keywords[0], keywords[1])
+		}
+	}
+
+	if pattern, ok := schema["pattern"]; ok {
+		s, ok := pattern.(string)
+		if !ok {
+			l.addError(path, "'pattern' must be a string")
+		} else if _, err := regexp.Compile(s); err != nil {
+			l.addError(path, "'pattern' is no valid regular expression (%v)", err)
+		}
+	}
+}
+
+// canResolveRef returns `true` if `ref` is a local reference,
+// like `#` or `#/$defs/name`, which points to a value in the root schema.
+func (l *jsonSchemaLinter) canResolveRef(ref string) bool {
+	if ref == "#" {
+		return true
+	}
+	if !strings.HasPrefix(ref, "#/") {
+		return false
+	}
+
+	var current any = l.root
+	for _, part := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+		part = strings.ReplaceAll(strings.ReplaceAll(part, "~1", "/"), "~0", "~")
+
+		m, ok := current.(map[string]any)
+		if !ok {
+			return false
+		}
+		current, ok = m[part]
+		if !ok {
+			return false
+		}
+	}
+
+	_, ok := current.(map[string]any)
+	return ok
+}
+
+func hasAnyJSONSchemaKeyword(schema map[string]any, keywords ...string) bool {
+	for _, k := range keywords {
+		if _, ok := schema[k]; ok {
+			return true
+		}
+	}
+	return false
+}
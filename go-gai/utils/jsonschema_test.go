@@ -0,0 +1,161 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+// parseTestJSONSchema parses the JSON `schema` for tests.
+func parseTestJSONSchema(t *testing.T, schema string) map[string]any {
+	t.Helper()
+
+	var value map[string]any
+	err := json.Unmarshal([]byte(schema), &value)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return value
+}
+
+func TestLintJSONSchema(t *testing.T) {
+	tests := []struct {
+		name                 string
+		schema               string
+		expectedErrors       []string
+		expectedStrictIssues []string
+	}{
+		{
+			"valid",
+			`{"type": "object", "additionalProperties": false, "required": ["tags", "title"], "properties": {
+				"tags": {"type": "array", "items": {"type": "string", "enum": ["a", "b"]}},
+				"title": {"type": ["string", "null"], "pattern": "^[A-Z]"}
+			}}`,
+			[]string{},
+			[]string{},
+		},
+		{
+			"valid with references",
+			`{"type": "object", "additionalProperties": false, "required": ["item"], "properties": {
+				"item": {"$ref": "#/$defs/item"}
+			}, "$defs": {"item": {"anyOf": [{"type": "string"}, {"type": "null"}]}}}`,
+			[]string{},
+			[]string{},
+		},
+		{
+			"missing required",
+			`{"type": "object", "additionalProperties": false, "required": ["title", "body"], "properties": {"title": {"type": "string"}}}`,
+			[]string{"$: required property 'body' is not defined in 'properties'"},
+			[]string{},
+		},
+		{
+			"non-object root",
+			`{"type": "array", "items": {"type": "string"}}`,
+			[]string{"$: type of root must be 'object'"},
+			[]string{},
+		},
+		{
+			"disallowed keywords",
+			`{"type": "object", "required": ["name"], "properties": {
+				"name": {"type": "string", "minLength": 1},
+				"tags": {"type": "array"}
+			}}`,
+			[]string{},
+			[]string{
+				"$.properties.name: keyword 'minLength' is not supported",
+				"$.properties.tags: 'items' is missing",
+				"$: 'additionalProperties' must be false",
+				"$: property 'tags' must be in 'required' (use a 'null' type for optional values)",
+			},
+		},
+		{
+			"invalid values",
+			`{"type": "object", "additionalProperties": false, "required": ["a", "b", "c"], "properties": {
+				"a": {"type": "text"},
+				"b": {"type": "string", "pattern": "("},
+				"c": {"type": "integer", "minimum": 10, "maximum": 1, "enum": []},
+				"d": {"$ref": "#/$defs/missing"}
+			}}`,
+			[]string{
+				"$.properties.a: 'text' is no valid type, use one of array, boolean, integer, null, number, object, string",
+				"$.properties.b: 'pattern' is no valid regular expression (error parsing regexp: missing closing ): `(`)",
+				"$.properties.c: 'enum' must be a non-empty array",
+				"$.properties.c: 'minimum' must not be greater than 'maximum'",
+				"$.properties.d: '$ref' '#/$defs/missing' cannot be resolved, only local references like '#/$defs/name' are supported",
+			},
+			[]string{
+				"$: property 'd' must be in 'required' (use a 'null' type for optional values)",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		errors, strictIssues := LintJSONSchema(parseTestJSONSchema(t, test.schema))
+
+		if strings.Join(errors, "\n") != strings.Join(test.expectedErrors, "\n") {
+			t.Errorf("%v: expected errors %q, got %q", test.name, test.expectedErrors, errors)
+		}
+		if strings.Join(strictIssues, "\n") != strings.Join(test.expectedStrictIssues, "\n") {
+			t.Errorf("%v: expected strict issues %q, got %q", test.name, test.expectedStrictIssues, strictIssues)
+		}
+	}
+}
+
+func TestValidateJSONSchema(t *testing.T) {
+	schema := parseTestJSONSchema(t, `{"type": "object", "required": ["title", "tags"], "additionalProperties": false, "properties": {
+		"title": {"type": "string", "maxLength": 5},
+		"tags": {"type": "array", "maxItems": 2, "items": {"enum": ["a", "b"]}}
+	}}`)
+
+	tests := []struct {
+		data               string
+		expectedViolations []string
+	}{
+		{`{"title": "Hi", "tags": ["a"]}`, []string{}},
+		{`{"title": 1, "tags": ["a", "c", "b"], "extra": true}`, []string{
+			"$: property 'extra' is not allowed",
+			"$.tags: must have at most 2 item(s), but has 3",
+			"$.tags[1]: must be one of [\"a\",\"b\"]",
+			"$.title: expected string, but got integer",
+		}},
+		{`{"title": "Hello, world"}`, []string{
+			"$: missing required property 'tags'",
+			"$.title: must have at most 5 character(s)",
+		}},
+		{`{"title": `, []string{"$: no valid JSON (unexpected end of JSON input)"}},
+	}
+
+	for _, test := range tests {
+		violations, err := ValidateJSONSchema(schema, test.data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if strings.Join(violations, "\n") != strings.Join(test.expectedViolations, "\n") {
+			t.Errorf("%v: expected %q, got %q", test.data, test.expectedViolations, violations)
+		}
+	}
+}
@@ -0,0 +1,327 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const openDocumentMimePrefix = "application/vnd.oasis.opendocument."
+
+const openDocumentTableNamespace = "urn:oasis:names:tc:opendocument:xmlns:table:1.0"
+const openDocumentTextNamespace = "urn:oasis:names:tc:opendocument:xmlns:text:1.0"
+
+// maxOpenDocumentRepeat limits repeated rows and columns of empty cells,
+// which are used to fill up the sheets of spreadsheets
+const maxOpenDocumentRepeat = 1024
+
+// GetOpenDocumentMimeType returns the MIME type of the `mimetype` entry
+// of an OpenDocument file, like `application/vnd.oasis.opendocument.text`,
+// or an empty string, if `data` is no OpenDocument file.
+func GetOpenDocumentMimeType(data []byte) (string, error) {
+	mimeType, err := GetZipMimeType(data)
+	if err == nil && strings.HasPrefix(mimeType, openDocumentMimePrefix) {
+		return mimeType, nil
+	}
+
+	return "", err
+}
+
+// GetZipMimeType returns the content of the `mimetype` entry of a ZIP
+// container, which is used by formats like OpenDocument or EPUB, or an
+// empty string if there is no such entry.
+func GetZipMimeType(data []byte) (string, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, f := range z.File {
+		if f.Name != "mimetype" {
+			continue
+		}
+
+		content, err := readZipEntry(f)
+		if err != nil {
+			return "", err
+		}
+
+		return strings.TrimSpace(string(content)), nil
+	}
+
+	return "", nil
+}
+
+// IsODS checks if `data` contains a spreadsheet in OpenDocument format.
+func IsODS(data []byte) (bool, error) {
+	mimeType, err := GetOpenDocumentMimeType(data)
+	return mimeType == openDocumentMimePrefix+"spreadsheet", err
+}
+
+// IsODT checks if `data` contains a text document in OpenDocument format.
+func IsODT(data []byte) (bool, error) {
+	mimeType, err := GetOpenDocumentMimeType(data)
+	return mimeType == openDocumentMimePrefix+"text", err
+}
+
+func readZipEntry(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	return io.ReadAll(rc)
+}
+
+func readOpenDocumentContent(data []byte) ([]byte, error) {
+	z, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, f := range z.File {
+		if f.Name == "content.xml" {
+			return readZipEntry(f)
+		}
+	}
+
+	return nil, fmt.Errorf("no content.xml found")
+}
+
+func getOpenDocumentAttr(se xml.StartElement, space string, local string) string {
+	for _, a := range se.Attr {
+		if a.Name.Space == space && a.Name.Local == local {
+			return a.Value
+		}
+	}
+	return ""
+}
+
+func getOpenDocumentRepeat(se xml.StartElement, local string) int {
+	n, err := strconv.Atoi(getOpenDocumentAttr(se, openDocumentTableNamespace, local))
+	if err != nil || n < 1 {
+		return 1
+	}
+	return n
+}
+
+// writeOpenDocumentText handles the text elements of paragraphs, like
+// spaces, tabs and line breaks, and returns `false` if `t` is none of them
+func writeOpenDocumentText(t xml.Token, text *strings.Builder) bool {
+	switch se := t.(type) {
+	case xml.CharData:
+		text.Write(se)
+		return true
+	case xml.StartElement:
+		if se.Name.Space != openDocumentTextNamespace {
+			return false
+		}
+
+		switch se.Name.Local {
+		case "s":
+			count, err := strconv.Atoi(getOpenDocumentAttr(se, openDocumentTextNamespace, "c"))
+			if err != nil || count < 1 {
+				count = 1
+			}
+			text.WriteString(strings.Repeat(" ", count))
+			return true
+		case "tab":
+			text.WriteString("\t")
+			return true
+		case "line-break":
+			text.WriteString("\n")
+			return true
+		}
+	}
+
+	return false
+}
+
+// extractODTText extracts the paragraphs and headings of an OpenDocument text
+func extractODTText(data []byte) (string, error) {
+	content, err := readOpenDocumentContent(data)
+	if err != nil {
+		return "", err
+	}
+
+	paragraphs := make([]string, 0)
+
+	var current strings.Builder
+	depth := 0 // paragraphs can contain other ones, like in notes
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		t, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return strings.Join(paragraphs, "\n"), err
+		}
+
+		switch se := t.(type) {
+		case xml.StartElement:
+			if se.Name.Space == openDocumentTextNamespace && (se.Name.Local == "p" || se.Name.Local == "h") {
+				depth++
+				continue
+			}
+		case xml.EndElement:
+			if se.Name.Space == openDocumentTextNamespace && (se.Name.Local == "p" || se.Name.Local == "h") && depth > 0 {
+				depth--
+				if depth == 0 {
+					paragraphs = append(paragraphs, current.String())
+					current.Reset()
+				} else {
+					current.WriteString(" ")
+				}
+			}
+			continue
+		}
+
+		if depth > 0 {
+			writeOpenDocumentText(t, &current)
+		}
+	}
+
+	return strings.Join(paragraphs, "\n"), nil
+}
+
+// extractODSText extracts all sheets of an OpenDocument spreadsheet as CSV
+func extractODSText(data []byte) (string, error) {
+	content, err := readOpenDocumentContent(data)
+	if err != nil {
+		return "", err
+	}
+
+	texts := make([]string, 0)
+	getJoinedText := func() string {
+		return strings.Join(texts, "\n\n\n")
+	}
+
+	var rows [][]string
+	var row []string
+	rowRepeat := 1
+	var cell strings.Builder
+	cellRepeat := 1
+	inCell := false
+	cellParagraphs := 0
+
+	decoder := xml.NewDecoder(bytes.NewReader(content))
+	for {
+		t, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return getJoinedText(), err
+		}
+
+		switch se := t.(type) {
+		case xml.StartElement:
+			if se.Name.Space == openDocumentTableNamespace {
+				switch se.Name.Local {
+				case "table":
+					rows = make([][]string, 0)
+				case "table-row":
+					row = make([]string, 0)
+					rowRepeat = getOpenDocumentRepeat(se, "number-rows-repeated")
+				case "table-cell", "covered-table-cell":
+					cell.Reset()
+					cellRepeat = getOpenDocumentRepeat(se, "number-columns-repeated")
+					inCell = true
+					cellParagraphs = 0
+				}
+				continue
+			}
+			if inCell && se.Name.Space == openDocumentTextNamespace && se.Name.Local == "p" {
+				if cellParagraphs > 0 {
+					cell.WriteString("\n")
+				}
+				cellParagraphs++
+				continue
+			}
+		case xml.EndElement:
+			if se.Name.Space == openDocumentTableNamespace {
+				switch se.Name.Local {
+				case "table-cell", "covered-table-cell":
+					value := cell.String()
+					if value == "" {
+						cellRepeat = min(cellRepeat, maxOpenDocumentRepeat)
+					}
+					for range cellRepeat {
+						row = append(row, value)
+					}
+					inCell = false
+				case "table-row":
+					// remove empty cells at the end
+					for len(row) > 0 && row[len(row)-1] == "" {
+						row = row[:len(row)-1]
+					}
+					if len(row) == 0 {
+						rowRepeat = min(rowRepeat, maxOpenDocumentRepeat)
+					}
+					for range rowRepeat {
+						rows = append(rows, row)
+					}
+				case "table":
+					// remove empty rows at the end
+					for len(rows) > 0 && len(rows[len(rows)-1]) == 0 {
+						rows = rows[:len(rows)-1]
+					}
+
+					buff := &bytes.Buffer{}
+					writer := csv.NewWriter(buff)
+					for _, record := range rows {
+						err := writer.Write(record)
+						if err != nil {
+							return getJoinedText(), err
+						}
+					}
+					writer.Flush()
+
+					err := writer.Error()
+					if err != nil {
+						return getJoinedText(), err
+					}
+
+					texts = append(texts, buff.String())
+				}
+			}
+			continue
+		}
+
+		if inCell {
+			writeOpenDocumentText(t, &cell)
+		}
+	}
+
+	return getJoinedText(), nil
+}
@@ -0,0 +1,251 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+
+	"golang.org/x/text/encoding/charmap"
+)
+
+const rtfMimeType = "application/rtf"
+
+// destinations of RTF documents, which contain no readable text
+var rtfIgnoredDestinations = map[string]bool{
+	"author":            true,
+	"colortbl":          true,
+	"comment":           true,
+	"datastore":         true,
+	"fldinst":           true,
+	"filetbl":           true,
+	"fonttbl":           true,
+	"footer":            true,
+	"footerf":           true,
+	"footerl":           true,
+	"footerr":           true,
+	"generator":         true,
+	"header":            true,
+	"headerf":           true,
+	"headerl":           true,
+	"headerr":           true,
+	"info":              true,
+	"latentstyles":      true,
+	"listoverridetable": true,
+	"listtable":         true,
+	"object":            true,
+	"pict":              true,
+	"rsidtbl":           true,
+	"stylesheet":        true,
+	"themedata":         true,
+	"xmlnstbl":          true,
+}
+
+// replacements of RTF control words
+var rtfControlWords = map[string]string{
+	"bullet":    "•",
+	"cell":      "\t",
+	"emdash":    "—",
+	"emspace":   " ",
+	"endash":    "–",
+	"enspace":   " ",
+	"ldblquote": "“",
+	"line":      "\n",
+	"lquote":    "‘",
+	"page":      "\n\n",
+	"par":       "\n",
+	"rdblquote": "”",
+	"row":       "\n",
+	"rquote":    "’",
+	"sect":      "\n\n",
+	"tab":       "\t",
+}
+
+// IsRTF checks if `data` contains a document in Rich Text Format.
+func IsRTF(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(`{\rtf`))
+}
+
+// extractRTFText removes control words and groups without readable
+// text, like font tables or pictures, from a RTF document
+func extractRTFText(data []byte) (string, error) {
+	type rtfGroup struct {
+		ignore bool
+		ucSkip int // number of fallback chars after \uN
+	}
+
+	var text strings.Builder
+
+	current := rtfGroup{ucSkip: 1}
+	stack := make([]rtfGroup, 0)
+	isGroupStart := false
+	skip := 0 // fallback chars of last \uN, which are left to skip
+	// first half of a char outside the BMP, like an emoji
+	var highSurrogate rune
+
+	write := func(s string) {
+		if skip > 0 {
+			skip--
+			return
+		}
+
+		if !current.ignore {
+			text.WriteString(s)
+		}
+	}
+
+	decoder := charmap.Windows1252.NewDecoder()
+
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+
+		wasGroupStart := isGroupStart
+		isGroupStart = false
+
+		switch c {
+		case '{':
+			stack = append(stack, current)
+			isGroupStart = true
+			skip = 0
+		case '}':
+			if len(stack) > 0 {
+				current = stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+			}
+			skip = 0
+		case '\r', '\n':
+			// line breaks of the source are no content
+		case '\\':
+			if i+1 >= len(data) {
+				break
+			}
+
+			i++
+			next := data[i]
+
+			switch {
+			case next == '\\' || next == '{' || next == '}':
+				write(string(next))
+			case next == '\'':
+				// char of code page as hex value
+				if i+2 < len(data) {
+					b, err := strconv.ParseUint(string(data[i+1:i+3]), 16, 8)
+					if err == nil {
+						decoded, err := decoder.Bytes([]byte{byte(b)})
+						if err == nil {
+							write(string(decoded))
+						}
+					}
+					i += 2
+				}
+			case next == '*':
+				// unknown destinations can be ignored
+				current.ignore = true
+			case next == '~':
+				write(" ")
+			case next == '_':
+				write("-")
+			case next == '\r' || next == '\n':
+				write("\n")
+			case (next >= 'a' && next <= 'z') || (next >= 'A' && next <= 'Z'):
+				start := i
+				for i < len(data) && ((data[i] >= 'a' && data[i] <= 'z') || (data[i] >= 'A' && data[i] <= 'Z')) {
+					i++
+				}
+				word := string(data[start:i])
+
+				paramStart := i
+				if i < len(data) && data[i] == '-' {
+					i++
+				}
+				for i < len(data) && data[i] >= '0' && data[i] <= '9' {
+					i++
+				}
+				param, hasParam := 0, false
+				if i > paramStart {
+					value, err := strconv.Atoi(string(data[paramStart:i]))
+					if err == nil {
+						param, hasParam = value, true
+					}
+				}
+
+				if i >= len(data) || data[i] != ' ' {
+					i-- // delimiter is part of the content
+				}
+
+				if wasGroupStart && rtfIgnoredDestinations[word] {
+					current.ignore = true
+					continue
+				}
+
+				switch word {
+				case "u":
+					if hasParam {
+						if param < 0 {
+							param += 65536
+						}
+
+						r := rune(param)
+						if utf16.IsSurrogate(r) {
+							if r < 0xDC00 {
+								highSurrogate = r // wait for second half
+								r = 0
+							} else {
+								r = utf16.DecodeRune(highSurrogate, r)
+								highSurrogate = 0
+							}
+						}
+
+						if r != 0 {
+							write(string(r))
+						}
+						skip = current.ucSkip
+					}
+				case "uc":
+					if hasParam {
+						current.ucSkip = max(param, 0)
+					}
+				default:
+					if replacement, ok := rtfControlWords[word]; ok {
+						write(replacement)
+					} else if skip > 0 {
+						skip-- // fallback can also be a control word
+					}
+				}
+			default:
+				// other control symbols, like optional hyphens
+			}
+		default:
+			write(string([]byte{c}))
+		}
+	}
+
+	lines := strings.Split(text.String(), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " \t")
+	}
+
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,66 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"testing"
+)
+
+func TestEnsurePlainTextRTF(t *testing.T) {
+	tests := []struct {
+		name     string
+		rtf      string
+		expected string
+	}{
+		{"plain", `{\rtf1\ansi Hello, world!}`, "Hello, world!"},
+		{"paragraphs", `{\rtf1\ansi First\par Second\line Third}`, "First\nSecond\nThird"},
+		{"formatting", `{\rtf1\ansi This is {\b bold} and {\i italic}.}`, "This is bold and italic."},
+		{"escaped chars", `{\rtf1\ansi a \{b\} c\\d}`, `a {b} c\d`},
+		{"font table", `{\rtf1\ansi{\fonttbl{\f0\fswiss Helvetica;}}{\colortbl;\red255\green0\blue0;}\f0 Text}`, "Text"},
+		{"unknown destination", `{\rtf1\ansi{\*\generator Writer 1.0;}{\*\unknown hidden}Visible}`, "Visible"},
+		{"unicode", `{\rtf1\ansi Gr\u246?\u223?e \u-10179?\u-8704?}`, "Größe 😀"},
+		{"unicode without fallback", `{\rtf1\ansi\uc0 Gr\u246\u223 e}`, "Größe"},
+		{"code page", `{\rtf1\ansi Caf\'e9 \'80}`, "Café €"},
+		{"special chars", `{\rtf1\ansi \ldblquote A\rdblquote\emdash B\tab C\~D}`, "“A”—B\tC D"},
+		{"source line breaks", "{\\rtf1\\ansi One\r\ntwo\\\nthree}", "Onetwo\nthree"},
+		{"trailing spaces", `{\rtf1\ansi a   \par\par  b }`, "a\n\n b"},
+	}
+
+	for _, test := range tests {
+		data := []byte(test.rtf)
+
+		if mimeType := DetectMime(data); mimeType != "application/rtf" {
+			t.Errorf("%v: expected type application/rtf, got %v", test.name, mimeType)
+		}
+
+		text, err := EnsurePlainText(data)
+		if err != nil {
+			t.Errorf("%v: %v", test.name, err)
+			continue
+		}
+
+		if text != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.name, test.expected, text)
+		}
+	}
+}
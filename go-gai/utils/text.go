@@ -0,0 +1,278 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// DedentText removes the common leading indentation of all non-empty lines of
+// `text`, trailing white spaces and repeating or surrounding empty lines.
+// Lines inside fenced code blocks keep their relative indentation,
+// trailing white spaces and empty lines.
+func DedentText(text string) string {
+	lines := strings.Split(strings.ReplaceAll(text, "\r\n", "\n"), "\n")
+
+	// find common indentation
+	indent := ""
+	indentFound := false
+	for _, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+
+		lineIndent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+		if !indentFound {
+			indent = lineIndent
+			indentFound = true
+			continue
+		}
+
+		i := 0
+		for i < len(indent) && i < len(lineIndent) && indent[i] == lineIndent[i] {
+			i++
+		}
+		indent = indent[:i]
+	}
+
+	result := make([]string, 0, len(lines))
+	fence := ""
+	lastWasEmpty := true // skip leading empty lines
+	for _, line := range lines {
+		line = strings.TrimPrefix(line, indent)
+		trimmed := strings.TrimSpace(line)
+
+		if fence != "" {
+			// inside fenced code block
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+				line = strings.TrimRight(line, " \t")
+			}
+
+			result = append(result, line)
+			lastWasEmpty = false
+			continue
+		}
+
+		if trimmed == "" {
+			if !lastWasEmpty {
+				result = append(result, "")
+			}
+
+			lastWasEmpty = true
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "```") {
+			fence = "```"
+		} else if strings.HasPrefix(trimmed, "~~~") {
+			fence = "~~~"
+		}
+
+		result = append(result, strings.TrimRight(line, " \t"))
+		lastWasEmpty = false
+	}
+
+	// remove trailing empty lines
+	for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+		result = result[:len(result)-1]
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// TruncateText keeps the head of `text` with a maximum of `maxBytes` bytes,
+// ending with a complete line, if possible, and appends a
+// `... [truncated N bytes] ...` marker. The second value contains
+// the number of removed bytes. A negative `maxBytes` means no limit.
+func TruncateText(text string, maxBytes int) (string, int) {
+	if maxBytes < 0 || len(text) <= maxBytes {
+		return text, 0
+	}
+
+	n := maxBytes
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n-- // do not split UTF-8 sequences
+	}
+
+	head := text[:n]
+	if i := strings.LastIndex(head, "\n"); i > -1 {
+		head = head[:i+1]
+	}
+
+	truncated := len(text) - len(head)
+
+	return fmt.Sprintf("%s... [truncated %d bytes] ...", head, truncated), truncated
+}
+
+// GetSnippet returns `text` as single line with collapsed white spaces,
+// which is cut after `maxChars` characters and ends with `...` then.
+func GetSnippet(text string, maxChars int) string {
+	snippet := strings.Join(strings.Fields(text), " ")
+
+	runes := []rune(snippet)
+	if maxChars >= 0 && len(runes) > maxChars {
+		return string(runes[:maxChars]) + "..."
+	}
+	return snippet
+}
+
+// WrapText wraps the lines of `text`, which are longer than `width` columns,
+// at white spaces, while continued lines keep the indentation of the original
+// line. Words longer than `width` are split. Fenced code blocks are not wrapped.
+func WrapText(text string, width int) string {
+	if width <= 0 {
+		return text
+	}
+
+	lines := strings.Split(text, "\n")
+
+	result := make([]string, 0, len(lines))
+	fence := ""
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if fence == "" {
+			if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+				fence = trimmed[:3]
+				result = append(result, line)
+				continue
+			}
+		} else {
+			if strings.HasPrefix(trimmed, fence) {
+				fence = ""
+			}
+			result = append(result, line)
+			continue
+		}
+
+		result = append(result, wrapLine(line, width)...)
+	}
+
+	return strings.Join(result, "\n")
+}
+
+func wrapLine(line string, width int) []string {
+	if utf8.RuneCountInString(line) <= width {
+		return []string{line}
+	}
+
+	isSpace := func(r rune) bool {
+		return r == ' ' || r == '\t'
+	}
+
+	leadingLen := utf8.RuneCountInString(line) - utf8.RuneCountInString(strings.TrimLeft(line, " \t"))
+
+	indent := []rune(line[:len(line)-len(strings.TrimLeft(line, " \t"))])
+	if len(indent) >= width/2 {
+		indent = nil // keep enough space for the text
+	}
+
+	wrapped := make([]string, 0)
+	current := []rune(line)
+	minBreak := leadingLen // do not break inside the indentation
+	for len(current) > width {
+		// last white space, which keeps the line inside `width`
+		cut := -1
+		for i := width; i > minBreak; i-- {
+			if isSpace(current[i]) {
+				cut = i
+				break
+			}
+		}
+
+		next := cut
+		if cut < 0 {
+			// split words, which are too long
+			cut = width
+			next = width
+		} else {
+			for cut > 0 && isSpace(current[cut-1]) {
+				cut--
+			}
+			for next < len(current) && isSpace(current[next]) {
+				next++
+			}
+		}
+
+		wrapped = append(wrapped, string(current[:cut]))
+
+		rest := current[next:]
+		if len(rest) == 0 {
+			return wrapped
+		}
+
+		current = append(append([]rune{}, indent...), rest...)
+		minBreak = len(indent)
+	}
+
+	return append(wrapped, string(current))
+}
+
+// ExtractFencedCodeBlocks returns the content of all fenced code blocks
+// of `markdown`, whose info string starts with `language` (case insensitive).
+// An empty `language` returns all blocks.
+func ExtractFencedCodeBlocks(markdown string, language string) []string {
+	language = strings.TrimSpace(strings.ToLower(language))
+
+	blocks := make([]string, 0)
+
+	var block []string
+	fence := ""
+	for _, line := range strings.Split(markdown, "\n") {
+		trimmed := strings.TrimSpace(strings.TrimRight(line, "\r"))
+
+		if fence != "" {
+			// inside fenced code block
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				if block != nil {
+					blocks = append(blocks, strings.Join(block, "\n"))
+				}
+
+				fence = ""
+				block = nil
+				continue
+			}
+
+			if block != nil {
+				block = append(block, strings.TrimRight(line, "\r"))
+			}
+			continue
+		}
+
+		if !strings.HasPrefix(trimmed, "```") && !strings.HasPrefix(trimmed, "~~~") {
+			continue
+		}
+
+		// fences can be longer than 3 characters
+		fence = trimmed[:len(trimmed)-len(strings.TrimLeft(trimmed, trimmed[:1]))]
+
+		info := strings.Fields(strings.ToLower(trimmed[len(fence):]))
+		if language == "" || (len(info) > 0 && info[0] == language) {
+			block = []string{}
+		}
+	}
+
+	return blocks
+}
@@ -0,0 +1,135 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDedentText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{"empty", "", ""},
+		{"not indented", "a\nb", "a\nb"},
+		{"common indentation", "    func a() {\n        return\n    }", "func a() {\n    return\n}"},
+		{"tabs", "\t\tif a {\n\t\t\tb()\n\t\t}", "if a {\n\tb()\n}"},
+		{"mixed indentation", "  \ta\n  b", "\ta\nb"},
+		{"empty lines", "\n\n  a  \n\n\n\n  b\n  \n", "a\n\nb"},
+		{"windows line breaks", "  a\r\n  b\r\n", "a\nb"},
+		{"fenced code", "  Fix this:\n  ```go\n  a := 1   \n\n\n    b()\n  ```  \n\n\n  Thanks", "Fix this:\n```go\na := 1   \n\n\n  b()\n```\n\nThanks"},
+		{"unclosed fence", "  ~~~\n  a\n\n\n", "~~~\na"},
+	}
+
+	for _, test := range tests {
+		if dedented := DedentText(test.text); dedented != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.name, test.expected, dedented)
+		}
+	}
+}
+
+func TestTruncateText(t *testing.T) {
+	tests := []struct {
+		name              string
+		text              string
+		maxBytes          int
+		expected          string
+		expectedTruncated int
+	}{
+		{"no limit", "a\nb\nc", -1, "a\nb\nc", 0},
+		{"small enough", "a\nb\nc", 5, "a\nb\nc", 0},
+		{"complete lines", "aa\nbb\ncc\n", 7, "aa\nbb\n... [truncated 3 bytes] ...", 3},
+		{"single line", "abcdef", 3, "abc... [truncated 3 bytes] ...", 3},
+		{"nothing", "abc", 0, "... [truncated 3 bytes] ...", 3},
+		{"utf-8", "äöü", 3, "ä... [truncated 4 bytes] ...", 4},
+	}
+
+	for _, test := range tests {
+		truncatedText, truncated := TruncateText(test.text, test.maxBytes)
+
+		if truncatedText != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.name, test.expected, truncatedText)
+		}
+		if truncated != test.expectedTruncated {
+			t.Errorf("%v: expected %d truncated bytes, got %d", test.name, test.expectedTruncated, truncated)
+		}
+	}
+}
+
+func TestWrapText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		width    int
+		expected string
+	}{
+		{"no wrap", "a b c d e f", 0, "a b c d e f"},
+		{"short lines", "a b\nc d", 10, "a b\nc d"},
+		{"at white space", "aaa bbb ccc ddd", 8, "aaa bbb\nccc ddd"},
+		{"exact width", "aaa bbb ccc", 7, "aaa bbb\nccc"},
+		{"long word", "abcdefghij", 4, "abcd\nefgh\nij"},
+		{"utf-8", "äöü äöü äöü", 8, "äöü äöü\näöü"},
+		{"keeps spacing", "a  b\tc   dd eeeeee", 12, "a  b\tc   dd\neeeeee"},
+		{"trailing spaces", "aaa bbb     ", 7, "aaa bbb"},
+		{"indentation", "    aaa bbb ccc", 11, "    aaa bbb\n    ccc"},
+		{"tab indentation", "\taaa bbb ccc", 8, "\taaa bbb\n\tccc"},
+		{"deep indentation", "        aaa bbb", 12, "        aaa\nbbb"},
+		{"markdown list", "- item  with  spaces", 12, "- item  with\nspaces"},
+		{"nested list", "  1. aaa bbb ccc", 12, "  1. aaa bbb\n  ccc"},
+		{"table", "| a   | b   |\n| --- | --- |", 20, "| a   | b   |\n| --- | --- |"},
+		{"fenced code", "aaa bbb\n```go\nfunc a() { return b + c }\n```\nccc ddd", 7, "aaa bbb\n```go\nfunc a() { return b + c }\n```\nccc ddd"},
+		{"tilde fence", "~~~\naaa   bbb ccc\n~~~\naaa bbb ccc", 7, "~~~\naaa   bbb ccc\n~~~\naaa bbb\nccc"},
+		{"unclosed fence", "```\naaa bbb ccc", 7, "```\naaa bbb ccc"},
+	}
+
+	for _, test := range tests {
+		if wrapped := WrapText(test.text, test.width); wrapped != test.expected {
+			t.Errorf("%v: expected %q, got %q", test.name, test.expected, wrapped)
+		}
+	}
+}
+
+func TestExtractFencedCodeBlocks(t *testing.T) {
+	markdown := "```mermaid\ngraph TD\n```\n\n~~~~ go main.go\nfunc main() {\n  // ```\n}\n~~~~\n\n```\nplain\n```\n\n```mermaid\nunclosed"
+
+	tests := []struct {
+		language string
+		expected []string
+	}{
+		{"mermaid", []string{"graph TD"}},
+		{"Go", []string{"func main() {\n  // ```\n}"}},
+		{"", []string{"graph TD", "func main() {\n  // ```\n}", "plain"}},
+		{"python", []string{}},
+	}
+
+	for _, test := range tests {
+		blocks := ExtractFencedCodeBlocks(markdown, test.language)
+
+		if strings.Join(blocks, "|") != strings.Join(test.expected, "|") || len(blocks) != len(test.expected) {
+			t.Errorf("%q: expected %q, got %q", test.language, test.expected, blocks)
+		}
+	}
+}
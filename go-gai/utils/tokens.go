@@ -0,0 +1,96 @@
+// MIT License
+//
+// Copyright (c) 2025 Marcel Joachim Kloubert (https://marcel.coffee)
+//
+// Permission is hereby granted, free of charge, to any person obtaining a copy
+// of this software and associated documentation files (the "Software"), to deal
+// in the Software without restriction, including without limitation the rights
+// to use, copy, modify, merge, publish, distribute, sublicense, and/or sell
+// copies of the Software, and to permit persons to whom the Software is
+// furnished to do so, subject to the following conditions:
+//
+// The above copyright notice and this permission notice shall be included in all
+// copies or substantial portions of the Software.
+//
+// THE SOFTWARE IS PROVIDED "AS IS", WITHOUT WARRANTY OF ANY KIND, EXPRESS OR
+// IMPLIED, INCLUDING BUT NOT LIMITED TO THE WARRANTIES OF MERCHANTABILITY,
+// FITNESS FOR A PARTICULAR PURPOSE AND NONINFRINGEMENT. IN NO EVENT SHALL THE
+// AUTHORS OR COPYRIGHT HOLDERS BE LIABLE FOR ANY CLAIM, DAMAGES OR OTHER
+// LIABILITY, WHETHER IN AN ACTION OF CONTRACT, TORT OR OTHERWISE, ARISING FROM,
+// OUT OF OR IN CONNECTION WITH THE SOFTWARE OR THE USE OR OTHER DEALINGS IN THE
+// SOFTWARE.
+
+package utils
+
+import (
+	"unicode/utf8"
+
+	"github.com/pkoukk/tiktoken-go"
+)
+
+// approximateCharsPerToken is used to estimate tokens, if no encoding is available.
+const approximateCharsPerToken = 4
+
+// TextTokenizer counts and splits text by GPT tokens.
+type TextTokenizer struct {
+	tkm *tiktoken.Tiktoken
+}
+
+// NewTextTokenizer creates a new `TextTokenizer` for `model`, which falls back
+// to `cl100k_base` encoding for unknown models and to an approximation
+// of 4 characters per token, if no encoding could be loaded at all.
+func NewTextTokenizer(model string) *TextTokenizer {
+	tkm, err := tiktoken.EncodingForModel(model)
+	if err != nil {
+		tkm, err = tiktoken.GetEncoding("cl100k_base")
+		if err != nil {
+			tkm = nil
+		}
+	}
+
+	return &TextTokenizer{
+		tkm: tkm,
+	}
+}
+
+// CountTokens returns the (approximate) number of tokens of `text`.
+func (t *TextTokenizer) CountTokens(text string) int {
+	if t.tkm == nil {
+		return (utf8.RuneCountInString(text) + approximateCharsPerToken - 1) / approximateCharsPerToken
+	}
+
+	return len(t.tkm.Encode(text, nil, nil))
+}
+
+// SplitByTokens splits `text` into chunks with not more than `maxTokens` tokens
+// by keeping lines together where possible. If `maxTokens` is `0` or less
+// or `text` is small enough, `text` is returned as single chunk.
+func (t *TextTokenizer) SplitByTokens(text string, maxTokens int) []string {
+	return Chunk(text, ChunkOptions{
+		MaxSize:   maxTokens,
+		Strategy:  ChunkStrategyTokens,
+		Tokenizer: t,
+	})
+}
+
+func (t *TextTokenizer) splitLongLine(line string, maxTokens int) []string {
+	chunks := make([]string, 0)
+
+	if t.tkm == nil {
+		runes := []rune(line)
+		maxChars := maxTokens * approximateCharsPerToken
+
+		for i := 0; i < len(runes); i += maxChars {
+			chunks = append(chunks, string(runes[i:min(i+maxChars, len(runes))]))
+		}
+
+		return chunks
+	}
+
+	tokens := t.tkm.Encode(line, nil, nil)
+	for i := 0; i < len(tokens); i += maxTokens {
+		chunks = append(chunks, t.tkm.Decode(tokens[i:min(i+maxTokens, len(tokens))]))
+	}
+
+	return chunks
+}